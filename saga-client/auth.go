@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// headerAPIKey carries a saga API key, as it does for the services
+const headerAPIKey = "X-API-Key"
+
+// Roles of the saga API: reading a saga's status needs read, starting one write,
+// which includes read
+const (
+	roleRead  = "read"
+	roleWrite = "write"
+)
+
+// APIPrincipal is an authenticated caller of the saga API
+type APIPrincipal struct {
+	Subject string
+	Roles   []string
+	// Tenant is the tenant the key is bound to; an unbound key may act for any tenant
+	Tenant string
+}
+
+// HasRole reports whether p was granted role; write implies read
+func (p APIPrincipal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role) || (role == roleRead && slices.Contains(p.Roles, roleWrite))
+}
+
+// ParseAPIKeys parses comma-separated subject:key:roles[:tenant] entries with
+// |-separated roles, the format of the services' API_KEYS, e.g.
+// "onboarding-portal:s3cret:write:lender-a,dashboard:r3port:read"
+func ParseAPIKeys(value string) (map[string]APIPrincipal, error) {
+	keys := map[string]APIPrincipal{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry for %q: expected subject:key:roles[:tenant]", parts[0])
+		}
+		roles := strings.Split(parts[2], "|")
+		for _, role := range roles {
+			if role != roleRead && role != roleWrite {
+				return nil, fmt.Errorf("invalid role %q for %s", role, parts[0])
+			}
+		}
+		principal := APIPrincipal{Subject: parts[0], Roles: roles}
+		if len(parts) == 4 {
+			if parts[3] == "" {
+				return nil, fmt.Errorf("empty tenant for %s", parts[0])
+			}
+			principal.Tenant = parts[3]
+		}
+		keys[parts[1]] = principal
+	}
+	return keys, nil
+}
+
+type principalKey struct{}
+
+// principalFrom returns the caller authenticated by apiKeyAuth, if any
+func principalFrom(ctx context.Context) (APIPrincipal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(APIPrincipal)
+	return principal, ok
+}
+
+// apiKeyAuth rejects requests without one of keys in X-API-Key with a 401 and
+// requests lacking the role their method needs with a 403. With no keys it lets
+// every request through, as the services do without API_KEYS.
+func apiKeyAuth(keys map[string]APIPrincipal) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if len(keys) == 0 {
+			return next
+		}
+		return func(c echo.Context) error {
+			principal, ok := lookupAPIKey(keys, c.Request().Header.Get(headerAPIKey))
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid credentials")
+			}
+			role := roleWrite
+			if c.Request().Method == http.MethodGet || c.Request().Method == http.MethodHead {
+				role = roleRead
+			}
+			if !principal.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("the %s role is required", role))
+			}
+			c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), principalKey{}, principal)))
+			return next(c)
+		}
+	}
+}
+
+// lookupAPIKey compares key against every configured key in constant time
+func lookupAPIKey(keys map[string]APIPrincipal, key string) (APIPrincipal, bool) {
+	var found APIPrincipal
+	ok := false
+	if key == "" {
+		return found, false
+	}
+	for candidate, principal := range keys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			found, ok = principal, true
+		}
+	}
+	return found, ok
+}
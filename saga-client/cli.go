@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// cliEnv builds what the commands run on; main builds it from the configuration
+type cliEnv struct {
+	// config loads and validates the configuration, reading file as well when set
+	config func(file string) (Config, error)
+	// stateStore opens the saga state store; closeStore releases it
+	stateStore func(ctx context.Context, config Config) (store saga.StateStore, closeStore func(), err error)
+	// sagas registers the sagas over the services' clients. Building them does not
+	// call the services; services probes them.
+	sagas func(config Config, store saga.StateStore) (registry *SagaRegistry, services []HealthCheck, closeClients func(), err error)
+}
+
+// cli holds the state shared by the commands of one invocation
+type cli struct {
+	env        cliEnv
+	output     string
+	configFile string
+	config     Config
+
+	store    saga.StateStore
+	registry *SagaRegistry
+	services []HealthCheck
+	closers  []func()
+}
+
+// Output formats of the --output flag
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// newRootCommand returns the saga-client command. Without a subcommand it serves the
+// saga API, as before there were subcommands.
+func newRootCommand(env cliEnv) *cobra.Command {
+	c := &cli{env: env}
+	root := &cobra.Command{
+		Use:   "saga-client",
+		Short: "Run, inspect and repair the sagas orchestrated across the services",
+		Long: "saga-client runs the sagas spanning the customer, application and servicing services.\n" +
+			"Without a subcommand it serves the saga API; see serve.",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         c.serve,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if c.output != outputTable && c.output != outputJSON {
+				return fmt.Errorf("invalid --output %q, want %s or %s", c.output, outputTable, outputJSON)
+			}
+			// Every command fails on a bad setting before it touches a saga
+			config, err := c.env.config(c.configFile)
+			if err != nil {
+				return fmt.Errorf("invalid configuration: %w", err)
+			}
+			c.config = config
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVarP(&c.output, "output", "o", outputTable, "output format, table or json")
+	root.PersistentFlags().StringVar(&c.configFile, "config", "", "dotenv file of settings, read after the environment (default $SAGA_CONFIG_FILE)")
+
+	state := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect persisted saga state",
+	}
+	state.AddCommand(&cobra.Command{
+		Use:   "show <sagaID>",
+		Short: "Show a saga's progress and what it has done so far",
+		Args:  cobra.ExactArgs(1),
+		RunE:  c.showState,
+	})
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "Serve the saga API until SIGINT or SIGTERM",
+			Args:  cobra.NoArgs,
+			RunE:  c.serve,
+		},
+		c.newRunCommand(),
+		&cobra.Command{
+			Use:   "resume <sagaID>",
+			Short: "Continue a persisted saga where it left off",
+			Args:  cobra.ExactArgs(1),
+			RunE:  c.resume,
+		},
+		&cobra.Command{
+			Use:   "compensate <sagaID>",
+			Short: "Roll back the steps a persisted saga executed",
+			Long: "compensate rolls back the steps a persisted saga executed, e.g. for one stuck\n" +
+				"RUNNING or FAILED. Compensations must be idempotent, as for resume.",
+			Args: cobra.ExactArgs(1),
+			RunE: c.compensate,
+		},
+		c.newListCommand(),
+		state,
+	)
+	return root
+}
+
+// newRunCommand returns the run command, which runs a saga to its end in the
+// foreground. Onboarding has flags for its input; every saga takes --input JSON.
+func (c *cli) newRunCommand() *cobra.Command {
+	var input, tenant string
+	run := &cobra.Command{
+		Use:   "run <saga>",
+		Short: "Run a saga to its end with --input as its JSON input",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if input == "" {
+				return errors.New("--input is required")
+			}
+			return c.run(cmd, args[0], json.RawMessage(input), c.tenant(cmd, tenant))
+		},
+	}
+	run.PersistentFlags().StringVar(&input, "input", "", "the saga's input as JSON")
+	run.PersistentFlags().StringVar(&tenant, "tenant", "", "the tenant the saga acts for (default SAGA_TENANT_ID)")
+
+	var request OnboardingRequest
+	var loanAmount, propertyValue string
+	onboarding := &cobra.Command{
+		Use:   CustomerOnboardingSagaName,
+		Short: "Onboard a customer applying for a mortgage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if input != "" {
+				if cmd.Flags().Changed("name") || cmd.Flags().Changed("email") {
+					return errors.New("--input cannot be combined with --name or --email")
+				}
+				return c.run(cmd, CustomerOnboardingSagaName, json.RawMessage(input), c.tenant(cmd, tenant))
+			}
+			var err error
+			if request.Application.LoanAmount, err = decimal.NewFromString(loanAmount); err != nil {
+				return fmt.Errorf("invalid --loan-amount: %w", err)
+			}
+			if request.Application.PropertyValue, err = decimal.NewFromString(propertyValue); err != nil {
+				return fmt.Errorf("invalid --property-value: %w", err)
+			}
+			body, err := json.Marshal(request)
+			if err != nil {
+				return err
+			}
+			return c.run(cmd, CustomerOnboardingSagaName, body, c.tenant(cmd, tenant))
+		},
+	}
+	flags := onboarding.Flags()
+	flags.StringVar(&request.Name, "name", "", "the customer's name")
+	flags.StringVar(&request.Email, "email", "", "the customer's email address")
+	flags.StringVar(&loanAmount, "loan-amount", "250000", "the loan applied for")
+	flags.StringVar(&propertyValue, "property-value", "400000", "the value of the mortgaged property")
+	flags.Float64Var(&request.Application.InterestRate, "interest-rate", 4.5, "the annual interest rate in percent")
+	flags.IntVar(&request.Application.TermYears, "term-years", 25, "the loan's term in years")
+	run.AddCommand(onboarding)
+	return run
+}
+
+// newListCommand returns the list command
+func (c *cli) newListCommand() *cobra.Command {
+	var status, name string
+	var limit int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List persisted sagas, most recently updated first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := saga.StateFilter{Status: saga.Status(strings.ToUpper(status)), Name: name, Limit: limit}
+			switch filter.Status {
+			case "", saga.StatusRunning, saga.StatusCompleted, saga.StatusCompensating, saga.StatusCompensated, saga.StatusFailed:
+			default:
+				return fmt.Errorf("invalid --status %q", status)
+			}
+			return c.list(cmd, filter)
+		},
+	}
+	list.Flags().StringVar(&status, "status", "", "only sagas with this status, e.g. FAILED")
+	list.Flags().StringVar(&name, "name", "", "only sagas of this kind, e.g. "+CustomerOnboardingSagaName)
+	list.Flags().IntVar(&limit, "limit", 50, "at most this many sagas, 0 for all")
+	return list
+}
+
+// serve serves the saga API until the command's context is done
+func (c *cli) serve(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	defer c.close()
+	if err := c.open(ctx, false); err != nil {
+		return err
+	}
+
+	// Expose pprof and runtime metrics on a separate debug port
+	if addr := c.config.DebugAddr; addr != "" {
+		go func() {
+			log.Printf("Debug endpoints listening on %s", addr)
+			if err := ListenAndServeDebug(addr); err != nil {
+				log.Printf("Debug server stopped: %v", err)
+			}
+		}()
+	}
+
+	// Expose /healthz and /readyz when running as a long-lived worker
+	if addr := c.config.HealthAddr; addr != "" {
+		health := NewHealthServer(append([]HealthCheck{StateStoreCheck(c.store)}, c.services...)...)
+		go func() {
+			log.Printf("Health endpoints listening on %s", addr)
+			if err := health.ListenAndServe(addr); err != nil {
+				log.Printf("Health server stopped: %v", err)
+			}
+		}()
+	}
+
+	if err := c.waitReady(ctx); err != nil {
+		return err
+	}
+
+	// A deployment serving several lenders runs sagas without an X-Tenant-ID header
+	// for SAGA_TENANT_ID
+	server := NewSagaServer(c.registry, c.store).
+		WithDefaultTenant(c.config.TenantID).
+		WithAPIKeys(c.config.ServerAPIKeys)
+	if len(c.config.ServerAPIKeys) == 0 {
+		log.Printf("SAGA_SERVER_API_KEYS is unset, so the saga API accepts unauthenticated requests")
+	}
+	log.Printf("Saga API listening on %s", c.config.HTTPAddr)
+	if err := server.ListenAndServe(ctx, c.config.HTTPAddr, c.config.ShutdownTimeout); err != nil {
+		return fmt.Errorf("server failed: %w", err)
+	}
+
+	// Give the sagas still running the same time to finish; the rest stay RUNNING in
+	// the state store for `saga-client resume`
+	waitCtx, cancel := context.WithTimeout(context.Background(), c.config.ShutdownTimeout)
+	defer cancel()
+	if err := server.Wait(waitCtx); err != nil {
+		log.Printf("Stopped with sagas still running: %v", err)
+	}
+	return nil
+}
+
+// run runs a saga of the named definition on input and prints how it ended
+func (c *cli) run(cmd *cobra.Command, name string, input json.RawMessage, tenant string) error {
+	ctx := cmd.Context()
+	defer c.close()
+	if err := c.open(ctx, true); err != nil {
+		return err
+	}
+	definition, ok := c.registry.Lookup(name)
+	if !ok {
+		return fmt.Errorf("unknown saga %q, want one of %s", name, strings.Join(c.registry.Names(), ", "))
+	}
+
+	id, done, err := definition.Start(saga.ContextWithTenant(ctx, tenant), input)
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return fmt.Errorf("invalid input: %s", formatDetails(validationErr))
+	}
+	if err != nil {
+		return err
+	}
+	return c.printAfter(cmd, id, <-done)
+}
+
+// resume continues the saga with the ID in args and prints how it ended
+func (c *cli) resume(cmd *cobra.Command, args []string) error {
+	id, err := parseSagaID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.requirePersistentStore(cmd); err != nil {
+		return err
+	}
+	defer c.close()
+	if err := c.open(cmd.Context(), true); err != nil {
+		return err
+	}
+	return c.printAfter(cmd, id, c.registry.Resume(cmd.Context(), c.store, id))
+}
+
+// compensate rolls back the saga with the ID in args and prints how it ended
+func (c *cli) compensate(cmd *cobra.Command, args []string) error {
+	id, err := parseSagaID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.requirePersistentStore(cmd); err != nil {
+		return err
+	}
+	defer c.close()
+	if err := c.open(cmd.Context(), true); err != nil {
+		return err
+	}
+	return c.printAfter(cmd, id, c.registry.Compensate(cmd.Context(), c.store, id))
+}
+
+// showState prints the status of the saga with the ID in args
+func (c *cli) showState(cmd *cobra.Command, args []string) error {
+	id, err := parseSagaID(args[0])
+	if err != nil {
+		return err
+	}
+	if err := c.requirePersistentStore(cmd); err != nil {
+		return err
+	}
+	defer c.close()
+	if err := c.open(cmd.Context(), false); err != nil {
+		return err
+	}
+	state, err := c.store.Load(cmd.Context(), id)
+	if errors.Is(err, saga.ErrStateNotFound) {
+		return fmt.Errorf("saga %s not found", id)
+	}
+	if err != nil {
+		return err
+	}
+	status, err := c.registry.statusOf(state)
+	if err != nil {
+		return err
+	}
+	return c.printStatus(cmd.OutOrStdout(), status)
+}
+
+// list prints the sagas matching filter
+func (c *cli) list(cmd *cobra.Command, filter saga.StateFilter) error {
+	if err := c.requirePersistentStore(cmd); err != nil {
+		return err
+	}
+	defer c.close()
+	if err := c.open(cmd.Context(), false); err != nil {
+		return err
+	}
+	lister, ok := c.store.(saga.StateLister)
+	if !ok {
+		return errors.New("the state store cannot list sagas")
+	}
+	states, err := lister.List(cmd.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	statuses := make([]SagaStatus, 0, len(states))
+	for i := range states {
+		status, err := c.registry.statusOf(&states[i])
+		if err != nil {
+			return err
+		}
+		statuses = append(statuses, status)
+	}
+	if c.output == outputJSON {
+		return writeJSON(cmd.OutOrStdout(), statuses)
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tSTEP\tUPDATED\tERROR")
+	for _, status := range statuses {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", status.ID, status.Name, status.Status, status.CurrentStep,
+			status.UpdatedAt.Format(time.RFC3339), status.Error)
+	}
+	return w.Flush()
+}
+
+// requirePersistentStore fails a command that works on sagas other processes ran
+// unless their state is kept in Postgres: a new in-memory store holds no sagas
+func (c *cli) requirePersistentStore(cmd *cobra.Command) error {
+	if c.config.stateStoreKind() != stateStorePostgres {
+		return fmt.Errorf("%s needs the sagas' persisted state: set SAGA_DATABASE_URL to the saga state database",
+			cmd.CommandPath())
+	}
+	return nil
+}
+
+// open opens the state store and registers the sagas; callsServices also waits for
+// the services to be ready, for a command that is going to call them. The caller
+// defers close, which releases what open got even when it fails halfway.
+func (c *cli) open(ctx context.Context, callsServices bool) error {
+	store, closeStore, err := c.env.stateStore(ctx, c.config)
+	if err != nil {
+		return fmt.Errorf("unable to set up saga state store: %w", err)
+	}
+	c.store = store
+	c.closers = append(c.closers, closeStore)
+
+	registry, services, closeClients, err := c.env.sagas(c.config, store)
+	if err != nil {
+		return err
+	}
+	c.registry, c.services = registry, services
+	c.closers = append(c.closers, closeClients)
+	if !callsServices {
+		return nil
+	}
+	return c.waitReady(ctx)
+}
+
+// close releases the state store and clients open got, in reverse order
+func (c *cli) close() {
+	for i := len(c.closers) - 1; i >= 0; i-- {
+		c.closers[i]()
+	}
+	c.closers = nil
+}
+
+// waitReady waits for the services' /readyz so a saga doesn't run before their
+// tables exist
+func (c *cli) waitReady(ctx context.Context) error {
+	readyCtx, cancel := context.WithTimeout(ctx, c.config.ReadyTimeout)
+	defer cancel()
+	if err := WaitReady(readyCtx, time.Second, c.services...); err != nil {
+		return fmt.Errorf("services are not ready: %w", err)
+	}
+	return nil
+}
+
+// tenant is the --tenant a run command was given, or the configured tenant
+func (c *cli) tenant(cmd *cobra.Command, flag string) string {
+	if cmd.Flags().Changed("tenant") {
+		return flag
+	}
+	return c.config.TenantID
+}
+
+// printAfter prints the status of the saga with id after a command ran it, and
+// returns the saga's error so the command fails when the saga did
+func (c *cli) printAfter(cmd *cobra.Command, id uuid.UUID, sagaErr error) error {
+	state, err := c.store.Load(cmd.Context(), id)
+	if err != nil {
+		return errors.Join(sagaErr, err)
+	}
+	status, err := c.registry.statusOf(state)
+	if err != nil {
+		return errors.Join(sagaErr, err)
+	}
+	if err := c.printStatus(cmd.OutOrStdout(), status); err != nil {
+		return errors.Join(sagaErr, err)
+	}
+	return sagaErr
+}
+
+// printStatus prints one saga's status in the chosen output format
+func (c *cli) printStatus(out io.Writer, status SagaStatus) error {
+	if c.output == outputJSON {
+		return writeJSON(out, status)
+	}
+	result := "-"
+	if status.Result != nil {
+		encoded, err := json.Marshal(status.Result)
+		if err != nil {
+			return err
+		}
+		result = string(encoded)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\t%s\n", status.ID)
+	fmt.Fprintf(w, "Name\t%s\n", status.Name)
+	fmt.Fprintf(w, "Status\t%s\n", status.Status)
+	fmt.Fprintf(w, "Current step\t%d\n", status.CurrentStep)
+	if status.Error != "" {
+		fmt.Fprintf(w, "Error\t%s\n", status.Error)
+	}
+	fmt.Fprintf(w, "Result\t%s\n", result)
+	fmt.Fprintf(w, "Created\t%s\n", status.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Updated\t%s\n", status.UpdatedAt.Format(time.RFC3339))
+	return w.Flush()
+}
+
+// writeJSON writes value as indented JSON
+func writeJSON(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// parseSagaID parses a saga ID argument
+func parseSagaID(arg string) (uuid.UUID, error) {
+	id, err := uuid.Parse(arg)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid saga ID %q: %w", arg, err)
+	}
+	return id, nil
+}
+
+// formatDetails lists a ValidationError's fields in order, e.g. "email must be an
+// email address; name is required"
+func formatDetails(details ValidationError) string {
+	fields := make([]string, 0, len(details))
+	for field, reason := range details {
+		fields = append(fields, field+" "+reason)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "; ")
+}
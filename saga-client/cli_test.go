@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"go.uber.org/mock/gomock"
+	customers "service1/api/pkg/client"
+)
+
+// executeCLI runs saga-client with args on store and registry and returns what it
+// printed. The store stands in for Postgres, as the repair commands require.
+func executeCLI(store saga.StateStore, registry *SagaRegistry, args ...string) (string, error) {
+	return execute(cliEnv{
+		config: persistedConfig,
+		stateStore: func(context.Context, Config) (saga.StateStore, func(), error) {
+			return store, func() {}, nil
+		},
+		sagas: func(Config, saga.StateStore) (*SagaRegistry, []HealthCheck, func(), error) {
+			return registry, nil, func() {}, nil
+		},
+	}, args...)
+}
+
+// persistedConfig loads the configuration as if it kept the sagas' state in Postgres
+func persistedConfig(file string) (Config, error) {
+	config, err := LoadConfig(file)
+	config.StateStore, config.DatabaseURL = stateStorePostgres, "postgres://localhost:5432/sagas"
+	return config, err
+}
+
+// execute runs saga-client on env with args and returns what it printed
+func execute(env cliEnv, args ...string) (string, error) {
+	root := newRootCommand(env)
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestCLI_RunCustomerOnboardingFromFlags(t *testing.T) {
+	onboarding, m := newSagaMocks(t)
+	store := saga.NewInMemoryStateStore()
+	registry := NewSagaRegistry().Register(CustomerOnboardingSagaName, onboarding.WithStateStore(store))
+	m.customers.EXPECT().Create(gomock.Any(), "Jane", "jane@example.com").
+		Return(customers.Customer{}, errors.New("customers unavailable"))
+
+	out, err := executeCLI(store, registry, "run", CustomerOnboardingSagaName, "--name", "Jane", "--email", "jane@example.com")
+	if err == nil || !strings.Contains(err.Error(), "customers unavailable") {
+		t.Errorf("Expected the command to fail with the saga, got %v", err)
+	}
+	if !strings.Contains(out, string(saga.StatusCompensated)) {
+		t.Errorf("Expected the compensated saga to be printed, got:\n%s", out)
+	}
+
+	if _, err := executeCLI(store, registry, "run", CustomerOnboardingSagaName, "--email", "jane"); err == nil ||
+		!strings.Contains(err.Error(), "name is required") {
+		t.Errorf("Expected the saga's validation to reject the flags, got %v", err)
+	}
+}
+
+func TestCLI_ListFiltersByStatus(t *testing.T) {
+	store := saga.NewInMemoryStateStore()
+	failed := uuid.New()
+	now := time.Now()
+	for _, state := range []saga.State{
+		{ID: failed, Name: PaymentSagaName, Status: saga.StatusFailed, Error: "reversal failed", UpdatedAt: now},
+		{ID: uuid.New(), Name: PaymentSagaName, Status: saga.StatusCompleted, UpdatedAt: now},
+	} {
+		if err := store.Save(context.Background(), &state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out, err := executeCLI(store, NewSagaRegistry(), "list", "--status", "failed", "-o", "json")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var listed []SagaStatus
+	if err := json.Unmarshal([]byte(out), &listed); err != nil {
+		t.Fatalf("Invalid JSON output %q: %v", out, err)
+	}
+	if len(listed) != 1 || listed[0].ID != failed || listed[0].Error != "reversal failed" {
+		t.Errorf("Expected only the failed saga, got %+v", listed)
+	}
+
+	out, err = executeCLI(store, NewSagaRegistry(), "list")
+	if err != nil || strings.Count(out, "\n") != 3 || !strings.HasPrefix(out, "ID") {
+		t.Errorf("Expected a header and a row per saga, got %v:\n%s", err, out)
+	}
+}
+
+func TestCLI_StateShowIncludesResult(t *testing.T) {
+	store := saga.NewInMemoryStateStore()
+	id, loanId := uuid.New(), uuid.New()
+	data, _ := json.Marshal(PaymentSagaData{LoanID: loanId})
+	if err := store.Save(context.Background(), &saga.State{ID: id, Name: PaymentSagaName, Status: saga.StatusRunning, Data: data}); err != nil {
+		t.Fatal(err)
+	}
+	registry := NewSagaRegistry().Register(PaymentSagaName, NewPaymentSaga(nil, nil))
+
+	out, err := executeCLI(store, registry, "state", "show", id.String())
+	if err != nil {
+		t.Fatalf("state show failed: %v", err)
+	}
+	for _, want := range []string{id.String(), PaymentSagaName, string(saga.StatusRunning), loanId.String()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in the output, got:\n%s", want, out)
+		}
+	}
+	if _, err := executeCLI(store, registry, "state", "show", uuid.NewString()); err == nil {
+		t.Error("Expected an unknown saga to fail")
+	}
+}
+
+func TestCLI_CompensateDispatchesByName(t *testing.T) {
+	store := saga.NewInMemoryStateStore()
+	id := uuid.New()
+	if err := store.Save(context.Background(), &saga.State{ID: id, Name: OffboardingSagaName, Status: saga.StatusFailed}); err != nil {
+		t.Fatal(err)
+	}
+	offboarding := &recordingDefinition{}
+
+	if _, err := executeCLI(store, NewSagaRegistry().Register(OffboardingSagaName, offboarding), "compensate", id.String()); err != nil {
+		t.Fatalf("compensate failed: %v", err)
+	}
+	if !reflect.DeepEqual(offboarding.compensated, []uuid.UUID{id}) {
+		t.Errorf("Expected the offboarding to be compensated, got %v", offboarding.compensated)
+	}
+}
+
+func TestCLI_RepairCommandsNeedPersistedState(t *testing.T) {
+	opened := false
+	env := cliEnv{
+		config: LoadConfig,
+		stateStore: func(context.Context, Config) (saga.StateStore, func(), error) {
+			opened = true
+			return saga.NewInMemoryStateStore(), func() {}, nil
+		},
+		sagas: func(Config, saga.StateStore) (*SagaRegistry, []HealthCheck, func(), error) {
+			return NewSagaRegistry(), nil, func() {}, nil
+		},
+	}
+	for _, args := range [][]string{{"resume", uuid.NewString()}, {"compensate", uuid.NewString()}, {"list"},
+		{"state", "show", uuid.NewString()}} {
+		if _, err := execute(env, args...); err == nil || !strings.Contains(err.Error(), "SAGA_DATABASE_URL") {
+			t.Errorf("Expected %s to require a persisted state store, got %v", args[0], err)
+		}
+	}
+	if opened {
+		t.Error("Expected no state store to be opened")
+	}
+}
+
+func TestCLI_ClosesOnFailure(t *testing.T) {
+	var closed []string
+	_, err := executeCLIWithClosers(&closed, "state", "show", uuid.NewString())
+	if err == nil {
+		t.Fatal("Expected showing a missing saga to fail")
+	}
+	if !reflect.DeepEqual(closed, []string{"clients", "store"}) {
+		t.Errorf("Expected the clients and then the store to be closed, got %v", closed)
+	}
+}
+
+// executeCLIWithClosers runs saga-client on an empty store, recording in closed what
+// the command released
+func executeCLIWithClosers(closed *[]string, args ...string) (string, error) {
+	return execute(cliEnv{
+		config: persistedConfig,
+		stateStore: func(context.Context, Config) (saga.StateStore, func(), error) {
+			return saga.NewInMemoryStateStore(), func() { *closed = append(*closed, "store") }, nil
+		},
+		sagas: func(Config, saga.StateStore) (*SagaRegistry, []HealthCheck, func(), error) {
+			return NewSagaRegistry(), nil, func() { *closed = append(*closed, "clients") }, nil
+		},
+	}, args...)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// requestFields are the fields of the clients' RequestMetrics
+type requestFields = struct {
+	Method   string
+	Endpoint string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// ClientMetrics publishes a service client's calls under client_requests in
+// /debug/vars, keyed by service, method and endpoint, e.g. "servicing POST
+// /v1/loans": how many were made, how many failed without a response or with a
+// 5xx, and their total latency, from which the mean follows. Comparing the services
+// shows which one a slow saga waits on. T is the client's RequestMetrics.
+type ClientMetrics[T ~requestFields] struct {
+	service string
+}
+
+func NewClientMetrics[T ~requestFields](service string) ClientMetrics[T] {
+	return ClientMetrics[T]{service: service}
+}
+
+// clientRequestsMu serializes creating an endpoint's entry in clientRequests
+var clientRequestsMu sync.Mutex
+
+func (m ClientMetrics[T]) RecordRequest(ctx context.Context, request T) {
+	fields := requestFields(request)
+	counters := endpointCounters(m.service + " " + fields.Method + " " + fields.Endpoint)
+	counters.Add("requests", 1)
+	if fields.Err != nil || fields.Status >= http.StatusInternalServerError {
+		counters.Add("failures", 1)
+	}
+	counters.AddFloat("latency_seconds_total", fields.Duration.Seconds())
+}
+
+// endpointCounters returns the counters of key, creating them on first use
+func endpointCounters(key string) *expvar.Map {
+	if counters, ok := clientRequests.Get(key).(*expvar.Map); ok {
+		return counters
+	}
+	clientRequestsMu.Lock()
+	defer clientRequestsMu.Unlock()
+	if counters, ok := clientRequests.Get(key).(*expvar.Map); ok {
+		return counters
+	}
+	counters := new(expvar.Map).Init()
+	clientRequests.Set(key, counters)
+	return counters
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net/http"
+	"testing"
+	"time"
+
+	servicing "service3/api/pkg/client"
+)
+
+func TestClientMetrics_CountsByEndpoint(t *testing.T) {
+	metrics := NewClientMetrics[servicing.RequestMetrics]("servicing-test")
+	ctx := context.Background()
+	metrics.RecordRequest(ctx, servicing.RequestMetrics{Method: http.MethodPost, Endpoint: "/v1/loans", Status: http.StatusCreated, Duration: time.Second})
+	metrics.RecordRequest(ctx, servicing.RequestMetrics{Method: http.MethodPost, Endpoint: "/v1/loans", Status: http.StatusBadGateway, Duration: time.Second})
+	metrics.RecordRequest(ctx, servicing.RequestMetrics{Method: http.MethodPost, Endpoint: "/v1/loans", Err: errors.New("connection reset"), Duration: time.Second})
+
+	counters, ok := clientRequests.Get("servicing-test POST /v1/loans").(*expvar.Map)
+	if !ok {
+		t.Fatal("Expected counters for the endpoint")
+	}
+	if got := counters.Get("requests").(*expvar.Int).Value(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+	if got := counters.Get("failures").(*expvar.Int).Value(); got != 2 {
+		t.Errorf("Expected 2 failures, got %d", got)
+	}
+	if got := counters.Get("latency_seconds_total").(*expvar.Float).Value(); got != 3 {
+		t.Errorf("Expected 3s of latency, got %v", got)
+	}
+}
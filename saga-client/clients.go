@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	customers "service1/api/pkg/client"
+	applictions "service2/api/pkg/client"
+	servicing "service3/api/pkg/client"
+)
+
+//go:generate mockgen -source=clients.go -destination=mocks/clients.go -package=mocks
+
+// CustomerAPI is what the saga needs from the customer service; *customers.Client
+// and *customers.GRPCClient implement it
+type CustomerAPI interface {
+	Create(ctx context.Context, name, email string) (customers.Customer, error)
+	Read(ctx context.Context, id uuid.UUID) (customers.Customer, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ApplicationAPI is what the saga needs from the mortgage application service;
+// *applictions.Client and *applictions.GRPCClient implement it
+type ApplicationAPI interface {
+	CreateIdempotent(ctx context.Context, idempotencyKey string, customerId uuid.UUID, loanAmount,
+		propertyValue decimal.Decimal, interestRate float64, termYears int) (applictions.MortgageApplication, error)
+	Cancel(ctx context.Context, id uuid.UUID, decision applictions.Decision) (applictions.MortgageApplication, error)
+}
+
+// ServicingAPI is what the saga needs from the loan servicing service's loans;
+// the *servicing.Loans and *servicing.GRPCLoans their clients' Loans return
+// implement it
+type ServicingAPI interface {
+	Create(ctx context.Context, customerId, mortgageId uuid.UUID, loanAmount decimal.Decimal, interestRate float64,
+		termYears int, monthlyPayment, outstandingBalance decimal.Decimal, startDate, maturityDate time.Time) (servicing.Loan, error)
+	Get(ctx context.Context, id uuid.UUID) (servicing.Loan, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancellation servicing.Cancellation) (servicing.Loan, error)
+}
+
+// PaymentAPI is what the payment saga needs from the loan servicing service's
+// payments; *servicing.Payments implements it
+type PaymentAPI interface {
+	Create(ctx context.Context, loanId, customerId uuid.UUID, paymentAmount, principalAmount, interestAmount,
+		escrowAmount decimal.Decimal, paymentDate time.Time, paymentType string) (servicing.Payment, error)
+	Reverse(ctx context.Context, id uuid.UUID) (servicing.Payment, error)
+}
+
+// OffboardingCustomerAPI is what the offboarding saga needs from the customer
+// service; *customers.Client implements it
+type OffboardingCustomerAPI interface {
+	Anonymize(ctx context.Context, id uuid.UUID, request customers.AnonymizationRequest) (customers.Customer, error)
+}
+
+// OffboardingApplicationAPI is what the offboarding saga needs from the mortgage
+// application service; *applictions.Client implements it
+type OffboardingApplicationAPI interface {
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]applictions.MortgageApplication, error)
+	Withdraw(ctx context.Context, id uuid.UUID, decision applictions.Decision) (applictions.MortgageApplication, error)
+}
+
+// OffboardingServicingAPI is what the offboarding saga needs from the loan
+// servicing service's loans; *servicing.Loans implements it
+type OffboardingServicingAPI interface {
+	ListByCustomer(ctx context.Context, customerId uuid.UUID, filter servicing.LoanFilter) ([]servicing.Loan, error)
+}
+
+var (
+	_ CustomerAPI    = (*customers.Client)(nil)
+	_ ApplicationAPI = (*applictions.Client)(nil)
+	_ ServicingAPI   = (*servicing.Loans)(nil)
+	_ PaymentAPI     = (*servicing.Payments)(nil)
+
+	_ OffboardingCustomerAPI    = (*customers.Client)(nil)
+	_ OffboardingApplicationAPI = (*applictions.Client)(nil)
+	_ OffboardingServicingAPI   = (*servicing.Loans)(nil)
+
+	_ CustomerAPI    = (*customers.GRPCClient)(nil)
+	_ ApplicationAPI = (*applictions.GRPCClient)(nil)
+	_ ServicingAPI   = (*servicing.GRPCLoans)(nil)
+)
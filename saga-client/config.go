@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	customers "service1/api/pkg/client"
+)
+
+// Config is the saga client's settings, loaded into one typed struct at startup like
+// the services' config. Values come from the environment, then from the dotenv file
+// named by --config or SAGA_CONFIG_FILE, then from a .env file in the working
+// directory; variables set earlier win. Invalid values stop the client with an error
+// naming the variable instead of being replaced by a default.
+type Config struct {
+	// The services' REST APIs
+	CustomersURL    string `env:"SAGA_CUSTOMERS_URL" envDefault:"http://localhost:8081"`
+	ApplicationsURL string `env:"SAGA_APPLICATIONS_URL" envDefault:"http://localhost:8082"`
+	ServicingURL    string `env:"SAGA_SERVICING_URL" envDefault:"http://localhost:8083"`
+	// Transport is http or grpc; with grpc the onboarding saga calls the services'
+	// gRPC ports
+	Transport            string `env:"SAGA_TRANSPORT" envDefault:"http"`
+	CustomersGRPCAddr    string `env:"SAGA_CUSTOMERS_GRPC_ADDR" envDefault:"localhost:9081"`
+	ApplicationsGRPCAddr string `env:"SAGA_APPLICATIONS_GRPC_ADDR" envDefault:"localhost:9082"`
+	ServicingGRPCAddr    string `env:"SAGA_SERVICING_GRPC_ADDR" envDefault:"localhost:9083"`
+
+	// CallTimeout bounds each client call, retries included; RetryAttempts is how
+	// often a retryable call is sent, 1 turning retries off. Both default to the
+	// clients' defaults.
+	CallTimeout   time.Duration `env:"SAGA_CALL_TIMEOUT"`
+	RetryAttempts int           `env:"SAGA_RETRY_ATTEMPTS"`
+	// CacheEntries is how many read responses each client keeps to revalidate
+	CacheEntries int `env:"SAGA_CACHE_ENTRIES"`
+	// MaxIdleConnsPerHost, DialTimeout and TLSHandshakeTimeout tune the clients'
+	// connections; 0 keeps the clients' defaults
+	MaxIdleConnsPerHost int           `env:"SAGA_MAX_IDLE_CONNS_PER_HOST"`
+	DialTimeout         time.Duration `env:"SAGA_DIAL_TIMEOUT"`
+	TLSHandshakeTimeout time.Duration `env:"SAGA_TLS_HANDSHAKE_TIMEOUT"`
+	// ReadyTimeout is how long to wait for the services' /readyz before running sagas
+	ReadyTimeout time.Duration `env:"SAGA_READY_TIMEOUT" envDefault:"1m"`
+
+	TLSCAFile   string `env:"SAGA_TLS_CA_FILE"`
+	TLSCertFile string `env:"SAGA_TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"SAGA_TLS_KEY_FILE"`
+	// At most one of the credentials is sent to the services
+	APIKey          string `env:"SAGA_API_KEY"`
+	BearerToken     string `env:"SAGA_BEARER_TOKEN"`
+	BearerTokenFile string `env:"SAGA_BEARER_TOKEN_FILE"`
+
+	// StateStore is memory or postgres; unset, it is postgres when DatabaseURL is set
+	StateStore  string `env:"SAGA_STATE_STORE"`
+	DatabaseURL string `env:"SAGA_DATABASE_URL"`
+
+	HTTPAddr string `env:"SAGA_HTTP_ADDR" envDefault:":8080"`
+	// ServerAPIKeys are the keys the saga API accepts, in the services' API_KEYS format;
+	// unset, the API is open to anyone who can reach it
+	ServerAPIKeys map[string]APIPrincipal `env:"SAGA_SERVER_API_KEYS"`
+	HealthAddr    string                  `env:"SAGA_HEALTH_ADDR"`
+	DebugAddr     string                  `env:"SAGA_DEBUG_ADDR"`
+	// ShutdownTimeout is how long requests and running sagas get to finish on SIGINT
+	// or SIGTERM
+	ShutdownTimeout time.Duration `env:"SAGA_SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	// TenantID is the tenant sagas act for when their request names none
+	TenantID   string `env:"SAGA_TENANT_ID"`
+	RequireKYC bool   `env:"SAGA_REQUIRE_KYC"`
+
+	SlackWebhookURL string `env:"SLACK_WEBHOOK_URL"`
+	SlackChannel    string `env:"SLACK_CHANNEL"`
+	AlertWebhookURL string `env:"ALERT_WEBHOOK_URL"`
+}
+
+// State stores of SAGA_STATE_STORE
+const (
+	stateStoreMemory   = "memory"
+	stateStorePostgres = "postgres"
+)
+
+// parsers reads the variables whose types env cannot parse by itself
+var parsers = map[reflect.Type]env.ParserFunc{
+	reflect.TypeOf(map[string]APIPrincipal{}): func(value string) (any, error) {
+		return ParseAPIKeys(value)
+	},
+}
+
+// LoadConfig reads and validates the configuration; file names a dotenv file to
+// read after the environment, SAGA_CONFIG_FILE when empty
+func LoadConfig(file string) (Config, error) {
+	if file == "" {
+		file = os.Getenv("SAGA_CONFIG_FILE")
+	}
+	if file != "" {
+		if err := godotenv.Load(file); err != nil {
+			return Config{}, fmt.Errorf("config file: %w", err)
+		}
+	}
+	if _, err := os.Stat(".env"); err == nil {
+		if err := godotenv.Load(); err != nil {
+			return Config{}, fmt.Errorf(".env: %w", err)
+		}
+	}
+
+	// Unset, the client settings keep the clients' own defaults
+	config := Config{
+		CallTimeout:   customers.DefaultTimeout,
+		RetryAttempts: customers.DefaultRetry.Attempts,
+	}
+	if err := env.ParseWithOptions(&config, env.Options{FuncMap: parsers}); err != nil {
+		return Config{}, byVariable(err)
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// byVariable rewrites env's parse errors, which name struct fields, to name the
+// variables the operator set
+func byVariable(err error) error {
+	var aggregate env.AggregateError
+	if !errors.As(err, &aggregate) {
+		return err
+	}
+	errs := make([]error, 0, len(aggregate.Errors))
+	for _, err := range aggregate.Errors {
+		var parseErr env.ParseError
+		if errors.As(err, &parseErr) {
+			if field, ok := reflect.TypeFor[Config]().FieldByName(parseErr.Name); ok {
+				name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+				err = fmt.Errorf("%s: %w", name, parseErr.Err)
+			}
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every setting that is out of range, not just the first
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	for _, setting := range []setting{
+		{"SAGA_CUSTOMERS_URL", c.CustomersURL},
+		{"SAGA_APPLICATIONS_URL", c.ApplicationsURL},
+		{"SAGA_SERVICING_URL", c.ServicingURL},
+	} {
+		if !isHTTPURL(setting.value) {
+			invalid("%s must be an http or https URL, got %q", setting.name, setting.value)
+		}
+	}
+	switch c.Transport {
+	case "http":
+	case "grpc":
+		for _, setting := range []setting{
+			{"SAGA_CUSTOMERS_GRPC_ADDR", c.CustomersGRPCAddr},
+			{"SAGA_APPLICATIONS_GRPC_ADDR", c.ApplicationsGRPCAddr},
+			{"SAGA_SERVICING_GRPC_ADDR", c.ServicingGRPCAddr},
+		} {
+			if _, _, err := net.SplitHostPort(setting.value); err != nil {
+				invalid("%s must be a host and port, got %q", setting.name, setting.value)
+			}
+		}
+	default:
+		invalid("SAGA_TRANSPORT must be http or grpc, got %q", c.Transport)
+	}
+
+	if c.CallTimeout <= 0 {
+		invalid("SAGA_CALL_TIMEOUT must be positive, got %s", c.CallTimeout)
+	}
+	if c.RetryAttempts < 1 {
+		invalid("SAGA_RETRY_ATTEMPTS must be at least 1, got %d", c.RetryAttempts)
+	}
+	if c.CacheEntries < 0 {
+		invalid("SAGA_CACHE_ENTRIES must not be negative, got %d", c.CacheEntries)
+	}
+	if c.MaxIdleConnsPerHost < 0 {
+		invalid("SAGA_MAX_IDLE_CONNS_PER_HOST must not be negative, got %d", c.MaxIdleConnsPerHost)
+	}
+	if c.DialTimeout < 0 {
+		invalid("SAGA_DIAL_TIMEOUT must not be negative, got %s", c.DialTimeout)
+	}
+	if c.TLSHandshakeTimeout < 0 {
+		invalid("SAGA_TLS_HANDSHAKE_TIMEOUT must not be negative, got %s", c.TLSHandshakeTimeout)
+	}
+	if c.ReadyTimeout <= 0 {
+		invalid("SAGA_READY_TIMEOUT must be positive, got %s", c.ReadyTimeout)
+	}
+	if c.ShutdownTimeout <= 0 {
+		invalid("SAGA_SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout)
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		invalid("SAGA_TLS_CERT_FILE and SAGA_TLS_KEY_FILE must be set together")
+	}
+	credentials := 0
+	for _, value := range []string{c.APIKey, c.BearerToken, c.BearerTokenFile} {
+		if value != "" {
+			credentials++
+		}
+	}
+	if credentials > 1 {
+		invalid("set at most one of SAGA_API_KEY, SAGA_BEARER_TOKEN and SAGA_BEARER_TOKEN_FILE")
+	}
+
+	switch c.StateStore {
+	case "", stateStoreMemory:
+	case stateStorePostgres:
+		if c.DatabaseURL == "" {
+			invalid("SAGA_DATABASE_URL is required with SAGA_STATE_STORE=postgres")
+		}
+	default:
+		invalid("SAGA_STATE_STORE must be memory or postgres, got %q", c.StateStore)
+	}
+	if c.DatabaseURL != "" {
+		if _, err := pgxpool.ParseConfig(c.DatabaseURL); err != nil {
+			invalid("SAGA_DATABASE_URL: %w", err)
+		}
+	}
+
+	if c.HTTPAddr == "" {
+		invalid("SAGA_HTTP_ADDR must not be empty")
+	}
+	for _, setting := range []setting{
+		{"SLACK_WEBHOOK_URL", c.SlackWebhookURL},
+		{"ALERT_WEBHOOK_URL", c.AlertWebhookURL},
+	} {
+		if setting.value != "" && !isHTTPURL(setting.value) {
+			invalid("%s must be an http or https URL", setting.name)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setting is a variable and its value, listed in a fixed order so Validate reports
+// errors in the same order every time
+type setting struct {
+	name  string
+	value string
+}
+
+// isHTTPURL reports whether value is an absolute http or https URL
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// stateStoreKind is the state store to persist sagas in
+func (c Config) stateStoreKind() string {
+	if c.StateStore == "" && c.DatabaseURL != "" {
+		return stateStorePostgres
+	}
+	if c.StateStore == "" {
+		return stateStoreMemory
+	}
+	return c.StateStore
+}
+
+// Connections returns the clients' connection settings
+func (c Config) Connections() customers.Connections {
+	return customers.Connections{
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		DialTimeout:         c.DialTimeout,
+		TLSHandshakeTimeout: c.TLSHandshakeTimeout,
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	customers "service1/api/pkg/client"
+)
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	config, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.CustomersURL != "http://localhost:8081" || config.Transport != "http" || config.HTTPAddr != ":8080" {
+		t.Errorf("Expected the local defaults, got %+v", config)
+	}
+	if config.CallTimeout != customers.DefaultTimeout || config.RetryAttempts != customers.DefaultRetry.Attempts {
+		t.Errorf("Expected the clients' defaults, got %s and %d attempts", config.CallTimeout, config.RetryAttempts)
+	}
+	if config.stateStoreKind() != stateStoreMemory {
+		t.Errorf("Expected the in-memory state store without a database, got %s", config.stateStoreKind())
+	}
+}
+
+func TestLoadConfig_ReportsInvalidSettings(t *testing.T) {
+	cases := map[string]map[string]string{
+		"SAGA_CUSTOMERS_URL":       {"SAGA_CUSTOMERS_URL": "customers:8081"},
+		"SAGA_TRANSPORT":           {"SAGA_TRANSPORT": "amqp"},
+		"SAGA_SERVICING_GRPC_ADDR": {"SAGA_TRANSPORT": "grpc", "SAGA_SERVICING_GRPC_ADDR": "servicing"},
+		"SAGA_CALL_TIMEOUT":        {"SAGA_CALL_TIMEOUT": "soon"},
+		"SAGA_RETRY_ATTEMPTS":      {"SAGA_RETRY_ATTEMPTS": "0"},
+		"SAGA_TLS_KEY_FILE":        {"SAGA_TLS_CERT_FILE": "client.pem"},
+		"SAGA_BEARER_TOKEN":        {"SAGA_API_KEY": "s3cret", "SAGA_BEARER_TOKEN": "token"},
+		"SAGA_DATABASE_URL":        {"SAGA_STATE_STORE": "postgres"},
+		"SAGA_STATE_STORE":         {"SAGA_STATE_STORE": "redis"},
+		"SAGA_SERVER_API_KEYS":     {"SAGA_SERVER_API_KEYS": "dashboard:r3port:owner"},
+	}
+	for name, vars := range cases {
+		t.Run(name, func(t *testing.T) {
+			for key, value := range vars {
+				t.Setenv(key, value)
+			}
+			_, err := LoadConfig("")
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected an error naming %s, got %v", name, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateReportsInOrder(t *testing.T) {
+	config, err := LoadConfig("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.CustomersURL, config.ApplicationsURL, config.ServicingURL = "a", "b", "c"
+	config.Transport = "grpc"
+	config.CustomersGRPCAddr, config.ApplicationsGRPCAddr, config.ServicingGRPCAddr = "d", "e", "f"
+	config.SlackWebhookURL, config.AlertWebhookURL = "g", "h"
+
+	want := []string{"SAGA_CUSTOMERS_URL", "SAGA_APPLICATIONS_URL", "SAGA_SERVICING_URL",
+		"SAGA_CUSTOMERS_GRPC_ADDR", "SAGA_APPLICATIONS_GRPC_ADDR", "SAGA_SERVICING_GRPC_ADDR",
+		"SLACK_WEBHOOK_URL", "ALERT_WEBHOOK_URL"}
+	for range 10 {
+		lines := strings.Split(config.Validate().Error(), "\n")
+		if len(lines) != len(want) {
+			t.Fatalf("Expected %d errors, got %q", len(want), lines)
+		}
+		for i, name := range want {
+			if !strings.HasPrefix(lines[i], name) {
+				t.Fatalf("Expected error %d to name %s, got %q", i, name, lines[i])
+			}
+		}
+	}
+}
+
+func TestLoadConfig_ConfigFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "saga-client.env")
+	settings := "SAGA_CUSTOMERS_URL=http://customers:8081\nSAGA_RETRY_ATTEMPTS=2\nSAGA_STATE_STORE=memory\n"
+	if err := os.WriteFile(file, []byte(settings), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SAGA_RETRY_ATTEMPTS", "3")
+	t.Cleanup(func() {
+		os.Unsetenv("SAGA_CUSTOMERS_URL")
+		os.Unsetenv("SAGA_STATE_STORE")
+	})
+
+	config, err := LoadConfig(file)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if config.CustomersURL != "http://customers:8081" {
+		t.Errorf("Expected SAGA_CUSTOMERS_URL from the file, got %q", config.CustomersURL)
+	}
+	if config.RetryAttempts != 3 {
+		t.Errorf("Expected the environment to win over the file, got %d", config.RetryAttempts)
+	}
+	if config.ReadyTimeout != time.Minute {
+		t.Errorf("Expected the default ready timeout, got %s", config.ReadyTimeout)
+	}
+}
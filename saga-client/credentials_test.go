@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileTokenSource_RereadsRotatedToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source := NewFileTokenSource(path)
+	if token, err := source.Token(context.Background()); err != nil || token != "first" {
+		t.Fatalf("Expected first, got %q, %v", token, err)
+	}
+
+	if err := os.WriteFile(path, []byte("rotated"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := source.Token(context.Background()); err != nil || token != "rotated" {
+		t.Errorf("Expected the rotated token, got %q, %v", token, err)
+	}
+}
+
+func TestFileTokenSource_EmptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileTokenSource(path).Token(context.Background()); err == nil {
+		t.Error("Expected an empty token file to be an error")
+	}
+}
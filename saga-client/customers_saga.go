@@ -2,10 +2,16 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/shopspring/decimal"
 	customers "service1/api/pkg/client"
 	applictions "service2/api/pkg/client"
 	servicing "service3/api/pkg/client"
@@ -15,8 +21,13 @@ import (
 // Steps can read from and write to this struct to pass data between steps
 type CustomerSagaData struct {
 	// Input fields
-	Name  string
-	Email string
+	Name  string `saga:"sensitive"`
+	Email string `saga:"sensitive"`
+
+	// ApplicationRequestKey is sent as the Idempotency-Key when creating the application.
+	// It is generated before the saga starts and persisted with its state, so a retried
+	// or resumed CreateApplication step cannot create a second application.
+	ApplicationRequestKey string
 
 	// Populated by steps during execution
 	CustomerID    *uuid.UUID // Set by CreateCustomer step
@@ -27,20 +38,25 @@ type CustomerSagaData struct {
 }
 
 type ApplicationSagaData struct {
-	LoanAmount     float64
-	PropertyAmount float64
+	LoanAmount     decimal.Decimal
+	PropertyAmount decimal.Decimal
 	InterestRate   float64
 	TermYears      int
 }
 
 type CustomersSaga struct {
-	customersClient    *customers.Client
-	applicationsClient *applictions.Client
-	servicingClient    *servicing.Client
+	customersClient    CustomerAPI
+	applicationsClient ApplicationAPI
+	servicingClient    ServicingAPI
+	alerter            saga.Alerter
+	stateStore         saga.StateStore
+	requireKYC         bool
 }
 
-func NewCustomersSaga(customers *customers.Client,
-	applications *applictions.Client, servicing *servicing.Client) *CustomersSaga {
+// CustomerOnboardingSagaName identifies the customer onboarding saga in persisted state
+const CustomerOnboardingSagaName = "customer-onboarding"
+
+func NewCustomersSaga(customers CustomerAPI, applications ApplicationAPI, servicing ServicingAPI) *CustomersSaga {
 	return &CustomersSaga{
 		customersClient:    customers,
 		applicationsClient: applications,
@@ -48,29 +64,137 @@ func NewCustomersSaga(customers *customers.Client,
 	}
 }
 
-func (s *CustomersSaga) CreateCustomer(ctx context.Context, name, email string) error {
-	// Initialize the saga data context
-	data := &CustomerSagaData{
-		Name:  name,
-		Email: email,
-		Application: ApplicationSagaData{
-			LoanAmount:     1,
-			PropertyAmount: 1,
-			InterestRate:   1,
-			TermYears:      1,
-		},
+// WithAlerter sets the alerter used by every saga this orchestrator runs
+func (s *CustomersSaga) WithAlerter(alerter saga.Alerter) *CustomersSaga {
+	s.alerter = alerter
+	return s
+}
+
+// WithStateStore sets the store used to persist and resume sagas
+func (s *CustomersSaga) WithStateStore(store saga.StateStore) *CustomersSaga {
+	s.stateStore = store
+	return s
+}
+
+// WithKYCRequired gates application creation on the customer's KYC status being verified
+func (s *CustomersSaga) WithKYCRequired() *CustomersSaga {
+	s.requireKYC = true
+	return s
+}
+
+// CreateCustomer runs the onboarding saga for name and email applying with the
+// application terms, returning once it completed or was rolled back
+func (s *CustomersSaga) CreateCustomer(ctx context.Context, name, email string, application ApplicationSagaData) error {
+	return s.newSaga(newCustomerSagaData(name, email, application)).Execute(ctx)
+}
+
+// OnboardingRequest is the input of a customer onboarding saga
+type OnboardingRequest struct {
+	Name        string           `json:"name"`
+	Email       string           `json:"email"`
+	Application ApplicationTerms `json:"application"`
+}
+
+// ApplicationTerms are the terms the onboarded customer applies for
+type ApplicationTerms struct {
+	LoanAmount    decimal.Decimal `json:"loan_amount"`
+	PropertyValue decimal.Decimal `json:"property_value"`
+	InterestRate  float64         `json:"interest_rate"`
+	TermYears     int             `json:"term_years"`
+}
+
+// OnboardingResult is what an onboarding saga has created so far
+type OnboardingResult struct {
+	CustomerID    *uuid.UUID `json:"customer_id,omitempty"`
+	ApplicationID *uuid.UUID `json:"application_id,omitempty"`
+	LoanID        *uuid.UUID `json:"loan_id,omitempty"`
+}
+
+// Start begins an onboarding for an OnboardingRequest in the background and returns
+// the saga's ID once its state is recorded, so the caller can look it up in the
+// state store right away. done receives the saga's result when it finishes.
+func (s *CustomersSaga) Start(ctx context.Context, input json.RawMessage) (id uuid.UUID, done <-chan error, err error) {
+	request, err := decodeInput[OnboardingRequest](input)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	request.Email = strings.TrimSpace(request.Email)
+	if details := request.validate(); len(details) > 0 {
+		return uuid.Nil, nil, details
 	}
 
-	// Configure compensation strategy with retry and continue-all behavior
-	retryConfig := DefaultRetryConfig()
-	retryConfig.MaxRetries = 3
-	retryConfig.InitialBackoff = 2 * time.Second
+	onboarding := s.newSaga(newCustomerSagaData(request.Name, request.Email, ApplicationSagaData{
+		LoanAmount:     request.Application.LoanAmount,
+		PropertyAmount: request.Application.PropertyValue,
+		InterestRate:   request.Application.InterestRate,
+		TermYears:      request.Application.TermYears,
+	}))
+	done, err = onboarding.Start(ctx)
+	return onboarding.ID, done, err
+}
+
+// Result returns the IDs of the customer, application and loan an onboarding
+// created, leaving out the customer's name and email
+func (s *CustomersSaga) Result(data json.RawMessage) (any, error) {
+	var onboarding CustomerSagaData
+	if err := json.Unmarshal(data, &onboarding); err != nil {
+		return nil, err
+	}
+	return OnboardingResult{
+		CustomerID:    onboarding.CustomerID,
+		ApplicationID: onboarding.ApplicationID,
+		LoanID:        onboarding.LoanID,
+	}, nil
+}
 
-	compensationStrategy := NewContinueAllStrategy[CustomerSagaData](retryConfig)
+// validate returns why each rejected field of the request was rejected
+func (r *OnboardingRequest) validate() ValidationError {
+	details := make(ValidationError)
+	if r.Name == "" {
+		details["name"] = "is required"
+	}
+	if address, err := mail.ParseAddress(r.Email); err != nil || address.Address != r.Email {
+		details["email"] = "must be an email address"
+	}
+	if !r.Application.LoanAmount.IsPositive() {
+		details["application.loan_amount"] = "must be greater than 0"
+	}
+	if !r.Application.PropertyValue.IsPositive() {
+		details["application.property_value"] = "must be greater than 0"
+	}
+	if r.Application.InterestRate <= 0 {
+		details["application.interest_rate"] = "must be greater than 0"
+	}
+	if r.Application.TermYears <= 0 {
+		details["application.term_years"] = "must be greater than 0"
+	}
+	return details
+}
 
-	// Create and execute the saga
-	err := NewSaga(data).
-		WithCompensationStrategy(compensationStrategy).
+// newCustomerSagaData initializes the saga data context for an onboarding
+func newCustomerSagaData(name, email string, application ApplicationSagaData) *CustomerSagaData {
+	return &CustomerSagaData{
+		Name:                  name,
+		Email:                 email,
+		ApplicationRequestKey: uuid.NewString(),
+		Application:           application,
+	}
+}
+
+// Resume continues a customer onboarding saga persisted in the state store
+func (s *CustomersSaga) Resume(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&CustomerSagaData{}).Resume(ctx, sagaID)
+}
+
+// Compensate rolls back a customer onboarding saga persisted in the state store
+func (s *CustomersSaga) Compensate(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&CustomerSagaData{}).Compensate(ctx, sagaID)
+}
+
+// newSaga builds the customer onboarding saga definition around data
+func (s *CustomersSaga) newSaga(data *CustomerSagaData) *saga.Saga[CustomerSagaData] {
+	onboarding := newSaga(CustomerOnboardingSagaName, data, s.alerter, s.stateStore).
 		AddStep(
 			"CreateCustomer",
 			func(ctx context.Context, data *CustomerSagaData) error {
@@ -87,13 +211,40 @@ func (s *CustomersSaga) CreateCustomer(ctx context.Context, name, email string)
 				if data.CustomerID == nil {
 					return nil // Nothing to compensate
 				}
-				return s.customersClient.Delete(ctx, *data.CustomerID)
+				err := s.customersClient.Delete(ctx, *data.CustomerID)
+				if errors.Is(err, customers.ErrNotFound) {
+					// Already deleted
+					return nil
+				}
+				return err
 			},
-		).
+		)
+
+	if s.requireKYC {
+		onboarding.AddStep(
+			"CheckKYC",
+			func(ctx context.Context, data *CustomerSagaData) error {
+				customer, err := s.customersClient.Read(ctx, *data.CustomerID)
+				if err != nil {
+					return fmt.Errorf("failed to read customer: %w", err)
+				}
+				if customer.KYCStatus != customers.KYCVerified {
+					return fmt.Errorf("customer KYC status is %q, must be %q to apply", customer.KYCStatus, customers.KYCVerified)
+				}
+				return nil
+			},
+			func(ctx context.Context, data *CustomerSagaData) error {
+				return nil // Read-only check, nothing to compensate
+			},
+		)
+	}
+
+	return onboarding.
 		AddStep(
 			"CreateApplication",
 			func(ctx context.Context, data *CustomerSagaData) error {
-				application, err := s.applicationsClient.Create(ctx, *data.CustomerID, data.Application.LoanAmount, data.Application.PropertyAmount, data.Application.InterestRate, data.Application.TermYears)
+				application, err := s.applicationsClient.CreateIdempotent(ctx, data.ApplicationRequestKey, *data.CustomerID,
+					data.Application.LoanAmount, data.Application.PropertyAmount, data.Application.InterestRate, data.Application.TermYears)
 				if err != nil {
 					return fmt.Errorf("failed to create application: %w", err)
 				}
@@ -101,19 +252,29 @@ func (s *CustomersSaga) CreateCustomer(ctx context.Context, name, email string)
 				return nil
 			},
 			func(ctx context.Context, data *CustomerSagaData) error {
+				// Compensation: cancel rather than delete the application so the
+				// rolled-back attempt stays on record
 				if data.ApplicationID == nil {
 					return nil
 				}
-				return s.applicationsClient.Delete(ctx, *data.ApplicationID)
+				_, err := s.applicationsClient.Cancel(ctx, *data.ApplicationID, applictions.Decision{
+					DecidedBy: CustomerOnboardingSagaName,
+					Reason:    applictions.CancelReasonSagaCompensation,
+				})
+				if errors.Is(err, applictions.ErrNotFound) {
+					// Already gone, so there is nothing left to undo
+					return nil
+				}
+				return err
 			},
 		).
 		AddStep(
 			"ExportToServicing",
 			func(ctx context.Context, data *CustomerSagaData) error {
 				//return fmt.Errorf("failed to export loan")
-				loan, err := s.servicingClient.CreateLoan(ctx, *data.CustomerID, *data.ApplicationID,
+				loan, err := s.servicingClient.Create(ctx, *data.CustomerID, *data.ApplicationID,
 					data.Application.LoanAmount, data.Application.InterestRate, data.Application.TermYears,
-					float64(100), data.Application.LoanAmount, time.Now(), time.Now().AddDate(1, 0, 0))
+					decimal.NewFromInt(100), data.Application.LoanAmount, time.Now(), time.Now().AddDate(1, 0, 0))
 				if err != nil {
 					return fmt.Errorf("failed to export loan: %w", err)
 				}
@@ -121,14 +282,19 @@ func (s *CustomersSaga) CreateCustomer(ctx context.Context, name, email string)
 				return nil
 			},
 			func(ctx context.Context, data *CustomerSagaData) error {
-				// Compensation: clean up order if it was created
-				if data.LoanID != nil {
+				// Compensation: cancel rather than delete the loan so any payments
+				// recorded against it keep their loan
+				if data.LoanID == nil {
+					return nil
+				}
+				_, err := s.servicingClient.Cancel(ctx, *data.LoanID, servicing.Cancellation{
+					CancelledBy: CustomerOnboardingSagaName,
+					Reason:      servicing.CancelReasonSagaCompensation,
+				})
+				if errors.Is(err, servicing.ErrNotFound) {
 					return nil
 				}
-				return s.servicingClient.DeleteLoan(ctx, *data.LoanID)
+				return err
 			},
-		).
-		Execute(ctx)
-
-	return err
+		)
 }
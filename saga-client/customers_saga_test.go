@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"saga-client/mocks"
+	customers "service1/api/pkg/client"
+	applictions "service2/api/pkg/client"
+	servicing "service3/api/pkg/client"
+)
+
+// sagaMocks are the clients a CustomersSaga under test is built on; a call without
+// an expectation fails the test
+type sagaMocks struct {
+	customers    *mocks.MockCustomerAPI
+	applications *mocks.MockApplicationAPI
+	servicing    *mocks.MockServicingAPI
+}
+
+func newSagaMocks(t *testing.T) (*CustomersSaga, sagaMocks) {
+	ctrl := gomock.NewController(t)
+	m := sagaMocks{
+		customers:    mocks.NewMockCustomerAPI(ctrl),
+		applications: mocks.NewMockApplicationAPI(ctrl),
+		servicing:    mocks.NewMockServicingAPI(ctrl),
+	}
+	return NewCustomersSaga(m.customers, m.applications, m.servicing), m
+}
+
+func TestCustomersSaga_KYCGateRejectsUnverifiedCustomer(t *testing.T) {
+	saga, m := newSagaMocks(t)
+	saga.WithKYCRequired()
+	customer := customers.Customer{Id: uuid.New(), Name: "Jane", Email: "jane@example.com", KYCStatus: customers.KYCUnverified}
+	gomock.InOrder(
+		m.customers.EXPECT().Create(gomock.Any(), "Jane", "jane@example.com").Return(customer, nil),
+		m.customers.EXPECT().Read(gomock.Any(), customer.Id).Return(customer, nil),
+		m.customers.EXPECT().Delete(gomock.Any(), customer.Id).Return(nil),
+	)
+
+	err := saga.CreateCustomer(context.Background(), "Jane", "jane@example.com", ApplicationSagaData{})
+	if err == nil || !strings.Contains(err.Error(), "KYC") {
+		t.Fatalf("Expected KYC error, got: %v", err)
+	}
+}
+
+func TestCustomersSaga_KYCGateNotAddedByDefault(t *testing.T) {
+	saga := NewCustomersSaga(nil, nil, nil)
+	for _, step := range saga.newSaga(&CustomerSagaData{}).Steps {
+		if step.Name == "CheckKYC" {
+			t.Error("Expected no CheckKYC step unless KYC is required")
+		}
+	}
+	if steps := saga.WithKYCRequired().newSaga(&CustomerSagaData{}).Steps; steps[1].Name != "CheckKYC" {
+		t.Errorf("Expected CheckKYC to run before CreateApplication, got %s", steps[1].Name)
+	}
+}
+
+func TestCustomersSaga_CreateApplicationRetrySendsSameIdempotencyKey(t *testing.T) {
+	saga, m := newSagaMocks(t)
+	customerId, applicationId := uuid.New(), uuid.New()
+	data := &CustomerSagaData{ApplicationRequestKey: uuid.NewString(), CustomerID: &customerId}
+	m.applications.EXPECT().
+		CreateIdempotent(gomock.Any(), data.ApplicationRequestKey, customerId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(applictions.MortgageApplication{Id: applicationId}, nil).
+		Times(2)
+
+	for _, step := range saga.newSaga(data).Steps {
+		if step.Name != "CreateApplication" {
+			continue
+		}
+		for attempt := 0; attempt < 2; attempt++ {
+			if err := step.Execute(context.Background(), data); err != nil {
+				t.Fatalf("CreateApplication failed: %v", err)
+			}
+		}
+	}
+
+	if data.ApplicationID == nil || *data.ApplicationID != applicationId {
+		t.Errorf("Expected application ID %v, got %v", applicationId, data.ApplicationID)
+	}
+}
+
+func TestCustomersSaga_CompensationCancelsApplication(t *testing.T) {
+	saga, m := newSagaMocks(t)
+	customer := customers.Customer{Id: uuid.New(), Name: "Jane", Email: "jane@example.com", KYCStatus: customers.KYCVerified}
+	applicationId := uuid.New()
+	m.customers.EXPECT().Create(gomock.Any(), "Jane", "jane@example.com").Return(customer, nil)
+	m.customers.EXPECT().Delete(gomock.Any(), customer.Id).Return(nil)
+	gomock.InOrder(
+		m.applications.EXPECT().
+			CreateIdempotent(gomock.Any(), gomock.Any(), customer.Id, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(applictions.MortgageApplication{Id: applicationId}, nil),
+		m.applications.EXPECT().
+			Cancel(gomock.Any(), applicationId, applictions.Decision{
+				DecidedBy: CustomerOnboardingSagaName,
+				Reason:    applictions.CancelReasonSagaCompensation,
+			}).
+			Return(applictions.MortgageApplication{Id: applicationId, Status: "cancelled"}, nil),
+	)
+	m.servicing.EXPECT().
+		Create(gomock.Any(), customer.Id, applicationId, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(servicing.Loan{}, errors.New("servicing unavailable")).
+		AnyTimes()
+
+	if err := saga.CreateCustomer(context.Background(), "Jane", "jane@example.com", ApplicationSagaData{}); err == nil {
+		t.Fatal("Expected the saga to fail when the loan export fails")
+	}
+}
+
+func TestCustomersSaga_CompensationCancelsLoan(t *testing.T) {
+	saga, m := newSagaMocks(t)
+	loanId := uuid.New()
+	m.servicing.EXPECT().
+		Cancel(gomock.Any(), loanId, servicing.Cancellation{
+			Reason:      servicing.CancelReasonSagaCompensation,
+			CancelledBy: CustomerOnboardingSagaName,
+		}).
+		Return(servicing.Loan{Id: loanId, Status: "cancelled"}, nil)
+
+	for _, loanID := range []*uuid.UUID{nil, &loanId} {
+		data := &CustomerSagaData{LoanID: loanID}
+		for _, step := range saga.newSaga(data).Steps {
+			if step.Name != "ExportToServicing" {
+				continue
+			}
+			if err := step.Compensate(context.Background(), data); err != nil {
+				t.Fatalf("ExportToServicing compensation failed: %v", err)
+			}
+		}
+	}
+}
+
+func TestCustomersSaga_CompensationToleratesMissingLoan(t *testing.T) {
+	saga, m := newSagaMocks(t)
+	loanId := uuid.New()
+	m.servicing.EXPECT().
+		Cancel(gomock.Any(), loanId, gomock.Any()).
+		Return(servicing.Loan{}, &servicing.APIError{StatusCode: http.StatusNotFound, Code: "not_found"})
+
+	data := &CustomerSagaData{LoanID: &loanId}
+	for _, step := range saga.newSaga(data).Steps {
+		if step.Name == "ExportToServicing" {
+			if err := step.Compensate(context.Background(), data); err != nil {
+				t.Errorf("Expected a loan that is already gone to need no compensation, got %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// clientRequests holds the service calls' counts, failures and latency by endpoint.
+// The saga package publishes sagas_in_flight and saga_outcomes alongside it.
+var clientRequests = expvar.NewMap("client_requests")
+
+func init() {
+	// memstats and cmdline are published by expvar itself
+	expvar.Publish("goroutines", expvar.Func(func() any {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("runtime", expvar.Func(func() any {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		return map[string]any{
+			"heap_alloc_bytes":   m.HeapAlloc,
+			"heap_objects":       m.HeapObjects,
+			"heap_inuse_bytes":   m.HeapInuse,
+			"stack_inuse_bytes":  m.StackInuse,
+			"total_alloc_bytes":  m.TotalAlloc,
+			"gc_cycles":          m.NumGC,
+			"gc_pause_total_ns":  m.PauseTotalNs,
+			"last_gc_unix_nanos": m.LastGC,
+			"num_cpu":            runtime.NumCPU(),
+			"gomaxprocs":         runtime.GOMAXPROCS(0),
+		}
+	}))
+}
+
+// NewDebugHandler exposes net/http/pprof under /debug/pprof/ and expvar runtime
+// metrics under /debug/vars. It is meant for a separate, non-public debug port.
+func NewDebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// ListenAndServeDebug serves the debug handler on addr until the server fails
+func ListenAndServeDebug(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           NewDebugHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+func TestDebugHandler_ExposesPprof(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewDebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
+
+func TestDebugHandler_ExposesRuntimeMetrics(t *testing.T) {
+	noop := func(ctx context.Context, data *struct{}) error { return nil }
+	completed := saga.NewWithLogger(&struct{}{}, log.New(io.Discard, "", 0)).AddStep("Step1", noop, noop)
+	if err := completed.Execute(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewDebugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&vars); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	for _, key := range []string{"memstats", "goroutines", "runtime", "sagas_in_flight", "saga_outcomes"} {
+		if _, ok := vars[key]; !ok {
+			t.Errorf("Expected %s in /debug/vars", key)
+		}
+	}
+
+	var outcomes map[string]int
+	if err := json.Unmarshal(vars["saga_outcomes"], &outcomes); err != nil {
+		t.Fatalf("Invalid saga_outcomes: %v", err)
+	}
+	if outcomes[string(saga.StatusCompleted)] < 1 {
+		t.Errorf("Expected at least one completed saga, got %v", outcomes)
+	}
+}
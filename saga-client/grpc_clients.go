@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/latebit-io/saga-pattern/saga"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	customers "service1/api/pkg/client"
+	applictions "service2/api/pkg/client"
+	servicing "service3/api/pkg/client"
+)
+
+// grpcRetryPolicy resends calls the server answered with UNAVAILABLE or, when it
+// was rate limited, RESOURCE_EXHAUSTED, which it only does before acting on them,
+// backing off like the HTTP clients' DefaultRetry
+const grpcRetryPolicy = `{"methodConfig":[{"name":[{}],"retryPolicy":{"maxAttempts":%d,` +
+	`"initialBackoff":"0.1s","maxBackoff":"1s","backoffMultiplier":2,` +
+	`"retryableStatusCodes":["UNAVAILABLE","RESOURCE_EXHAUSTED"]}}]}`
+
+// newGRPCClients dials the services' gRPC ports at config's gRPC addresses, over TLS
+// when tlsConfig is set, and sends the same tenant, trace context and credentials, within the same
+// timeout, as the HTTP clients. The returned func closes the connections.
+func newGRPCClients(config Config, tlsConfig *tls.Config) (CustomerAPI, ApplicationAPI, ServicingAPI, func(), error) {
+	var conns []*grpc.ClientConn
+	closeConns := func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	dial := func(addr string) (*grpc.ClientConn, error) {
+		conn, err := dialGRPC(addr, tlsConfig, config.RetryAttempts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", addr, err)
+		}
+		conns = append(conns, conn)
+		return conn, nil
+	}
+	customersConn, err := dial(config.CustomersGRPCAddr)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	applicationsConn, err := dial(config.ApplicationsGRPCAddr)
+	if err != nil {
+		closeConns()
+		return nil, nil, nil, nil, err
+	}
+	servicingConn, err := dial(config.ServicingGRPCAddr)
+	if err != nil {
+		closeConns()
+		return nil, nil, nil, nil, err
+	}
+
+	customersClient := customers.NewGRPCClient(customersConn).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[customers.TraceContext])
+	applicationsClient := applictions.NewGRPCClient(applicationsConn).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[applictions.TraceContext])
+	servicingClient := servicing.NewGRPCClient(servicingConn).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[servicing.TraceContext])
+	customersClient.WithTimeout(config.CallTimeout)
+	applicationsClient.WithTimeout(config.CallTimeout)
+	servicingClient.WithTimeout(config.CallTimeout)
+	if config.APIKey != "" {
+		customersClient.WithAPIKey(config.APIKey)
+		applicationsClient.WithAPIKey(config.APIKey)
+		servicingClient.WithAPIKey(config.APIKey)
+	} else if config.BearerToken != "" {
+		customersClient.WithBearerToken(config.BearerToken)
+		applicationsClient.WithBearerToken(config.BearerToken)
+		servicingClient.WithBearerToken(config.BearerToken)
+	} else if config.BearerTokenFile != "" {
+		tokens := NewFileTokenSource(config.BearerTokenFile)
+		customersClient.WithTokenSource(tokens)
+		applicationsClient.WithTokenSource(tokens)
+		servicingClient.WithTokenSource(tokens)
+	}
+	return customersClient, applicationsClient, servicingClient.Loans(), closeConns, nil
+}
+
+// dialGRPC connects to addr, in plaintext unless tlsConfig is set, resending a call
+// answered with UNAVAILABLE or RESOURCE_EXHAUSTED until it was sent attempts times
+func dialGRPC(addr string, tlsConfig *tls.Config, attempts int) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if attempts > 1 {
+		// gRPC caps a retry policy at five attempts
+		opts = append(opts, grpc.WithDefaultServiceConfig(fmt.Sprintf(grpcRetryPolicy, min(attempts, 5))))
+	}
+	return grpc.NewClient(addr, opts...)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+// HealthCheck is a named readiness probe
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// Pinger is implemented by state stores that can verify their backing connection
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthServer serves /healthz (process is alive) and /readyz (dependencies are reachable)
+type HealthServer struct {
+	checks  []HealthCheck
+	timeout time.Duration
+}
+
+func NewHealthServer(checks ...HealthCheck) *HealthServer {
+	return &HealthServer{
+		checks:  checks,
+		timeout: 5 * time.Second,
+	}
+}
+
+// Handler returns the HTTP handler exposing the health endpoints
+func (h *HealthServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", h.liveness)
+	mux.HandleFunc("GET /readyz", h.readiness)
+	return mux
+}
+
+// ListenAndServe serves the health endpoints on addr until the server fails
+func (h *HealthServer) ListenAndServe(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+func (h *HealthServer) liveness(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+func (h *HealthServer) readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	// Run checks concurrently so one slow dependency doesn't hide the others
+	results := make(map[string]string, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			result := "ok"
+			if err := check.Check(ctx); err != nil {
+				result = err.Error()
+			}
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	response := healthResponse{Status: "ready", Checks: results}
+	status := http.StatusOK
+	for _, result := range results {
+		if result != "ok" {
+			response.Status = "not_ready"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+	writeHealth(w, status, response)
+}
+
+func writeHealth(w http.ResponseWriter, status int, response healthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// StateStoreCheck verifies the state store connection when the store supports Ping
+func StateStoreCheck(store saga.StateStore) HealthCheck {
+	return HealthCheck{
+		Name: "state_store",
+		Check: func(ctx context.Context) error {
+			if pinger, ok := store.(Pinger); ok {
+				return pinger.Ping(ctx)
+			}
+			return nil
+		},
+	}
+}
+
+// HTTPServiceCheck verifies a downstream service is ready by calling its /readyz probe,
+// which only answers 200 once the service's database is reachable and migrated
+func HTTPServiceCheck(name, baseURL string) HealthCheck {
+	return HTTPServiceCheckWithClient(name, baseURL, &http.Client{})
+}
+
+// HTTPServiceCheckWithClient is HTTPServiceCheck calling the probe with httpClient,
+// e.g. one set up for the service's TLS
+func HTTPServiceCheckWithClient(name, baseURL string, httpClient *http.Client) HealthCheck {
+	return HealthCheck{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/readyz", nil)
+			if err != nil {
+				return err
+			}
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
+
+// WaitReady polls checks every interval until they all pass, so a saga doesn't start
+// against services that are still starting up. It gives up when ctx is done.
+func WaitReady(ctx context.Context, interval time.Duration, checks ...HealthCheck) error {
+	for {
+		err := firstFailure(ctx, checks)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("dependencies not ready: %w", err)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func firstFailure(ctx context.Context, checks []HealthCheck) error {
+	for _, check := range checks {
+		if err := check.Check(ctx); err != nil {
+			return fmt.Errorf("%s: %w", check.Name, err)
+		}
+	}
+	return nil
+}
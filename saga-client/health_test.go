@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+type pingStore struct {
+	*saga.InMemoryStateStore
+	err error
+}
+
+func (p *pingStore) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func TestHealthServer_Liveness(t *testing.T) {
+	health := NewHealthServer(HealthCheck{Name: "broken", Check: func(ctx context.Context) error {
+		return errors.New("down")
+	}})
+
+	rec := httptest.NewRecorder()
+	health.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	// Liveness must not depend on downstream checks
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
+
+func TestHealthServer_ReadinessAllHealthy(t *testing.T) {
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/readyz" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer downstream.Close()
+
+	health := NewHealthServer(
+		StateStoreCheck(&pingStore{InMemoryStateStore: saga.NewInMemoryStateStore()}),
+		HTTPServiceCheck("customers", downstream.URL),
+	)
+
+	rec := httptest.NewRecorder()
+	health.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if response.Status != "ready" || response.Checks["state_store"] != "ok" || response.Checks["customers"] != "ok" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+}
+
+func TestHealthServer_ReadinessFailingDependency(t *testing.T) {
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer downstream.Close()
+
+	health := NewHealthServer(
+		StateStoreCheck(&pingStore{InMemoryStateStore: saga.NewInMemoryStateStore(), err: errors.New("connection refused")}),
+		HTTPServiceCheck("servicing", downstream.URL),
+	)
+
+	rec := httptest.NewRecorder()
+	health.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	var response healthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if response.Status != "not_ready" {
+		t.Errorf("Expected not_ready, got %s", response.Status)
+	}
+	if response.Checks["state_store"] != "connection refused" {
+		t.Errorf("Expected state store failure, got %q", response.Checks["state_store"])
+	}
+	if response.Checks["servicing"] == "ok" {
+		t.Error("Expected servicing check to fail")
+	}
+}
+
+func TestWaitReady(t *testing.T) {
+	attempts := 0
+	starting := HealthCheck{Name: "servicing", Check: func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}
+
+	if err := WaitReady(context.Background(), time.Millisecond, starting); err != nil {
+		t.Fatalf("Expected dependencies to become ready, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestWaitReady_GivesUp(t *testing.T) {
+	down := HealthCheck{Name: "servicing", Check: func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := WaitReady(ctx, 5*time.Millisecond, down)
+	if err == nil || !strings.Contains(err.Error(), "servicing: connection refused") {
+		t.Errorf("Expected the failing check in the error, got: %v", err)
+	}
+}
@@ -2,26 +2,175 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/latebit-io/saga-pattern/saga/postgres"
 	customers "service1/api/pkg/client"
 	applictions "service2/api/pkg/client"
 	servicing "service3/api/pkg/client"
 )
 
 func main() {
-	customersClient := customers.NewClient("http://localhost:8081")
-	applicationsClient := applictions.NewClient("http://localhost:8082")
-	servicingClient := servicing.NewClient("http://localhost:8083")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	saga := NewCustomersSaga(customersClient, applicationsClient, servicingClient)
+	env := cliEnv{config: LoadConfig, stateStore: newStateStore, sagas: newSagas}
+	if err := newRootCommand(env).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
 
-	err := saga.CreateCustomer(
-		context.Background(),
-		"John",
-		"john@makes.beats",
-	)
+// newSagas builds the saga clients from config and registers the sagas that run on
+// them. The returned checks probe the services the sagas call; closeClients releases
+// the gRPC connections.
+func newSagas(config Config, stateStore saga.StateStore) (registry *SagaRegistry, services []HealthCheck, closeClients func(), err error) {
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	// Readiness probes go over the same TLS as the saga's calls
+	probeTransport := http.DefaultTransport.(*http.Transport).Clone()
+	probeTransport.TLSClientConfig = tlsConfig
+	probeClient := &http.Client{Transport: probeTransport}
+	services = []HealthCheck{
+		HTTPServiceCheckWithClient("customers", config.CustomersURL, probeClient),
+		HTTPServiceCheckWithClient("applications", config.ApplicationsURL, probeClient),
+		HTTPServiceCheckWithClient("servicing", config.ServicingURL, probeClient),
+	}
 
+	// Reads revalidate what the clients kept with If-None-Match; the caches sit
+	// innermost so entries are kept per tenant and credentials
+	customersClient := customers.NewClient(config.CustomersURL).WithCache(config.CacheEntries).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[customers.TraceContext])
+	applicationsClient := applictions.NewClient(config.ApplicationsURL).WithCache(config.CacheEntries).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[applictions.TraceContext])
+	servicingClient := servicing.NewClient(config.ServicingURL).WithCache(config.CacheEntries).WithTenantFrom(saga.TenantFromContext).
+		WithTraceContextFrom(saga.TraceContextFromContext[servicing.TraceContext])
+	// Name the saga in the services' logs and warn when one answers with another API
+	// version than the clients speak
+	userAgent := sagaUserAgent()
+	customersClient.WithUserAgent(userAgent).
+		WithVersionMismatch(versionMismatchLogger("customers", customers.APIVersion))
+	applicationsClient.WithUserAgent(userAgent).
+		WithVersionMismatch(versionMismatchLogger("applications", applictions.APIVersion))
+	servicingClient.WithUserAgent(userAgent).
+		WithVersionMismatch(versionMismatchLogger("servicing", servicing.APIVersion))
+	if tlsConfig != nil {
+		customersClient.WithTLSConfig(tlsConfig)
+		applicationsClient.WithTLSConfig(tlsConfig)
+		servicingClient.WithTLSConfig(tlsConfig)
+	}
+	connections := config.Connections()
+	customersClient.WithConnections(connections)
+	applicationsClient.WithConnections(applictions.Connections(connections))
+	servicingClient.WithConnections(servicing.Connections(connections))
+	// Ride out transient failures instead of compensating; CreateApplication's POST
+	// carries an idempotency key, so it is safe to resend too
+	retry := customers.DefaultRetry
+	retry.Attempts = config.RetryAttempts
+	retry.IdempotentPosts = true
+	customersClient.WithRetry(retry)
+	applicationsClient.WithRetry(applictions.Retry(retry))
+	servicingClient.WithRetry(servicing.Retry(retry))
+	// Bound each call, retries included, so one slow service fails its step instead
+	// of stalling the saga
+	customersClient.WithTimeout(config.CallTimeout)
+	applicationsClient.WithTimeout(config.CallTimeout)
+	servicingClient.WithTimeout(config.CallTimeout)
+	// Measured outside the retries, so a call's latency is what the saga waited
+	customersClient.WithMetrics(NewClientMetrics[customers.RequestMetrics]("customers"))
+	applicationsClient.WithMetrics(NewClientMetrics[applictions.RequestMetrics]("applications"))
+	servicingClient.WithMetrics(NewClientMetrics[servicing.RequestMetrics]("servicing"))
+	// Creates carry their step's idempotency key; added after the retries so they see it
+	customersClient.WithIdempotencyKeyFrom(saga.IdempotencyKeyFromContext)
+	applicationsClient.WithIdempotencyKeyFrom(saga.IdempotencyKeyFromContext)
+	servicingClient.WithIdempotencyKeyFrom(saga.IdempotencyKeyFromContext)
+	if config.APIKey != "" {
+		customersClient.WithAPIKey(config.APIKey)
+		applicationsClient.WithAPIKey(config.APIKey)
+		servicingClient.WithAPIKey(config.APIKey)
+	} else if config.BearerToken != "" {
+		customersClient.WithBearerToken(config.BearerToken)
+		applicationsClient.WithBearerToken(config.BearerToken)
+		servicingClient.WithBearerToken(config.BearerToken)
+	} else if config.BearerTokenFile != "" {
+		// A rotated token is picked up by the next call
+		tokens := NewFileTokenSource(config.BearerTokenFile)
+		customersClient.WithTokenSource(tokens)
+		applicationsClient.WithTokenSource(tokens)
+		servicingClient.WithTokenSource(tokens)
+	}
+
+	// SAGA_TRANSPORT=grpc runs the saga's calls over the services' gRPC ports instead
+	var customerAPI CustomerAPI = customersClient
+	var applicationAPI ApplicationAPI = applicationsClient
+	var servicingAPI ServicingAPI = servicingClient.Loans()
+	closeClients = func() {}
+	if config.Transport == "grpc" {
+		customerAPI, applicationAPI, servicingAPI, closeClients, err = newGRPCClients(config, tlsConfig)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to set up gRPC clients: %w", err)
+		}
+	}
+
+	// Each saga the client runs is registered under the name a request picks it by.
+	// Onboarding goes over SAGA_TRANSPORT; the payment and offboarding sagas call
+	// endpoints the gRPC clients don't have, so they always use HTTP.
+	alerter := newAlerter(config)
+	onboarding := NewCustomersSaga(customerAPI, applicationAPI, servicingAPI).
+		WithAlerter(alerter).
+		WithStateStore(stateStore)
+	if config.RequireKYC {
+		onboarding.WithKYCRequired()
+	}
+	registry = NewSagaRegistry().
+		Register(CustomerOnboardingSagaName, onboarding).
+		Register(PaymentSagaName, NewPaymentSaga(servicingClient.Loans(), servicingClient.Payments()).
+			WithAlerter(alerter).
+			WithStateStore(stateStore)).
+		Register(OffboardingSagaName, NewOffboardingSaga(customersClient, applicationsClient, servicingClient.Loans()).
+			WithAlerter(alerter).
+			WithStateStore(stateStore))
+	return registry, services, closeClients, nil
+}
+
+// newAlerter builds the alerter from the Slack and webhook URLs. When neither is
+// set, alerts are disabled and failures are only logged.
+func newAlerter(config Config) saga.Alerter {
+	var alerters saga.MultiAlerter
+	if config.SlackWebhookURL != "" {
+		alerters = append(alerters, saga.NewSlackAlerter(config.SlackWebhookURL, config.SlackChannel))
+	}
+	if config.AlertWebhookURL != "" {
+		alerters = append(alerters, saga.NewWebhookAlerter(config.AlertWebhookURL, nil))
+	}
+	if len(alerters) == 0 {
+		return nil
+	}
+	return alerters
+}
+
+// newStateStore persists saga state in Postgres when config selects it, otherwise
+// state is kept in memory for the lifetime of the process.
+func newStateStore(ctx context.Context, config Config) (saga.StateStore, func(), error) {
+	if config.stateStoreKind() == stateStoreMemory {
+		return saga.NewInMemoryStateStore(), func() {}, nil
+	}
+
+	pool, err := pgxpool.New(ctx, config.DatabaseURL)
 	if err != nil {
-		panic(err)
+		return nil, nil, err
+	}
+	store := postgres.NewStateStore(pool)
+	if err := store.CreateSchema(ctx); err != nil {
+		pool.Close()
+		return nil, nil, err
 	}
+	return store, pool.Close, nil
 }
@@ -0,0 +1,400 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: clients.go
+//
+// Generated by this command:
+//
+//	mockgen -source=clients.go -destination=mocks/clients.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	client "service1/api/pkg/client"
+	client0 "service2/api/pkg/client"
+	client1 "service3/api/pkg/client"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	decimal "github.com/shopspring/decimal"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCustomerAPI is a mock of CustomerAPI interface.
+type MockCustomerAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockCustomerAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockCustomerAPIMockRecorder is the mock recorder for MockCustomerAPI.
+type MockCustomerAPIMockRecorder struct {
+	mock *MockCustomerAPI
+}
+
+// NewMockCustomerAPI creates a new mock instance.
+func NewMockCustomerAPI(ctrl *gomock.Controller) *MockCustomerAPI {
+	mock := &MockCustomerAPI{ctrl: ctrl}
+	mock.recorder = &MockCustomerAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCustomerAPI) EXPECT() *MockCustomerAPIMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCustomerAPI) Create(ctx context.Context, name, email string) (client.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, name, email)
+	ret0, _ := ret[0].(client.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCustomerAPIMockRecorder) Create(ctx, name, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCustomerAPI)(nil).Create), ctx, name, email)
+}
+
+// Delete mocks base method.
+func (m *MockCustomerAPI) Delete(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCustomerAPIMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCustomerAPI)(nil).Delete), ctx, id)
+}
+
+// Read mocks base method.
+func (m *MockCustomerAPI) Read(ctx context.Context, id uuid.UUID) (client.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read", ctx, id)
+	ret0, _ := ret[0].(client.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockCustomerAPIMockRecorder) Read(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockCustomerAPI)(nil).Read), ctx, id)
+}
+
+// MockApplicationAPI is a mock of ApplicationAPI interface.
+type MockApplicationAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockApplicationAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockApplicationAPIMockRecorder is the mock recorder for MockApplicationAPI.
+type MockApplicationAPIMockRecorder struct {
+	mock *MockApplicationAPI
+}
+
+// NewMockApplicationAPI creates a new mock instance.
+func NewMockApplicationAPI(ctrl *gomock.Controller) *MockApplicationAPI {
+	mock := &MockApplicationAPI{ctrl: ctrl}
+	mock.recorder = &MockApplicationAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApplicationAPI) EXPECT() *MockApplicationAPIMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockApplicationAPI) Cancel(ctx context.Context, id uuid.UUID, decision client0.Decision) (client0.MortgageApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, decision)
+	ret0, _ := ret[0].(client0.MortgageApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockApplicationAPIMockRecorder) Cancel(ctx, id, decision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockApplicationAPI)(nil).Cancel), ctx, id, decision)
+}
+
+// CreateIdempotent mocks base method.
+func (m *MockApplicationAPI) CreateIdempotent(ctx context.Context, idempotencyKey string, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int) (client0.MortgageApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdempotent", ctx, idempotencyKey, customerId, loanAmount, propertyValue, interestRate, termYears)
+	ret0, _ := ret[0].(client0.MortgageApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdempotent indicates an expected call of CreateIdempotent.
+func (mr *MockApplicationAPIMockRecorder) CreateIdempotent(ctx, idempotencyKey, customerId, loanAmount, propertyValue, interestRate, termYears any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdempotent", reflect.TypeOf((*MockApplicationAPI)(nil).CreateIdempotent), ctx, idempotencyKey, customerId, loanAmount, propertyValue, interestRate, termYears)
+}
+
+// MockServicingAPI is a mock of ServicingAPI interface.
+type MockServicingAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockServicingAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockServicingAPIMockRecorder is the mock recorder for MockServicingAPI.
+type MockServicingAPIMockRecorder struct {
+	mock *MockServicingAPI
+}
+
+// NewMockServicingAPI creates a new mock instance.
+func NewMockServicingAPI(ctrl *gomock.Controller) *MockServicingAPI {
+	mock := &MockServicingAPI{ctrl: ctrl}
+	mock.recorder = &MockServicingAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServicingAPI) EXPECT() *MockServicingAPIMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockServicingAPI) Cancel(ctx context.Context, id uuid.UUID, cancellation client1.Cancellation) (client1.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id, cancellation)
+	ret0, _ := ret[0].(client1.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockServicingAPIMockRecorder) Cancel(ctx, id, cancellation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockServicingAPI)(nil).Cancel), ctx, id, cancellation)
+}
+
+// Create mocks base method.
+func (m *MockServicingAPI) Create(ctx context.Context, customerId, mortgageId uuid.UUID, loanAmount decimal.Decimal, interestRate float64, termYears int, monthlyPayment, outstandingBalance decimal.Decimal, startDate, maturityDate time.Time) (client1.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, customerId, mortgageId, loanAmount, interestRate, termYears, monthlyPayment, outstandingBalance, startDate, maturityDate)
+	ret0, _ := ret[0].(client1.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServicingAPIMockRecorder) Create(ctx, customerId, mortgageId, loanAmount, interestRate, termYears, monthlyPayment, outstandingBalance, startDate, maturityDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockServicingAPI)(nil).Create), ctx, customerId, mortgageId, loanAmount, interestRate, termYears, monthlyPayment, outstandingBalance, startDate, maturityDate)
+}
+
+// Get mocks base method.
+func (m *MockServicingAPI) Get(ctx context.Context, id uuid.UUID) (client1.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(client1.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockServicingAPIMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockServicingAPI)(nil).Get), ctx, id)
+}
+
+// MockPaymentAPI is a mock of PaymentAPI interface.
+type MockPaymentAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentAPIMockRecorder is the mock recorder for MockPaymentAPI.
+type MockPaymentAPIMockRecorder struct {
+	mock *MockPaymentAPI
+}
+
+// NewMockPaymentAPI creates a new mock instance.
+func NewMockPaymentAPI(ctrl *gomock.Controller) *MockPaymentAPI {
+	mock := &MockPaymentAPI{ctrl: ctrl}
+	mock.recorder = &MockPaymentAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentAPI) EXPECT() *MockPaymentAPIMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPaymentAPI) Create(ctx context.Context, loanId, customerId uuid.UUID, paymentAmount, principalAmount, interestAmount, escrowAmount decimal.Decimal, paymentDate time.Time, paymentType string) (client1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, loanId, customerId, paymentAmount, principalAmount, interestAmount, escrowAmount, paymentDate, paymentType)
+	ret0, _ := ret[0].(client1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPaymentAPIMockRecorder) Create(ctx, loanId, customerId, paymentAmount, principalAmount, interestAmount, escrowAmount, paymentDate, paymentType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentAPI)(nil).Create), ctx, loanId, customerId, paymentAmount, principalAmount, interestAmount, escrowAmount, paymentDate, paymentType)
+}
+
+// Reverse mocks base method.
+func (m *MockPaymentAPI) Reverse(ctx context.Context, id uuid.UUID) (client1.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, id)
+	ret0, _ := ret[0].(client1.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockPaymentAPIMockRecorder) Reverse(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockPaymentAPI)(nil).Reverse), ctx, id)
+}
+
+// MockOffboardingCustomerAPI is a mock of OffboardingCustomerAPI interface.
+type MockOffboardingCustomerAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOffboardingCustomerAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockOffboardingCustomerAPIMockRecorder is the mock recorder for MockOffboardingCustomerAPI.
+type MockOffboardingCustomerAPIMockRecorder struct {
+	mock *MockOffboardingCustomerAPI
+}
+
+// NewMockOffboardingCustomerAPI creates a new mock instance.
+func NewMockOffboardingCustomerAPI(ctrl *gomock.Controller) *MockOffboardingCustomerAPI {
+	mock := &MockOffboardingCustomerAPI{ctrl: ctrl}
+	mock.recorder = &MockOffboardingCustomerAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOffboardingCustomerAPI) EXPECT() *MockOffboardingCustomerAPIMockRecorder {
+	return m.recorder
+}
+
+// Anonymize mocks base method.
+func (m *MockOffboardingCustomerAPI) Anonymize(ctx context.Context, id uuid.UUID, request client.AnonymizationRequest) (client.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Anonymize", ctx, id, request)
+	ret0, _ := ret[0].(client.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Anonymize indicates an expected call of Anonymize.
+func (mr *MockOffboardingCustomerAPIMockRecorder) Anonymize(ctx, id, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Anonymize", reflect.TypeOf((*MockOffboardingCustomerAPI)(nil).Anonymize), ctx, id, request)
+}
+
+// MockOffboardingApplicationAPI is a mock of OffboardingApplicationAPI interface.
+type MockOffboardingApplicationAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOffboardingApplicationAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockOffboardingApplicationAPIMockRecorder is the mock recorder for MockOffboardingApplicationAPI.
+type MockOffboardingApplicationAPIMockRecorder struct {
+	mock *MockOffboardingApplicationAPI
+}
+
+// NewMockOffboardingApplicationAPI creates a new mock instance.
+func NewMockOffboardingApplicationAPI(ctrl *gomock.Controller) *MockOffboardingApplicationAPI {
+	mock := &MockOffboardingApplicationAPI{ctrl: ctrl}
+	mock.recorder = &MockOffboardingApplicationAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOffboardingApplicationAPI) EXPECT() *MockOffboardingApplicationAPIMockRecorder {
+	return m.recorder
+}
+
+// GetByCustomerId mocks base method.
+func (m *MockOffboardingApplicationAPI) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]client0.MortgageApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCustomerId", ctx, customerId)
+	ret0, _ := ret[0].([]client0.MortgageApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCustomerId indicates an expected call of GetByCustomerId.
+func (mr *MockOffboardingApplicationAPIMockRecorder) GetByCustomerId(ctx, customerId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCustomerId", reflect.TypeOf((*MockOffboardingApplicationAPI)(nil).GetByCustomerId), ctx, customerId)
+}
+
+// Withdraw mocks base method.
+func (m *MockOffboardingApplicationAPI) Withdraw(ctx context.Context, id uuid.UUID, decision client0.Decision) (client0.MortgageApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, id, decision)
+	ret0, _ := ret[0].(client0.MortgageApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockOffboardingApplicationAPIMockRecorder) Withdraw(ctx, id, decision any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockOffboardingApplicationAPI)(nil).Withdraw), ctx, id, decision)
+}
+
+// MockOffboardingServicingAPI is a mock of OffboardingServicingAPI interface.
+type MockOffboardingServicingAPI struct {
+	ctrl     *gomock.Controller
+	recorder *MockOffboardingServicingAPIMockRecorder
+	isgomock struct{}
+}
+
+// MockOffboardingServicingAPIMockRecorder is the mock recorder for MockOffboardingServicingAPI.
+type MockOffboardingServicingAPIMockRecorder struct {
+	mock *MockOffboardingServicingAPI
+}
+
+// NewMockOffboardingServicingAPI creates a new mock instance.
+func NewMockOffboardingServicingAPI(ctrl *gomock.Controller) *MockOffboardingServicingAPI {
+	mock := &MockOffboardingServicingAPI{ctrl: ctrl}
+	mock.recorder = &MockOffboardingServicingAPIMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOffboardingServicingAPI) EXPECT() *MockOffboardingServicingAPIMockRecorder {
+	return m.recorder
+}
+
+// ListByCustomer mocks base method.
+func (m *MockOffboardingServicingAPI) ListByCustomer(ctx context.Context, customerId uuid.UUID, filter client1.LoanFilter) ([]client1.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByCustomer", ctx, customerId, filter)
+	ret0, _ := ret[0].([]client1.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByCustomer indicates an expected call of ListByCustomer.
+func (mr *MockOffboardingServicingAPIMockRecorder) ListByCustomer(ctx, customerId, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByCustomer", reflect.TypeOf((*MockOffboardingServicingAPI)(nil).ListByCustomer), ctx, customerId, filter)
+}
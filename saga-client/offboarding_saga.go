@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	customers "service1/api/pkg/client"
+	applictions "service2/api/pkg/client"
+	servicing "service3/api/pkg/client"
+)
+
+// OffboardingSagaName identifies the customer offboarding saga in persisted state
+const OffboardingSagaName = "customer-offboarding"
+
+// OffboardingSagaData holds the shared data context for the offboarding saga
+type OffboardingSagaData struct {
+	// Input fields
+	CustomerID  uuid.UUID
+	RequestedBy string
+	Reason      string `saga:"sensitive"`
+
+	// Populated by steps during execution
+	WithdrawnApplicationIDs []uuid.UUID // Set by WithdrawApplications step
+	Anonymized              bool
+}
+
+// OffboardingRequest is the input of a customer offboarding saga
+type OffboardingRequest struct {
+	CustomerID  uuid.UUID `json:"customer_id"`
+	RequestedBy string    `json:"requested_by"`
+	Reason      string    `json:"reason"`
+}
+
+// OffboardingResult is what an offboarding saga has done so far
+type OffboardingResult struct {
+	CustomerID              uuid.UUID   `json:"customer_id"`
+	WithdrawnApplicationIDs []uuid.UUID `json:"withdrawn_application_ids,omitempty"`
+	Anonymized              bool        `json:"anonymized"`
+}
+
+// OffboardingSaga withdraws a customer's open applications and anonymizes them once
+// they hold no active loan. Neither can be undone, so the steps have nothing to
+// compensate: the loan check runs first so a customer who cannot leave is turned
+// away before anything changes, and a failed saga is resumed rather than rolled back.
+type OffboardingSaga struct {
+	customersClient    OffboardingCustomerAPI
+	applicationsClient OffboardingApplicationAPI
+	loansClient        OffboardingServicingAPI
+	alerter            saga.Alerter
+	stateStore         saga.StateStore
+}
+
+func NewOffboardingSaga(customers OffboardingCustomerAPI, applications OffboardingApplicationAPI, loans OffboardingServicingAPI) *OffboardingSaga {
+	return &OffboardingSaga{
+		customersClient:    customers,
+		applicationsClient: applications,
+		loansClient:        loans,
+	}
+}
+
+// WithAlerter sets the alerter used by every saga this orchestrator runs
+func (s *OffboardingSaga) WithAlerter(alerter saga.Alerter) *OffboardingSaga {
+	s.alerter = alerter
+	return s
+}
+
+// WithStateStore sets the store used to persist and resume sagas
+func (s *OffboardingSaga) WithStateStore(store saga.StateStore) *OffboardingSaga {
+	s.stateStore = store
+	return s
+}
+
+// Start begins offboarding for an OffboardingRequest in the background and returns
+// the saga's ID once its state is recorded
+func (s *OffboardingSaga) Start(ctx context.Context, input json.RawMessage) (id uuid.UUID, done <-chan error, err error) {
+	request, err := decodeInput[OffboardingRequest](input)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	request.RequestedBy = strings.TrimSpace(request.RequestedBy)
+	if details := request.validate(); len(details) > 0 {
+		return uuid.Nil, nil, details
+	}
+
+	offboarding := s.newSaga(&OffboardingSagaData{
+		CustomerID:  request.CustomerID,
+		RequestedBy: request.RequestedBy,
+		Reason:      request.Reason,
+	})
+	done, err = offboarding.Start(ctx)
+	return offboarding.ID, done, err
+}
+
+// Resume continues an offboarding saga persisted in the state store
+func (s *OffboardingSaga) Resume(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&OffboardingSagaData{}).Resume(ctx, sagaID)
+}
+
+// Compensate rolls back an offboarding saga persisted in the state store
+func (s *OffboardingSaga) Compensate(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&OffboardingSagaData{}).Compensate(ctx, sagaID)
+}
+
+// Result returns the applications an offboarding withdrew and whether the customer
+// was anonymized
+func (s *OffboardingSaga) Result(data json.RawMessage) (any, error) {
+	var offboarding OffboardingSagaData
+	if err := json.Unmarshal(data, &offboarding); err != nil {
+		return nil, err
+	}
+	return OffboardingResult{
+		CustomerID:              offboarding.CustomerID,
+		WithdrawnApplicationIDs: offboarding.WithdrawnApplicationIDs,
+		Anonymized:              offboarding.Anonymized,
+	}, nil
+}
+
+// validate returns why each rejected field of the request was rejected
+func (r *OffboardingRequest) validate() ValidationError {
+	details := make(ValidationError)
+	if r.CustomerID == uuid.Nil {
+		details["customer_id"] = "is required"
+	}
+	if r.RequestedBy == "" {
+		details["requested_by"] = "is required"
+	}
+	return details
+}
+
+// newSaga builds the customer offboarding saga definition around data
+func (s *OffboardingSaga) newSaga(data *OffboardingSagaData) *saga.Saga[OffboardingSagaData] {
+	return newSaga(OffboardingSagaName, data, s.alerter, s.stateStore).
+		AddStep(
+			"CheckLoans",
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				loans, err := s.loansClient.ListByCustomer(ctx, data.CustomerID, servicing.LoanFilter{Status: "active"})
+				if err != nil {
+					return fmt.Errorf("failed to list loans: %w", err)
+				}
+				if len(loans) > 0 {
+					return fmt.Errorf("customer has %d active loan(s), which must be paid off first", len(loans))
+				}
+				return nil
+			},
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				return nil // Read-only check, nothing to compensate
+			},
+		).
+		AddStep(
+			"WithdrawApplications",
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				applications, err := s.applicationsClient.GetByCustomerId(ctx, data.CustomerID)
+				if err != nil {
+					return fmt.Errorf("failed to list applications: %w", err)
+				}
+				for _, application := range applications {
+					if application.Status != "pending" && application.Status != "approved" {
+						continue
+					}
+					_, err := s.applicationsClient.Withdraw(ctx, application.Id, applictions.Decision{
+						DecidedBy: data.RequestedBy,
+						Reason:    "customer offboarding",
+					})
+					if errors.Is(err, applictions.ErrConflict) {
+						// Decided since it was listed, so it is no longer open
+						continue
+					}
+					if err != nil {
+						return fmt.Errorf("failed to withdraw application %s: %w", application.Id, err)
+					}
+					data.WithdrawnApplicationIDs = append(data.WithdrawnApplicationIDs, application.Id)
+				}
+				return nil
+			},
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				return nil // A withdrawal is final, there is no reopening an application
+			},
+		).
+		AddStep(
+			"AnonymizeCustomer",
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				_, err := s.customersClient.Anonymize(ctx, data.CustomerID, customers.AnonymizationRequest{
+					RequestedBy: data.RequestedBy,
+					Reason:      data.Reason,
+				})
+				// A conflict means a resumed saga anonymized the customer already
+				if err != nil && !errors.Is(err, customers.ErrConflict) {
+					return fmt.Errorf("failed to anonymize customer: %w", err)
+				}
+				data.Anonymized = true
+				return nil
+			},
+			func(ctx context.Context, data *OffboardingSagaData) error {
+				return nil // The erased details are gone, nothing to restore
+			},
+		)
+}
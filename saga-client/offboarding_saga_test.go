@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"go.uber.org/mock/gomock"
+	"saga-client/mocks"
+	customers "service1/api/pkg/client"
+	applictions "service2/api/pkg/client"
+	servicing "service3/api/pkg/client"
+)
+
+// offboardingMocks are the clients an OffboardingSaga under test is built on
+type offboardingMocks struct {
+	customers    *mocks.MockOffboardingCustomerAPI
+	applications *mocks.MockOffboardingApplicationAPI
+	loans        *mocks.MockOffboardingServicingAPI
+}
+
+func newOffboardingSagaMocks(t *testing.T) (*OffboardingSaga, offboardingMocks) {
+	ctrl := gomock.NewController(t)
+	m := offboardingMocks{
+		customers:    mocks.NewMockOffboardingCustomerAPI(ctrl),
+		applications: mocks.NewMockOffboardingApplicationAPI(ctrl),
+		loans:        mocks.NewMockOffboardingServicingAPI(ctrl),
+	}
+	offboarding := NewOffboardingSaga(m.customers, m.applications, m.loans).WithStateStore(saga.NewInMemoryStateStore())
+	return offboarding, m
+}
+
+func TestOffboardingSaga_RefusesCustomerWithActiveLoan(t *testing.T) {
+	offboarding, m := newOffboardingSagaMocks(t)
+	customerId := uuid.New()
+	m.loans.EXPECT().ListByCustomer(gomock.Any(), customerId, servicing.LoanFilter{Status: "active"}).
+		Return([]servicing.Loan{{Id: uuid.New(), Status: "active"}}, nil)
+
+	_, done, err := offboarding.Start(context.Background(),
+		[]byte(`{"customer_id":"`+customerId.String()+`","requested_by":"support"}`))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "active loan") {
+		t.Errorf("Expected the offboarding to be refused, got %v", err)
+	}
+}
+
+func TestOffboardingSaga_WithdrawsOpenApplicationsAndAnonymizes(t *testing.T) {
+	offboarding, m := newOffboardingSagaMocks(t)
+	customerId := uuid.New()
+	pending, approved, raced, rejected := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	decision := applictions.Decision{DecidedBy: "support", Reason: "customer offboarding"}
+	m.loans.EXPECT().ListByCustomer(gomock.Any(), customerId, gomock.Any()).Return(nil, nil)
+	m.applications.EXPECT().GetByCustomerId(gomock.Any(), customerId).Return([]applictions.MortgageApplication{
+		{Id: pending, Status: "pending"},
+		{Id: approved, Status: "approved"},
+		{Id: raced, Status: "pending"},
+		{Id: rejected, Status: "rejected"},
+	}, nil)
+	m.applications.EXPECT().Withdraw(gomock.Any(), pending, decision).Return(applictions.MortgageApplication{}, nil)
+	m.applications.EXPECT().Withdraw(gomock.Any(), approved, decision).Return(applictions.MortgageApplication{}, nil)
+	m.applications.EXPECT().Withdraw(gomock.Any(), raced, decision).
+		Return(applictions.MortgageApplication{}, &applictions.APIError{StatusCode: http.StatusConflict, Code: "conflict"})
+	m.customers.EXPECT().
+		Anonymize(gomock.Any(), customerId, customers.AnonymizationRequest{RequestedBy: "support", Reason: "closed account"}).
+		Return(customers.Customer{}, &customers.APIError{StatusCode: http.StatusConflict, Code: "conflict"})
+
+	id, done, err := offboarding.Start(context.Background(),
+		[]byte(`{"customer_id":"`+customerId.String()+`","requested_by":" support ","reason":"closed account"}`))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected the offboarding to complete, got %v", err)
+	}
+	state, err := offboarding.stateStore.Load(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := offboarding.Result(state.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := result.(OffboardingResult)
+	if !got.Anonymized || !reflect.DeepEqual(got.WithdrawnApplicationIDs, []uuid.UUID{pending, approved}) {
+		t.Errorf("Unexpected result %+v", got)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/shopspring/decimal"
+	servicing "service3/api/pkg/client"
+)
+
+// PaymentSagaName identifies the payment processing saga in persisted state
+const PaymentSagaName = "payment-processing"
+
+// PaymentSagaData holds the shared data context for the payment saga
+type PaymentSagaData struct {
+	// Input fields
+	LoanID          uuid.UUID
+	PaymentAmount   decimal.Decimal
+	PrincipalAmount decimal.Decimal
+	InterestAmount  decimal.Decimal
+	EscrowAmount    decimal.Decimal
+	PaymentDate     time.Time
+	PaymentType     string
+
+	// Populated by steps during execution
+	CustomerID *uuid.UUID // Set by CheckLoan step
+	PaymentID  *uuid.UUID
+}
+
+// PaymentRequest is the input of a payment processing saga
+type PaymentRequest struct {
+	LoanID          uuid.UUID       `json:"loan_id"`
+	PaymentAmount   decimal.Decimal `json:"payment_amount"`
+	PrincipalAmount decimal.Decimal `json:"principal_amount"`
+	InterestAmount  decimal.Decimal `json:"interest_amount"`
+	EscrowAmount    decimal.Decimal `json:"escrow_amount"`
+	PaymentDate     time.Time       `json:"payment_date"`
+	PaymentType     string          `json:"payment_type"`
+}
+
+// PaymentResult is what a payment saga has recorded so far
+type PaymentResult struct {
+	LoanID     uuid.UUID  `json:"loan_id"`
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	PaymentID  *uuid.UUID `json:"payment_id,omitempty"`
+}
+
+// PaymentSaga records a payment against an active loan. The payment is reversed if
+// the saga is rolled back after recording it, e.g. by a step added after it.
+type PaymentSaga struct {
+	loansClient    ServicingAPI
+	paymentsClient PaymentAPI
+	alerter        saga.Alerter
+	stateStore     saga.StateStore
+}
+
+func NewPaymentSaga(loans ServicingAPI, payments PaymentAPI) *PaymentSaga {
+	return &PaymentSaga{
+		loansClient:    loans,
+		paymentsClient: payments,
+	}
+}
+
+// WithAlerter sets the alerter used by every saga this orchestrator runs
+func (s *PaymentSaga) WithAlerter(alerter saga.Alerter) *PaymentSaga {
+	s.alerter = alerter
+	return s
+}
+
+// WithStateStore sets the store used to persist and resume sagas
+func (s *PaymentSaga) WithStateStore(store saga.StateStore) *PaymentSaga {
+	s.stateStore = store
+	return s
+}
+
+// Start begins processing a PaymentRequest in the background and returns the saga's
+// ID once its state is recorded
+func (s *PaymentSaga) Start(ctx context.Context, input json.RawMessage) (id uuid.UUID, done <-chan error, err error) {
+	request, err := decodeInput[PaymentRequest](input)
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	if request.PaymentDate.IsZero() {
+		request.PaymentDate = time.Now().UTC()
+	}
+	if request.PaymentType == "" {
+		request.PaymentType = "regular"
+	}
+	if details := request.validate(); len(details) > 0 {
+		return uuid.Nil, nil, details
+	}
+
+	payment := s.newSaga(&PaymentSagaData{
+		LoanID:          request.LoanID,
+		PaymentAmount:   request.PaymentAmount,
+		PrincipalAmount: request.PrincipalAmount,
+		InterestAmount:  request.InterestAmount,
+		EscrowAmount:    request.EscrowAmount,
+		PaymentDate:     request.PaymentDate,
+		PaymentType:     request.PaymentType,
+	})
+	done, err = payment.Start(ctx)
+	return payment.ID, done, err
+}
+
+// Resume continues a payment saga persisted in the state store
+func (s *PaymentSaga) Resume(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&PaymentSagaData{}).Resume(ctx, sagaID)
+}
+
+// Compensate rolls back a payment saga persisted in the state store
+func (s *PaymentSaga) Compensate(ctx context.Context, sagaID uuid.UUID) error {
+	return s.newSaga(&PaymentSagaData{}).Compensate(ctx, sagaID)
+}
+
+// Result returns the loan, customer and payment a payment saga touched
+func (s *PaymentSaga) Result(data json.RawMessage) (any, error) {
+	var payment PaymentSagaData
+	if err := json.Unmarshal(data, &payment); err != nil {
+		return nil, err
+	}
+	return PaymentResult{
+		LoanID:     payment.LoanID,
+		CustomerID: payment.CustomerID,
+		PaymentID:  payment.PaymentID,
+	}, nil
+}
+
+// validate returns why each rejected field of the request was rejected
+func (r *PaymentRequest) validate() ValidationError {
+	details := make(ValidationError)
+	if r.LoanID == uuid.Nil {
+		details["loan_id"] = "is required"
+	}
+	if !r.PaymentAmount.IsPositive() {
+		details["payment_amount"] = "must be greater than 0"
+	}
+	for field, amount := range map[string]decimal.Decimal{
+		"principal_amount": r.PrincipalAmount,
+		"interest_amount":  r.InterestAmount,
+		"escrow_amount":    r.EscrowAmount,
+	} {
+		if amount.IsNegative() {
+			details[field] = "must not be negative"
+		}
+	}
+	switch r.PaymentType {
+	case "regular", "extra", "payoff":
+	default:
+		details["payment_type"] = "must be one of regular, extra, payoff"
+	}
+	return details
+}
+
+// newSaga builds the payment processing saga definition around data
+func (s *PaymentSaga) newSaga(data *PaymentSagaData) *saga.Saga[PaymentSagaData] {
+	return newSaga(PaymentSagaName, data, s.alerter, s.stateStore).
+		AddStep(
+			"CheckLoan",
+			func(ctx context.Context, data *PaymentSagaData) error {
+				loan, err := s.loansClient.Get(ctx, data.LoanID)
+				if err != nil {
+					return fmt.Errorf("failed to read loan: %w", err)
+				}
+				if loan.Status != "active" {
+					return fmt.Errorf("loan status is %q, must be \"active\" to take payments", loan.Status)
+				}
+				data.CustomerID = &loan.CustomerId
+				return nil
+			},
+			func(ctx context.Context, data *PaymentSagaData) error {
+				return nil // Read-only check, nothing to compensate
+			},
+		).
+		AddStep(
+			"RecordPayment",
+			func(ctx context.Context, data *PaymentSagaData) error {
+				payment, err := s.paymentsClient.Create(ctx, data.LoanID, *data.CustomerID, data.PaymentAmount,
+					data.PrincipalAmount, data.InterestAmount, data.EscrowAmount, data.PaymentDate, data.PaymentType)
+				if err != nil {
+					return fmt.Errorf("failed to record payment: %w", err)
+				}
+				data.PaymentID = &payment.Id
+				return nil
+			},
+			func(ctx context.Context, data *PaymentSagaData) error {
+				// Compensation: reverse rather than delete the payment so the loan's
+				// history shows both
+				if data.PaymentID == nil {
+					return nil
+				}
+				_, err := s.paymentsClient.Reverse(ctx, *data.PaymentID)
+				if errors.Is(err, servicing.ErrNotFound) {
+					return nil
+				}
+				return err
+			},
+		)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/shopspring/decimal"
+	"go.uber.org/mock/gomock"
+	"saga-client/mocks"
+	servicing "service3/api/pkg/client"
+)
+
+func newPaymentSagaMocks(t *testing.T) (*PaymentSaga, *mocks.MockServicingAPI, *mocks.MockPaymentAPI) {
+	ctrl := gomock.NewController(t)
+	loans, payments := mocks.NewMockServicingAPI(ctrl), mocks.NewMockPaymentAPI(ctrl)
+	return NewPaymentSaga(loans, payments).WithStateStore(saga.NewInMemoryStateStore()), loans, payments
+}
+
+func TestPaymentSaga_RecordsPaymentAgainstActiveLoan(t *testing.T) {
+	payment, loans, payments := newPaymentSagaMocks(t)
+	loan := servicing.Loan{Id: uuid.New(), CustomerId: uuid.New(), Status: "active"}
+	paymentId := uuid.New()
+	gomock.InOrder(
+		loans.EXPECT().Get(gomock.Any(), loan.Id).Return(loan, nil),
+		payments.EXPECT().
+			Create(gomock.Any(), loan.Id, loan.CustomerId, decimal.RequireFromString("1500"), decimal.Decimal{},
+				decimal.Decimal{}, decimal.Decimal{}, gomock.Any(), "regular").
+			Return(servicing.Payment{Id: paymentId}, nil),
+	)
+
+	id, done, err := payment.Start(context.Background(), []byte(`{"loan_id":"`+loan.Id.String()+`","payment_amount":"1500"}`))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Expected the payment to be recorded, got %v", err)
+	}
+	state, err := payment.stateStore.Load(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := payment.Result(state.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.(PaymentResult); got.PaymentID == nil || *got.PaymentID != paymentId || *got.CustomerID != loan.CustomerId {
+		t.Errorf("Unexpected result %+v", got)
+	}
+}
+
+func TestPaymentSaga_RejectsInactiveLoan(t *testing.T) {
+	payment, loans, _ := newPaymentSagaMocks(t)
+	loanId := uuid.New()
+	loans.EXPECT().Get(gomock.Any(), loanId).Return(servicing.Loan{Id: loanId, Status: "cancelled"}, nil)
+
+	_, done, err := payment.Start(context.Background(), []byte(`{"loan_id":"`+loanId.String()+`","payment_amount":"10"}`))
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := <-done; err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("Expected the cancelled loan to be rejected, got %v", err)
+	}
+}
+
+func TestPaymentSaga_RejectsInvalidRequest(t *testing.T) {
+	payment, _, _ := newPaymentSagaMocks(t)
+
+	_, _, err := payment.Start(context.Background(), []byte(`{"payment_amount":"0","escrow_amount":"-1","payment_type":"reversal"}`))
+	var details ValidationError
+	if !errors.As(err, &details) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	for _, field := range []string{"loan_id", "payment_amount", "escrow_amount", "payment_type"} {
+		if _, ok := details[field]; !ok {
+			t.Errorf("Expected %s to be rejected, got %v", field, details)
+		}
+	}
+}
+
+func TestPaymentSaga_CompensationReversesPayment(t *testing.T) {
+	payment, _, payments := newPaymentSagaMocks(t)
+	paymentId := uuid.New()
+	gomock.InOrder(
+		payments.EXPECT().Reverse(gomock.Any(), paymentId).Return(servicing.Payment{PaymentType: "reversal"}, nil),
+		payments.EXPECT().Reverse(gomock.Any(), paymentId).
+			Return(servicing.Payment{}, &servicing.APIError{StatusCode: http.StatusNotFound, Code: "not_found"}),
+	)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		data := &PaymentSagaData{PaymentID: &paymentId, PaymentDate: time.Now()}
+		for _, step := range payment.newSaga(data).Steps {
+			if step.Name != "RecordPayment" {
+				continue
+			}
+			if err := step.Compensate(context.Background(), data); err != nil {
+				t.Errorf("Expected the payment to be reversed, got %v", err)
+			}
+		}
+	}
+}
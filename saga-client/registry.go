@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+// SagaDefinition is a kind of saga the client runs, registered under its name
+type SagaDefinition interface {
+	// Start decodes and validates input and begins a saga for it in the background,
+	// returning the saga's ID once its state is recorded; done receives the saga's
+	// result when it finishes. Input that fails validation is a ValidationError.
+	Start(ctx context.Context, input json.RawMessage) (id uuid.UUID, done <-chan error, err error)
+	// Resume continues the persisted saga with id
+	Resume(ctx context.Context, id uuid.UUID) error
+	// Compensate rolls back the steps the persisted saga with id executed
+	Compensate(ctx context.Context, id uuid.UUID) error
+	// Result returns what the saga has done so far, from its persisted data, without
+	// the personal details it may carry
+	Result(data json.RawMessage) (any, error)
+}
+
+var (
+	_ SagaDefinition = (*CustomersSaga)(nil)
+	_ SagaDefinition = (*PaymentSaga)(nil)
+	_ SagaDefinition = (*OffboardingSaga)(nil)
+)
+
+// ValidationError lists why each rejected field of a saga's input was rejected
+type ValidationError map[string]string
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("validation failed for %d field(s)", len(e))
+}
+
+// SagaRegistry holds the saga definitions by name, so a request or a resume picks
+// the definition to run
+type SagaRegistry struct {
+	definitions map[string]SagaDefinition
+}
+
+func NewSagaRegistry() *SagaRegistry {
+	return &SagaRegistry{definitions: make(map[string]SagaDefinition)}
+}
+
+// Register adds definition under name, which must be the name it records in the
+// saga state; a second definition under the same name replaces the first
+func (r *SagaRegistry) Register(name string, definition SagaDefinition) *SagaRegistry {
+	r.definitions[name] = definition
+	return r
+}
+
+// Lookup returns the definition registered under name
+func (r *SagaRegistry) Lookup(name string) (SagaDefinition, bool) {
+	definition, ok := r.definitions[name]
+	return definition, ok
+}
+
+// Names returns the registered names in order
+func (r *SagaRegistry) Names() []string {
+	names := make([]string, 0, len(r.definitions))
+	for name := range r.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resume continues the saga with id persisted in store, with the definition it was
+// started from
+func (r *SagaRegistry) Resume(ctx context.Context, store saga.StateStore, id uuid.UUID) error {
+	definition, err := r.definitionOf(ctx, store, id)
+	if err != nil {
+		return err
+	}
+	return definition.Resume(ctx, id)
+}
+
+// Compensate rolls back the saga with id persisted in store, with the definition it
+// was started from
+func (r *SagaRegistry) Compensate(ctx context.Context, store saga.StateStore, id uuid.UUID) error {
+	definition, err := r.definitionOf(ctx, store, id)
+	if err != nil {
+		return err
+	}
+	return definition.Compensate(ctx, id)
+}
+
+// definitionOf returns the definition the saga with id persisted in store was
+// started from
+func (r *SagaRegistry) definitionOf(ctx context.Context, store saga.StateStore, id uuid.UUID) (SagaDefinition, error) {
+	state, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saga state: %w", err)
+	}
+	definition, ok := r.Lookup(state.Name)
+	if !ok {
+		return nil, fmt.Errorf("saga %s: no saga named %q is registered", id, state.Name)
+	}
+	return definition, nil
+}
+
+// statusOf reports state with the result its definition reads from its data
+func (r *SagaRegistry) statusOf(state *saga.State) (SagaStatus, error) {
+	status := SagaStatus{
+		ID:          state.ID,
+		Name:        state.Name,
+		Status:      state.Status,
+		CurrentStep: state.CurrentStep,
+		Error:       state.Error,
+		CreatedAt:   state.CreatedAt,
+		UpdatedAt:   state.UpdatedAt,
+	}
+	if definition, ok := r.Lookup(state.Name); ok && len(state.Data) > 0 {
+		result, err := definition.Result(state.Data)
+		if err != nil {
+			return SagaStatus{}, err
+		}
+		status.Result = result
+	}
+	return status, nil
+}
+
+// newSaga builds a saga named name around data the way this client runs all of them:
+// each compensation is retried with backoff and every executed step is compensated
+// even when one of them fails
+func newSaga[T any](name string, data *T, alerter saga.Alerter, store saga.StateStore) *saga.Saga[T] {
+	retryConfig := saga.DefaultRetryConfig()
+	retryConfig.MaxRetries = 3
+	retryConfig.InitialBackoff = 2 * time.Second
+
+	return saga.New(data).
+		WithName(name).
+		WithCompensationStrategy(saga.NewContinueAllStrategy[T](retryConfig)).
+		WithAlerter(alerter).
+		WithStateStore(store)
+}
+
+// decodeInput unmarshals a saga's input into a new T
+func decodeInput[T any](input json.RawMessage) (*T, error) {
+	value := new(T)
+	if err := json.Unmarshal(input, value); err != nil {
+		return nil, ValidationError{"body": "must be a JSON object: " + err.Error()}
+	}
+	return value, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+// recordingDefinition is a SagaDefinition that records the sagas it resumes and
+// compensates
+type recordingDefinition struct {
+	resumed     []uuid.UUID
+	compensated []uuid.UUID
+}
+
+func (d *recordingDefinition) Start(ctx context.Context, input json.RawMessage) (uuid.UUID, <-chan error, error) {
+	return uuid.Nil, nil, errors.New("not implemented")
+}
+
+func (d *recordingDefinition) Resume(ctx context.Context, id uuid.UUID) error {
+	d.resumed = append(d.resumed, id)
+	return nil
+}
+
+func (d *recordingDefinition) Compensate(ctx context.Context, id uuid.UUID) error {
+	d.compensated = append(d.compensated, id)
+	return nil
+}
+
+func (d *recordingDefinition) Result(data json.RawMessage) (any, error) {
+	return nil, nil
+}
+
+func TestSagaRegistry_ResumeDispatchesByName(t *testing.T) {
+	ctx := context.Background()
+	store := saga.NewInMemoryStateStore()
+	onboarding, payment := &recordingDefinition{}, &recordingDefinition{}
+	registry := NewSagaRegistry().
+		Register(CustomerOnboardingSagaName, onboarding).
+		Register(PaymentSagaName, payment)
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{CustomerOnboardingSagaName, PaymentSagaName}) {
+		t.Errorf("Expected the names in order, got %v", names)
+	}
+
+	id := uuid.New()
+	if err := store.Save(ctx, &saga.State{ID: id, Name: PaymentSagaName, Status: saga.StatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Resume(ctx, store, id); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if len(onboarding.resumed) != 0 || !reflect.DeepEqual(payment.resumed, []uuid.UUID{id}) {
+		t.Errorf("Expected only the payment saga to be resumed, got onboarding %v, payment %v", onboarding.resumed, payment.resumed)
+	}
+}
+
+func TestSagaRegistry_ResumeUnregisteredName(t *testing.T) {
+	ctx := context.Background()
+	store := saga.NewInMemoryStateStore()
+	id := uuid.New()
+	if err := store.Save(ctx, &saga.State{ID: id, Name: "legacy-saga", Status: saga.StatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := NewSagaRegistry().Resume(ctx, store, id)
+	if err == nil || !strings.Contains(err.Error(), "legacy-saga") {
+		t.Errorf("Expected an error naming the unregistered saga, got %v", err)
+	}
+	if err := NewSagaRegistry().Resume(ctx, store, uuid.New()); !errors.Is(err, saga.ErrStateNotFound) {
+		t.Errorf("Expected ErrStateNotFound for an unknown ID, got %v", err)
+	}
+}
@@ -1,80 +0,0 @@
-package main
-
-import (
-	"context"
-	"fmt"
-	"log"
-)
-
-// SagaStep represents a single step in the saga with execute and compensate functions
-type SagaStep[T any] struct {
-	Name       string
-	Execute    func(ctx context.Context, data *T) error
-	Compensate func(ctx context.Context, data *T) error
-}
-
-// Saga represents the saga orchestrator
-type Saga[T any] struct {
-	Steps                []*SagaStep[T]
-	Data                 *T
-	logger               *log.Logger
-	compensationStrategy CompensationStrategy[T]
-}
-
-// NewSaga creates a new saga instance with default FailFast strategy
-func NewSaga[T any](data *T) *Saga[T] {
-	return &Saga[T]{
-		Steps:                make([]*SagaStep[T], 0),
-		Data:                 data,
-		logger:               log.Default(),
-		compensationStrategy: NewFailFastStrategy[T](),
-	}
-}
-
-// NewSagaWithLogger creates a new saga instance with a custom logger and default FailFast strategy
-func NewSagaWithLogger[T any](data *T, logger *log.Logger) *Saga[T] {
-	return &Saga[T]{
-		Steps:                make([]*SagaStep[T], 0),
-		Data:                 data,
-		logger:               logger,
-		compensationStrategy: NewFailFastStrategy[T](),
-	}
-}
-
-// WithCompensationStrategy sets the compensation strategy for the saga (fluent API)
-func (s *Saga[T]) WithCompensationStrategy(strategy CompensationStrategy[T]) *Saga[T] {
-	s.compensationStrategy = strategy
-	return s
-}
-
-// AddStep adds a step to the saga
-func (s *Saga[T]) AddStep(name string, execute, compensate func(ctx context.Context, data *T) error) *Saga[T] {
-	step := &SagaStep[T]{
-		Name:       name,
-		Execute:    execute,
-		Compensate: compensate,
-	}
-	s.Steps = append(s.Steps, step)
-	return s
-}
-
-// Execute runs the saga
-func (s *Saga[T]) Execute(ctx context.Context) error {
-	for i, step := range s.Steps {
-		if err := step.Execute(ctx, s.Data); err != nil {
-			s.logger.Printf("Step %s failed: %v", step.Name, err)
-			if compErr := s.compensate(ctx, i); compErr != nil {
-				return fmt.Errorf("execution failed: %w, compensation failed: %w", err, compErr)
-			}
-			return fmt.Errorf("saga failed and rolled back: %w", err)
-		}
-		s.logger.Printf("Executed: %s", step.Name)
-	}
-	return nil
-}
-
-// compensate runs compensation for executed steps using the configured strategy
-func (s *Saga[T]) compensate(ctx context.Context, failedStepIndex int) error {
-	// Directly use the typed strategy - no conversion needed!
-	return s.compensationStrategy.Compensate(ctx, s.Steps, failedStepIndex, s.Data, s.logger)
-}
\ No newline at end of file
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+// headerTenant names the tenant a request acts for, as it does for the services
+const headerTenant = "X-Tenant-ID"
+
+// maxRequestBody bounds a request's body, a saga's input, so callers cannot make the
+// server buffer without limit; larger bodies get a 413
+const maxRequestBody = "1M"
+
+// SagaStatus is a saga's progress as the API reports it. The saga's data is left out
+// since it may hold personal details; Result holds what its definition reports of
+// it instead, e.g. the IDs of the records the saga created so far.
+type SagaStatus struct {
+	ID          uuid.UUID   `json:"id"`
+	Name        string      `json:"name"`
+	Status      saga.Status `json:"status"`
+	CurrentStep int         `json:"current_step"`
+	Error       string      `json:"error,omitempty"`
+	Result      any         `json:"result,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// errorResponse is the body of a failed request, in the services' error envelope
+type errorResponse struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// SagaServer serves the saga API: POST /sagas/:name starts a saga of a registered
+// definition in the background and answers with its ID, and GET /sagas/:id reports
+// a saga's progress from the state store
+type SagaServer struct {
+	registry *SagaRegistry
+	store    saga.StateStore
+	tenant   string
+	keys     map[string]APIPrincipal
+	running  sync.WaitGroup
+}
+
+func NewSagaServer(registry *SagaRegistry, store saga.StateStore) *SagaServer {
+	return &SagaServer{registry: registry, store: store}
+}
+
+// WithDefaultTenant sets the tenant requests without an X-Tenant-ID header act for
+func (s *SagaServer) WithDefaultTenant(tenant string) *SagaServer {
+	s.tenant = tenant
+	return s
+}
+
+// WithAPIKeys requires requests to carry one of keys in X-API-Key. A key bound to a
+// tenant only acts for that tenant, whatever X-Tenant-ID the request sends.
+func (s *SagaServer) WithAPIKeys(keys map[string]APIPrincipal) *SagaServer {
+	s.keys = keys
+	return s
+}
+
+// Handler returns the HTTP handler exposing the saga API
+func (s *SagaServer) Handler() http.Handler {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = errorHandler
+	e.Use(middleware.RequestID())
+	e.Use(middleware.Recover())
+	e.Use(apiKeyAuth(s.keys))
+	e.Use(middleware.BodyLimit(maxRequestBody))
+
+	e.POST("/sagas/:name", s.Start)
+	e.GET("/sagas/:id", s.Status)
+	return e
+}
+
+// ListenAndServe serves the saga API on addr until ctx is done, then stops accepting
+// requests and gives in-flight ones up to timeout to finish. Sagas already started
+// keep running; see Wait.
+func (s *SagaServer) ListenAndServe(ctx context.Context, addr string, timeout time.Duration) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// Wait blocks until every saga the server started has finished or ctx is done. A
+// saga still running then stays RUNNING in the state store and can be resumed.
+func (s *SagaServer) Wait(ctx context.Context) error {
+	finished := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Start starts a saga of the definition named in the path with the request body as
+// its input and answers 202 with its status and a Location to poll, without waiting
+// for the saga to finish
+func (s *SagaServer) Start(c echo.Context) error {
+	definition, ok := s.registry.Lookup(c.Param("name"))
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "unknown saga, want one of "+strings.Join(s.registry.Names(), ", "))
+	}
+	tenant, err := s.tenantOf(c)
+	if err != nil {
+		return err
+	}
+	input, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	// The saga outlives the request, so it keeps the request's values but not its
+	// cancellation
+	ctx := saga.ContextWithTenant(context.WithoutCancel(c.Request().Context()), tenant)
+	id, done, err := definition.Start(ctx, input)
+	var validationErr ValidationError
+	if errors.As(err, &validationErr) {
+		return c.JSON(http.StatusUnprocessableEntity, errorResponse{
+			Code:      "validation_failed",
+			Message:   "validation failed",
+			Details:   validationErr,
+			RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		if err := <-done; err != nil {
+			log.Printf("Saga %s failed: %v", id, err)
+		}
+	}()
+
+	state, err := s.store.Load(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	status, err := s.registry.statusOf(state)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(echo.HeaderLocation, "/sagas/"+id.String())
+	return c.JSON(http.StatusAccepted, status)
+}
+
+// Status answers with the progress of the saga with the path's ID. Sagas of another
+// tenant are reported as not found.
+func (s *SagaServer) Status(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid saga ID")
+	}
+	tenant, err := s.tenantOf(c)
+	if err != nil {
+		return err
+	}
+	state, err := s.store.Load(c.Request().Context(), id)
+	if errors.Is(err, saga.ErrStateNotFound) || err == nil && state.Tenant != tenant {
+		return echo.NewHTTPError(http.StatusNotFound, "saga not found")
+	}
+	if err != nil {
+		return err
+	}
+	status, err := s.registry.statusOf(state)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+// tenantOf returns the tenant the request acts for: the one its API key is bound to,
+// else its X-Tenant-ID, else the default. A key cannot act for another tenant.
+func (s *SagaServer) tenantOf(c echo.Context) (string, error) {
+	requested := c.Request().Header.Get(headerTenant)
+	principal, _ := principalFrom(c.Request().Context())
+	switch {
+	case principal.Tenant != "" && requested != "" && requested != principal.Tenant:
+		return "", echo.NewHTTPError(http.StatusForbidden, "the credentials are not bound to tenant "+requested)
+	case principal.Tenant != "":
+		return principal.Tenant, nil
+	case requested != "":
+		return requested, nil
+	}
+	return s.tenant, nil
+}
+
+// errorHandler renders errors in the services' error envelope. Echo HTTP errors keep
+// their status; anything else is logged and reported as a 500 without leaking internals.
+func errorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, message := http.StatusInternalServerError, "internal server error"
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+		if msg, ok := httpErr.Message.(string); ok {
+			message = msg
+		} else {
+			message = http.StatusText(status)
+		}
+	} else {
+		log.Printf("Request %s %s failed: %v", c.Request().Method, c.Request().URL.Path, err)
+	}
+
+	code := strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+	if code == "" {
+		code = "error"
+	}
+	if err := c.JSON(status, errorResponse{
+		Code:      code,
+		Message:   message,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+	}); err != nil {
+		log.Printf("Unable to write error response: %v", err)
+	}
+}
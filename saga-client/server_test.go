@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/latebit-io/saga-pattern/saga"
+	"go.uber.org/mock/gomock"
+	customers "service1/api/pkg/client"
+)
+
+const onboardingBody = `{"name":"Jane","email":"jane@example.com",` +
+	`"application":{"loan_amount":"250000","property_value":"400000","interest_rate":4.5,"term_years":25}}`
+
+func newTestSagaServer(t *testing.T) (*SagaServer, sagaMocks, *saga.InMemoryStateStore) {
+	onboarding, m := newSagaMocks(t)
+	store := saga.NewInMemoryStateStore()
+	registry := NewSagaRegistry().Register(CustomerOnboardingSagaName, onboarding.WithStateStore(store))
+	return NewSagaServer(registry, store), m, store
+}
+
+func serve(server *SagaServer, method, path, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header.Set(key, values[0])
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSagaServer_StartsOnboardingAndReportsStatus(t *testing.T) {
+	server, m, _ := newTestSagaServer(t)
+	m.customers.EXPECT().Create(gomock.Any(), "Jane", "jane@example.com").
+		Return(customers.Customer{}, errors.New("customers unavailable"))
+
+	rec := serve(server, http.MethodPost, "/sagas/customer-onboarding", onboardingBody, nil)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var started SagaStatus
+	if err := json.NewDecoder(rec.Body).Decode(&started); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	if started.ID == uuid.Nil || started.Name != CustomerOnboardingSagaName {
+		t.Errorf("Unexpected saga %+v", started)
+	}
+	if location := rec.Header().Get("Location"); location != "/sagas/"+started.ID.String() {
+		t.Errorf("Expected Location /sagas/%s, got %q", started.ID, location)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Wait(ctx); err != nil {
+		t.Fatalf("Expected the saga to finish, got: %v", err)
+	}
+	rec = serve(server, http.MethodGet, "/sagas/"+started.ID.String(), "", nil)
+	var status SagaStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with the saga's status, got %d: %v", rec.Code, err)
+	}
+	if status.Status != saga.StatusCompensated || !strings.Contains(status.Error, "customers unavailable") {
+		t.Errorf("Expected the failed saga to be compensated, got %+v", status)
+	}
+	if strings.Contains(rec.Body.String(), "jane@example.com") {
+		t.Errorf("Expected the status to leave out personal details, got %s", rec.Body.String())
+	}
+}
+
+func TestSagaServer_RejectsInvalidRequest(t *testing.T) {
+	server, _, _ := newTestSagaServer(t)
+
+	rec := serve(server, http.MethodPost, "/sagas/customer-onboarding",
+		`{"name":" ","email":"jane","application":{"loan_amount":"0","term_years":25}}`, nil)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var response errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	for _, field := range []string{"name", "email", "application.loan_amount", "application.property_value", "application.interest_rate"} {
+		if _, ok := response.Details[field]; !ok {
+			t.Errorf("Expected %s to be rejected, got %v", field, response.Details)
+		}
+	}
+	if _, ok := response.Details["application.term_years"]; ok {
+		t.Errorf("Expected term_years to be accepted, got %v", response.Details)
+	}
+}
+
+func TestSagaServer_StatusOfUnknownSaga(t *testing.T) {
+	server, _, _ := newTestSagaServer(t)
+
+	rec := serve(server, http.MethodGet, "/sagas/"+uuid.NewString(), "", nil)
+	var response errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || rec.Code != http.StatusNotFound || response.Code != "not_found" {
+		t.Errorf("Expected a 404 envelope, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := serve(server, http.MethodGet, "/sagas/not-a-uuid", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid ID, got %d", rec.Code)
+	}
+}
+
+func TestSagaServer_StatusHidesOtherTenantsSagas(t *testing.T) {
+	server, _, store := newTestSagaServer(t)
+	id := uuid.New()
+	if err := store.Save(context.Background(), &saga.State{ID: id, Status: saga.StatusCompleted, Tenant: "lender-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/sagas/" + id.String()
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerTenant: {"lender-a"}}); rec.Code != http.StatusOK {
+		t.Errorf("Expected the tenant's own saga, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerTenant: {"lender-b"}}); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected another tenant's saga to be hidden, got %d", rec.Code)
+	}
+	if rec := serve(server.WithDefaultTenant("lender-a"), http.MethodGet, path, "", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected the default tenant's saga, got %d", rec.Code)
+	}
+}
+
+func TestSagaServer_UnknownSagaName(t *testing.T) {
+	server, _, _ := newTestSagaServer(t)
+
+	rec := serve(server, http.MethodPost, "/sagas/mortgage-refinancing", `{}`, nil)
+	var response errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil || rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected a 404 envelope, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(response.Message, CustomerOnboardingSagaName) {
+		t.Errorf("Expected the registered names to be listed, got %q", response.Message)
+	}
+}
+
+func TestSagaServer_RequiresAPIKey(t *testing.T) {
+	server, _, store := newTestSagaServer(t)
+	keys, err := ParseAPIKeys("dashboard:r3port:read,portal:s3cret:write:lender-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = server.WithAPIKeys(keys)
+	id := uuid.New()
+	if err := store.Save(context.Background(), &saga.State{ID: id, Status: saga.StatusCompleted, Tenant: "lender-a"}); err != nil {
+		t.Fatal(err)
+	}
+	path := "/sagas/" + id.String()
+
+	if rec := serve(server, http.MethodGet, path, "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a key, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerAPIKey: {"guess"}}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an unknown key, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodPost, "/sagas/customer-onboarding", onboardingBody, http.Header{headerAPIKey: {"r3port"}}); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a read-only key starting a saga, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerAPIKey: {"r3port"}, headerTenant: {"lender-a"}}); rec.Code != http.StatusOK {
+		t.Errorf("Expected an unbound key to act for the requested tenant, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerAPIKey: {"s3cret"}}); rec.Code != http.StatusOK {
+		t.Errorf("Expected a bound key to act for its tenant, got %d", rec.Code)
+	}
+	if rec := serve(server, http.MethodGet, path, "", http.Header{headerAPIKey: {"s3cret"}, headerTenant: {"lender-b"}}); rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for a bound key naming another tenant, got %d", rec.Code)
+	}
+}
+
+func TestSagaServer_RejectsOversizedInput(t *testing.T) {
+	server, _, _ := newTestSagaServer(t)
+
+	body := `{"name":"` + strings.Repeat("J", 2<<20) + `"}`
+	if rec := serve(server, http.MethodPost, "/sagas/customer-onboarding", body, nil); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized body, got %d", rec.Code)
+	}
+}
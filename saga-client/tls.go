@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// newTLSConfig builds the TLS config for https service URLs: config's CA file is a
+// PEM bundle of the CAs to trust instead of the system ones, and its certificate and
+// key files the client certificate presented for mutual TLS. It returns nil when
+// none is set, leaving the defaults.
+func newTLSConfig(config Config) (*tls.Config, error) {
+	caFile, certFile, keyFile := config.TLSCAFile, config.TLSCertFile, config.TLSKeyFile
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in SAGA_TLS_CA_FILE %s", caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("SAGA_TLS_CERT_FILE and SAGA_TLS_KEY_FILE must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// sagaUserAgent names the saga client, and its version when the binary was built
+// from a tagged module, ahead of each client's own User-Agent
+func sagaUserAgent() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return "saga-client/" + info.Main.Version
+	}
+	return "saga-client"
+}
+
+// versionMismatchLogger logs, once per version, that service answered with an API
+// version the clients were not generated against or marked it deprecated: a sign
+// the saga is running against a mixed-version deployment, e.g. mid-upgrade
+func versionMismatchLogger(service, clientVersion string) func(ctx context.Context, served string, deprecated bool) {
+	var logged sync.Map
+	return func(ctx context.Context, served string, deprecated bool) {
+		key := fmt.Sprintf("%s/%t", served, deprecated)
+		if _, seen := logged.LoadOrStore(key, true); seen {
+			return
+		}
+		if deprecated {
+			log.Printf("The %s service marked its API version %q deprecated; the client speaks %s", service, served, clientVersion)
+			return
+		}
+		log.Printf("The %s service answered with API version %q; the client speaks %s", service, served, clientVersion)
+	}
+}
@@ -0,0 +1,166 @@
+package saga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity describes how urgently an alert needs attention
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// Alerter is notified when a saga reaches a state that needs a human,
+// e.g. compensation retries are exhausted or a saga is dead-lettered
+type Alerter interface {
+	Notify(ctx context.Context, severity Severity, sagaID string, message string, details map[string]any) error
+}
+
+// Alert is the payload delivered by the webhook alerter
+type Alert struct {
+	Severity  Severity       `json:"severity"`
+	SagaID    string         `json:"saga_id"`
+	Message   string         `json:"message"`
+	Details   map[string]any `json:"details,omitempty"`
+	Timestamp time.Time      `json:"timestamp"`
+}
+
+// =====================================
+// Slack
+// =====================================
+
+// SlackAlerter posts alerts to a Slack incoming webhook
+type SlackAlerter struct {
+	webhookURL string
+	channel    string
+	httpClient *http.Client
+}
+
+func NewSlackAlerter(webhookURL, channel string) *SlackAlerter {
+	return &SlackAlerter{
+		webhookURL: webhookURL,
+		channel:    channel,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *SlackAlerter) Notify(ctx context.Context, severity Severity, sagaID string, message string, details map[string]any) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s *%s* saga `%s`: %s", severityEmoji(severity), strings.ToUpper(string(severity)), sagaID, message)
+
+	// Sort keys so the message is stable between alerts
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&text, "\n• %s: %v", k, details[k])
+	}
+
+	payload := struct {
+		Channel string `json:"channel,omitempty"`
+		Text    string `json:"text"`
+	}{
+		Channel: s.channel,
+		Text:    text.String(),
+	}
+
+	return postJSON(ctx, s.httpClient, s.webhookURL, nil, payload)
+}
+
+func severityEmoji(severity Severity) string {
+	switch severity {
+	case SeverityCritical:
+		return ":rotating_light:"
+	case SeverityWarning:
+		return ":warning:"
+	default:
+		return ":information_source:"
+	}
+}
+
+// =====================================
+// Generic Webhook
+// =====================================
+
+// WebhookAlerter posts alerts as JSON to an arbitrary HTTP endpoint
+type WebhookAlerter struct {
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewWebhookAlerter creates an alerter posting to url; headers are added to
+// every request (e.g. an Authorization header for the receiving system)
+func NewWebhookAlerter(url string, headers map[string]string) *WebhookAlerter {
+	return &WebhookAlerter{
+		url:        url,
+		headers:    headers,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *WebhookAlerter) Notify(ctx context.Context, severity Severity, sagaID string, message string, details map[string]any) error {
+	alert := Alert{
+		Severity:  severity,
+		SagaID:    sagaID,
+		Message:   message,
+		Details:   details,
+		Timestamp: time.Now().UTC(),
+	}
+	return postJSON(ctx, w.httpClient, w.url, w.headers, alert)
+}
+
+// =====================================
+// Fan-out
+// =====================================
+
+// MultiAlerter notifies every wrapped alerter, returning the first error
+type MultiAlerter []Alerter
+
+func (m MultiAlerter) Notify(ctx context.Context, severity Severity, sagaID string, message string, details map[string]any) error {
+	var firstErr error
+	for _, alerter := range m {
+		if err := alerter.Notify(ctx, severity, sagaID, message, details); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func postJSON(ctx context.Context, httpClient *http.Client, url string, headers map[string]string, payload any) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Alerter that records every notification
+type recordingAlerter struct {
+	alerts []Alert
+}
+
+func (r *recordingAlerter) Notify(ctx context.Context, severity Severity, sagaID string, message string, details map[string]any) error {
+	r.alerts = append(r.alerts, Alert{Severity: severity, SagaID: sagaID, Message: message, Details: details})
+	return nil
+}
+
+func TestSlackAlerter_PostsMessage(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("Invalid JSON payload: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	alerter := NewSlackAlerter(server.URL, "#sagas")
+	err := alerter.Notify(context.Background(), SeverityCritical, "saga-1", "compensation failed", map[string]any{"failed_step": "CreateLoan"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if body["channel"] != "#sagas" {
+		t.Errorf("Expected channel #sagas, got %q", body["channel"])
+	}
+	for _, want := range []string{"CRITICAL", "saga-1", "compensation failed", "failed_step: CreateLoan"} {
+		if !strings.Contains(body["text"], want) {
+			t.Errorf("Expected text to contain %q, got %q", want, body["text"])
+		}
+	}
+}
+
+func TestWebhookAlerter_PostsAlertWithHeaders(t *testing.T) {
+	var alert Alert
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("Invalid JSON payload: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	alerter := NewWebhookAlerter(server.URL, map[string]string{"Authorization": "Bearer secret"})
+	err := alerter.Notify(context.Background(), SeverityWarning, "saga-2", "dead-lettered", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if authHeader != "Bearer secret" {
+		t.Errorf("Expected Authorization header to be forwarded, got %q", authHeader)
+	}
+	if alert.Severity != SeverityWarning || alert.SagaID != "saga-2" || alert.Message != "dead-lettered" {
+		t.Errorf("Unexpected alert payload: %+v", alert)
+	}
+}
+
+func TestWebhookAlerter_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	alerter := NewWebhookAlerter(server.URL, nil)
+	err := alerter.Notify(context.Background(), SeverityCritical, "saga-3", "boom", nil)
+	if err == nil {
+		t.Error("Expected error for non-2xx response")
+	}
+}
+
+func TestSaga_AlertsWhenCompensationExhausted(t *testing.T) {
+	alerter := &recordingAlerter{}
+	step1 := newMockStep("Step1", 999) // Compensation always fails
+
+	config := RetryConfig{
+		MaxRetries:      1,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      time.Millisecond,
+		BackoffMultiple: 1,
+	}
+
+	data := &TestData{StepResults: make(map[string]string)}
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).
+		WithCompensationStrategy(NewContinueAllStrategy[TestData](config)).
+		WithAlerter(alerter)
+	saga.Steps = append(saga.Steps, step1.toStep())
+	saga.AddStep("Step2",
+		func(ctx context.Context, data *TestData) error { return errors.New("step2 failed") },
+		func(ctx context.Context, data *TestData) error { return nil },
+	)
+
+	if err := saga.Execute(context.Background()); err == nil {
+		t.Fatal("Expected saga to fail")
+	}
+
+	if len(alerter.alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerter.alerts))
+	}
+	alert := alerter.alerts[0]
+	if alert.Severity != SeverityCritical {
+		t.Errorf("Expected critical severity, got %s", alert.Severity)
+	}
+	if alert.SagaID != saga.ID.String() {
+		t.Errorf("Expected saga ID %s, got %s", saga.ID, alert.SagaID)
+	}
+	if alert.Details["failed_step"] != "Step2" {
+		t.Errorf("Expected failed_step Step2, got %v", alert.Details["failed_step"])
+	}
+	steps, _ := alert.Details["uncompensated_steps"].([]string)
+	if len(steps) != 1 || steps[0] != "Step1" {
+		t.Errorf("Expected uncompensated_steps [Step1], got %v", alert.Details["uncompensated_steps"])
+	}
+}
+
+func TestSaga_NoAlertWhenCompensationSucceeds(t *testing.T) {
+	alerter := &recordingAlerter{}
+	step1 := newMockStep("Step1", 0)
+
+	data := &TestData{StepResults: make(map[string]string)}
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).WithAlerter(alerter)
+	saga.Steps = append(saga.Steps, step1.toStep())
+	saga.AddStep("Step2",
+		func(ctx context.Context, data *TestData) error { return errors.New("step2 failed") },
+		func(ctx context.Context, data *TestData) error { return nil },
+	)
+
+	if err := saga.Execute(context.Background()); err == nil {
+		t.Fatal("Expected saga to fail")
+	}
+
+	if len(alerter.alerts) != 0 {
+		t.Errorf("Expected no alerts, got %d", len(alerter.alerts))
+	}
+}
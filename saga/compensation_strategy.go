@@ -1,4 +1,4 @@
-package main
+package saga
 
 import (
 	"context"
@@ -9,7 +9,7 @@ import (
 
 // CompensationStrategy defines how to handle compensation failures
 type CompensationStrategy[T any] interface {
-	Compensate(ctx context.Context, steps []*SagaStep[T], failedStepIndex int, data *T, logger *log.Logger) error
+	Compensate(ctx context.Context, steps []*Step[T], failedStepIndex int, data *T, logger *log.Logger) error
 }
 
 // CompensationResult tracks the result of compensating a single step
@@ -49,7 +49,7 @@ func NewRetryStrategy[T any](config RetryConfig) *RetryStrategy[T] {
 	return &RetryStrategy[T]{config: config}
 }
 
-func (r *RetryStrategy[T]) Compensate(ctx context.Context, steps []*SagaStep[T], failedStepIndex int, data *T, logger *log.Logger) error {
+func (r *RetryStrategy[T]) Compensate(ctx context.Context, steps []*Step[T], failedStepIndex int, data *T, logger *log.Logger) error {
 	// Compensate in reverse order
 	for i := failedStepIndex - 1; i >= 0; i-- {
 		step := steps[i]
@@ -64,7 +64,7 @@ func (r *RetryStrategy[T]) Compensate(ctx context.Context, steps []*SagaStep[T],
 	return nil
 }
 
-func (r *RetryStrategy[T]) compensateStepWithRetry(ctx context.Context, step *SagaStep[T], data *T, logger *log.Logger) error {
+func (r *RetryStrategy[T]) compensateStepWithRetry(ctx context.Context, step *Step[T], data *T, logger *log.Logger) error {
 	var lastErr error
 	backoff := r.config.InitialBackoff
 
@@ -108,7 +108,7 @@ func NewContinueAllStrategy[T any](retryConfig RetryConfig) *ContinueAllStrategy
 	return &ContinueAllStrategy[T]{retryConfig: retryConfig}
 }
 
-func (c *ContinueAllStrategy[T]) Compensate(ctx context.Context, steps []*SagaStep[T], failedStepIndex int, data *T, logger *log.Logger) error {
+func (c *ContinueAllStrategy[T]) Compensate(ctx context.Context, steps []*Step[T], failedStepIndex int, data *T, logger *log.Logger) error {
 	var compensationErrors []CompensationResult
 	retryHelper := NewRetryStrategy[T](c.retryConfig)
 
@@ -154,7 +154,7 @@ func NewFailFastStrategy[T any]() *FailFastStrategy[T] {
 	return &FailFastStrategy[T]{}
 }
 
-func (f *FailFastStrategy[T]) Compensate(ctx context.Context, steps []*SagaStep[T], failedStepIndex int, data *T, logger *log.Logger) error {
+func (f *FailFastStrategy[T]) Compensate(ctx context.Context, steps []*Step[T], failedStepIndex int, data *T, logger *log.Logger) error {
 	for i := failedStepIndex - 1; i >= 0; i-- {
 		step := steps[i]
 		if err := step.Compensate(ctx, data); err != nil {
@@ -1,4 +1,4 @@
-package main
+package saga
 
 import (
 	"context"
@@ -32,8 +32,8 @@ func newMockStep(name string, failUntilCall int) *mockStep {
 	}
 }
 
-func (m *mockStep) toSagaStep() *SagaStep[TestData] {
-	return &SagaStep[TestData]{
+func (m *mockStep) toStep() *Step[TestData] {
+	return &Step[TestData]{
 		Name: m.name,
 		Execute: func(ctx context.Context, data *TestData) error {
 			data.StepResults[m.name] = "executed"
@@ -91,9 +91,9 @@ func TestRetryStrategy_SuccessfulCompensation(t *testing.T) {
 	step1 := newMockStep("Step1", 0) // Never fails
 	step2 := newMockStep("Step2", 0) // Never fails
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -129,8 +129,8 @@ func TestRetryStrategy_EventualSuccess(t *testing.T) {
 	// Step fails twice, then succeeds
 	step1 := newMockStep("Step1", 2) // Fail first 2 attempts
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
@@ -165,8 +165,8 @@ func TestRetryStrategy_ExhaustedRetries(t *testing.T) {
 	// Step always fails
 	step1 := newMockStep("Step1", 999) // Always fails
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
@@ -203,9 +203,9 @@ func TestRetryStrategy_StopsOnFirstFailure(t *testing.T) {
 	step1 := newMockStep("Step1", 999) // Always fails
 	step2 := newMockStep("Step2", 0)   // Would succeed
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -242,8 +242,8 @@ func TestRetryStrategy_StopsOnFirstFailure(t *testing.T) {
 func TestRetryStrategy_ContextCancellation(t *testing.T) {
 	step1 := newMockStep("Step1", 999) // Always fails
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
@@ -281,9 +281,9 @@ func TestContinueAllStrategy_AllSucceed(t *testing.T) {
 	step1 := newMockStep("Step1", 0)
 	step2 := newMockStep("Step2", 0)
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -308,9 +308,9 @@ func TestContinueAllStrategy_ContinuesAfterFailure(t *testing.T) {
 	step1 := newMockStep("Step1", 999) // Always fails
 	step2 := newMockStep("Step2", 0)   // Succeeds
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -363,10 +363,10 @@ func TestContinueAllStrategy_MultipleFailures(t *testing.T) {
 	step2 := newMockStep("Step2", 999) // Always fails
 	step3 := newMockStep("Step3", 0)   // Succeeds
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
-		step3.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
+		step3.toStep(),
 	}
 
 	data := &TestData{
@@ -416,8 +416,8 @@ func TestContinueAllStrategy_MultipleFailures(t *testing.T) {
 func TestContinueAllStrategy_CompensationErrorDetails(t *testing.T) {
 	step1 := newMockStep("Step1", 999)
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
@@ -476,9 +476,9 @@ func TestFailFastStrategy_AllSucceed(t *testing.T) {
 	step1 := newMockStep("Step1", 0)
 	step2 := newMockStep("Step2", 0)
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -502,9 +502,9 @@ func TestFailFastStrategy_StopsImmediately(t *testing.T) {
 	step1 := newMockStep("Step1", 1) // Fails once
 	step2 := newMockStep("Step2", 0) // Would succeed
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
-		step2.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
+		step2.toStep(),
 	}
 
 	data := &TestData{
@@ -537,8 +537,8 @@ func TestFailFastStrategy_StopsImmediately(t *testing.T) {
 func TestFailFastStrategy_NoRetries(t *testing.T) {
 	step1 := newMockStep("Step1", 999) // Always fails
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
@@ -567,7 +567,7 @@ func TestCompensationInReverseOrder(t *testing.T) {
 	// Test that all strategies compensate in reverse order
 	executionOrder := []string{}
 
-	step1 := &SagaStep[TestData]{
+	step1 := &Step[TestData]{
 		Name:    "Step1",
 		Execute: func(ctx context.Context, data *TestData) error { return nil },
 		Compensate: func(ctx context.Context, data *TestData) error {
@@ -576,7 +576,7 @@ func TestCompensationInReverseOrder(t *testing.T) {
 		},
 	}
 
-	step2 := &SagaStep[TestData]{
+	step2 := &Step[TestData]{
 		Name:    "Step2",
 		Execute: func(ctx context.Context, data *TestData) error { return nil },
 		Compensate: func(ctx context.Context, data *TestData) error {
@@ -585,7 +585,7 @@ func TestCompensationInReverseOrder(t *testing.T) {
 		},
 	}
 
-	step3 := &SagaStep[TestData]{
+	step3 := &Step[TestData]{
 		Name:    "Step3",
 		Execute: func(ctx context.Context, data *TestData) error { return nil },
 		Compensate: func(ctx context.Context, data *TestData) error {
@@ -594,7 +594,7 @@ func TestCompensationInReverseOrder(t *testing.T) {
 		},
 	}
 
-	steps := []*SagaStep[TestData]{step1, step2, step3}
+	steps := []*Step[TestData]{step1, step2, step3}
 	data := &TestData{StepResults: make(map[string]string)}
 
 	strategies := []CompensationStrategy[TestData]{
@@ -629,8 +629,8 @@ func TestCompensationInReverseOrder(t *testing.T) {
 func TestExponentialBackoff(t *testing.T) {
 	step1 := newMockStep("Step1", 2) // Fails first 2 times
 
-	steps := []*SagaStep[TestData]{
-		step1.toSagaStep(),
+	steps := []*Step[TestData]{
+		step1.toStep(),
 	}
 
 	data := &TestData{
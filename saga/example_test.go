@@ -0,0 +1,121 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/latebit-io/saga-pattern/saga"
+)
+
+type Transfer struct {
+	Account string `saga:"sensitive"`
+	Amount  int
+	Debited bool
+}
+
+// quiet keeps the saga's progress log out of the examples' output
+var quiet = log.New(io.Discard, "", 0)
+
+func Example() {
+	data := &Transfer{Account: "ACC-1", Amount: 100}
+
+	err := saga.NewWithLogger(data, quiet).
+		AddStep("Debit",
+			func(ctx context.Context, data *Transfer) error {
+				data.Debited = true
+				fmt.Println("debited", data.Amount)
+				return nil
+			},
+			func(ctx context.Context, data *Transfer) error {
+				data.Debited = false
+				fmt.Println("refunded", data.Amount)
+				return nil
+			}).
+		AddStep("Credit",
+			func(ctx context.Context, data *Transfer) error {
+				return errors.New("account closed")
+			},
+			func(ctx context.Context, data *Transfer) error {
+				return nil
+			}).
+		Execute(context.Background())
+
+	fmt.Println(err)
+	// Output:
+	// debited 100
+	// refunded 100
+	// saga failed and rolled back: account closed
+}
+
+func ExampleNewContinueAllStrategy() {
+	retryConfig := saga.RetryConfig{
+		MaxRetries:      2,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      10 * time.Millisecond,
+		BackoffMultiple: 2,
+	}
+	noop := func(ctx context.Context, data *Transfer) error { return nil }
+	unavailable := func(ctx context.Context, data *Transfer) error { return errors.New("service unavailable") }
+
+	err := saga.NewWithLogger(&Transfer{}, quiet).
+		WithCompensationStrategy(saga.NewContinueAllStrategy[Transfer](retryConfig)).
+		AddStep("Reserve", noop, unavailable).
+		AddStep("Notify", noop, noop).
+		AddStep("Capture", unavailable, noop).
+		Execute(context.Background())
+
+	var compErr *saga.CompensationError
+	if errors.As(err, &compErr) {
+		for _, failure := range compErr.Failures {
+			fmt.Printf("%s not compensated after %d attempts\n", failure.StepName, failure.Attempts)
+		}
+	}
+	// Output:
+	// Reserve not compensated after 3 attempts
+}
+
+func ExampleSaga_Resume() {
+	store := saga.NewInMemoryStateStore()
+	steps := func(s *saga.Saga[Transfer]) *saga.Saga[Transfer] {
+		for _, name := range []string{"Debit", "Credit"} {
+			s.AddStep(name,
+				func(ctx context.Context, data *Transfer) error {
+					fmt.Println("executed", name)
+					return nil
+				},
+				func(ctx context.Context, data *Transfer) error { return nil })
+		}
+		return s
+	}
+
+	// A process that ran the first step and crashed left this state behind
+	first := steps(saga.NewWithLogger(&Transfer{Amount: 100}, quiet).WithStateStore(store))
+	_ = store.Save(context.Background(), &saga.State{
+		ID:          first.ID,
+		Status:      saga.StatusRunning,
+		CurrentStep: 1,
+		Data:        []byte(`{"Amount":100,"Debited":true}`),
+	})
+
+	// Another process continues with the step after it
+	resumed := steps(saga.NewWithLogger(&Transfer{}, quiet).WithStateStore(store))
+	if err := resumed.Resume(context.Background(), first.ID); err != nil {
+		fmt.Println(err)
+	}
+	state, _ := store.Load(context.Background(), first.ID)
+	fmt.Println(state.Status, resumed.Data.Amount)
+	// Output:
+	// executed Credit
+	// COMPLETED 100
+}
+
+func ExampleRedact() {
+	redacted := saga.Redact(Transfer{Account: "ACC-1", Amount: 100})
+	fmt.Printf("%+v\n", redacted)
+	// Output:
+	// {Account:[REDACTED] Amount:100 Debited:false}
+}
@@ -0,0 +1,23 @@
+package saga
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type idempotencyKey struct{}
+
+// ContextWithStepIdempotencyKey returns a context whose service calls carry the
+// idempotency key of step in saga id. The key stays the same when the step is
+// retried or the saga resumed, so the services can tell a repeat from a new request.
+func ContextWithStepIdempotencyKey(ctx context.Context, id uuid.UUID, step string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, id.String()+":"+step)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key of the step ctx runs, or ""
+// outside a step
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
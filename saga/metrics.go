@@ -0,0 +1,12 @@
+package saga
+
+import "expvar"
+
+// The process's saga counters, published with expvar so a debug endpoint serving
+// expvar.Handler shows them under /debug/vars
+var (
+	// sagasInFlight counts sagas currently executing (or resuming) in this process
+	sagasInFlight = expvar.NewInt("sagas_in_flight")
+	// sagaOutcomes counts finished sagas by final status
+	sagaOutcomes = expvar.NewMap("saga_outcomes")
+)
@@ -0,0 +1,18 @@
+-- Baseline: the saga_states table the store used to create on startup. The statement
+-- is idempotent so existing tables are adopted unchanged.
+
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saga_states(
+    id uuid PRIMARY KEY,
+    name varchar NOT NULL,
+    status varchar NOT NULL,
+    current_step int NOT NULL,
+    data jsonb,
+    trace_parent varchar,
+    error text,
+    created_at timestamp NOT NULL,
+    updated_at timestamp NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS saga_states;
@@ -0,0 +1,8 @@
+-- Tenancy: a saga records the tenant (lender) it acts for, so a resumed saga keeps
+-- acting for it. Sagas started before tenancy act for the default tenant.
+
+-- +goose Up
+ALTER TABLE saga_states ADD COLUMN tenant varchar;
+
+-- +goose Down
+ALTER TABLE saga_states DROP COLUMN tenant;
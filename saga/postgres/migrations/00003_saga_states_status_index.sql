@@ -0,0 +1,8 @@
+-- Listing: operators list sagas by status, e.g. the FAILED ones needing attention,
+-- most recently updated first.
+
+-- +goose Up
+CREATE INDEX IF NOT EXISTS saga_states_status_updated_at ON saga_states (status, updated_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS saga_states_status_updated_at;
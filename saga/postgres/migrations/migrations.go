@@ -0,0 +1,48 @@
+// Package migrations holds the versioned schema of the Postgres saga state store.
+// The SQL files are embedded in the binary and applied in order with goose.
+//
+// To change the schema, add the next numbered file with "-- +goose Up" and
+// "-- +goose Down" sections; never edit an applied migration.
+package migrations
+
+import (
+	"context"
+	"embed"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/pressly/goose/v3/lock"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// The store may share a database with a service, so it records its versions in its
+// own table and takes its own advisory lock
+const (
+	versionTable       = "saga_goose_db_version"
+	lockID       int64 = 4673927380925374501
+)
+
+// Up applies the migrations the database has not run yet
+func Up(ctx context.Context, pool *pgxpool.Pool) error {
+	store, err := database.NewStore(database.DialectPostgres, versionTable)
+	if err != nil {
+		return err
+	}
+	locker, err := lock.NewPostgresSessionLocker(lock.WithLockID(lockID))
+	if err != nil {
+		return err
+	}
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+
+	provider, err := goose.NewProvider("", db, files, goose.WithStore(store), goose.WithSessionLocker(locker))
+	if err != nil {
+		return err
+	}
+	_, err = provider.Up(ctx)
+	return err
+}
@@ -0,0 +1,121 @@
+// Package postgres persists saga state in Postgres, so a saga can be resumed by
+// another process after a crash or a deploy.
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/latebit-io/saga-pattern/saga"
+	"github.com/latebit-io/saga-pattern/saga/postgres/migrations"
+)
+
+// StateStore persists saga state in the saga_states table
+type StateStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewStateStore(pool *pgxpool.Pool) *StateStore {
+	return &StateStore{pool: pool}
+}
+
+// CreateSchema applies the store's migrations, creating saga_states on a new database
+func (p *StateStore) CreateSchema(ctx context.Context) error {
+	return migrations.Up(ctx, p.pool)
+}
+
+// Ping verifies the database connection is alive
+func (p *StateStore) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p *StateStore) Save(ctx context.Context, state *saga.State) error {
+	sql := `INSERT INTO saga_states
+		(id, name, status, current_step, data, trace_parent, tenant, error, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE
+		SET status = EXCLUDED.status, current_step = EXCLUDED.current_step, data = EXCLUDED.data,
+			trace_parent = EXCLUDED.trace_parent, error = EXCLUDED.error, updated_at = EXCLUDED.updated_at`
+	_, err := p.pool.Exec(ctx, sql,
+		state.ID,
+		state.Name,
+		state.Status,
+		state.CurrentStep,
+		state.Data,
+		state.TraceParent,
+		state.Tenant,
+		state.Error,
+		state.CreatedAt,
+		state.UpdatedAt,
+	)
+	return err
+}
+
+// List returns the sagas matching filter, most recently updated first
+func (p *StateStore) List(ctx context.Context, filter saga.StateFilter) ([]saga.State, error) {
+	sql := `SELECT id, name, status, current_step, data, COALESCE(trace_parent, ''), COALESCE(tenant, ''),
+		COALESCE(error, ''),
+		created_at, updated_at
+		FROM saga_states
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR name = $2)
+		ORDER BY updated_at DESC
+		LIMIT NULLIF($3, 0)`
+	rows, err := p.pool.Query(ctx, sql, string(filter.Status), filter.Name, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	states := make([]saga.State, 0)
+	for rows.Next() {
+		var state saga.State
+		if err := rows.Scan(
+			&state.ID,
+			&state.Name,
+			&state.Status,
+			&state.CurrentStep,
+			&state.Data,
+			&state.TraceParent,
+			&state.Tenant,
+			&state.Error,
+			&state.CreatedAt,
+			&state.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	return states, rows.Err()
+}
+
+func (p *StateStore) Load(ctx context.Context, id uuid.UUID) (*saga.State, error) {
+	sql := `SELECT id, name, status, current_step, data, COALESCE(trace_parent, ''), COALESCE(tenant, ''),
+		COALESCE(error, ''),
+		created_at, updated_at
+		FROM saga_states WHERE id = $1`
+	var state saga.State
+	err := p.pool.QueryRow(ctx, sql, id).Scan(
+		&state.ID,
+		&state.Name,
+		&state.Status,
+		&state.CurrentStep,
+		&state.Data,
+		&state.TraceParent,
+		&state.Tenant,
+		&state.Error,
+		&state.CreatedAt,
+		&state.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, saga.ErrStateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+var _ saga.StateLister = (*StateStore)(nil)
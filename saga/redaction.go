@@ -0,0 +1,133 @@
+package saga
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// RedactedValue replaces sensitive string values in logs and redacted state
+const RedactedValue = "[REDACTED]"
+
+// Redactor is implemented by saga data that needs custom redaction. Redacted returns
+// a copy of the value that is safe to log or persist; the receiver must not be modified.
+type Redactor interface {
+	Redacted() any
+}
+
+// Redact returns a copy of v with sensitive data masked. Types implementing Redactor
+// redact themselves; otherwise struct fields tagged `saga:"sensitive"` are masked
+// (strings become RedactedValue, other kinds their zero value), recursing through
+// nested structs, pointers, slices, arrays, maps and interfaces. Pointers and maps that are
+// shared or cyclic stay so in the copy. The original value is never modified.
+func Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	if redactor, ok := v.(Redactor); ok {
+		return redactor.Redacted()
+	}
+	return redactValue(reflect.ValueOf(v), map[visit]reflect.Value{}).Interface()
+}
+
+// visit identifies a pointer or map already copied, so a cycle ends at its copy
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func redactValue(v reflect.Value, seen map[visit]reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		if redactor, ok := v.Interface().(Redactor); ok {
+			if redacted := reflect.ValueOf(redactor.Redacted()); redacted.IsValid() && redacted.Type().AssignableTo(v.Type()) {
+				return redacted
+			}
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		key := visit{v.Pointer(), v.Type()}
+		if out, ok := seen[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Elem().Type())
+		seen[key] = out
+		out.Elem().Set(redactValue(v.Elem(), seen))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(redactValue(v.Elem(), seen))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("saga") == "sensitive" {
+				out.Field(i).Set(maskedValue(v.Field(i)))
+				continue
+			}
+			out.Field(i).Set(redactValue(v.Field(i), seen))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i), seen))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		key := visit{v.Pointer(), v.Type()}
+		if out, ok := seen[key]; ok {
+			return out
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		seen[key] = out
+		for iter := v.MapRange(); iter.Next(); {
+			out.SetMapIndex(iter.Key(), redactValue(iter.Value(), seen))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func maskedValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.String {
+		if v.Len() == 0 {
+			return v
+		}
+		return reflect.ValueOf(RedactedValue).Convert(v.Type())
+	}
+	return reflect.Zero(v.Type())
+}
+
+// redactedJSON renders v with sensitive data masked, for log lines
+func redactedJSON(v any) string {
+	data, err := json.Marshal(Redact(v))
+	if err != nil {
+		return "<unserializable: " + err.Error() + ">"
+	}
+	return string(data)
+}
@@ -0,0 +1,159 @@
+package saga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+)
+
+type contactDetails struct {
+	Phone string `saga:"sensitive"`
+	City  string
+}
+
+type sensitiveData struct {
+	Name     string `saga:"sensitive"`
+	Email    string `saga:"sensitive"`
+	Age      int    `saga:"sensitive"`
+	Product  string
+	Contact  *contactDetails
+	Contacts []contactDetails
+}
+
+type selfRedacting struct {
+	Secret string
+}
+
+func (s selfRedacting) Redacted() any {
+	return selfRedacting{Secret: "custom"}
+}
+
+func TestRedact_MasksTaggedFields(t *testing.T) {
+	data := &sensitiveData{
+		Name:     "John",
+		Email:    "john@example.com",
+		Age:      42,
+		Product:  "mortgage",
+		Contact:  &contactDetails{Phone: "555-1234", City: "Toronto"},
+		Contacts: []contactDetails{{Phone: "555-0000", City: "Ottawa"}},
+	}
+
+	redacted, ok := Redact(data).(*sensitiveData)
+	if !ok {
+		t.Fatalf("Expected *sensitiveData, got %T", Redact(data))
+	}
+
+	if redacted.Name != RedactedValue || redacted.Email != RedactedValue {
+		t.Errorf("Expected name and email to be redacted, got %q, %q", redacted.Name, redacted.Email)
+	}
+	if redacted.Age != 0 {
+		t.Errorf("Expected non-string sensitive field to be zeroed, got %d", redacted.Age)
+	}
+	if redacted.Product != "mortgage" {
+		t.Errorf("Expected non-sensitive field to be kept, got %q", redacted.Product)
+	}
+	if redacted.Contact.Phone != RedactedValue || redacted.Contact.City != "Toronto" {
+		t.Errorf("Expected nested struct to be redacted, got %+v", redacted.Contact)
+	}
+	if redacted.Contacts[0].Phone != RedactedValue || redacted.Contacts[0].City != "Ottawa" {
+		t.Errorf("Expected slice elements to be redacted, got %+v", redacted.Contacts)
+	}
+
+	// The original must be untouched so steps keep working with real values
+	if data.Name != "John" || data.Contact.Phone != "555-1234" || data.Contacts[0].Phone != "555-0000" {
+		t.Errorf("Redact modified the original value: %+v", data)
+	}
+}
+
+func TestRedact_UsesRedactor(t *testing.T) {
+	redacted := Redact(selfRedacting{Secret: "hunter2"}).(selfRedacting)
+	if redacted.Secret != "custom" {
+		t.Errorf("Expected Redactor to be used, got %q", redacted.Secret)
+	}
+}
+
+func TestRedact_MasksMapValues(t *testing.T) {
+	data := map[string]any{
+		"contact":  contactDetails{Phone: "555-1234", City: "Toronto"},
+		"contacts": map[string]*contactDetails{"home": {Phone: "555-0000", City: "Ottawa"}},
+	}
+
+	redacted := Redact(data).(map[string]any)
+	if contact := redacted["contact"].(contactDetails); contact.Phone != RedactedValue || contact.City != "Toronto" {
+		t.Errorf("Expected map values to be redacted, got %+v", contact)
+	}
+	if home := redacted["contacts"].(map[string]*contactDetails)["home"]; home.Phone != RedactedValue {
+		t.Errorf("Expected nested map values to be redacted, got %+v", home)
+	}
+	if data["contacts"].(map[string]*contactDetails)["home"].Phone != "555-0000" {
+		t.Errorf("Redact modified the original map: %+v", data)
+	}
+}
+
+func TestRedact_MasksArrayElements(t *testing.T) {
+	data := [2]contactDetails{{Phone: "555-1234", City: "Toronto"}, {Phone: "555-0000", City: "Ottawa"}}
+
+	redacted := Redact(data).([2]contactDetails)
+	if redacted[0].Phone != RedactedValue || redacted[1].Phone != RedactedValue || redacted[1].City != "Ottawa" {
+		t.Errorf("Expected array elements to be redacted, got %+v", redacted)
+	}
+}
+
+type linkedContact struct {
+	Phone string `saga:"sensitive"`
+	Next  *linkedContact
+}
+
+func TestRedact_HandlesCycles(t *testing.T) {
+	first := &linkedContact{Phone: "555-1234"}
+	first.Next = &linkedContact{Phone: "555-0000", Next: first}
+
+	redacted := Redact(first).(*linkedContact)
+	if redacted.Phone != RedactedValue || redacted.Next.Phone != RedactedValue {
+		t.Errorf("Expected every contact to be redacted, got %+v", redacted)
+	}
+	if redacted.Next.Next != redacted {
+		t.Errorf("Expected the copy to keep the cycle")
+	}
+	if first.Next.Phone != "555-0000" {
+		t.Errorf("Redact modified the original value: %+v", first.Next)
+	}
+}
+
+func TestSaga_RedactsLogsAndOptionallyPersistence(t *testing.T) {
+	var logs bytes.Buffer
+	store := NewInMemoryStateStore()
+	data := &sensitiveData{Name: "John", Email: "john@example.com", Product: "mortgage"}
+
+	var seenEmail string
+	saga := NewWithLogger(data, log.New(&logs, "", 0)).
+		WithStateStore(store).
+		WithRedactedPersistence().
+		AddStep("Step1", func(ctx context.Context, data *sensitiveData) error {
+			seenEmail = data.Email
+			return nil
+		}, func(ctx context.Context, data *sensitiveData) error { return nil })
+
+	if err := saga.Execute(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if seenEmail != "john@example.com" {
+		t.Errorf("Expected step to see the real email, got %q", seenEmail)
+	}
+	if strings.Contains(logs.String(), "john@example.com") || strings.Contains(logs.String(), "John") {
+		t.Errorf("Expected logs to be redacted, got: %s", logs.String())
+	}
+
+	state, _ := store.Load(context.Background(), saga.ID)
+	var persisted sensitiveData
+	if err := json.Unmarshal(state.Data, &persisted); err != nil {
+		t.Fatalf("Failed to decode persisted data: %v", err)
+	}
+	if persisted.Email != RedactedValue || persisted.Product != "mortgage" {
+		t.Errorf("Expected persisted data to be redacted, got %+v", persisted)
+	}
+}
@@ -0,0 +1,373 @@
+// Package saga runs a sequence of steps across services as one unit of work: if a
+// step fails, the steps that already ran are compensated in reverse order.
+//
+// A saga is built around a pointer to its data, which steps read and write to pass
+// results along:
+//
+//	err := saga.New(data).
+//		WithCompensationStrategy(saga.NewContinueAllStrategy[Data](saga.DefaultRetryConfig())).
+//		WithStateStore(store).
+//		AddStep("CreateCustomer", createCustomer, deleteCustomer).
+//		AddStep("CreateApplication", createApplication, cancelApplication).
+//		Execute(ctx)
+//
+// With a StateStore the saga persists its progress after every step, so Resume can
+// continue it in another process; the postgres subpackage provides a durable store.
+// Each step runs with a context carrying a trace, a correlation ID, the tenant and
+// an idempotency key stable across retries and resumes, for the service clients to
+// send along.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Step represents a single step in the saga with execute and compensate functions
+type Step[T any] struct {
+	Name       string
+	Execute    func(ctx context.Context, data *T) error
+	Compensate func(ctx context.Context, data *T) error
+}
+
+// Saga represents the saga orchestrator
+type Saga[T any] struct {
+	ID                   uuid.UUID
+	Name                 string
+	Steps                []*Step[T]
+	Data                 *T
+	logger               *log.Logger
+	compensationStrategy CompensationStrategy[T]
+	alerter              Alerter
+	stateStore           StateStore
+	state                *State
+	redactPersistedData  bool
+}
+
+// New creates a new saga instance with default FailFast strategy
+func New[T any](data *T) *Saga[T] {
+	return &Saga[T]{
+		ID:                   uuid.New(),
+		Steps:                make([]*Step[T], 0),
+		Data:                 data,
+		logger:               log.Default(),
+		compensationStrategy: NewFailFastStrategy[T](),
+	}
+}
+
+// NewWithLogger creates a new saga instance with a custom logger and default FailFast strategy
+func NewWithLogger[T any](data *T, logger *log.Logger) *Saga[T] {
+	return &Saga[T]{
+		ID:                   uuid.New(),
+		Steps:                make([]*Step[T], 0),
+		Data:                 data,
+		logger:               logger,
+		compensationStrategy: NewFailFastStrategy[T](),
+	}
+}
+
+// WithName sets the saga definition name recorded in persisted state (fluent API)
+func (s *Saga[T]) WithName(name string) *Saga[T] {
+	s.Name = name
+	return s
+}
+
+// WithCompensationStrategy sets the compensation strategy for the saga (fluent API)
+func (s *Saga[T]) WithCompensationStrategy(strategy CompensationStrategy[T]) *Saga[T] {
+	s.compensationStrategy = strategy
+	return s
+}
+
+// WithAlerter sets the alerter notified when compensation cannot complete (fluent API)
+func (s *Saga[T]) WithAlerter(alerter Alerter) *Saga[T] {
+	s.alerter = alerter
+	return s
+}
+
+// WithStateStore persists the saga state after every transition so it can be resumed (fluent API)
+func (s *Saga[T]) WithStateStore(store StateStore) *Saga[T] {
+	s.stateStore = store
+	return s
+}
+
+// WithRedactedPersistence masks sensitive fields (see Redact) in the data written to the
+// state store. Steps still see the real values in memory, but a resumed saga restores the
+// masked values, so steps that run after a resume must not depend on sensitive fields (fluent API)
+func (s *Saga[T]) WithRedactedPersistence() *Saga[T] {
+	s.redactPersistedData = true
+	return s
+}
+
+// AddStep adds a step to the saga
+func (s *Saga[T]) AddStep(name string, execute, compensate func(ctx context.Context, data *T) error) *Saga[T] {
+	step := &Step[T]{
+		Name:       name,
+		Execute:    execute,
+		Compensate: compensate,
+	}
+	s.Steps = append(s.Steps, step)
+	return s
+}
+
+// Execute runs the saga
+func (s *Saga[T]) Execute(ctx context.Context) error {
+	sagasInFlight.Add(1)
+	defer sagasInFlight.Add(-1)
+
+	ctx, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+	return s.run(ctx, 0)
+}
+
+// Start records the saga as running and executes its steps in the background, so a
+// caller can hand out the saga's ID before it finishes. The state is saved before
+// Start returns, so the store already knows the saga when the caller looks it up.
+// The channel receives the result Execute would have returned; ctx must outlive the
+// saga, e.g. it must not be the context of the request that started it.
+func (s *Saga[T]) Start(ctx context.Context) (<-chan error, error) {
+	ctx, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sagasInFlight.Add(1)
+	done := make(chan error, 1)
+	go func() {
+		defer sagasInFlight.Add(-1)
+		done <- s.run(ctx, 0)
+	}()
+	return done, nil
+}
+
+// begin records the saga as running and returns the context its steps run with
+func (s *Saga[T]) begin(ctx context.Context) (context.Context, error) {
+	// Continue the caller's trace if there is one, otherwise start a new one
+	traceParent, ok := TraceParentFromContext(ctx)
+	if !ok {
+		traceParent = NewTraceParent()
+		ctx = ContextWithTraceParent(ctx, traceParent)
+	}
+
+	// Correlate the services' logs by saga unless the caller brought its own ID
+	if CorrelationIDFromContext(ctx) == "" {
+		ctx = ContextWithCorrelationID(ctx, s.ID.String())
+	}
+
+	now := time.Now().UTC()
+	s.state = &State{
+		ID:          s.ID,
+		Name:        s.Name,
+		Status:      StatusRunning,
+		TraceParent: traceParent.String(),
+		Tenant:      TenantFromContext(ctx),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.saveState(ctx); err != nil {
+		return nil, fmt.Errorf("failed to persist saga state: %w", err)
+	}
+
+	s.logger.Printf("Starting saga %s with data %s", s.ID, redactedJSON(s.Data))
+	return ctx, nil
+}
+
+// Resume loads the persisted state for the saga ID and continues where it left off:
+// running sagas continue with the next step, and sagas that were compensating (or
+// failed to compensate) retry compensation, so compensations must be idempotent.
+// The original trace and tenant are restored so the resumed work is recorded under
+// the same trace, and acts for the same lender, as the first attempt.
+func (s *Saga[T]) Resume(ctx context.Context, id uuid.UUID) error {
+	ctx, state, err := s.restore(ctx, id)
+	if err != nil {
+		return err
+	}
+	sagasInFlight.Add(1)
+	defer sagasInFlight.Add(-1)
+	s.logger.Printf("Resuming saga %s (%s) at step %d", s.ID, state.Status, state.CurrentStep)
+
+	switch state.Status {
+	case StatusRunning:
+		return s.run(ctx, state.CurrentStep)
+	case StatusCompensating, StatusFailed:
+		return s.rollback(ctx, state.CurrentStep, fmt.Errorf("resumed compensation: %s", state.Error))
+	default:
+		return nil
+	}
+}
+
+// Compensate loads the persisted state for the saga ID and rolls back the steps it
+// executed, for an operator to undo a saga that is stuck running or, once its
+// effects are unwanted, one that completed. Compensated sagas are left as they are.
+func (s *Saga[T]) Compensate(ctx context.Context, id uuid.UUID) error {
+	ctx, state, err := s.restore(ctx, id)
+	if err != nil {
+		return err
+	}
+	if state.Status == StatusCompensated {
+		return nil
+	}
+	sagasInFlight.Add(1)
+	defer sagasInFlight.Add(-1)
+	s.logger.Printf("Compensating saga %s (%s) from step %d", s.ID, state.Status, state.CurrentStep)
+
+	reason := state.Error
+	if reason == "" {
+		reason = "requested by operator"
+	}
+	// rollback reports the original failure even when compensation succeeded
+	err = s.rollback(ctx, state.CurrentStep, fmt.Errorf("compensation: %s", reason))
+	if s.state.Status == StatusCompensated {
+		return nil
+	}
+	return err
+}
+
+// restore loads the persisted state for the saga ID into the saga and returns the
+// context to continue it with, carrying its trace, tenant and correlation ID
+func (s *Saga[T]) restore(ctx context.Context, id uuid.UUID) (context.Context, *State, error) {
+	if s.stateStore == nil {
+		return nil, nil, fmt.Errorf("cannot load saga %s: no state store configured", id)
+	}
+
+	state, err := s.stateStore.Load(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load saga state: %w", err)
+	}
+
+	if len(state.Data) > 0 {
+		if err := json.Unmarshal(state.Data, s.Data); err != nil {
+			return nil, nil, fmt.Errorf("failed to restore saga data: %w", err)
+		}
+	}
+	if state.TraceParent != "" {
+		if traceParent, err := ParseTraceParent(state.TraceParent); err == nil {
+			ctx = ContextWithTraceParent(ctx, traceParent)
+		} else {
+			s.logger.Printf("Ignoring stored trace context for saga %s: %v", id, err)
+		}
+	}
+
+	if state.Tenant != "" {
+		ctx = ContextWithTenant(ctx, state.Tenant)
+	}
+
+	if CorrelationIDFromContext(ctx) == "" {
+		ctx = ContextWithCorrelationID(ctx, state.ID.String())
+	}
+
+	s.ID = state.ID
+	s.state = state
+	return ctx, state, nil
+}
+
+// run executes steps starting at index from
+func (s *Saga[T]) run(ctx context.Context, from int) error {
+	for i := from; i < len(s.Steps); i++ {
+		step := s.Steps[i]
+		if err := step.Execute(ContextWithStepIdempotencyKey(ctx, s.ID, step.Name), s.Data); err != nil {
+			s.logger.Printf("Step %s failed: %v", step.Name, err)
+			return s.rollback(ctx, i, err)
+		}
+		s.logger.Printf("Executed: %s", step.Name)
+
+		s.state.CurrentStep = i + 1
+		s.saveStateOrLog(ctx)
+	}
+
+	s.state.Status = StatusCompleted
+	s.saveStateOrLog(ctx)
+	sagaOutcomes.Add(string(StatusCompleted), 1)
+	return nil
+}
+
+// rollback compensates the steps before failedStepIndex and records the outcome
+func (s *Saga[T]) rollback(ctx context.Context, failedStepIndex int, err error) error {
+	s.state.Status = StatusCompensating
+	s.state.Error = err.Error()
+	s.saveStateOrLog(ctx)
+
+	if compErr := s.compensate(ctx, failedStepIndex); compErr != nil {
+		s.state.Status = StatusFailed
+		s.state.Error = compErr.Error()
+		s.saveStateOrLog(ctx)
+		sagaOutcomes.Add(string(StatusFailed), 1)
+		s.alertCompensationFailure(ctx, s.stepName(failedStepIndex), err, compErr)
+		return fmt.Errorf("execution failed: %w, compensation failed: %w", err, compErr)
+	}
+
+	s.state.Status = StatusCompensated
+	s.saveStateOrLog(ctx)
+	sagaOutcomes.Add(string(StatusCompensated), 1)
+	return fmt.Errorf("saga failed and rolled back: %w", err)
+}
+
+// compensate runs compensation for executed steps using the configured strategy
+func (s *Saga[T]) compensate(ctx context.Context, failedStepIndex int) error {
+	// Directly use the typed strategy - no conversion needed!
+	return s.compensationStrategy.Compensate(ctx, s.Steps, failedStepIndex, s.Data, s.logger)
+}
+
+func (s *Saga[T]) stepName(index int) string {
+	if index < len(s.Steps) {
+		return s.Steps[index].Name
+	}
+	return ""
+}
+
+// saveState snapshots the saga data into the state and persists it
+func (s *Saga[T]) saveState(ctx context.Context) error {
+	if s.stateStore == nil {
+		return nil
+	}
+
+	var payload any = s.Data
+	if s.redactPersistedData {
+		payload = Redact(s.Data)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	s.state.Data = data
+	s.state.UpdatedAt = time.Now().UTC()
+	return s.stateStore.Save(ctx, s.state)
+}
+
+// saveStateOrLog persists state mid-run, where a store failure must not abort the saga
+func (s *Saga[T]) saveStateOrLog(ctx context.Context) {
+	if err := s.saveState(ctx); err != nil {
+		s.logger.Printf("Failed to persist state for saga %s: %v", s.ID, err)
+	}
+}
+
+// alertCompensationFailure notifies the alerter that the saga could not be rolled back
+func (s *Saga[T]) alertCompensationFailure(ctx context.Context, failedStep string, execErr, compErr error) {
+	if s.alerter == nil {
+		return
+	}
+
+	details := map[string]any{
+		"failed_step":     failedStep,
+		"execution_error": execErr.Error(),
+	}
+	if compensationErr, ok := IsCompensationError(compErr); ok {
+		steps := make([]string, 0, len(compensationErr.Failures))
+		for _, failure := range compensationErr.Failures {
+			steps = append(steps, failure.StepName)
+		}
+		details["uncompensated_steps"] = steps
+	} else {
+		details["compensation_error"] = compErr.Error()
+	}
+
+	if err := s.alerter.Notify(ctx, SeverityCritical, s.ID.String(), "compensation failed, manual intervention required", details); err != nil {
+		s.logger.Printf("Failed to send alert for saga %s: %v", s.ID, err)
+	}
+}
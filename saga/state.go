@@ -0,0 +1,115 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is the lifecycle status of a persisted saga
+type Status string
+
+const (
+	StatusRunning      Status = "RUNNING"
+	StatusCompleted    Status = "COMPLETED"
+	StatusCompensating Status = "COMPENSATING"
+	StatusCompensated  Status = "COMPENSATED"
+	StatusFailed       Status = "FAILED" // compensation failed, needs manual intervention
+)
+
+// ErrStateNotFound is returned by a StateStore when no state exists for a saga ID
+var ErrStateNotFound = errors.New("saga state not found")
+
+// State is the persisted snapshot of a saga, enough to resume it in another process
+type State struct {
+	ID          uuid.UUID       `json:"id"`
+	Name        string          `json:"name"`
+	Status      Status          `json:"status"`
+	CurrentStep int             `json:"current_step"` // index of the next step to execute
+	Data        json.RawMessage `json:"data"`
+	TraceParent string          `json:"trace_parent,omitempty"`
+	Tenant      string          `json:"tenant,omitempty"` // the tenant the saga acts for; empty for the default tenant
+	Error       string          `json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// StateStore persists saga state between steps
+type StateStore interface {
+	Save(ctx context.Context, state *State) error
+	Load(ctx context.Context, id uuid.UUID) (*State, error)
+}
+
+// StateFilter narrows a StateLister's listing; zero fields match every saga
+type StateFilter struct {
+	Status Status
+	Name   string
+	Limit  int // at most this many sagas, all of them when 0
+}
+
+// matches reports whether state passes the filter's status and name
+func (f StateFilter) matches(state *State) bool {
+	return (f.Status == "" || state.Status == f.Status) && (f.Name == "" || state.Name == f.Name)
+}
+
+// StateLister is implemented by state stores that can list the sagas they hold,
+// most recently updated first, e.g. to find the failed ones needing attention
+type StateLister interface {
+	List(ctx context.Context, filter StateFilter) ([]State, error)
+}
+
+// =====================================
+// In-memory store
+// =====================================
+
+// InMemoryStateStore keeps saga state in process memory; useful for tests and one-shot runs
+type InMemoryStateStore struct {
+	mu     sync.RWMutex
+	states map[uuid.UUID]State
+}
+
+func NewInMemoryStateStore() *InMemoryStateStore {
+	return &InMemoryStateStore{states: make(map[uuid.UUID]State)}
+}
+
+func (m *InMemoryStateStore) Save(ctx context.Context, state *State) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[state.ID] = *state
+	return nil
+}
+
+func (m *InMemoryStateStore) Load(ctx context.Context, id uuid.UUID) (*State, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.states[id]
+	if !ok {
+		return nil, ErrStateNotFound
+	}
+	return &state, nil
+}
+
+func (m *InMemoryStateStore) List(ctx context.Context, filter StateFilter) ([]State, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	states := make([]State, 0, len(m.states))
+	for _, state := range m.states {
+		if filter.matches(&state) {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].UpdatedAt.After(states[j].UpdatedAt)
+	})
+	if filter.Limit > 0 && len(states) > filter.Limit {
+		states = states[:filter.Limit]
+	}
+	return states, nil
+}
+
+var _ StateLister = (*InMemoryStateStore)(nil)
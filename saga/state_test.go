@@ -0,0 +1,413 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// traceContext has the shape of the service clients' TraceContext
+type traceContext struct {
+	TraceParent   string
+	CorrelationID string
+}
+
+type resumeData struct {
+	Value    string
+	Executed []string
+}
+
+func TestTraceParent_RoundTrip(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	tp, err := ParseTraceParent(header)
+	if err != nil {
+		t.Fatalf("Expected valid traceparent, got: %v", err)
+	}
+	if tp.String() != header {
+		t.Errorf("Expected %s, got %s", header, tp.String())
+	}
+	if tp.TraceIDString() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Unexpected trace ID %s", tp.TraceIDString())
+	}
+
+	child := tp.Child()
+	if child.TraceID != tp.TraceID {
+		t.Error("Expected child to keep the trace ID")
+	}
+	if child.ParentID == tp.ParentID {
+		t.Error("Expected child to get a new parent ID")
+	}
+}
+
+func TestTraceParent_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-zzf067aa0ba902b7-01",
+	}
+	for _, value := range invalid {
+		if _, err := ParseTraceParent(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestSaga_PersistsCompletedState(t *testing.T) {
+	store := NewInMemoryStateStore()
+	data := &resumeData{Value: "hello"}
+
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).
+		WithName("test-saga").
+		WithStateStore(store).
+		AddStep("Step1", appendStep("Step1"), noopCompensate).
+		AddStep("Step2", appendStep("Step2"), noopCompensate)
+
+	traceParent := NewTraceParent()
+	ctx := ContextWithTraceParent(context.Background(), traceParent)
+	if err := saga.Execute(ctx); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	state, err := store.Load(context.Background(), saga.ID)
+	if err != nil {
+		t.Fatalf("Expected state to be persisted, got: %v", err)
+	}
+	if state.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, state.Status)
+	}
+	if state.Name != "test-saga" {
+		t.Errorf("Expected name test-saga, got %s", state.Name)
+	}
+	if state.CurrentStep != 2 {
+		t.Errorf("Expected current step 2, got %d", state.CurrentStep)
+	}
+	if state.TraceParent != traceParent.String() {
+		t.Errorf("Expected trace parent %s, got %s", traceParent, state.TraceParent)
+	}
+
+	var persisted resumeData
+	if err := json.Unmarshal(state.Data, &persisted); err != nil {
+		t.Fatalf("Failed to decode persisted data: %v", err)
+	}
+	if len(persisted.Executed) != 2 {
+		t.Errorf("Expected persisted data to include both steps, got %v", persisted.Executed)
+	}
+}
+
+func TestSaga_StartPersistsStateBeforeRunningSteps(t *testing.T) {
+	store := NewInMemoryStateStore()
+	release := make(chan struct{})
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", func(ctx context.Context, data *resumeData) error {
+			<-release
+			return nil
+		}, noopCompensate)
+
+	done, err := saga.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	state, err := store.Load(context.Background(), saga.ID)
+	if err != nil || state.Status != StatusRunning || state.CurrentStep != 0 {
+		t.Fatalf("Expected the saga to be recorded as running, got %+v, %v", state, err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if state, _ := store.Load(context.Background(), saga.ID); state.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, state.Status)
+	}
+}
+
+func TestSaga_StartReportsStoreFailure(t *testing.T) {
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(failingStore{}).
+		AddStep("Step1", appendStep("Step1"), noopCompensate)
+
+	if done, err := saga.Start(context.Background()); err == nil || done != nil {
+		t.Errorf("Expected the store failure without starting the saga, got %v", err)
+	}
+}
+
+// failingStore rejects every save
+type failingStore struct{}
+
+func (failingStore) Save(ctx context.Context, state *State) error {
+	return errors.New("database unavailable")
+}
+
+func (failingStore) Load(ctx context.Context, id uuid.UUID) (*State, error) {
+	return nil, ErrStateNotFound
+}
+
+func TestSaga_PersistsCompensatedState(t *testing.T) {
+	store := NewInMemoryStateStore()
+	data := &resumeData{}
+
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", appendStep("Step1"), noopCompensate).
+		AddStep("Step2", func(ctx context.Context, data *resumeData) error {
+			return errors.New("boom")
+		}, noopCompensate)
+
+	if err := saga.Execute(context.Background()); err == nil {
+		t.Fatal("Expected saga to fail")
+	}
+
+	state, err := store.Load(context.Background(), saga.ID)
+	if err != nil {
+		t.Fatalf("Expected state to be persisted, got: %v", err)
+	}
+	if state.Status != StatusCompensated {
+		t.Errorf("Expected status %s, got %s", StatusCompensated, state.Status)
+	}
+	if state.Error != "boom" {
+		t.Errorf("Expected error boom, got %q", state.Error)
+	}
+	if state.TraceParent == "" {
+		t.Error("Expected a trace parent to be generated")
+	}
+}
+
+func TestSaga_ResumeContinuesUnderOriginalTrace(t *testing.T) {
+	store := NewInMemoryStateStore()
+	traceParent := NewTraceParent()
+	sagaID := uuid.New()
+
+	persisted, _ := json.Marshal(resumeData{Value: "restored", Executed: []string{"Step1"}})
+	_ = store.Save(context.Background(), &State{
+		ID:          sagaID,
+		Status:      StatusRunning,
+		CurrentStep: 1,
+		Data:        persisted,
+		TraceParent: traceParent.String(),
+	})
+
+	var seenTrace TraceParent
+	var seenValue string
+	data := &resumeData{}
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", func(ctx context.Context, data *resumeData) error {
+			t.Error("Step1 should not be re-executed on resume")
+			return nil
+		}, noopCompensate).
+		AddStep("Step2", func(ctx context.Context, data *resumeData) error {
+			seenTrace, _ = TraceParentFromContext(ctx)
+			seenValue = data.Value
+			data.Executed = append(data.Executed, "Step2")
+			return nil
+		}, noopCompensate)
+
+	if err := saga.Resume(context.Background(), sagaID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if seenTrace != traceParent {
+		t.Errorf("Expected resumed step to run under trace %s, got %s", traceParent, seenTrace)
+	}
+	if seenValue != "restored" {
+		t.Errorf("Expected restored data, got %q", seenValue)
+	}
+	if saga.ID != sagaID {
+		t.Errorf("Expected saga ID %s, got %s", sagaID, saga.ID)
+	}
+
+	state, _ := store.Load(context.Background(), sagaID)
+	if state.Status != StatusCompleted {
+		t.Errorf("Expected status %s, got %s", StatusCompleted, state.Status)
+	}
+	if state.TraceParent != traceParent.String() {
+		t.Errorf("Expected trace parent to be kept, got %s", state.TraceParent)
+	}
+}
+
+func TestSaga_ResumeWithoutStore(t *testing.T) {
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0))
+	if err := saga.Resume(context.Background(), uuid.New()); err == nil {
+		t.Error("Expected error when resuming without a state store")
+	}
+}
+
+func TestSaga_ResumeUnknownSaga(t *testing.T) {
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(NewInMemoryStateStore())
+	err := saga.Resume(context.Background(), uuid.New())
+	if !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("Expected ErrStateNotFound, got: %v", err)
+	}
+}
+
+func TestSaga_CompensateRollsBackCompletedSaga(t *testing.T) {
+	store := NewInMemoryStateStore()
+	sagaID := uuid.New()
+	persisted, _ := json.Marshal(resumeData{Executed: []string{"Step1", "Step2"}})
+	_ = store.Save(context.Background(), &State{ID: sagaID, Status: StatusCompleted, CurrentStep: 2, Data: persisted})
+
+	var compensated []string
+	compensate := func(name string) func(ctx context.Context, data *resumeData) error {
+		return func(ctx context.Context, data *resumeData) error {
+			compensated = append(compensated, name)
+			return nil
+		}
+	}
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", appendStep("Step1"), compensate("Step1")).
+		AddStep("Step2", appendStep("Step2"), compensate("Step2"))
+
+	if err := saga.Compensate(context.Background(), sagaID); err != nil {
+		t.Fatalf("Expected the saga to be compensated, got: %v", err)
+	}
+	if len(compensated) != 2 || compensated[0] != "Step2" || compensated[1] != "Step1" {
+		t.Errorf("Expected both steps compensated in reverse order, got %v", compensated)
+	}
+	state, _ := store.Load(context.Background(), sagaID)
+	if state.Status != StatusCompensated {
+		t.Errorf("Expected status %s, got %s", StatusCompensated, state.Status)
+	}
+
+	// A compensated saga is left alone
+	compensated = nil
+	if err := saga.Compensate(context.Background(), sagaID); err != nil || len(compensated) != 0 {
+		t.Errorf("Expected nothing to compensate again, got %v, %v", err, compensated)
+	}
+}
+
+func TestInMemoryStateStore_ListFiltersNewestFirst(t *testing.T) {
+	store := NewInMemoryStateStore()
+	now := time.Now()
+	older, newer, other := uuid.New(), uuid.New(), uuid.New()
+	for _, state := range []State{
+		{ID: older, Name: "onboarding", Status: StatusFailed, UpdatedAt: now.Add(-time.Hour)},
+		{ID: newer, Name: "onboarding", Status: StatusFailed, UpdatedAt: now},
+		{ID: other, Name: "onboarding", Status: StatusCompleted, UpdatedAt: now},
+		{ID: uuid.New(), Name: "payment", Status: StatusFailed, UpdatedAt: now},
+	} {
+		_ = store.Save(context.Background(), &state)
+	}
+
+	states, err := store.List(context.Background(), StateFilter{Status: StatusFailed, Name: "onboarding"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 || states[0].ID != newer || states[1].ID != older {
+		t.Errorf("Expected the failed onboardings newest first, got %+v", states)
+	}
+	if states, _ := store.List(context.Background(), StateFilter{Limit: 3}); len(states) != 3 {
+		t.Errorf("Expected the limit to apply, got %d sagas", len(states))
+	}
+}
+
+func appendStep(name string) func(ctx context.Context, data *resumeData) error {
+	return func(ctx context.Context, data *resumeData) error {
+		data.Executed = append(data.Executed, name)
+		return nil
+	}
+}
+
+func noopCompensate(ctx context.Context, data *resumeData) error {
+	return nil
+}
+
+func TestSaga_RecordsAndRestoresTenant(t *testing.T) {
+	store := NewInMemoryStateStore()
+	data := &resumeData{}
+	saga := NewWithLogger(data, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", func(ctx context.Context, data *resumeData) error {
+			return nil
+		}, noopCompensate)
+
+	if err := saga.Execute(ContextWithTenant(context.Background(), "lender-a")); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	state, _ := store.Load(context.Background(), saga.ID)
+	if state.Tenant != "lender-a" {
+		t.Errorf("Expected the tenant to be recorded, got %q", state.Tenant)
+	}
+
+	state.Status = StatusRunning
+	state.CurrentStep = 0
+	_ = store.Save(context.Background(), state)
+
+	var seenTenant string
+	resumed := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", func(ctx context.Context, data *resumeData) error {
+			seenTenant = TenantFromContext(ctx)
+			return nil
+		}, noopCompensate)
+	if err := resumed.Resume(context.Background(), saga.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if seenTenant != "lender-a" {
+		t.Errorf("Expected the resumed step to act for lender-a, got %q", seenTenant)
+	}
+}
+
+func TestSaga_StepsKeepIdempotencyKeysAcrossResume(t *testing.T) {
+	store := NewInMemoryStateStore()
+	keys := []string{}
+	step := func(ctx context.Context, data *resumeData) error {
+		keys = append(keys, IdempotencyKeyFromContext(ctx))
+		return nil
+	}
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", step, noopCompensate).
+		AddStep("Step2", step, noopCompensate)
+	if err := saga.Execute(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	state, _ := store.Load(context.Background(), saga.ID)
+	state.Status = StatusRunning
+	state.CurrentStep = 1
+	_ = store.Save(context.Background(), state)
+	resumed := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		WithStateStore(store).
+		AddStep("Step1", step, noopCompensate).
+		AddStep("Step2", step, noopCompensate)
+	if err := resumed.Resume(context.Background(), saga.ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	want := []string{saga.ID.String() + ":Step1", saga.ID.String() + ":Step2", saga.ID.String() + ":Step2"}
+	if len(keys) != len(want) || keys[0] != want[0] || keys[1] != want[1] || keys[2] != want[2] {
+		t.Errorf("Expected keys %v, got %v", want, keys)
+	}
+}
+
+func TestSaga_StepsCarryTraceAndCorrelationID(t *testing.T) {
+	traceParent := NewTraceParent()
+	var seen traceContext
+	saga := NewWithLogger(&resumeData{}, log.New(io.Discard, "", 0)).
+		AddStep("Step1", func(ctx context.Context, data *resumeData) error {
+			seen = TraceContextFromContext[traceContext](ctx)
+			return nil
+		}, noopCompensate)
+	if err := saga.Execute(ContextWithTraceParent(context.Background(), traceParent)); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if seen.CorrelationID != saga.ID.String() {
+		t.Errorf("Expected the saga ID as correlation ID, got %q", seen.CorrelationID)
+	}
+	child, err := ParseTraceParent(seen.TraceParent)
+	if err != nil || child.TraceID != traceParent.TraceID || child.ParentID == traceParent.ParentID {
+		t.Errorf("Expected a new span in trace %s, got %q", traceParent.TraceIDString(), seen.TraceParent)
+	}
+}
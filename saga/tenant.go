@@ -0,0 +1,17 @@
+package saga
+
+import "context"
+
+type tenantKey struct{}
+
+// ContextWithTenant returns a context acting for tenant. A saga started with it
+// records the tenant in its state and the service clients send it with every call.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ctx acts for, or "" for the default tenant
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
@@ -0,0 +1,127 @@
+package saga
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// TraceParent is a W3C Trace Context traceparent
+// (https://www.w3.org/TR/trace-context/#traceparent-header)
+type TraceParent struct {
+	TraceID  [16]byte
+	ParentID [8]byte
+	Flags    byte
+}
+
+const traceParentVersion = "00"
+
+// NewTraceParent starts a new sampled trace
+func NewTraceParent() TraceParent {
+	var tp TraceParent
+	_, _ = rand.Read(tp.TraceID[:])
+	_, _ = rand.Read(tp.ParentID[:])
+	tp.Flags = 0x01
+	return tp
+}
+
+// ParseTraceParent parses a traceparent header value such as
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func ParseTraceParent(value string) (TraceParent, error) {
+	var tp TraceParent
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != traceParentVersion {
+		return tp, fmt.Errorf("invalid traceparent: %q", value)
+	}
+	if err := decodeHex(tp.TraceID[:], parts[1]); err != nil {
+		return tp, fmt.Errorf("invalid traceparent trace-id: %w", err)
+	}
+	if err := decodeHex(tp.ParentID[:], parts[2]); err != nil {
+		return tp, fmt.Errorf("invalid traceparent parent-id: %w", err)
+	}
+	var flags [1]byte
+	if err := decodeHex(flags[:], parts[3]); err != nil {
+		return tp, fmt.Errorf("invalid traceparent flags: %w", err)
+	}
+	tp.Flags = flags[0]
+	if !tp.IsValid() {
+		return tp, fmt.Errorf("invalid traceparent: %q", value)
+	}
+	return tp, nil
+}
+
+func decodeHex(dst []byte, s string) error {
+	if hex.EncodedLen(len(dst)) != len(s) {
+		return fmt.Errorf("expected %d hex characters, got %d", hex.EncodedLen(len(dst)), len(s))
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// IsValid reports whether the trace and parent IDs are non-zero, as the spec requires
+func (t TraceParent) IsValid() bool {
+	return t.TraceID != [16]byte{} && t.ParentID != [8]byte{}
+}
+
+// TraceIDString returns the hex-encoded trace ID
+func (t TraceParent) TraceIDString() string {
+	return hex.EncodeToString(t.TraceID[:])
+}
+
+// Child returns a traceparent in the same trace with a new parent ID
+func (t TraceParent) Child() TraceParent {
+	child := t
+	_, _ = rand.Read(child.ParentID[:])
+	return child
+}
+
+func (t TraceParent) String() string {
+	return fmt.Sprintf("%s-%s-%s-%02x", traceParentVersion,
+		hex.EncodeToString(t.TraceID[:]), hex.EncodeToString(t.ParentID[:]), t.Flags)
+}
+
+type traceParentKey struct{}
+
+// ContextWithTraceParent returns a context carrying the traceparent
+func ContextWithTraceParent(ctx context.Context, tp TraceParent) context.Context {
+	return context.WithValue(ctx, traceParentKey{}, tp)
+}
+
+// TraceParentFromContext returns the traceparent carried by ctx, if any
+func TraceParentFromContext(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentKey{}).(TraceParent)
+	return tp, ok && tp.IsValid()
+}
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a context whose service calls carry id in their
+// X-Correlation-ID header, tying them together in the services' logs
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, or ""
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// TraceContextFromContext returns the headers a service call made with ctx carries,
+// for the clients' WithTraceContextFrom: a traceparent for a new span in the trace
+// ctx carries, if any, and the correlation ID
+func TraceContextFromContext[T ~struct {
+	TraceParent   string
+	CorrelationID string
+}](ctx context.Context) T {
+	var traceParent string
+	if tp, ok := TraceParentFromContext(ctx); ok {
+		traceParent = tp.Child().String()
+	}
+	return T(struct {
+		TraceParent   string
+		CorrelationID string
+	}{traceParent, CorrelationIDFromContext(ctx)})
+}
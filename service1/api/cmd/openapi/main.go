@@ -0,0 +1,29 @@
+// Command openapi writes the service's OpenAPI document, the input the client
+// package is generated from
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"service1/api/internal/openapi"
+)
+
+func main() {
+	out := flag.String("o", "openapi.json", "file to write the document to")
+	flag.Parse()
+
+	doc, err := openapi.Document()
+	if err != nil {
+		log.Fatalf("Unable to build OpenAPI document: %v", err)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Fatalf("Unable to encode OpenAPI document: %v", err)
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		log.Fatalf("Unable to write %s: %v", *out, err)
+	}
+}
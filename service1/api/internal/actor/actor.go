@@ -0,0 +1,41 @@
+package actor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header carries the identity of the user or system making a request
+const Header = "X-Actor"
+
+// Unknown is recorded when a request does not identify its actor
+const Unknown = "unknown"
+
+type contextKey struct{}
+
+// WithActor returns a copy of ctx that carries the actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// FromContext returns the actor stored in ctx, or Unknown
+func FromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(contextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return Unknown
+}
+
+// Middleware stores the X-Actor request header in the request context
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actor := strings.TrimSpace(c.Request().Header.Get(Header)); actor != "" {
+				c.SetRequest(c.Request().WithContext(WithActor(c.Request().Context(), actor)))
+			}
+			return next(c)
+		}
+	}
+}
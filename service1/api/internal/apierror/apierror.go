@@ -0,0 +1,127 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/validation"
+)
+
+// Response is the JSON body returned for every failed request. Code is a stable
+// machine-readable value clients can branch on; RequestID echoes the X-Request-ID
+// header so a failure can be matched with the server logs.
+type Response struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Error is a failed request with its own code and details, for failures the status
+// alone does not describe
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details any
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validation returns a 422 error listing why the payload was rejected in details
+func Validation(details any, cause error) error {
+	return &Error{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "validation_failed",
+		Message: "validation failed",
+		Details: details,
+		Err:     cause,
+	}
+}
+
+// Handler is an echo.HTTPErrorHandler that renders errors as a Response. Echo HTTP
+// errors keep their status, a missing row becomes a 404 and anything else is logged
+// and reported as a 500 without leaking internals.
+func Handler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, response := toResponse(err)
+	response.RequestID = c.Response().Header().Get(echo.HeaderXRequestID)
+	if status >= http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, response)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// Describe returns the status and body Handler would send for err, so a bulk request
+// can report each item's failure the way a request for that item alone would
+func Describe(err error) (int, Response) {
+	return toResponse(err)
+}
+
+func toResponse(err error) (int, Response) {
+	var validationErr *validation.Error
+	if errors.As(err, &validationErr) {
+		err = Validation(validationErr.Fields, err)
+	}
+
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Status, Response{Code: apiErr.Code, Message: apiErr.Message, Details: apiErr.Details}
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message, ok := httpErr.Message.(string)
+		if !ok {
+			message = http.StatusText(httpErr.Code)
+		}
+		return httpErr.Code, Response{Code: code(httpErr.Code), Message: message}
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, Response{Code: code(http.StatusNotFound), Message: "resource not found"}
+	}
+
+	return http.StatusInternalServerError, Response{
+		Code:    code(http.StatusInternalServerError),
+		Message: "internal server error",
+	}
+}
+
+// code turns a status into a stable machine-readable code, e.g. 404 -> "not_found"
+func code(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ToLower(strings.ReplaceAll(text, " ", "_"))
+}
+
+// BadRequest returns a 400 error with the given message, wrapping the cause
+func BadRequest(message string, cause error) error {
+	return echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(cause)
+}
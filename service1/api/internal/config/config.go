@@ -0,0 +1,207 @@
+// Package config loads the service's settings into one typed struct at startup.
+// Values come from the environment, then from the dotenv file named by CONFIG_FILE,
+// then from a .env file in the working directory; variables set earlier win. Invalid
+// values stop the service with an error naming the variable instead of being
+// replaced by a default the operator did not ask for.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"service1/api/internal/auth"
+	"service1/api/internal/database"
+	"service1/api/internal/security"
+	"service1/api/internal/timeout"
+	"service1/api/internal/versioning"
+)
+
+type Config struct {
+	HTTPAddr string `env:"HTTP_ADDR" envDefault:":8081"`
+	GRPCAddr string `env:"GRPC_ADDR" envDefault:":9081"`
+	// ShutdownTimeout is how long in-flight requests get to finish on SIGINT or SIGTERM
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"10s"`
+	// RequestTimeout bounds each request and the queries it runs; RouteTimeouts
+	// overrides it per route, e.g. "GET /customers/:id=5s,POST /customers=10s"
+	RequestTimeout time.Duration            `env:"REQUEST_TIMEOUT" envDefault:"30s"`
+	RouteTimeouts  map[string]time.Duration `env:"ROUTE_TIMEOUTS" envKeyValSeparator:"="`
+
+	DatabaseURL string `env:"DATABASE_URL,required"`
+	// DBMaxConns and DBMinConns size the pool; 0 keeps pgxpool's defaults
+	DBMaxConns int32 `env:"DB_MAX_CONNS"`
+	DBMinConns int32 `env:"DB_MIN_CONNS"`
+	// DBConnectAttempts pings, backing off from DBConnectBackoff, wait for the database
+	// at startup
+	DBConnectAttempts int           `env:"DB_CONNECT_ATTEMPTS" envDefault:"10"`
+	DBConnectBackoff  time.Duration `env:"DB_CONNECT_BACKOFF" envDefault:"500ms"`
+	// DBHealthCheckPeriod is how often the pool checks idle connections and replaces
+	// the ones a database restart broke
+	DBHealthCheckPeriod time.Duration `env:"DB_HEALTH_CHECK_PERIOD" envDefault:"15s"`
+
+	// APIKeys are the accepted API keys, parsed by auth.ParseAPIKeys
+	APIKeys   map[string]auth.Principal `env:"API_KEYS"`
+	JWTSecret string                    `env:"JWT_SECRET"`
+
+	// RateLimitRPS of 0 turns rate limiting off; RateLimitBurst of 0 allows bursts of
+	// twice the rate
+	RateLimitRPS   float64 `env:"RATE_LIMIT_RPS" envDefault:"50"`
+	RateLimitBurst int     `env:"RATE_LIMIT_BURST"`
+
+	CORSAllowOrigins      []string `env:"CORS_ALLOW_ORIGINS"`
+	ContentSecurityPolicy string   `env:"CONTENT_SECURITY_POLICY"`
+	HSTSMaxAge            int      `env:"HSTS_MAX_AGE"`
+
+	// OutboxPublishURL receives outbox events; unset, they are only logged
+	OutboxPublishURL string `env:"OUTBOX_PUBLISH_URL"`
+}
+
+// parsers reads the variables whose types env cannot parse by itself
+var parsers = map[reflect.Type]env.ParserFunc{
+	reflect.TypeOf(map[string]auth.Principal{}): func(value string) (any, error) {
+		return auth.ParseAPIKeys(value)
+	},
+}
+
+// Load reads and validates the configuration
+func Load() (Config, error) {
+	if file := os.Getenv("CONFIG_FILE"); file != "" {
+		if err := godotenv.Load(file); err != nil {
+			return Config{}, fmt.Errorf("CONFIG_FILE: %w", err)
+		}
+	}
+	if _, err := os.Stat(".env"); err == nil {
+		if err := godotenv.Load(); err != nil {
+			return Config{}, fmt.Errorf(".env: %w", err)
+		}
+	}
+
+	var config Config
+	if err := env.ParseWithOptions(&config, env.Options{FuncMap: parsers}); err != nil {
+		return Config{}, byVariable(err)
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// byVariable rewrites env's parse errors, which name struct fields, to name the
+// variables the operator set
+func byVariable(err error) error {
+	var aggregate env.AggregateError
+	if !errors.As(err, &aggregate) {
+		return err
+	}
+	errs := make([]error, 0, len(aggregate.Errors))
+	for _, err := range aggregate.Errors {
+		var parseErr env.ParseError
+		if errors.As(err, &parseErr) {
+			if field, ok := reflect.TypeFor[Config]().FieldByName(parseErr.Name); ok {
+				name, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+				err = fmt.Errorf("%s: %w", name, parseErr.Err)
+			}
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Validate reports every setting that is out of range, not just the first
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	if c.ShutdownTimeout <= 0 {
+		invalid("SHUTDOWN_TIMEOUT must be positive, got %s", c.ShutdownTimeout)
+	}
+	if c.RequestTimeout <= 0 {
+		invalid("REQUEST_TIMEOUT must be positive, got %s", c.RequestTimeout)
+	}
+	for route, limit := range c.RouteTimeouts {
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			invalid("ROUTE_TIMEOUTS: route %q must be a method and a path, e.g. \"GET /customers/:id\"", route)
+		}
+		if limit <= 0 {
+			invalid("ROUTE_TIMEOUTS: %s must be positive, got %s", route, limit)
+		}
+	}
+	if _, err := pgxpool.ParseConfig(c.DatabaseURL); err != nil {
+		invalid("DATABASE_URL: %w", err)
+	}
+	if c.DBMaxConns < 0 {
+		invalid("DB_MAX_CONNS must not be negative, got %d", c.DBMaxConns)
+	}
+	if c.DBMinConns < 0 || (c.DBMaxConns > 0 && c.DBMinConns > c.DBMaxConns) {
+		invalid("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS, got %d", c.DBMinConns)
+	}
+	if c.DBConnectAttempts < 1 {
+		invalid("DB_CONNECT_ATTEMPTS must be at least 1, got %d", c.DBConnectAttempts)
+	}
+	if c.DBConnectBackoff <= 0 {
+		invalid("DB_CONNECT_BACKOFF must be positive, got %s", c.DBConnectBackoff)
+	}
+	if c.DBHealthCheckPeriod <= 0 {
+		invalid("DB_HEALTH_CHECK_PERIOD must be positive, got %s", c.DBHealthCheckPeriod)
+	}
+	if c.RateLimitRPS < 0 {
+		invalid("RATE_LIMIT_RPS must not be negative, got %v", c.RateLimitRPS)
+	}
+	if c.RateLimitBurst < 0 {
+		invalid("RATE_LIMIT_BURST must not be negative, got %d", c.RateLimitBurst)
+	}
+	if c.HSTSMaxAge < 0 {
+		invalid("HSTS_MAX_AGE must not be negative, got %d", c.HSTSMaxAge)
+	}
+	return errors.Join(errs...)
+}
+
+// ConnectRetry returns how long to wait for the database at startup
+func (c Config) ConnectRetry() database.Retry {
+	return database.Retry{
+		Attempts:       c.DBConnectAttempts,
+		InitialBackoff: c.DBConnectBackoff,
+		MaxBackoff:     database.DefaultRetry.MaxBackoff,
+	}
+}
+
+// Auth returns the credentials the REST and gRPC APIs accept
+func (c Config) Auth() auth.Config {
+	return auth.Config{APIKeys: c.APIKeys, JWTSecret: []byte(c.JWTSecret)}
+}
+
+// Timeouts returns the request timeout of every route. A route given without a
+// version prefix applies to it in every API version.
+func (c Config) Timeouts() timeout.Config {
+	routes := make(map[string]time.Duration, len(c.RouteTimeouts))
+	for route, limit := range c.RouteTimeouts {
+		method, path, _ := strings.Cut(strings.TrimSpace(route), " ")
+		for _, registered := range versioning.Registered(path) {
+			routes[method+" "+registered] = limit
+		}
+	}
+	return timeout.Config{Default: c.RequestTimeout, Routes: routes}
+}
+
+// Security returns the CORS and security header settings
+func (c Config) Security() security.Config {
+	var origins []string
+	for _, origin := range c.CORSAllowOrigins {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return security.Config{
+		AllowOrigins:          origins,
+		ContentSecurityPolicy: c.ContentSecurityPolicy,
+		HSTSMaxAge:            c.HSTSMaxAge,
+	}
+}
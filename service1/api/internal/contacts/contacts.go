@@ -0,0 +1,263 @@
+package contacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service1/api/internal/database"
+	"service1/api/internal/tenant"
+)
+
+// ChannelType is the medium used to reach a customer
+type ChannelType string
+
+const (
+	ChannelEmail ChannelType = "email"
+	ChannelPhone ChannelType = "phone"
+	ChannelSMS   ChannelType = "sms"
+)
+
+// ContactChannel is one way of reaching a customer, e.g. for servicing notifications.
+// At most one channel per type is preferred for a customer.
+type ContactChannel struct {
+	Id         uuid.UUID   `json:"id"`
+	CustomerId uuid.UUID   `json:"customer_id"`
+	Type       ChannelType `json:"type" validate:"required,oneof=email phone sms"`
+	Value      string      `json:"value" validate:"required,max=255"`
+	Preferred  bool        `json:"preferred"`
+	OptedIn    bool        `json:"opted_in"` // customer consents to notifications on this channel
+	CreatedAt  time.Time   `json:"created_at"`
+	ModifiedAt time.Time   `json:"modified_at"`
+}
+
+// emailValue and phoneValue carry the format rule for a channel's value
+type emailValue struct {
+	Value string `json:"value" validate:"rfc_email"`
+}
+
+type phoneValue struct {
+	Value string `json:"value" validate:"e164"`
+}
+
+// valueRule returns the channel value wrapped in the struct that validates its format
+func (c ContactChannel) valueRule() any {
+	if c.Type == ChannelEmail {
+		return emailValue{c.Value}
+	}
+	return phoneValue{c.Value}
+}
+
+var (
+	// ErrNotFound is returned when the customer has no contact channel with the requested ID
+	ErrNotFound = errors.New("contact channel not found")
+	// ErrCustomerNotFound is returned when adding a channel to a customer that does not exist
+	ErrCustomerNotFound = errors.New("customer not found")
+)
+
+type Repository interface {
+	Create(ctx context.Context, channel ContactChannel) (ContactChannel, error)
+	Read(ctx context.Context, customerId, id uuid.UUID) (ContactChannel, error)
+	Update(ctx context.Context, channel ContactChannel) (ContactChannel, error)
+	Delete(ctx context.Context, customerId, id uuid.UUID) error
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]ContactChannel, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, channel ContactChannel) (ContactChannel, error)
+	Read(ctx context.Context, customerId, id uuid.UUID) (ContactChannel, error)
+	Update(ctx context.Context, channel ContactChannel) (ContactChannel, error)
+	Delete(ctx context.Context, customerId, id uuid.UUID) error
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]ContactChannel, error)
+}
+
+const channelColumns = "id, customer_id, type, value, preferred, opted_in, created_at, modified_at"
+
+// scanChannel scans a row selected with channelColumns
+func scanChannel(row pgx.Row) (ContactChannel, error) {
+	var channel ContactChannel
+	err := row.Scan(&channel.Id, &channel.CustomerId, &channel.Type, &channel.Value, &channel.Preferred,
+		&channel.OptedIn, &channel.CreatedAt, &channel.ModifiedAt)
+	return channel, err
+}
+
+type ContactRepository struct {
+	db database.DB
+}
+
+func NewContactRepository(pool *pgxpool.Pool) *ContactRepository {
+	return &ContactRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *ContactRepository) WithTx(tx pgx.Tx) *ContactRepository {
+	return &ContactRepository{tx}
+}
+
+func (r *ContactRepository) Create(ctx context.Context, channel ContactChannel) (ContactChannel, error) {
+	var created ContactChannel
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		check := "SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1 AND tenant_id = $2)"
+		if err := tx.QueryRow(ctx, check, channel.CustomerId, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return ErrCustomerNotFound
+		}
+		if err := clearPreferred(ctx, tx, channel); err != nil {
+			return err
+		}
+		sql := `INSERT INTO contact_channels
+			(id, customer_id, type, value, preferred, opted_in, created_at, modified_at)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+			RETURNING ` + channelColumns
+		var err error
+		created, err = scanChannel(tx.QueryRow(ctx, sql,
+			channel.Id,
+			channel.CustomerId,
+			channel.Type,
+			channel.Value,
+			channel.Preferred,
+			channel.OptedIn,
+		))
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+			return ErrCustomerNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return ContactChannel{}, err
+	}
+	return created, nil
+}
+
+func (r *ContactRepository) Read(ctx context.Context, customerId, id uuid.UUID) (ContactChannel, error) {
+	sql := "SELECT " + channelColumns + " FROM contact_channels WHERE id = $1 AND customer_id = $2 AND " + inTenant(3)
+	channel, err := scanChannel(r.db.QueryRow(ctx, sql, id, customerId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ContactChannel{}, ErrNotFound
+	}
+	if err != nil {
+		return ContactChannel{}, err
+	}
+	return channel, nil
+}
+
+func (r *ContactRepository) Update(ctx context.Context, channel ContactChannel) (ContactChannel, error) {
+	var updated ContactChannel
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		if err := clearPreferred(ctx, tx, channel); err != nil {
+			return err
+		}
+		sql := `UPDATE contact_channels
+			SET type = $1, value = $2, preferred = $3, opted_in = $4, modified_at = NOW()
+			WHERE id = $5 AND customer_id = $6 AND ` + inTenant(7) + `
+			RETURNING ` + channelColumns
+		var err error
+		updated, err = scanChannel(tx.QueryRow(ctx, sql,
+			channel.Type,
+			channel.Value,
+			channel.Preferred,
+			channel.OptedIn,
+			channel.Id,
+			channel.CustomerId,
+			tenant.FromContext(ctx),
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return ContactChannel{}, err
+	}
+	return updated, nil
+}
+
+func (r *ContactRepository) Delete(ctx context.Context, customerId, id uuid.UUID) error {
+	sql := "DELETE FROM contact_channels WHERE id = $1 AND customer_id = $2 AND " + inTenant(3)
+	_, err := r.db.Exec(ctx, sql, id, customerId, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ContactRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]ContactChannel, error) {
+	sql := "SELECT " + channelColumns + ` FROM contact_channels
+		WHERE customer_id = $1 AND ` + inTenant(2) + `
+		ORDER BY type, preferred DESC, created_at`
+	rows, err := r.db.Query(ctx, sql, customerId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []ContactChannel{}
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (r *ContactRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, r.db, fn)
+}
+
+// inTenant is a condition limiting contact_channels to the customers of the tenant
+// passed as parameter n
+func inTenant(n int) string {
+	return fmt.Sprintf("customer_id IN (SELECT id FROM customers WHERE tenant_id = $%d)", n)
+}
+
+// clearPreferred unsets the customer's other preferred channel of the same type
+// before channel is saved as preferred
+func clearPreferred(ctx context.Context, tx pgx.Tx, channel ContactChannel) error {
+	if !channel.Preferred {
+		return nil
+	}
+	sql := `UPDATE contact_channels SET preferred = false, modified_at = NOW()
+		WHERE customer_id = $1 AND type = $2 AND preferred AND id <> $3`
+	_, err := tx.Exec(ctx, sql, channel.CustomerId, channel.Type, channel.Id)
+	return err
+}
+
+type ContactService struct {
+	repo Repository
+}
+
+func NewContactService(repo Repository) *ContactService {
+	return &ContactService{repo}
+}
+
+func (s *ContactService) Create(ctx context.Context, channel ContactChannel) (ContactChannel, error) {
+	return s.repo.Create(ctx, channel)
+}
+
+func (s *ContactService) Read(ctx context.Context, customerId, id uuid.UUID) (ContactChannel, error) {
+	return s.repo.Read(ctx, customerId, id)
+}
+
+func (s *ContactService) Update(ctx context.Context, channel ContactChannel) (ContactChannel, error) {
+	return s.repo.Update(ctx, channel)
+}
+
+func (s *ContactService) Delete(ctx context.Context, customerId, id uuid.UUID) error {
+	return s.repo.Delete(ctx, customerId, id)
+}
+
+func (s *ContactService) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]ContactChannel, error) {
+	return s.repo.GetByCustomerId(ctx, customerId)
+}
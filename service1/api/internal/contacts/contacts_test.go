@@ -0,0 +1,45 @@
+package contacts
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/validation"
+)
+
+func TestBindChannel(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		valid bool
+	}{
+		{"email", `{"type": "email", "value": "jane@example.com"}`, true},
+		{"phone", `{"type": "phone", "value": "+14165550100", "preferred": true}`, true},
+		{"sms with spacing and case", `{"type": " SMS ", "value": " +442071838750 "}`, true},
+		{"invalid email", `{"type": "email", "value": "not-an-email"}`, false},
+		{"phone without country code", `{"type": "phone", "value": "416-555-0100"}`, false},
+		{"unknown type", `{"type": "fax", "value": "+14165550100"}`, false},
+		{"missing value", `{"type": "phone"}`, false},
+	}
+
+	e := echo.New()
+	e.Validator = validation.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/customers/id/contacts", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			channel, err := bindChannel(c)
+			if tt.valid && err != nil {
+				t.Errorf("Expected payload to be valid, got: %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("Expected payload to be rejected, got %+v", channel)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package contacts
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/apierror"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewContactHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	customerId, err := parseID(c, "id", "invalid customer id")
+	if err != nil {
+		return err
+	}
+	channel, err := bindChannel(c)
+	if err != nil {
+		return err
+	}
+
+	channel.Id = uuid.New()
+	channel.CustomerId = customerId
+	created, err := h.service.Create(c.Request().Context(), *channel)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	customerId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	channel, err := h.service.Read(c.Request().Context(), customerId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, channel)
+}
+
+func (h *Handler) Update(c echo.Context) error {
+	customerId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+	channel, err := bindChannel(c)
+	if err != nil {
+		return err
+	}
+
+	channel.Id = id
+	channel.CustomerId = customerId
+	updated, err := h.service.Update(c.Request().Context(), *channel)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	customerId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), customerId, id); err != nil {
+		return httpError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetByCustomerId(c echo.Context) error {
+	customerId, err := parseID(c, "id", "invalid customer id")
+	if err != nil {
+		return err
+	}
+
+	channels, err := h.service.GetByCustomerId(c.Request().Context(), customerId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, channels)
+}
+
+// bindChannel binds and validates a channel payload, including the format of its value
+func bindChannel(c echo.Context) (*ContactChannel, error) {
+	channel := new(ContactChannel)
+	if err := c.Bind(channel); err != nil {
+		return nil, err
+	}
+	channel.Type = ChannelType(strings.ToLower(strings.TrimSpace(string(channel.Type))))
+	channel.Value = strings.TrimSpace(channel.Value)
+	if err := c.Validate(channel); err != nil {
+		return nil, err
+	}
+	if err := c.Validate(channel.valueRule()); err != nil {
+		return nil, err
+	}
+	return channel, nil
+}
+
+func parseIDs(c echo.Context) (customerId, id uuid.UUID, err error) {
+	customerId, err = parseID(c, "id", "invalid customer id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err = parseID(c, "contactId", "invalid contact channel id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return customerId, id, nil
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest(message, err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrCustomerNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,11 @@
+package contacts
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/customers/:id/contacts", handler.Create)
+	g.GET("/customers/:id/contacts", handler.GetByCustomerId)
+	g.GET("/customers/:id/contacts/:contactId", handler.Read)
+	g.PUT("/customers/:id/contacts/:contactId", handler.Update)
+	g.DELETE("/customers/:id/contacts/:contactId", handler.Delete)
+}
@@ -0,0 +1,88 @@
+package customers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"service1/api/internal/actor"
+	"service1/api/internal/tenant"
+)
+
+// AuditAction names the kind of change recorded in the audit trail
+type AuditAction string
+
+const (
+	AuditCreate    AuditAction = "create"
+	AuditUpdate    AuditAction = "update"
+	AuditDelete    AuditAction = "delete"
+	AuditAnonymize AuditAction = "anonymize"
+	AuditMerge     AuditAction = "merge"
+	AuditKYC       AuditAction = "kyc"
+)
+
+// AuditEntry is one change to a customer: who made it, when, and the customer
+// before and after the change
+type AuditEntry struct {
+	Id         uuid.UUID   `json:"id"`
+	CustomerId uuid.UUID   `json:"customer_id"`
+	Action     AuditAction `json:"action"`
+	Actor      string      `json:"actor"`
+	OldValues  *Customer   `json:"old_values"`
+	NewValues  *Customer   `json:"new_values"`
+	ChangedAt  time.Time   `json:"changed_at"`
+}
+
+// recordAudit writes an audit entry in the caller's transaction. The actor and the
+// tenant are taken from ctx (see actor.Middleware and tenant.Middleware).
+func recordAudit(ctx context.Context, tx pgx.Tx, id uuid.UUID, action AuditAction, oldValues, newValues *Customer) error {
+	sql := `INSERT INTO customers_audit (id, tenant_id, customer_id, action, actor, old_values, new_values, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	_, err := tx.Exec(ctx, sql, uuid.New(), tenant.FromContext(ctx), id, action, actor.FromContext(ctx),
+		auditValues(oldValues), auditValues(newValues))
+	return err
+}
+
+// auditValues marshals a customer snapshot, keeping nil as SQL NULL
+func auditValues(customer *Customer) []byte {
+	if customer == nil {
+		return nil
+	}
+	data, _ := json.Marshal(customer) // Customer always marshals
+	return data
+}
+
+// scrubAudit removes the recorded values from a customer's history, keeping who
+// changed what and when; used when the customer's personal data is erased
+func scrubAudit(ctx context.Context, tx pgx.Tx, id uuid.UUID) error {
+	sql := "UPDATE customers_audit SET old_values = NULL, new_values = NULL WHERE customer_id = $1"
+	_, err := tx.Exec(ctx, sql, id)
+	return err
+}
+
+// History returns the audit trail of a customer, oldest first. It is kept after
+// the customer is deleted.
+func (c *CustomersRepository) History(ctx context.Context, id uuid.UUID) ([]AuditEntry, error) {
+	sql := `SELECT id, customer_id, action, actor, old_values, new_values, changed_at
+		FROM customers_audit WHERE customer_id = $1 AND tenant_id = $2
+		ORDER BY changed_at, id`
+	rows, err := c.db.Query(ctx, sql, id, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var entry AuditEntry
+		err := rows.Scan(&entry.Id, &entry.CustomerId, &entry.Action, &entry.Actor,
+			&entry.OldValues, &entry.NewValues, &entry.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
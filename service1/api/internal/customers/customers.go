@@ -2,81 +2,604 @@ package customers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service1/api/internal/database"
+	"service1/api/internal/outbox"
+	"service1/api/internal/pagination"
+	"service1/api/internal/tenant"
+	"service1/api/internal/webhooks"
 )
 
 type Customer struct {
-	Id         uuid.UUID `json:"id"`
-	Name       string    `json:"name"`
-	Email      string    `json:"email"`
+	Id uuid.UUID `json:"id"`
+	// TenantId is the lender the customer belongs to, taken from the creating request
+	TenantId   string    `json:"tenant_id"`
+	Name       string    `json:"name" validate:"required,max=255"`
+	Email      string    `json:"email" validate:"required,max=254,rfc_email"`
 	CreatedAt  time.Time `json:"created_at"`
 	ModifiedAt time.Time `json:"modified_at"`
+	Version    int       `json:"version"`
+	// AnonymizedAt is set once the customer's personal data has been scrubbed
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty"`
+	// MergedInto is the surviving customer once this duplicate has been merged
+	MergedInto *uuid.UUID `json:"merged_into,omitempty"`
+	KYCStatus  KYCStatus  `json:"kyc_status"`
 }
 
+// KYCStatus is the know-your-customer verification state of a customer
+type KYCStatus string
+
+const (
+	KYCUnverified KYCStatus = "unverified"
+	KYCPending    KYCStatus = "pending"
+	KYCVerified   KYCStatus = "verified"
+	KYCFailed     KYCStatus = "failed"
+)
+
+// kycTransitions lists the statuses each KYC status may move to. Verified is final;
+// a failed check can be resubmitted.
+var kycTransitions = map[KYCStatus][]KYCStatus{
+	KYCUnverified: {KYCPending},
+	KYCPending:    {KYCVerified, KYCFailed},
+	KYCFailed:     {KYCPending},
+}
+
+// CanTransitionTo reports whether the KYC workflow allows moving from s to next
+func (s KYCStatus) CanTransitionTo(next KYCStatus) bool {
+	for _, allowed := range kycTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// KYCStatusChanged is the payload of the CustomerKYCStatusChanged event
+type KYCStatusChanged struct {
+	Id   uuid.UUID `json:"id"`
+	From KYCStatus `json:"from"`
+	To   KYCStatus `json:"to"`
+}
+
+// ErrInvalidKYCTransition is returned when a KYC transition is not allowed from the current status
+var ErrInvalidKYCTransition = errors.New("invalid KYC status transition")
+
+// Domain events written to the outbox alongside customer changes
+const (
+	AggregateType           = "customer"
+	EventCustomerCreated    = "CustomerCreated"
+	EventCustomerUpdated    = "CustomerUpdated"
+	EventCustomerDeleted    = "CustomerDeleted"
+	EventCustomerAnonymized = "CustomerAnonymized"
+	EventCustomerMerged     = "CustomerMerged"
+	EventKYCStatusChanged   = "CustomerKYCStatusChanged"
+)
+
+// Events lists the event types above; webhooks can be subscribed to them
+var Events = []string{EventCustomerCreated, EventCustomerUpdated, EventCustomerDeleted, EventCustomerAnonymized,
+	EventCustomerMerged, EventKYCStatusChanged}
+
+// AnonymizedName replaces the name of an anonymized customer
+const AnonymizedName = "Anonymized Customer"
+
+// ErrNotFound is returned when no customer exists with the requested ID
+var ErrNotFound = errors.New("customer not found")
+
+// ErrVersionConflict is returned when an update targets a stale customer version
+var ErrVersionConflict = errors.New("customer was modified by another request")
+
+// ErrTooManyIds is returned when more than MaxListLimit customers are requested by ID
+var ErrTooManyIds = errors.New("too many customer IDs")
+
+// CustomerPatch is a sparse customer update; nil fields are left unchanged.
+// When Version is set the patch only applies to that version of the customer.
+type CustomerPatch struct {
+	Name    *string `json:"name" validate:"omitnil,min=1,max=255"`
+	Email   *string `json:"email" validate:"omitnil,min=1,max=254,rfc_email"`
+	Version *int    `json:"version,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p CustomerPatch) IsEmpty() bool {
+	return p.Name == nil && p.Email == nil
+}
+
+// AnonymizationRequest records who asked for a customer's personal data to be erased and why
+type AnonymizationRequest struct {
+	RequestedBy string `json:"requested_by" validate:"required,max=255"`
+	Reason      string `json:"reason" validate:"max=1000"`
+}
+
+// ErrAlreadyAnonymized is returned when anonymizing or updating an anonymized customer
+var ErrAlreadyAnonymized = errors.New("customer is already anonymized")
+
+// MergeRequest names the duplicate customer to fold into the target customer
+type MergeRequest struct {
+	SourceId uuid.UUID `json:"source_id" validate:"required"`
+}
+
+// CustomerMerged is the payload of the CustomerMerged event; services holding a
+// customer_id should re-point SourceId to TargetId
+type CustomerMerged struct {
+	SourceId uuid.UUID `json:"source_id"`
+	TargetId uuid.UUID `json:"target_id"`
+}
+
+var (
+	// ErrMergeWithSelf is returned when a customer is merged into itself
+	ErrMergeWithSelf = errors.New("cannot merge a customer into itself")
+	// ErrAlreadyMerged is returned when either customer has already been merged away,
+	// and when updating a customer that was
+	ErrAlreadyMerged = errors.New("customer has already been merged")
+)
+
+// CustomerFilter narrows and pages a customer listing. Name and Email match
+// case-insensitive substrings; empty values match everything.
+type CustomerFilter struct {
+	Name   string
+	Email  string
+	Limit  int
+	Offset int
+}
+
+const (
+	DefaultListLimit = pagination.DefaultLimit
+	MaxListLimit     = pagination.MaxLimit
+)
+
 type Repository interface {
-	Create(ctx context.Context, customer Customer) error
+	Create(ctx context.Context, customer Customer) (Customer, error)
 	Read(ctx context.Context, id uuid.UUID) (Customer, error)
-	Update(ctx context.Context, customer Customer) error
+	ReadMany(ctx context.Context, ids []uuid.UUID) ([]Customer, error)
+	Update(ctx context.Context, customer Customer) (Customer, error)
+	UpdateFields(ctx context.Context, id uuid.UUID, patch CustomerPatch) (Customer, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter CustomerFilter) ([]Customer, error)
+	Anonymize(ctx context.Context, id uuid.UUID, request AnonymizationRequest) (Customer, error)
+	Merge(ctx context.Context, targetId, sourceId uuid.UUID) (Customer, error)
+	TransitionKYC(ctx context.Context, id uuid.UUID, to KYCStatus) (Customer, error)
+	History(ctx context.Context, id uuid.UUID) ([]AuditEntry, error)
 }
 
 type Service interface {
-	Create(ctx context.Context, customer Customer) error
+	Create(ctx context.Context, customer Customer) (Customer, error)
 	Read(ctx context.Context, id uuid.UUID) (Customer, error)
-	Update(ctx context.Context, customer Customer) error
+	ReadMany(ctx context.Context, ids []uuid.UUID) ([]Customer, error)
+	Update(ctx context.Context, customer Customer) (Customer, error)
+	UpdateFields(ctx context.Context, id uuid.UUID, patch CustomerPatch) (Customer, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filter CustomerFilter) ([]Customer, error)
+	Anonymize(ctx context.Context, id uuid.UUID, request AnonymizationRequest) (Customer, error)
+	Merge(ctx context.Context, targetId, sourceId uuid.UUID) (Customer, error)
+	TransitionKYC(ctx context.Context, id uuid.UUID, to KYCStatus) (Customer, error)
+	History(ctx context.Context, id uuid.UUID) ([]AuditEntry, error)
+}
+
+const customerColumns = "id, tenant_id, name, email, created_at, modified_at, version, anonymized_at, merged_into, kyc_status"
+
+// scanCustomer scans a row selected with customerColumns
+func scanCustomer(row pgx.Row) (Customer, error) {
+	var customer Customer
+	err := row.Scan(&customer.Id, &customer.TenantId, &customer.Name, &customer.Email, &customer.CreatedAt, &customer.ModifiedAt,
+		&customer.Version, &customer.AnonymizedAt, &customer.MergedInto, &customer.KYCStatus)
+	return customer, err
 }
 
 type CustomersRepository struct {
-	conn *pgx.Conn
+	db database.DB
 }
 
-func NewCustomersRepository(conn *pgx.Conn) *CustomersRepository {
-	return &CustomersRepository{conn}
+func NewCustomersRepository(pool *pgxpool.Pool) *CustomersRepository {
+	return &CustomersRepository{pool}
 }
 
-func (c *CustomersRepository) Create(ctx context.Context, customer Customer) error {
-	sql := "INSERT INTO customers (id, name, email, created_at, modified_at) VALUES ($1, $2, $3, NOW(), NOW())"
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (c *CustomersRepository) WithTx(tx pgx.Tx) *CustomersRepository {
+	return &CustomersRepository{tx}
+}
 
-	_, err := c.conn.Exec(ctx, sql, customer.Id, customer.Name, customer.Email)
+// Create inserts the customer and returns it as stored, with its tenant, version and timestamps
+func (c *CustomersRepository) Create(ctx context.Context, customer Customer) (Customer, error) {
+	var created Customer
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		sql := `INSERT INTO customers (id, tenant_id, name, email, created_at, modified_at, version, kyc_status)
+			VALUES ($1, $2, $3, $4, NOW(), NOW(), 1, $5)
+			RETURNING ` + customerColumns
+		row := tx.QueryRow(ctx, sql, customer.Id, tenant.FromContext(ctx), customer.Name, customer.Email, KYCUnverified)
+		var err error
+		created, err = scanCustomer(row)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, created.Id, AuditCreate, nil, &created); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, created.Id, EventCustomerCreated, created)
+	})
 	if err != nil {
-		return err
+		return Customer{}, err
 	}
-	return nil
+	return created, nil
 }
 
 func (c *CustomersRepository) Read(ctx context.Context, id uuid.UUID) (Customer, error) {
-	sql := "SELECT id, name, email, created_at, modified_at FROM customers WHERE id = $1"
-	row := c.conn.QueryRow(ctx, sql, id)
+	sql := "SELECT " + customerColumns + " FROM customers WHERE id = $1 AND tenant_id = $2"
+	row := c.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx))
+	customer, err := scanCustomer(row)
+	if err != nil {
+		return Customer{}, notFoundOr(err)
+	}
+	return customer, nil
+}
+
+// ReadMany returns the customers with the given IDs in the order requested, skipping
+// IDs with no customer. The lookups are sent as one batch, so the number of IDs does
+// not add round trips.
+func (c *CustomersRepository) ReadMany(ctx context.Context, ids []uuid.UUID) ([]Customer, error) {
+	customers := []Customer{}
+	batch := &pgx.Batch{}
+	sql := "SELECT " + customerColumns + " FROM customers WHERE id = $1 AND tenant_id = $2"
+	for _, id := range ids {
+		batch.Queue(sql, id, tenant.FromContext(ctx)).QueryRow(func(row pgx.Row) error {
+			customer, err := scanCustomer(row)
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			customers = append(customers, customer)
+			return nil
+		})
+	}
+	if err := c.db.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+// Update replaces the customer if customer.Version is still current and returns the
+// customer with its new version. ErrVersionConflict is returned for a stale version,
+// and ErrAlreadyAnonymized or ErrAlreadyMerged for a customer that can no longer change.
+func (c *CustomersRepository) Update(ctx context.Context, customer Customer) (Customer, error) {
+	var updated Customer
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		old, err := lockCustomer(ctx, tx, customer.Id)
+		if err != nil {
+			return err
+		}
+		if err := checkUpdatable(old); err != nil {
+			return err
+		}
+		if old.Version != customer.Version {
+			return ErrVersionConflict
+		}
+
+		sql := `UPDATE customers
+			SET name = $1, email = $2, modified_at = NOW(), version = version + 1
+			WHERE id = $3
+			RETURNING ` + customerColumns
+		updated, err = scanCustomer(tx.QueryRow(ctx, sql, customer.Name, customer.Email, customer.Id))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, updated.Id, AuditUpdate, &old, &updated); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, updated.Id, EventCustomerUpdated, updated)
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return updated, nil
+}
+
+// UpdateFields applies only the fields set in patch and returns the updated customer.
+// It fails like Update for a stale version or an anonymized or merged customer.
+func (c *CustomersRepository) UpdateFields(ctx context.Context, id uuid.UUID, patch CustomerPatch) (Customer, error) {
+	var customer Customer
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		old, err := lockCustomer(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkUpdatable(old); err != nil {
+			return err
+		}
+		if patch.Version != nil && old.Version != *patch.Version {
+			return ErrVersionConflict
+		}
+
+		sql := `UPDATE customers
+			SET name = COALESCE($1, name), email = COALESCE($2, email), modified_at = NOW(), version = version + 1
+			WHERE id = $3
+			RETURNING ` + customerColumns
+		customer, err = scanCustomer(tx.QueryRow(ctx, sql, patch.Name, patch.Email, id))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, id, AuditUpdate, &old, &customer); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, customer.Id, EventCustomerUpdated, customer)
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return customer, nil
+}
+
+// Delete removes the customer. Deleting a missing customer is not an error, so saga
+// compensations that delete customers can safely be retried.
+func (c *CustomersRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.withTx(ctx, func(tx pgx.Tx) error {
+		old, err := lockCustomer(ctx, tx, id)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM customers WHERE id = $1", id); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, id, AuditDelete, &old, nil); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, id, EventCustomerDeleted, map[string]uuid.UUID{"id": id})
+	})
+}
+
+// Anonymize irreversibly replaces the customer's name, email and addresses, removes their
+// contact channels and scrubs the values from their audit trail, outbox events and webhook
+// deliveries, while keeping the row and ID so mortgages and loans still reference it. The
+// request is recorded in customer_anonymizations in the same transaction.
+func (c *CustomersRepository) Anonymize(ctx context.Context, id uuid.UUID, request AnonymizationRequest) (Customer, error) {
 	var customer Customer
-	err := row.Scan(&customer.Id, &customer.Name, &customer.Email, &customer.CreatedAt, &customer.ModifiedAt)
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		old, err := lockCustomer(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if old.AnonymizedAt != nil {
+			return ErrAlreadyAnonymized
+		}
+
+		sql := `UPDATE customers
+			SET name = $1, email = 'anonymized+' || id || '@invalid', anonymized_at = NOW(),
+				modified_at = NOW(), version = version + 1
+			WHERE id = $2
+			RETURNING ` + customerColumns
+		customer, err = scanCustomer(tx.QueryRow(ctx, sql, AnonymizedName, id))
+		if err != nil {
+			return err
+		}
+
+		addresses := `UPDATE addresses
+			SET number = NULL, street = NULL, city = NULL, province = NULL, postalCode = NULL
+			WHERE customersId = $1`
+		if _, err := tx.Exec(ctx, addresses, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM contact_channels WHERE customer_id = $1", id); err != nil {
+			return err
+		}
+		if err := scrubAudit(ctx, tx, id); err != nil {
+			return err
+		}
+		if err := scrubEvents(ctx, tx, customer); err != nil {
+			return err
+		}
+
+		anonymization := `INSERT INTO customer_anonymizations (id, customer_id, requested_by, reason, requested_at)
+			VALUES ($1, $2, $3, $4, NOW())`
+		if _, err := tx.Exec(ctx, anonymization, uuid.New(), id, request.RequestedBy, request.Reason); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, id, AuditAnonymize, nil, nil); err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, tx, id, EventCustomerAnonymized, map[string]uuid.UUID{"id": id})
+	})
 	if err != nil {
 		return Customer{}, err
 	}
 	return customer, nil
 }
 
-func (c *CustomersRepository) Update(ctx context.Context, customer Customer) error {
-	sql := "UPDATE customers SET name = $1, email = $2, modified_at = NOW() WHERE id = $3"
-	_, err := c.conn.Exec(ctx, sql, customer.Name, customer.Email, customer.Id)
+// Merge folds the duplicate source customer into target: the source's addresses and
+// contact channels move to the target and the source is marked as merged into it.
+// A CustomerMerged event lets the mortgage and loan services re-point their
+// customer_id references.
+func (c *CustomersRepository) Merge(ctx context.Context, targetId, sourceId uuid.UUID) (Customer, error) {
+	if targetId == sourceId {
+		return Customer{}, ErrMergeWithSelf
+	}
+
+	var target Customer
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		// Lock both rows in a fixed order so concurrent merges cannot deadlock
+		lock := "SELECT " + customerColumns + " FROM customers WHERE id = ANY($1) AND tenant_id = $2 ORDER BY id FOR UPDATE"
+		rows, err := tx.Query(ctx, lock, []uuid.UUID{targetId, sourceId}, tenant.FromContext(ctx))
+		if err != nil {
+			return err
+		}
+		locked := map[uuid.UUID]Customer{}
+		for rows.Next() {
+			customer, err := scanCustomer(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			locked[customer.Id] = customer
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		source, ok := locked[sourceId]
+		if !ok {
+			return fmt.Errorf("merge source: %w", ErrNotFound)
+		}
+		if target, ok = locked[targetId]; !ok {
+			return ErrNotFound
+		}
+		if source.MergedInto != nil || target.MergedInto != nil {
+			return ErrAlreadyMerged
+		}
+
+		if _, err := tx.Exec(ctx, "UPDATE addresses SET customersId = $1 WHERE customersId = $2", targetId, sourceId); err != nil {
+			return err
+		}
+		// The target keeps its own preferred channels
+		contactChannels := `UPDATE contact_channels SET customer_id = $1, preferred = false, modified_at = NOW()
+			WHERE customer_id = $2`
+		if _, err := tx.Exec(ctx, contactChannels, targetId, sourceId); err != nil {
+			return err
+		}
+		merge := `UPDATE customers
+			SET merged_into = $1, modified_at = NOW(), version = version + 1
+			WHERE id = $2
+			RETURNING ` + customerColumns
+		merged, err := scanCustomer(tx.QueryRow(ctx, merge, targetId, sourceId))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, sourceId, AuditMerge, &source, &merged); err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, tx, sourceId, EventCustomerMerged, CustomerMerged{SourceId: sourceId, TargetId: targetId})
+	})
 	if err != nil {
-		return err
+		return Customer{}, err
 	}
-	return nil
+	return target, nil
 }
 
-func (c *CustomersRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	sql := "DELETE FROM customers WHERE id = $1"
-	_, err := c.conn.Exec(ctx, sql, id)
+// TransitionKYC moves the customer's KYC status to the given status, returning
+// ErrInvalidKYCTransition if the workflow does not allow it from the current status
+func (c *CustomersRepository) TransitionKYC(ctx context.Context, id uuid.UUID, to KYCStatus) (Customer, error) {
+	var customer Customer
+	err := c.withTx(ctx, func(tx pgx.Tx) error {
+		old, err := lockCustomer(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !old.KYCStatus.CanTransitionTo(to) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidKYCTransition, old.KYCStatus, to)
+		}
+
+		sql := `UPDATE customers
+			SET kyc_status = $1, modified_at = NOW(), version = version + 1
+			WHERE id = $2
+			RETURNING ` + customerColumns
+		customer, err = scanCustomer(tx.QueryRow(ctx, sql, to, id))
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, id, AuditKYC, &old, &customer); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, id, EventKYCStatusChanged, KYCStatusChanged{Id: id, From: old.KYCStatus, To: to})
+	})
+	if err != nil {
+		return Customer{}, err
+	}
+	return customer, nil
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (c *CustomersRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, c.db, fn)
+}
+
+// recordEvent writes a customer event to the outbox in the caller's transaction
+func recordEvent(ctx context.Context, tx pgx.Tx, id uuid.UUID, eventType string, payload any) error {
+	event, err := outbox.NewEvent(AggregateType, id, eventType, payload)
 	if err != nil {
 		return err
 	}
+	return outbox.Insert(ctx, tx, event)
+}
+
+// scrubEvents replaces the name and email recorded in the customer's events, in the
+// outbox and in webhook deliveries, with the anonymized customer's
+func scrubEvents(ctx context.Context, tx pgx.Tx, anonymized Customer) error {
+	eventTypes := []string{EventCustomerCreated, EventCustomerUpdated}
+	values := map[string]any{"name": anonymized.Name, "email": anonymized.Email}
+	if err := outbox.Scrub(ctx, tx, AggregateType, anonymized.Id, eventTypes, values); err != nil {
+		return err
+	}
+	return webhooks.Scrub(ctx, tx, AggregateType, anonymized.Id, eventTypes, values)
+}
+
+// lockCustomer reads the customer and locks its row until the transaction ends. A
+// customer of another tenant is not found, so the statements run after the lock only
+// touch the request's tenant.
+func lockCustomer(ctx context.Context, tx pgx.Tx, id uuid.UUID) (Customer, error) {
+	sql := "SELECT " + customerColumns + " FROM customers WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+	customer, err := scanCustomer(tx.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if err != nil {
+		return Customer{}, notFoundOr(err)
+	}
+	return customer, nil
+}
+
+// checkUpdatable returns ErrAlreadyAnonymized or ErrAlreadyMerged for a customer whose
+// name and email must not change again: an update would write personal data back
+// after anonymization, or to a duplicate that only points at its merge target
+func checkUpdatable(customer Customer) error {
+	switch {
+	case customer.AnonymizedAt != nil:
+		return ErrAlreadyAnonymized
+	case customer.MergedInto != nil:
+		return ErrAlreadyMerged
+	}
 	return nil
 }
 
+// notFoundOr maps a missing row to ErrNotFound
+func notFoundOr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (c *CustomersRepository) List(ctx context.Context, filter CustomerFilter) ([]Customer, error) {
+	sql := "SELECT " + customerColumns + ` FROM customers
+		WHERE tenant_id = $5
+			AND ($1 = '' OR name ILIKE '%' || $1 || '%')
+			AND ($2 = '' OR email ILIKE '%' || $2 || '%')
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4`
+	rows, err := c.db.Query(ctx, sql, filter.Name, filter.Email, filter.Limit, filter.Offset, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []Customer{}
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	return customers, rows.Err()
+}
+
 type CustomerService struct {
 	repo Repository
 }
@@ -85,7 +608,7 @@ func NewCustomerService(repo Repository) *CustomerService {
 	return &CustomerService{repo}
 }
 
-func (c *CustomerService) Create(ctx context.Context, customer Customer) error {
+func (c *CustomerService) Create(ctx context.Context, customer Customer) (Customer, error) {
 	return c.repo.Create(ctx, customer)
 }
 
@@ -93,10 +616,61 @@ func (c *CustomerService) Read(ctx context.Context, id uuid.UUID) (Customer, err
 	return c.repo.Read(ctx, id)
 }
 
-func (c *CustomerService) Update(ctx context.Context, customer Customer) error {
+// ReadMany returns the customers with the given IDs, each at most once
+func (c *CustomerService) ReadMany(ctx context.Context, ids []uuid.UUID) ([]Customer, error) {
+	if len(ids) > MaxListLimit {
+		return nil, ErrTooManyIds
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return c.repo.ReadMany(ctx, unique)
+}
+
+func (c *CustomerService) Update(ctx context.Context, customer Customer) (Customer, error) {
 	return c.repo.Update(ctx, customer)
 }
 
+func (c *CustomerService) UpdateFields(ctx context.Context, id uuid.UUID, patch CustomerPatch) (Customer, error) {
+	if patch.IsEmpty() {
+		customer, err := c.repo.Read(ctx, id)
+		if err == nil && patch.Version != nil && *patch.Version != customer.Version {
+			return Customer{}, ErrVersionConflict
+		}
+		return customer, err
+	}
+	return c.repo.UpdateFields(ctx, id, patch)
+}
+
 func (c *CustomerService) Delete(ctx context.Context, id uuid.UUID) error {
 	return c.repo.Delete(ctx, id)
 }
+
+func (c *CustomerService) Anonymize(ctx context.Context, id uuid.UUID, request AnonymizationRequest) (Customer, error) {
+	return c.repo.Anonymize(ctx, id, request)
+}
+
+func (c *CustomerService) Merge(ctx context.Context, targetId, sourceId uuid.UUID) (Customer, error) {
+	return c.repo.Merge(ctx, targetId, sourceId)
+}
+
+func (c *CustomerService) TransitionKYC(ctx context.Context, id uuid.UUID, to KYCStatus) (Customer, error) {
+	return c.repo.TransitionKYC(ctx, id, to)
+}
+
+func (c *CustomerService) History(ctx context.Context, id uuid.UUID) ([]AuditEntry, error) {
+	return c.repo.History(ctx, id)
+}
+
+func (c *CustomerService) List(ctx context.Context, filter CustomerFilter) ([]Customer, error) {
+	filter.Limit = pagination.ClampLimit(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return c.repo.List(ctx, filter)
+}
@@ -1,10 +1,14 @@
 package customers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"service1/api/internal/apierror"
 )
 
 type Handler struct {
@@ -20,47 +24,109 @@ func (h *Handler) Create(c echo.Context) error {
 	if err := c.Bind(customer); err != nil {
 		return err
 	}
+	normalize(customer)
+	if err := c.Validate(customer); err != nil {
+		return err
+	}
 
 	customer.Id = uuid.New()
-	if err := h.service.Create(c.Request().Context(), *customer); err != nil {
+	created, err := h.service.Create(c.Request().Context(), *customer)
+	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusCreated, customer)
+	setETag(c, created.Version)
+	return c.JSON(http.StatusCreated, created)
 }
 
 func (h *Handler) Read(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c)
 	if err != nil {
 		return err
 	}
 
 	customer, err := h.service.Read(c.Request().Context(), id)
 	if err != nil {
-		return err
+		return httpError(err)
+	}
+	if notModified(c, setETag(c, customer.Version)) {
+		return c.NoContent(http.StatusNotModified)
 	}
 	return c.JSON(http.StatusOK, customer)
 }
 
+// Update replaces a customer. The expected version must be sent in an If-Match header
+// or as "version" in the body; updates against a stale version are rejected with 409.
 func (h *Handler) Update(c echo.Context) error {
-	id := c.Param("id")
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
 	customer := new(Customer)
 	if err := c.Bind(customer); err != nil {
 		return err
 	}
-	var err error
-	customer.Id, err = uuid.Parse(id)
+	normalize(customer)
+	if err := c.Validate(customer); err != nil {
+		return err
+	}
+	customer.Id = id
+
+	version, ok, err := ifMatchVersion(c)
 	if err != nil {
 		return err
 	}
-	if err := h.service.Update(c.Request().Context(), *customer); err != nil {
+	if ok {
+		customer.Version = version
+	}
+	if customer.Version == 0 {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header or version is required")
+	}
+
+	updated, err := h.service.Update(c.Request().Context(), *customer)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, updated.Version)
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) Patch(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	patch := new(CustomerPatch)
+	if err := c.Bind(patch); err != nil {
+		return err
+	}
+	if patch.Name != nil {
+		*patch.Name = strings.TrimSpace(*patch.Name)
+	}
+	if patch.Email != nil {
+		*patch.Email = strings.TrimSpace(*patch.Email)
+	}
+	if err := c.Validate(patch); err != nil {
+		return err
+	}
+	version, ok, err := ifMatchVersion(c)
+	if err != nil {
 		return err
 	}
+	if ok {
+		patch.Version = &version
+	}
+
+	customer, err := h.service.UpdateFields(c.Request().Context(), id, *patch)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, customer.Version)
 	return c.JSON(http.StatusOK, customer)
 }
 
 func (h *Handler) Delete(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c)
 	if err != nil {
 		return err
 	}
@@ -69,3 +135,195 @@ func (h *Handler) Delete(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+// List pages through customers, or returns the customers named by repeated id
+// parameters in one batch when any are given
+func (h *Handler) List(c echo.Context) error {
+	var filter CustomerFilter
+	var rawIds []string
+	err := echo.QueryParamsBinder(c).
+		Strings("id", &rawIds).
+		String("name", &filter.Name).
+		String("email", &filter.Email).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
+	if err != nil {
+		return err
+	}
+
+	if len(rawIds) > 0 {
+		ids := make([]uuid.UUID, len(rawIds))
+		for i, raw := range rawIds {
+			if ids[i], err = uuid.Parse(raw); err != nil {
+				return apierror.BadRequest("invalid customer id", err)
+			}
+		}
+		customers, err := h.service.ReadMany(c.Request().Context(), ids)
+		if err != nil {
+			return httpError(err)
+		}
+		return c.JSON(http.StatusOK, customers)
+	}
+
+	customers, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, customers)
+}
+
+// Anonymize scrubs the customer's personal data; the customer ID stays valid
+func (h *Handler) Anonymize(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	request := new(AnonymizationRequest)
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+	request.RequestedBy = strings.TrimSpace(request.RequestedBy)
+	if err := c.Validate(request); err != nil {
+		return err
+	}
+
+	customer, err := h.service.Anonymize(c.Request().Context(), id, *request)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, customer.Version)
+	return c.JSON(http.StatusOK, customer)
+}
+
+// Merge folds the customer named by source_id into the customer in the path
+func (h *Handler) Merge(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	request := new(MergeRequest)
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+	if err := c.Validate(request); err != nil {
+		return err
+	}
+
+	customer, err := h.service.Merge(c.Request().Context(), id, request.SourceId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, customer)
+}
+
+// History returns the customer's change history, oldest first
+func (h *Handler) History(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.service.History(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+// SubmitKYC marks the customer's KYC check as pending review
+func (h *Handler) SubmitKYC(c echo.Context) error {
+	return h.transitionKYC(c, KYCPending)
+}
+
+// VerifyKYC records a passed KYC check
+func (h *Handler) VerifyKYC(c echo.Context) error {
+	return h.transitionKYC(c, KYCVerified)
+}
+
+// FailKYC records a failed KYC check
+func (h *Handler) FailKYC(c echo.Context) error {
+	return h.transitionKYC(c, KYCFailed)
+}
+
+func (h *Handler) transitionKYC(c echo.Context, to KYCStatus) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+
+	customer, err := h.service.TransitionKYC(c.Request().Context(), id, to)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, customer.Version)
+	return c.JSON(http.StatusOK, customer)
+}
+
+// parseID reads the customer ID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest("invalid customer id", err)
+	}
+	return id, nil
+}
+
+// normalize trims surrounding whitespace so blank values fail validation
+func normalize(customer *Customer) {
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = strings.TrimSpace(customer.Email)
+}
+
+// setETag exposes the customer version as a weak ETag, which clients send back in
+// If-Match to update and in If-None-Match to poll. It returns the ETag.
+func setETag(c echo.Context, version int) string {
+	etag := "W/" + strconv.Quote(strconv.Itoa(version))
+	c.Response().Header().Set("ETag", etag)
+	return etag
+}
+
+// notModified reports whether If-None-Match names etag or "*", meaning the client's
+// copy is current. ETags are compared weakly, so quoted and W/ forms both match.
+func notModified(c echo.Context, etag string) bool {
+	for _, candidate := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion reads the expected version from the If-Match header, accepting
+// quoted, weak and bare values. ok is false when the header is absent.
+func ifMatchVersion(c echo.Context) (version int, ok bool, err error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" {
+		return 0, false, nil
+	}
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err = strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, false, echo.NewHTTPError(http.StatusBadRequest, "If-Match must be a customer version")
+	}
+	return version, true, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrTooManyIds):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrMergeWithSelf):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrVersionConflict),
+		errors.Is(err, ErrAlreadyAnonymized),
+		errors.Is(err, ErrAlreadyMerged),
+		errors.Is(err, ErrInvalidKYCTransition):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,167 @@
+//go:build integration
+
+package customers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/actor"
+	"service1/api/internal/apierror"
+	"service1/api/internal/customers"
+	"service1/api/internal/tenant"
+	"service1/api/internal/testdb"
+	"service1/api/internal/validation"
+	"service1/api/internal/versioning"
+)
+
+func TestMain(m *testing.M) {
+	testdb.Main(m)
+}
+
+// client calls the customers API served over httptest on a database of its own,
+// wired the way main.go wires it
+type client struct {
+	t      *testing.T
+	url    string
+	header http.Header
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	pool := testdb.New(t)
+	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.Pre(versioning.Middleware())
+	e.Use(tenant.Middleware())
+	e.Use(actor.Middleware())
+	service := customers.NewCustomerService(customers.NewCustomersRepository(pool))
+	customers.Routes(e.Group(versioning.Prefix("v1")), customers.NewCustomersHandler(service))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return &client{t: t, url: server.URL, header: http.Header{}}
+}
+
+// with returns a client that also sends the header key
+func (c *client) with(key, value string) *client {
+	header := c.header.Clone()
+	header.Set(key, value)
+	return &client{t: c.t, url: c.url, header: header}
+}
+
+// do sends body, if not nil, as JSON and decodes a JSON response into out, if not nil
+func (c *client) do(method, path string, body, out any) *http.Response {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header = c.header.Clone()
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			c.t.Fatalf("%s %s: decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestAPI_CustomerLifecycle(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+
+	var created customers.Customer
+	resp := c.do(http.MethodPost, "/v1/customers", map[string]string{"name": "Jane Doe", "email": "jane@example.com"}, &created)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+
+	var read customers.Customer
+	if resp := c.do(http.MethodGet, "/v1/customers/"+created.Id.String(), nil, &read); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if read.Email != "jane@example.com" || read.KYCStatus != customers.KYCUnverified {
+		t.Errorf("Unexpected customer %+v", read)
+	}
+
+	update := map[string]any{"name": "Jane Smith", "email": "jane@example.com", "version": read.Version}
+	var updated customers.Customer
+	if resp := c.do(http.MethodPut, "/v1/customers/"+created.Id.String(), update, &updated); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if updated.Name != "Jane Smith" || updated.Version != read.Version+1 {
+		t.Errorf("Unexpected update %+v", updated)
+	}
+	if resp := c.do(http.MethodPut, "/v1/customers/"+created.Id.String(), update, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected a stale version to get 409, got %d", resp.StatusCode)
+	}
+
+	if resp := c.do(http.MethodDelete, "/v1/customers/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", resp.StatusCode)
+	}
+	if resp := c.do(http.MethodGet, "/v1/customers/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a deleted customer to be 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPI_RejectsInvalidCustomer(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+
+	resp := c.do(http.MethodPost, "/v1/customers", map[string]string{"name": "Jane Doe", "email": "not-an-email"}, nil)
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPI_TenantsAreIsolated(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	lenderA, lenderB := c.with(tenant.Header, "lender-a"), c.with(tenant.Header, "lender-b")
+
+	var created customers.Customer
+	if resp := lenderA.do(http.MethodPost, "/v1/customers", map[string]string{"name": "Jane Doe", "email": "jane@example.com"}, &created); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	if resp := lenderB.do(http.MethodGet, "/v1/customers/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected another tenant's customer to be 404, got %d", resp.StatusCode)
+	}
+	var listed []customers.Customer
+	if lenderB.do(http.MethodGet, "/v1/customers", nil, &listed); len(listed) != 0 {
+		t.Errorf("Expected lender-b to list no customers, got %d", len(listed))
+	}
+}
+
+func TestAPI_UnversionedAlias(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+
+	var created customers.Customer
+	resp := c.do(http.MethodPost, "/customers", map[string]string{"name": "Jane Doe", "email": "jane@example.com"}, &created)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected the alias to create the customer, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("Deprecation") != "true" {
+		t.Errorf("Expected the alias to be marked deprecated")
+	}
+}
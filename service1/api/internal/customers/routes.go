@@ -2,9 +2,17 @@ package customers
 
 import "github.com/labstack/echo/v4"
 
-func Routes(e *echo.Echo, handler Handler) {
-	e.POST("/customers", handler.Create)
-	e.GET("/customers/:id", handler.Read)
-	e.PUT("/customers/:id", handler.Update)
-	e.DELETE("/customers/:id", handler.Delete)
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/customers", handler.Create)
+	g.GET("/customers", handler.List)
+	g.GET("/customers/:id", handler.Read)
+	g.PUT("/customers/:id", handler.Update)
+	g.PATCH("/customers/:id", handler.Patch)
+	g.DELETE("/customers/:id", handler.Delete)
+	g.POST("/customers/:id/anonymize", handler.Anonymize)
+	g.POST("/customers/:id/merge", handler.Merge)
+	g.GET("/customers/:id/history", handler.History)
+	g.POST("/customers/:id/kyc/submit", handler.SubmitKYC)
+	g.POST("/customers/:id/kyc/verify", handler.VerifyKYC)
+	g.POST("/customers/:id/kyc/fail", handler.FailKYC)
 }
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyPinger fails until it has been pinged more than failures times
+type flakyPinger struct {
+	failures int
+	pings    int
+}
+
+func (p *flakyPinger) Ping(ctx context.Context) error {
+	p.pings++
+	if p.pings <= p.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestWaitReady(t *testing.T) {
+	retry := Retry{Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	db := &flakyPinger{failures: 2}
+	if err := WaitReady(context.Background(), db, retry); err != nil || db.pings != 3 {
+		t.Errorf("Expected the third ping to succeed, got %v after %d pings", err, db.pings)
+	}
+
+	db = &flakyPinger{failures: 5}
+	if err := WaitReady(context.Background(), db, retry); err == nil || db.pings != 3 {
+		t.Errorf("Expected to give up after 3 pings, got %v after %d pings", err, db.pings)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	db = &flakyPinger{failures: 5}
+	if err := WaitReady(ctx, db, Retry{Attempts: 10, InitialBackoff: time.Hour, MaxBackoff: time.Hour}); err == nil || db.pings != 1 {
+		t.Errorf("Expected a cancelled context to stop the retries, got %v after %d pings", err, db.pings)
+	}
+}
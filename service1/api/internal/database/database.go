@@ -0,0 +1,69 @@
+// Package database lets repositories run on the pool or inside a transaction, so a
+// service can compose calls to several repositories into one unit of work that
+// commits or rolls back as a whole.
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DB is satisfied by *pgxpool.Pool and pgx.Tx. Begin on a pgx.Tx starts a savepoint,
+// so a repository's own transaction nests inside the unit of work it is bound to.
+type DB interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// InTx runs fn in a transaction on db, committing only if fn succeeds
+func InTx(ctx context.Context, db DB, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// UnitOfWork begins transactions and binds a set of repositories R to each of them.
+// bind is typically a function building R from the repositories' WithTx methods.
+type UnitOfWork[R any] struct {
+	db   DB
+	bind func(tx pgx.Tx) R
+}
+
+func NewUnitOfWork[R any](db DB, bind func(tx pgx.Tx) R) *UnitOfWork[R] {
+	return &UnitOfWork[R]{db: db, bind: bind}
+}
+
+// Tx is an open transaction with the repositories bound to it
+type Tx[R any] struct {
+	pgx.Tx
+	Repos R
+}
+
+// BeginTx starts a transaction and returns the repositories bound to it. The caller
+// must Commit or Rollback it; deferring Rollback is safe once Commit has succeeded.
+func (u *UnitOfWork[R]) BeginTx(ctx context.Context) (*Tx[R], error) {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx[R]{Tx: tx, Repos: u.bind(tx)}, nil
+}
+
+// Do runs fn with the repositories bound to one transaction, committing only if fn succeeds
+func (u *UnitOfWork[R]) Do(ctx context.Context, fn func(repos R) error) error {
+	return InTx(ctx, u.db, func(tx pgx.Tx) error {
+		return fn(u.bind(tx))
+	})
+}
@@ -0,0 +1,89 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"service1/api/internal/customers"
+	"service1/api/internal/validation"
+	"service1/api/pkg/pb/customersv1"
+)
+
+// customerSentinels mirror the customers handlers' httpError
+var customerSentinels = []sentinel{
+	{customers.ErrNotFound, codes.NotFound},
+	{customers.ErrMergeWithSelf, codes.InvalidArgument},
+	{customers.ErrVersionConflict, codes.Aborted},
+	{customers.ErrAlreadyAnonymized, codes.FailedPrecondition},
+	{customers.ErrAlreadyMerged, codes.FailedPrecondition},
+	{customers.ErrInvalidKYCTransition, codes.FailedPrecondition},
+}
+
+type CustomerServer struct {
+	customersv1.UnimplementedCustomerServiceServer
+	service   customers.Service
+	validator *validation.Validator
+}
+
+func NewCustomerServer(service customers.Service) *CustomerServer {
+	return &CustomerServer{service: service, validator: validation.New()}
+}
+
+func (s *CustomerServer) CreateCustomer(ctx context.Context, req *customersv1.CreateCustomerRequest) (*customersv1.Customer, error) {
+	customer := customers.Customer{
+		Name:  strings.TrimSpace(req.GetName()),
+		Email: strings.TrimSpace(req.GetEmail()),
+	}
+	if err := s.validator.Validate(customer); err != nil {
+		return nil, statusError(err, customerSentinels)
+	}
+
+	customer.Id = uuid.New()
+	created, err := s.service.Create(ctx, customer)
+	if err != nil {
+		return nil, statusError(err, customerSentinels)
+	}
+	return toCustomer(created), nil
+}
+
+func (s *CustomerServer) GetCustomer(ctx context.Context, req *customersv1.GetCustomerRequest) (*customersv1.Customer, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.service.Read(ctx, id)
+	if err != nil {
+		return nil, statusError(err, customerSentinels)
+	}
+	return toCustomer(customer), nil
+}
+
+func (s *CustomerServer) DeleteCustomer(ctx context.Context, req *customersv1.DeleteCustomerRequest) (*customersv1.DeleteCustomerResponse, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.Delete(ctx, id); err != nil {
+		return nil, statusError(err, customerSentinels)
+	}
+	return &customersv1.DeleteCustomerResponse{}, nil
+}
+
+func toCustomer(customer customers.Customer) *customersv1.Customer {
+	message := &customersv1.Customer{
+		Id:           customer.Id.String(),
+		Name:         customer.Name,
+		Email:        customer.Email,
+		Version:      int32(customer.Version),
+		KycStatus:    string(customer.KYCStatus),
+		CreatedAt:    timestamp(customer.CreatedAt),
+		ModifiedAt:   timestamp(customer.ModifiedAt),
+		AnonymizedAt: optionalTimestamp(customer.AnonymizedAt),
+	}
+	if customer.MergedInto != nil {
+		message.MergedInto = customer.MergedInto.String()
+	}
+	return message
+}
@@ -0,0 +1,156 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"service1/api/internal/auth"
+	"service1/api/internal/customers"
+	"service1/api/internal/ratelimit"
+	"service1/api/pkg/pb/customersv1"
+)
+
+// fakeCustomers implements the calls the gRPC server makes; the embedded interface
+// panics on anything else
+type fakeCustomers struct {
+	customers.Service
+	created []customers.Customer
+}
+
+func (f *fakeCustomers) Create(ctx context.Context, customer customers.Customer) (customers.Customer, error) {
+	customer.Version = 1
+	customer.KYCStatus = customers.KYCUnverified
+	f.created = append(f.created, customer)
+	return customer, nil
+}
+
+func (f *fakeCustomers) Read(ctx context.Context, id uuid.UUID) (customers.Customer, error) {
+	for _, customer := range f.created {
+		if customer.Id == id {
+			return customer, nil
+		}
+	}
+	return customers.Customer{}, customers.ErrNotFound
+}
+
+func dial(t *testing.T, server *grpc.Server) customersv1.CustomerServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return customersv1.NewCustomerServiceClient(conn)
+}
+
+func newServer(config auth.Config, limiter *ratelimit.Limiter, service customers.Service) *grpc.Server {
+	server := New(config, limiter, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	customersv1.RegisterCustomerServiceServer(server, NewCustomerServer(service))
+	return server
+}
+
+func TestCustomerServer(t *testing.T) {
+	client := dial(t, newServer(auth.Config{}, nil, &fakeCustomers{}))
+	ctx := context.Background()
+
+	created, err := client.CreateCustomer(ctx, &customersv1.CreateCustomerRequest{Name: " Jane ", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("CreateCustomer failed: %v", err)
+	}
+	if created.GetName() != "Jane" || created.GetKycStatus() != string(customers.KYCUnverified) || created.GetVersion() != 1 {
+		t.Errorf("Unexpected customer: %v", created)
+	}
+
+	read, err := client.GetCustomer(ctx, &customersv1.GetCustomerRequest{Id: created.GetId()})
+	if err != nil {
+		t.Fatalf("GetCustomer failed: %v", err)
+	}
+	if read.GetEmail() != "jane@example.com" {
+		t.Errorf("Expected the created customer, got %v", read)
+	}
+
+	_, err = client.GetCustomer(ctx, &customersv1.GetCustomerRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+	_, err = client.GetCustomer(ctx, &customersv1.GetCustomerRequest{Id: "not-a-uuid"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestCustomerServer_ValidationDetails(t *testing.T) {
+	client := dial(t, newServer(auth.Config{}, nil, &fakeCustomers{}))
+
+	_, err := client.CreateCustomer(context.Background(), &customersv1.CreateCustomerRequest{Email: "not-an-email"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields[violation.GetField()] = true
+			}
+		}
+	}
+	if !fields["name"] || !fields["email"] {
+		t.Errorf("Expected name and email violations, got %v", st.Details())
+	}
+}
+
+func TestInterceptors_AuthAndRateLimit(t *testing.T) {
+	config := auth.Config{APIKeys: map[string]auth.Principal{
+		"reader-key": {Subject: "reporting", Roles: []string{auth.RoleRead}},
+	}}
+	client := dial(t, newServer(config, ratelimit.NewLimiter(0.001, 1), &fakeCustomers{}))
+	request := &customersv1.GetCustomerRequest{Id: uuid.NewString()}
+
+	_, err := client.GetCustomer(context.Background(), request)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyKey, "reader-key")
+	_, err = client.CreateCustomer(ctx, &customersv1.CreateCustomerRequest{Name: "Jane", Email: "jane@example.com"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for a reader creating, got %v", err)
+	}
+
+	// The rejected create did not reach the limiter, so the first read uses the only token
+	_, err = client.GetCustomer(ctx, request)
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected the read to reach the service, got %v", err)
+	}
+	var header metadata.MD
+	_, err = client.GetCustomer(ctx, request, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+	if len(header.Get(retryAfterKey)) == 0 {
+		t.Error("Expected a retry-after header")
+	}
+	if len(header.Get(requestIDKey)) == 0 {
+		t.Error("Expected a generated request id")
+	}
+}
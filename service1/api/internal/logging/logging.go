@@ -0,0 +1,65 @@
+// Package logging writes the service's logs as JSON through log/slog, with one line
+// per request carrying the X-Request-ID, and the caller's X-Correlation-ID, so calls
+// made by a saga run can be correlated
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// HeaderCorrelationID ties the requests of one business operation, such as a saga
+// run, together; the saga client sends its saga ID
+const HeaderCorrelationID = "X-Correlation-ID"
+
+// New returns a JSON logger writing to w
+func New(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// Middleware logs each request's method, path, status, latency, request_id and, when
+// the caller sent one, correlation_id once the error handler has run, so the logged
+// status is the one the client received.
+// It must be registered after middleware.RequestID.
+func Middleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		HandleError:  true,
+		LogMethod:    true,
+		LogURIPath:   true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRequestID: true,
+		LogError:     true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.String("path", v.URIPath),
+				slog.String("route", v.RoutePath),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency),
+				slog.String("request_id", v.RequestID),
+			}
+			if id := c.Request().Header.Get(HeaderCorrelationID); id != "" {
+				attrs = append(attrs, slog.String("correlation_id", id))
+			}
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case v.Status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+			logger.LogAttrs(context.Background(), level, "request", attrs...)
+			return nil
+		},
+	})
+}
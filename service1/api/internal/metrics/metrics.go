@@ -0,0 +1,116 @@
+// Package metrics records database metrics for Prometheus next to the HTTP metrics
+// of the echo middleware, so slow requests seen by the saga client can be matched to
+// the queries behind them. Everything is served at /metrics.
+package metrics
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// tablePattern finds the table a statement reads or writes first
+var tablePattern = regexp.MustCompile(`(?i)\b(?:from|into|update|join)\s+([a-z_][a-z0-9_.]*)`)
+
+// QueryTracer is a pgx.QueryTracer observing every query's duration by operation,
+// table and outcome. Set it as the pool's ConnConfig.Tracer.
+type QueryTracer struct {
+	duration *prometheus.HistogramVec
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	at        time.Time
+	operation string
+	table     string
+}
+
+func NewQueryTracer(registerer prometheus.Registerer) *QueryTracer {
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of database queries by operation, table and outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"operation", "table", "outcome"})
+	registerer.MustRegister(duration)
+	return &QueryTracer{duration: duration}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation, table := describe(data.SQL)
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), operation: operation, table: table})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	outcome := "ok"
+	if data.Err != nil {
+		outcome = "error"
+	}
+	t.duration.WithLabelValues(start.operation, start.table, outcome).Observe(time.Since(start.at).Seconds())
+}
+
+// describe labels a statement by its leading keyword and first table. The repositories
+// only run fixed statements, so both labels stay low in cardinality.
+func describe(sql string) (operation, table string) {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "unknown", ""
+	}
+	operation = strings.ToLower(fields[0])
+	if match := tablePattern.FindStringSubmatch(sql); match != nil {
+		table = strings.ToLower(match[1])
+	}
+	return operation, table
+}
+
+// PoolCollector exports the connection pool's statistics
+type PoolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	max          *prometheus.Desc
+	acquires     *prometheus.Desc
+	emptyAcquire *prometheus.Desc
+	acquireWait  *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	return &PoolCollector{
+		pool:         pool,
+		acquired:     prometheus.NewDesc("db_pool_acquired_connections", "Connections currently in use.", nil, nil),
+		idle:         prometheus.NewDesc("db_pool_idle_connections", "Connections currently idle in the pool.", nil, nil),
+		max:          prometheus.NewDesc("db_pool_max_connections", "Maximum size of the pool.", nil, nil),
+		acquires:     prometheus.NewDesc("db_pool_acquires_total", "Connections acquired from the pool.", nil, nil),
+		emptyAcquire: prometheus.NewDesc("db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty.", nil, nil),
+		acquireWait:  prometheus.NewDesc("db_pool_acquire_wait_seconds_total", "Time spent waiting for a connection.", nil, nil),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.max
+	ch <- c.acquires
+	ch <- c.emptyAcquire
+	ch <- c.acquireWait
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireWait, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
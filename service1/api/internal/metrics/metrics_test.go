@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		sql       string
+		operation string
+		table     string
+	}{
+		{"SELECT id, name FROM customers WHERE id = $1", "select", "customers"},
+		{"\n\t\tINSERT INTO outbox (id, event_type) VALUES ($1, $2)", "insert", "outbox"},
+		{"UPDATE customers SET name = $1 WHERE id = $2", "update", "customers"},
+		{"DELETE FROM contact_channels WHERE id = $1", "delete", "contact_channels"},
+		{"begin", "begin", ""},
+		{"", "unknown", ""},
+	}
+	for _, tt := range tests {
+		operation, table := describe(tt.sql)
+		if operation != tt.operation || table != tt.table {
+			t.Errorf("describe(%q) = %q, %q, want %q, %q", tt.sql, operation, table, tt.operation, tt.table)
+		}
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	tracer := NewQueryTracer(registry)
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1 FROM customers"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "UPDATE customers SET name = $1"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	if got := testutil.CollectAndCount(registry, "db_query_duration_seconds"); got != 2 {
+		t.Errorf("Expected a series for each operation and outcome, got %d", got)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	failed := map[string]string{}
+	for _, metric := range families[0].GetMetric() {
+		labels := map[string]string{}
+		for _, label := range metric.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["outcome"] == "error" {
+			failed = labels
+		}
+	}
+	if failed["operation"] != "update" || failed["table"] != "customers" {
+		t.Errorf("Expected the failed update to be labelled, got %v", failed)
+	}
+}
@@ -0,0 +1,80 @@
+-- Baseline: the schema the service used to create at startup. The statements are
+-- idempotent so databases created that way are adopted unchanged.
+
+-- +goose Up
+-- customer
+CREATE TABLE IF NOT EXISTS customers(
+    id uuid PRIMARY KEY,
+    name varchar,
+    email varchar,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL,
+    version int NOT NULL DEFAULT 1
+);
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS anonymized_at timestamp;
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS merged_into uuid;
+
+ALTER TABLE customers ADD COLUMN IF NOT EXISTS kyc_status varchar NOT NULL DEFAULT 'unverified';
+
+CREATE TABLE IF NOT EXISTS addresses(id uuid PRIMARY KEY, customersId uuid, number int, street varchar, city varchar, province varchar, postalCode varchar);
+
+CREATE TABLE IF NOT EXISTS customers_audit(
+    id uuid PRIMARY KEY,
+    customer_id uuid NOT NULL,
+    action varchar NOT NULL,
+    actor varchar NOT NULL,
+    old_values jsonb,
+    new_values jsonb,
+    changed_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS customers_audit_customer_idx ON customers_audit (customer_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS customer_anonymizations(
+    id uuid PRIMARY KEY,
+    customer_id uuid NOT NULL,
+    requested_by varchar NOT NULL,
+    reason varchar,
+    requested_at timestamp NOT NULL
+);
+
+-- contact channels
+CREATE TABLE IF NOT EXISTS contact_channels(
+    id uuid PRIMARY KEY,
+    customer_id uuid NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    type varchar NOT NULL,
+    value varchar NOT NULL,
+    preferred boolean NOT NULL DEFAULT false,
+    opted_in boolean NOT NULL DEFAULT false,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+-- At most one preferred channel of each type per customer
+CREATE UNIQUE INDEX IF NOT EXISTS contact_channels_preferred_idx
+    ON contact_channels (customer_id, type) WHERE preferred;
+
+-- outbox
+CREATE TABLE IF NOT EXISTS outbox(
+    id uuid PRIMARY KEY,
+    aggregate_type varchar NOT NULL,
+    aggregate_id uuid NOT NULL,
+    event_type varchar NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp NOT NULL,
+    published_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS contact_channels;
+DROP TABLE IF EXISTS customer_anonymizations;
+DROP TABLE IF EXISTS customers_audit;
+DROP TABLE IF EXISTS addresses;
+DROP TABLE IF EXISTS customers;
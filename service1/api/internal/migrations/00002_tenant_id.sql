@@ -0,0 +1,14 @@
+-- Tenancy: every customer belongs to a tenant (a lender). Rows created before tenancy
+-- belong to the default tenant. The audit trail records the tenant too, so a deleted
+-- customer's history stays scoped to it.
+
+-- +goose Up
+ALTER TABLE customers ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+CREATE INDEX customers_tenant_idx ON customers (tenant_id, created_at, id);
+
+ALTER TABLE customers_audit ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+-- +goose Down
+ALTER TABLE customers_audit DROP COLUMN tenant_id;
+DROP INDEX customers_tenant_idx;
+ALTER TABLE customers DROP COLUMN tenant_id;
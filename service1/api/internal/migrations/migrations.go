@@ -0,0 +1,38 @@
+// Package migrations holds the service's versioned schema migrations. The SQL files
+// are embedded in the binary and applied in order with goose, which records the
+// applied versions in goose_db_version.
+//
+// To change the schema, add the next numbered file (e.g. 00002_add_x.sql) with
+// "-- +goose Up" and "-- +goose Down" sections; never edit an applied migration.
+package migrations
+
+import (
+	"context"
+	"embed"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// Up applies the migrations the database has not run yet. An advisory lock keeps
+// instances that start together from applying them twice.
+func Up(ctx context.Context, pool *pgxpool.Pool) error {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return err
+	}
+	db := stdlib.OpenDBFromPool(pool)
+	defer db.Close()
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, files, goose.WithSessionLocker(locker))
+	if err != nil {
+		return err
+	}
+	_, err = provider.Up(ctx)
+	return err
+}
@@ -0,0 +1,261 @@
+// Package openapi generates the service's OpenAPI 3 document from its Go types and
+// serves it at /openapi.json. Operations are listed in spec.go next to the types their
+// handlers bind and return; schemas, including the constraints in validate tags, are
+// reflected from those types so the document follows the code.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/apierror"
+	"service1/api/internal/auth"
+)
+
+// Operation documents one route
+type Operation struct {
+	ID      string // operationId, unique across the document
+	Method  string
+	Path    string // registered echo path, e.g. /v1/customers/:id; every path parameter is a UUID
+	Tag     string
+	Summary string
+	Public  bool // served without credentials, see auth.Middleware
+	Query   []Param
+	// Request and Response are zero values of the types the handler binds and
+	// returns. A nil Request means no body; a nil Response means an empty one.
+	Request  any
+	Status   int
+	Response any
+}
+
+// Param is a query parameter
+type Param struct {
+	Name        string
+	Type        string // string, integer, number or boolean
+	Description string
+}
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// builder collects the component schemas while the operations are added
+type builder struct {
+	doc   *openapi3.T
+	types map[string]reflect.Type
+}
+
+// Build generates the document for ops. Named struct types become component schemas,
+// every operation may fail with an apierror.Response and all but public ones need
+// an API key or bearer token.
+func Build(info openapi3.Info, ops []Operation) (*openapi3.T, error) {
+	b := &builder{
+		doc: &openapi3.T{
+			OpenAPI: "3.0.3",
+			Info:    &info,
+			Paths:   openapi3.NewPaths(),
+			Components: &openapi3.Components{
+				Schemas: openapi3.Schemas{},
+				SecuritySchemes: openapi3.SecuritySchemes{
+					"apiKey": {Value: &openapi3.SecurityScheme{Type: "apiKey", In: "header", Name: auth.HeaderAPIKey}},
+					"bearer": {Value: openapi3.NewJWTSecurityScheme()},
+				},
+			},
+			// Either credential is accepted when the service has auth configured
+			Security: openapi3.SecurityRequirements{
+				openapi3.NewSecurityRequirement().Authenticate("apiKey"),
+				openapi3.NewSecurityRequirement().Authenticate("bearer"),
+			},
+		},
+		types: map[string]reflect.Type{},
+	}
+
+	errorSchema, err := b.component("Error", apierror.Response{})
+	if err != nil {
+		return nil, err
+	}
+	errorResponse := openapi3.NewResponse().
+		WithDescription("Error; clients should branch on its code").
+		WithContent(openapi3.NewContentWithJSONSchemaRef(errorSchema))
+
+	for _, op := range ops {
+		if err := b.add(op, errorResponse); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+	}
+	return b.doc, nil
+}
+
+func (b *builder) add(op Operation, errorResponse *openapi3.Response) error {
+	operation := openapi3.NewOperation()
+	operation.OperationID = op.ID
+	operation.Summary = op.Summary
+	operation.Tags = []string{op.Tag}
+	if op.Public {
+		operation.Security = openapi3.NewSecurityRequirements()
+	}
+
+	path, params := pathParams(op.Path)
+	for _, name := range params {
+		param := openapi3.NewPathParameter(name).WithSchema(openapi3.NewUUIDSchema())
+		operation.AddParameter(param)
+	}
+	for _, query := range op.Query {
+		schema := &openapi3.Schema{Type: &openapi3.Types{query.Type}}
+		operation.AddParameter(openapi3.NewQueryParameter(query.Name).WithSchema(schema).WithDescription(query.Description))
+	}
+
+	if op.Request != nil {
+		schema, err := b.schemaRef(op.Request)
+		if err != nil {
+			return err
+		}
+		body := openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schema)
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: body}
+	}
+
+	response := openapi3.NewResponse().WithDescription(http.StatusText(op.Status))
+	if op.Response != nil {
+		schema, err := b.schemaRef(op.Response)
+		if err != nil {
+			return err
+		}
+		response.WithJSONSchemaRef(schema)
+	}
+	operation.Responses = openapi3.NewResponses(
+		openapi3.WithStatus(op.Status, &openapi3.ResponseRef{Value: response}),
+		openapi3.WithName("default", errorResponse),
+	)
+
+	b.doc.AddOperation(path, op.Method, operation)
+	return nil
+}
+
+// pathParams turns an echo path into an OpenAPI one, returning its parameter names
+func pathParams(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			params = append(params, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// schemaRef returns the schema of value's type. Named structs are added to the
+// components once and referenced; slices reference their element.
+func (b *builder) schemaRef(value any) (*openapi3.SchemaRef, error) {
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Slice {
+		items, err := b.schemaRef(reflect.Zero(t.Elem()).Interface())
+		if err != nil {
+			return nil, err
+		}
+		return openapi3.NewSchemaRef("", &openapi3.Schema{Type: &openapi3.Types{"array"}, Items: items}), nil
+	}
+
+	if t.Kind() != reflect.Struct || t.Name() == "" || t == timeType {
+		return openapi3gen.NewSchemaRefForValue(value, nil, openapi3gen.SchemaCustomizer(customize))
+	}
+
+	return b.component(t.Name(), value)
+}
+
+// component adds value's schema to the components as name and references it
+func (b *builder) component(name string, value any) (*openapi3.SchemaRef, error) {
+	t := reflect.TypeOf(value)
+	if existing, ok := b.types[name]; ok && existing != t {
+		return nil, fmt.Errorf("schema %s is defined by both %s and %s", name, existing, t)
+	}
+	schema, err := openapi3gen.NewSchemaRefForValue(value, nil, openapi3gen.SchemaCustomizer(customize))
+	if err != nil {
+		return nil, err
+	}
+	b.types[name] = t
+	b.doc.Components.Schemas[name] = openapi3.NewSchemaRef("", schema.Value)
+	return openapi3.NewSchemaRef("#/components/schemas/"+name, schema.Value), nil
+}
+
+// customize describes UUIDs as strings and turns validate tags into schema
+// constraints: required fields, oneof enums, numeric bounds and string lengths
+func customize(_ string, t reflect.Type, tag reflect.StructTag, schema *openapi3.Schema) error {
+	if t == uuidType {
+		schema.Type = &openapi3.Types{"string"}
+		schema.Format = "uuid"
+	}
+	if t.Kind() == reflect.Struct && t != timeType && t != uuidType {
+		schema.Required = requiredFields(t)
+	}
+
+	isString := t.Kind() == reflect.String
+	for _, rule := range strings.Split(tag.Get("validate"), ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, value)
+			}
+		case "gt", "gte", "lte", "min", "max":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				return fmt.Errorf("validate tag %q: %w", rule, err)
+			}
+			applyBound(schema, key, n, isString)
+		case "email", "rfc_email":
+			schema.Format = "email"
+		}
+	}
+	return nil
+}
+
+func applyBound(schema *openapi3.Schema, key string, n float64, isString bool) {
+	switch {
+	case isString && key == "min":
+		schema.MinLength = uint64(n)
+	case isString && key == "max":
+		length := uint64(n)
+		schema.MaxLength = &length
+	case key == "gt":
+		schema.Min = &n
+		schema.ExclusiveMin = true
+	case key == "gte", key == "min":
+		schema.Min = &n
+	case key == "lte", key == "max":
+		schema.Max = &n
+	}
+}
+
+// requiredFields lists the JSON names of t's fields tagged validate:"required"
+func requiredFields(t reflect.Type) []string {
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !slices.Contains(strings.Split(field.Tag.Get("validate"), ","), "required") {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			required = append(required, name)
+		}
+	}
+	return required
+}
+
+// Handler serves doc as JSON
+func Handler(doc *openapi3.T) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	}
+}
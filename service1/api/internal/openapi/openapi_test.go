@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/contacts"
+	"service1/api/internal/customers"
+	"service1/api/internal/health"
+	"service1/api/internal/versioning"
+	"service1/api/internal/webhooks"
+)
+
+func TestDocument_Valid(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Errorf("Document is not valid OpenAPI: %v", err)
+	}
+	if len(doc.Security) != 2 {
+		t.Errorf("Expected API key and bearer security, got %v", doc.Security)
+	}
+	if readyz := doc.Paths.Value("/readyz").Get; readyz.Security == nil || len(*readyz.Security) != 0 {
+		t.Error("Expected /readyz to be served without credentials")
+	}
+
+	customer := doc.Components.Schemas["Customer"]
+	if customer == nil {
+		t.Fatal("Expected a Customer component schema")
+	}
+	if len(customer.Value.Required) != 2 {
+		t.Errorf("Expected name and email to be required, got %v", customer.Value.Required)
+	}
+	if id := customer.Value.Properties["id"].Value; id.Format != "uuid" {
+		t.Errorf("Expected id to be a uuid, got %q", id.Format)
+	}
+}
+
+// TestDocument_CoversRoutes keeps the document in step with the routes: every
+// registered route must be documented, and every documented operation registered
+func TestDocument_CoversRoutes(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+
+	e := echo.New()
+	v1 := e.Group(versioning.Prefix("v1"))
+	customers.Routes(v1, customers.NewCustomersHandler(nil))
+	contacts.Routes(v1, contacts.NewContactHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+
+	for _, route := range e.Routes() {
+		path, _ := pathParams(route.Path)
+		item := doc.Paths.Value(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s is not documented", route.Method, route.Path)
+		}
+	}
+	if documented := len(operations); documented != len(e.Routes()) {
+		t.Errorf("Expected %d documented operations, got %d", len(e.Routes()), documented)
+	}
+}
+
+// TestDocument_MatchesClient keeps the client in step with the document: the client
+// package is generated from a copy of it, which must be regenerated when it changes
+func TestDocument_MatchesClient(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	generated, err := os.ReadFile("../../pkg/client/internal/oapi/openapi.json")
+	if err != nil {
+		t.Fatalf("Unable to read the client's document: %v", err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(generated, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("The client was generated from a stale document; run go generate ./api/pkg/client/...")
+	}
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"service1/api/internal/contacts"
+	"service1/api/internal/customers"
+	"service1/api/internal/health"
+	"service1/api/internal/webhooks"
+)
+
+var pageParams = []Param{
+	{Name: "limit", Type: "integer", Description: "page size, at most 100 (default 20)"},
+	{Name: "offset", Type: "integer", Description: "number of results to skip"},
+}
+
+var operations = []Operation{
+	{ID: "createCustomer", Method: http.MethodPost, Path: "/v1/customers", Tag: "customers", Summary: "Create a customer",
+		Request: customers.Customer{}, Status: http.StatusCreated, Response: customers.Customer{}},
+	{ID: "listCustomers", Method: http.MethodGet, Path: "/v1/customers", Tag: "customers", Summary: "List customers",
+		Query: append([]Param{
+			{Name: "id", Type: "string", Description: "customer ID; repeat (up to 100) to fetch those customers instead of a page"},
+			{Name: "name", Type: "string", Description: "name substring"},
+			{Name: "email", Type: "string", Description: "email substring"},
+		}, pageParams...),
+		Status: http.StatusOK, Response: []customers.Customer{}},
+	{ID: "getCustomer", Method: http.MethodGet, Path: "/v1/customers/:id", Tag: "customers", Summary: "Get a customer; 304 if If-None-Match names its ETag",
+		Status: http.StatusOK, Response: customers.Customer{}},
+	{ID: "updateCustomer", Method: http.MethodPut, Path: "/v1/customers/:id", Tag: "customers",
+		Summary: `Update a customer; requires If-Match: "<version>" or version in the body`,
+		Request: customers.Customer{}, Status: http.StatusOK, Response: customers.Customer{}},
+	{ID: "patchCustomer", Method: http.MethodPatch, Path: "/v1/customers/:id", Tag: "customers",
+		Summary: "Partially update a customer; only the fields present are changed",
+		Request: customers.CustomerPatch{}, Status: http.StatusOK, Response: customers.Customer{}},
+	{ID: "deleteCustomer", Method: http.MethodDelete, Path: "/v1/customers/:id", Tag: "customers", Summary: "Delete a customer",
+		Status: http.StatusNoContent},
+	{ID: "anonymizeCustomer", Method: http.MethodPost, Path: "/v1/customers/:id/anonymize", Tag: "customers",
+		Summary: "Irreversibly scrub a customer's personal data",
+		Request: customers.AnonymizationRequest{}, Status: http.StatusOK, Response: customers.Customer{}},
+	{ID: "mergeCustomer", Method: http.MethodPost, Path: "/v1/customers/:id/merge", Tag: "customers",
+		Summary: "Merge a duplicate customer into this one",
+		Request: customers.MergeRequest{}, Status: http.StatusOK, Response: customers.Customer{}},
+	{ID: "getCustomerHistory", Method: http.MethodGet, Path: "/v1/customers/:id/history", Tag: "customers",
+		Summary: "List a customer's changes, oldest first",
+		Status:  http.StatusOK, Response: []customers.AuditEntry{}},
+	{ID: "submitCustomerKYC", Method: http.MethodPost, Path: "/v1/customers/:id/kyc/submit", Tag: "customers",
+		Summary: "Submit a customer for KYC verification",
+		Status:  http.StatusOK, Response: customers.Customer{}},
+	{ID: "verifyCustomerKYC", Method: http.MethodPost, Path: "/v1/customers/:id/kyc/verify", Tag: "customers",
+		Summary: "Mark a customer's KYC check verified",
+		Status:  http.StatusOK, Response: customers.Customer{}},
+	{ID: "failCustomerKYC", Method: http.MethodPost, Path: "/v1/customers/:id/kyc/fail", Tag: "customers",
+		Summary: "Mark a customer's KYC check failed",
+		Status:  http.StatusOK, Response: customers.Customer{}},
+
+	{ID: "createContact", Method: http.MethodPost, Path: "/v1/customers/:id/contacts", Tag: "contacts",
+		Summary: "Add a contact channel",
+		Request: contacts.ContactChannel{}, Status: http.StatusCreated, Response: contacts.ContactChannel{}},
+	{ID: "listContacts", Method: http.MethodGet, Path: "/v1/customers/:id/contacts", Tag: "contacts",
+		Summary: "List a customer's contact channels",
+		Status:  http.StatusOK, Response: []contacts.ContactChannel{}},
+	{ID: "getContact", Method: http.MethodGet, Path: "/v1/customers/:id/contacts/:contactId", Tag: "contacts",
+		Summary: "Get a contact channel",
+		Status:  http.StatusOK, Response: contacts.ContactChannel{}},
+	{ID: "updateContact", Method: http.MethodPut, Path: "/v1/customers/:id/contacts/:contactId", Tag: "contacts",
+		Summary: "Update a contact channel",
+		Request: contacts.ContactChannel{}, Status: http.StatusOK, Response: contacts.ContactChannel{}},
+	{ID: "deleteContact", Method: http.MethodDelete, Path: "/v1/customers/:id/contacts/:contactId", Tag: "contacts",
+		Summary: "Delete a contact channel",
+		Status:  http.StatusNoContent},
+
+	{ID: "createWebhook", Method: http.MethodPost, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "Subscribe a URL to events; the response is the only one carrying the signing secret",
+		Request: webhooks.Subscription{}, Status: http.StatusCreated, Response: webhooks.Subscription{}},
+	{ID: "listWebhooks", Method: http.MethodGet, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "List the tenant's webhook subscriptions",
+		Status:  http.StatusOK, Response: []webhooks.Subscription{}},
+	{ID: "getWebhook", Method: http.MethodGet, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Get a webhook subscription",
+		Status:  http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "updateWebhook", Method: http.MethodPut, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Change a subscription's URL or event types, or pause it with active false",
+		Request: webhooks.Subscription{}, Status: http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "deleteWebhook", Method: http.MethodDelete, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Delete a webhook subscription and its delivery log",
+		Status:  http.StatusNoContent},
+	{ID: "listWebhookDeliveries", Method: http.MethodGet, Path: "/v1/webhooks/:id/deliveries", Tag: "webhooks",
+		Summary: "List a subscription's deliveries, newest first",
+		Query:   pageParams,
+		Status:  http.StatusOK, Response: []webhooks.Delivery{}},
+
+	{ID: "getLiveness", Method: http.MethodGet, Path: "/healthz", Tag: "health", Public: true,
+		Summary: "Liveness probe; 200 while the process is serving",
+		Status:  http.StatusOK, Response: health.Status{}},
+	{ID: "getReadiness", Method: http.MethodGet, Path: "/readyz", Tag: "health", Public: true,
+		Summary: "Readiness probe; 503 with the failing check until the database answers",
+		Status:  http.StatusOK, Response: health.Status{}},
+}
+
+// Document returns the customer service's OpenAPI document
+func Document() (*openapi3.T, error) {
+	return Build(openapi3.Info{Title: "Customer Service", Version: "1.0.0"}, operations)
+}
@@ -0,0 +1,232 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service1/api/internal/tenant"
+)
+
+// Event is a domain event recorded in the outbox table
+type Event struct {
+	Id uuid.UUID `json:"id"`
+	// TenantId is the tenant the event was raised for, taken from the writing request
+	TenantId      string          `json:"tenant_id"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateId   uuid.UUID       `json:"aggregate_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Executor is satisfied by *pgxpool.Pool and pgx.Tx
+type Executor interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// NewEvent builds an event with the payload marshalled to JSON
+func NewEvent(aggregateType string, aggregateId uuid.UUID, eventType string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Id:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		EventType:     eventType,
+		Payload:       data,
+		CreatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+const insertSQL = `INSERT INTO outbox (id, tenant_id, aggregate_type, aggregate_id, event_type, payload, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+// Insert writes the event to the outbox for the tenant ctx acts for. Pass the
+// transaction that changes the aggregate so the event is only recorded if the
+// change commits.
+func Insert(ctx context.Context, db Executor, event Event) error {
+	_, err := db.Exec(ctx, insertSQL, insertArgs(ctx, event)...)
+	return err
+}
+
+// Queue adds the event's insert to batch, for writes recording many events in one
+// round trip. Send the batch on the transaction that changes the aggregates.
+func Queue(ctx context.Context, batch *pgx.Batch, event Event) {
+	batch.Queue(insertSQL, insertArgs(ctx, event)...)
+}
+
+// insertArgs returns the arguments of insertSQL for event in the tenant of ctx
+func insertArgs(ctx context.Context, event Event) []any {
+	return []any{event.Id, tenant.FromContext(ctx), event.AggregateType, event.AggregateId, event.EventType,
+		event.Payload, event.CreatedAt}
+}
+
+// Scrub overwrites fields of the payloads of an aggregate's events of the given types
+// with values, for erasing personal data the events recorded. Events of other
+// tenants are left alone.
+func Scrub(ctx context.Context, db Executor, aggregateType string, aggregateId uuid.UUID, eventTypes []string, values map[string]any) error {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	sql := `UPDATE outbox SET payload = payload || $1::jsonb
+		WHERE tenant_id = $2 AND aggregate_type = $3 AND aggregate_id = $4 AND event_type = ANY($5)`
+	_, err = db.Exec(ctx, sql, data, tenant.FromContext(ctx), aggregateType, aggregateId, eventTypes)
+	return err
+}
+
+// Publisher delivers outbox events to a broker
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher writes events to the log; used when no broker is configured
+type LogPublisher struct {
+	logger *log.Logger
+}
+
+func NewLogPublisher(logger *log.Logger) *LogPublisher {
+	return &LogPublisher{logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	p.logger.Printf("outbox event %s %s for %s %s: %s", event.Id, event.EventType, event.AggregateType, event.AggregateId, event.Payload)
+	return nil
+}
+
+// HTTPPublisher posts each event as JSON to a broker or webhook endpoint
+type HTTPPublisher struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewHTTPPublisher(url string) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", event.Id.String())
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Relay polls the outbox and publishes unpublished events in order. Delivery is
+// at-least-once: an event is marked published only after Publish succeeds, so
+// consumers should deduplicate on the event ID.
+type Relay struct {
+	pool      *pgxpool.Pool
+	publisher Publisher
+	interval  time.Duration
+	batchSize int
+	logger    *log.Logger
+}
+
+// NewRelay creates a relay.
+func NewRelay(pool *pgxpool.Pool, publisher Publisher, logger *log.Logger) *Relay {
+	return &Relay{
+		pool:      pool,
+		publisher: publisher,
+		interval:  time.Second,
+		batchSize: 100,
+		logger:    logger,
+	}
+}
+
+// Run publishes pending events every interval until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.PublishPending(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Printf("outbox relay: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publishes one batch of unpublished events and returns how many
+// were published. It stops at the first failure so events stay in order.
+func (r *Relay) PublishPending(ctx context.Context) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `SELECT id, tenant_id, aggregate_type, aggregate_id, event_type, payload, created_at FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
+	rows, err := tx.Query(ctx, sql, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	events := []Event{}
+	for rows.Next() {
+		var event Event
+		err := rows.Scan(&event.Id, &event.TenantId, &event.AggregateType, &event.AggregateId, &event.EventType, &event.Payload, &event.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var publishErr error
+	for _, event := range events {
+		if publishErr = r.publisher.Publish(ctx, event); publishErr != nil {
+			publishErr = fmt.Errorf("failed to publish event %s: %w", event.Id, publishErr)
+			break
+		}
+		_, err := tx.Exec(ctx, "UPDATE outbox SET published_at = NOW() WHERE id = $1", event.Id)
+		if err != nil {
+			return 0, err
+		}
+		published++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
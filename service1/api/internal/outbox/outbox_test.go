@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewEvent(t *testing.T) {
+	aggregateId := uuid.New()
+	event, err := NewEvent("customer", aggregateId, "CustomerCreated", map[string]string{"name": "Jane"})
+	if err != nil {
+		t.Fatalf("NewEvent failed: %v", err)
+	}
+	if event.Id == uuid.Nil {
+		t.Error("Expected event ID to be set")
+	}
+	if event.AggregateId != aggregateId {
+		t.Errorf("Expected aggregate ID %v, got %v", aggregateId, event.AggregateId)
+	}
+	if string(event.Payload) != `{"name":"Jane"}` {
+		t.Errorf("Unexpected payload %s", event.Payload)
+	}
+}
+
+func TestHTTPPublisher_Publish(t *testing.T) {
+	var received Event
+	var idempotencyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey = r.Header.Get("Idempotency-Key")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event, _ := NewEvent("customer", uuid.New(), "CustomerDeleted", map[string]string{})
+	if err := NewHTTPPublisher(server.URL).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if received.Id != event.Id || received.EventType != "CustomerDeleted" {
+		t.Errorf("Expected event %v, got %v", event, received)
+	}
+	if idempotencyKey != event.Id.String() {
+		t.Errorf("Expected Idempotency-Key %v, got %v", event.Id, idempotencyKey)
+	}
+}
+
+func TestHTTPPublisher_PublishError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	event, _ := NewEvent("customer", uuid.New(), "CustomerCreated", nil)
+	if err := NewHTTPPublisher(server.URL).Publish(context.Background(), event); err == nil {
+		t.Error("Expected error for non-2xx response")
+	}
+}
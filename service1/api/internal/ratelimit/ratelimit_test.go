@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/auth"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(1, 2)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("a"); !ok {
+			t.Fatalf("Expected request %d to fit in the burst", i+1)
+		}
+	}
+	ok, retryAfter := limiter.Allow("a")
+	if ok {
+		t.Fatal("Expected the third request to be limited")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("Expected to retry after 1s, got %s", retryAfter)
+	}
+	if ok, _ := limiter.Allow("b"); !ok {
+		t.Error("Expected other callers to have their own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := limiter.Allow("a"); !ok {
+		t.Error("Expected a token to be available after a second")
+	}
+}
+
+func TestLimiter_DropsIdleBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(1, 1)
+	limiter.now = func() time.Time { return now }
+
+	limiter.Allow("a")
+	now = now.Add(idleExpiry + time.Second)
+	limiter.Allow("b")
+
+	if _, ok := limiter.buckets["a"]; ok {
+		t.Error("Expected the idle bucket to be dropped")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware(NewLimiter(0.001, 1)))
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/customers", ok)
+	e.GET("/readyz", ok)
+	e.GET("/metrics", ok)
+
+	serve := func(path string, principal *auth.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if principal != nil {
+			req = req.WithContext(auth.WithPrincipal(req.Context(), *principal))
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := serve("/customers", nil); rec.Code != http.StatusOK {
+		t.Fatalf("Expected the first request to pass, got %d", rec.Code)
+	}
+	rec := serve("/customers", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected a Retry-After header")
+	}
+	if rec := serve("/readyz", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected probes not to be limited, got %d", rec.Code)
+	}
+	if rec := serve("/metrics", nil); rec.Code != http.StatusOK {
+		t.Errorf("Expected scrapes not to be limited, got %d", rec.Code)
+	}
+	if rec := serve("/customers", &auth.Principal{Subject: "saga-client"}); rec.Code != http.StatusOK {
+		t.Errorf("Expected an authenticated caller to have its own bucket, got %d", rec.Code)
+	}
+}
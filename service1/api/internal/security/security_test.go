@@ -0,0 +1,75 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func serve(config Config, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(Middleware(config))
+	e.GET("/items", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_SecurityHeaders(t *testing.T) {
+	rec := serve(Config{}, httptest.NewRequest(http.MethodGet, "/items", nil))
+
+	want := map[string]string{
+		"Content-Security-Policy": DefaultContentSecurityPolicy,
+		"X-Content-Type-Options":  "nosniff",
+		"X-Frame-Options":         "DENY",
+		"Referrer-Policy":         "no-referrer",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS headers without allowed origins, got %q", got)
+	}
+
+	rec = serve(Config{ContentSecurityPolicy: "default-src 'self'"}, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if got := rec.Header().Get("Content-Security-Policy"); got != "default-src 'self'" {
+		t.Errorf("Expected the configured policy, got %q", got)
+	}
+}
+
+func TestMiddleware_CORS(t *testing.T) {
+	config := Config{AllowOrigins: []string{"https://dashboard.example.com"}}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	preflight.Header.Set("Origin", "https://dashboard.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	preflight.Header.Set("Access-Control-Request-Headers", "Authorization")
+	rec := serve(config, preflight)
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected preflight to get 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://dashboard.example.com" {
+		t.Errorf("Expected the origin to be allowed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != "Authorization" {
+		t.Errorf("Expected the requested headers to be allowed, got %q", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Origin", "https://dashboard.example.com")
+	rec = serve(config, req)
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got == "" {
+		t.Errorf("Expected ETag and Link to be exposed")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = serve(config, req)
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected another origin to be refused, got %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Package tenant identifies the lender a request acts for, so one deployment can
+// serve several lenders without their customers, applications, loans and payments
+// mixing. Credentials bound to a tenant (see auth.Principal) decide it; otherwise the
+// X-Tenant-ID header names it, and requests naming none act for Default.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/auth"
+)
+
+// Header names the tenant a request acts for
+const Header = "X-Tenant-ID"
+
+// Default owns the rows that existed before tenancy and the requests naming no tenant
+const Default = "default"
+
+var (
+	ErrInvalid  = errors.New("tenant ids are 1-64 letters, digits, '.', '_' or '-'")
+	ErrMismatch = errors.New("the credentials are bound to another tenant")
+)
+
+var valid = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+type contextKey struct{}
+
+// WithTenant returns a copy of ctx that carries the tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenant)
+}
+
+// FromContext returns the tenant stored in ctx, or Default
+func FromContext(ctx context.Context) string {
+	if tenant, ok := ctx.Value(contextKey{}).(string); ok && tenant != "" {
+		return tenant
+	}
+	return Default
+}
+
+// Resolve picks the tenant a request acts for from the tenant its credentials are
+// bound to, empty when they are not bound, and the tenant it asked for, empty when it
+// named none. Bound credentials cannot act for another tenant.
+func Resolve(bound, requested string) (string, error) {
+	requested = strings.TrimSpace(requested)
+	if requested != "" && !valid.MatchString(requested) {
+		return "", ErrInvalid
+	}
+	switch {
+	case bound != "" && requested != "" && requested != bound:
+		return "", ErrMismatch
+	case bound != "":
+		return bound, nil
+	case requested != "":
+		return requested, nil
+	}
+	return Default, nil
+}
+
+// Middleware stores the request's tenant in its context, answering 400 for a
+// malformed X-Tenant-ID and 403 for one the credentials are not bound to. It must
+// run after auth.Middleware.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, _ := auth.FromContext(c.Request().Context())
+			tenant, err := Resolve(principal.Tenant, c.Request().Header.Get(Header))
+			if errors.Is(err, ErrInvalid) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusForbidden, err.Error())
+			}
+			c.SetRequest(c.Request().WithContext(WithTenant(c.Request().Context(), tenant)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package timeout
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware(Config{
+		Default: 10 * time.Millisecond,
+		Routes:  map[string]time.Duration{"GET /slow/:id": time.Second},
+	}))
+	// Each handler waits on its context the way a query does, up to 50ms
+	wait := func(c echo.Context) error {
+		select {
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		case <-time.After(50 * time.Millisecond):
+			return c.NoContent(http.StatusOK)
+		}
+	}
+	e.GET("/fast", wait)
+	e.GET("/slow/:id", wait)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected the default timeout to give a 503, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow/1", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the route's longer timeout to apply, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup_WithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := Setup(context.Background(), "service1")
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Expected a no-op shutdown, got %v", err)
+	}
+}
+
+// TestQueryTracer_ContinuesCallerTrace follows a traceparent from the request header
+// through the handler span to the span of a query run with the request's context
+func TestQueryTracer_ContinuesCallerTrace(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	if _, err := Setup(context.Background(), "service1"); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := NewQueryTracer(provider)
+
+	e := echo.New()
+	e.Use(otelecho.Middleware("service1", otelecho.WithTracerProvider(provider)))
+	e.GET("/customers/:id", func(c echo.Context) error {
+		ctx := tracer.TraceQueryStart(c.Request().Context(), nil, pgx.TraceQueryStartData{SQL: "SELECT id FROM customers WHERE id = $1"})
+		tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/customers/1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected a query and a request span, got %d", len(spans))
+	}
+	query, handler := spans[0], spans[1]
+	if got := handler.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the handler span to continue the caller's trace, got %s", got)
+	}
+	if handler.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the caller's span as parent, got %s", handler.Parent().SpanID())
+	}
+	if query.Parent().SpanID() != handler.SpanContext().SpanID() {
+		t.Errorf("Expected the query span under the handler span")
+	}
+	if query.Name() != "SELECT" || query.Status().Code != codes.Error {
+		t.Errorf("Expected a failed SELECT span, got %s with status %v", query.Name(), query.Status())
+	}
+}
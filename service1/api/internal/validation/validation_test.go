@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type payload struct {
+	Name  string `json:"name" validate:"required,max=5"`
+	Email string `json:"email" validate:"required,rfc_email"`
+}
+
+func TestValidator_Valid(t *testing.T) {
+	v := New()
+	if err := v.Validate(payload{Name: "John", Email: "john@example.com"}); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestValidator_FieldErrors(t *testing.T) {
+	v := New()
+	err := v.Validate(payload{Name: "Johnathan", Email: ""})
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", httpErr.Code)
+	}
+
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected wrapped validation Error, got %v", err)
+	}
+	fields := map[string]string{}
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = field.Message
+	}
+	if fields["name"] != "must be at most 5 characters" {
+		t.Errorf("Unexpected name error: %q", fields["name"])
+	}
+	if fields["email"] != "is required" {
+		t.Errorf("Unexpected email error: %q", fields["email"])
+	}
+}
+
+func TestValidator_RFCEmail(t *testing.T) {
+	v := New()
+	valid := []string{"john@example.com", "john.doe+saga@mail.example.co.uk"}
+	invalid := []string{"john", "john@", "John <john@example.com>", "john@example.com extra"}
+
+	for _, email := range valid {
+		if err := v.Validate(payload{Name: "John", Email: email}); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", email, err)
+		}
+	}
+	for _, email := range invalid {
+		if err := v.Validate(payload{Name: "John", Email: email}); err == nil {
+			t.Errorf("Expected %q to be rejected", email)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Package versioning serves the REST API under version prefixes such as /v1, so a
+// breaking change to a resource can ship under the next version while clients move
+// over at their own pace. Unversioned paths remain as deprecated aliases: Middleware
+// routes them to the version named by the Api-Version request header, or to Default,
+// and marks the response so clients can tell they should switch.
+package versioning
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderVersion names the version an unversioned request wants; every versioned
+// response carries it with the version that served it
+const HeaderVersion = "Api-Version"
+
+// Versions the API serves, oldest first
+var Versions = []string{"v1"}
+
+// Default serves unversioned requests that do not name a version
+const Default = "v1"
+
+// unversionedPaths are operational endpoints that belong to no API version
+var unversionedPaths = []string{"/healthz", "/readyz", "/openapi.json", "/metrics"}
+
+// Prefix returns the path prefix of version, e.g. /v1
+func Prefix(version string) string {
+	return "/" + version
+}
+
+// Of returns the version path starts with, or "" for an unversioned path
+func Of(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if slices.Contains(Versions, segment) {
+		return segment
+	}
+	return ""
+}
+
+// Registered returns the registered paths a configured path stands for: path itself
+// when it is versioned or an operational endpoint, otherwise path under every version
+func Registered(path string) []string {
+	if Of(path) != "" || slices.Contains(unversionedPaths, path) {
+		return []string{path}
+	}
+	paths := make([]string, 0, len(Versions))
+	for _, version := range Versions {
+		paths = append(paths, Prefix(version)+path)
+	}
+	return paths
+}
+
+// Negotiate returns the version an unversioned request asked for in requested, "v1"
+// or just "1", or Default when it asked for none
+func Negotiate(requested string) (string, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested == "" {
+		return Default, nil
+	}
+	version := requested
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !slices.Contains(Versions, version) {
+		return "", fmt.Errorf("unsupported %s %q, expected one of %v", HeaderVersion, requested, Versions)
+	}
+	return version, nil
+}
+
+// Middleware rewrites unversioned requests to the negotiated version's path before
+// routing; register it with e.Pre. The aliased response carries Deprecation and a
+// successor-version Link to the versioned path. An unsupported Api-Version is a 400.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			header := c.Response().Header()
+			if slices.Contains(unversionedPaths, req.URL.Path) {
+				return next(c)
+			}
+			if version := Of(req.URL.Path); version != "" {
+				header.Set(HeaderVersion, version)
+				return next(c)
+			}
+
+			version, err := Negotiate(req.Header.Get(HeaderVersion))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			prefix := Prefix(version)
+			header.Set(HeaderVersion, version)
+			header.Set("Deprecation", "true")
+			header.Add("Link", "<"+prefix+req.URL.Path+`>; rel="successor-version"`)
+			req.URL.Path = prefix + req.URL.Path
+			if req.URL.RawPath != "" {
+				req.URL.RawPath = prefix + req.URL.RawPath
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func serve(req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Pre(Middleware())
+	v1 := e.Group(Prefix("v1"))
+	v1.GET("/items/:id", func(c echo.Context) error { return c.String(http.StatusOK, c.Path()) })
+	e.GET("/healthz", func(c echo.Context) error { return c.String(http.StatusOK, c.Path()) })
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware_Versioned(t *testing.T) {
+	rec := serve(httptest.NewRequest(http.MethodGet, "/v1/items/1", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "/v1/items/:id" {
+		t.Fatalf("Expected the v1 route, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get(HeaderVersion); got != "v1" {
+		t.Errorf("%s = %q, want v1", HeaderVersion, got)
+	}
+	if got := rec.Header().Get("Deprecation"); got != "" {
+		t.Errorf("Expected a versioned path not to be deprecated, got %q", got)
+	}
+}
+
+func TestMiddleware_UnversionedAlias(t *testing.T) {
+	rec := serve(httptest.NewRequest(http.MethodGet, "/items/1?expand=true", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "/v1/items/:id" {
+		t.Fatalf("Expected the alias to reach the v1 route, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Deprecation"); got != "true" {
+		t.Errorf("Deprecation = %q, want true", got)
+	}
+	if got, want := rec.Header().Get("Link"), `</v1/items/1>; rel="successor-version"`; got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(HeaderVersion, "1")
+	if rec := serve(req); rec.Code != http.StatusOK || rec.Header().Get(HeaderVersion) != "v1" {
+		t.Errorf("Expected Api-Version 1 to select v1, got %d %q", rec.Code, rec.Header().Get(HeaderVersion))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(HeaderVersion, "v9")
+	if rec := serve(req); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unsupported version to get 400, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware_OperationalPathsStayUnversioned(t *testing.T) {
+	rec := serve(httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "/healthz" {
+		t.Fatalf("Expected /healthz to be served as is, got %d %q", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get(HeaderVersion); got != "" {
+		t.Errorf("Expected no %s on a probe, got %q", HeaderVersion, got)
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	cases := map[string][]string{
+		"/items/:id":    {"/v1/items/:id"},
+		"/v1/items/:id": {"/v1/items/:id"},
+		"/readyz":       {"/readyz"},
+	}
+	for path, want := range cases {
+		if got := Registered(path); !slices.Equal(got, want) {
+			t.Errorf("Registered(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -2,62 +2,257 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
+	"log/slog"
+	"math"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/jackc/pgx/v5"
-	"github.com/joho/godotenv"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"service1/api/internal/actor"
+	"service1/api/internal/apierror"
+	"service1/api/internal/auth"
+	"service1/api/internal/config"
+	"service1/api/internal/contacts"
 	"service1/api/internal/customers"
+	"service1/api/internal/database"
+	"service1/api/internal/grpcserver"
+	"service1/api/internal/health"
+	"service1/api/internal/logging"
+	"service1/api/internal/metrics"
+	"service1/api/internal/migrations"
+	"service1/api/internal/openapi"
+	"service1/api/internal/outbox"
+	"service1/api/internal/ratelimit"
+	"service1/api/internal/security"
+	"service1/api/internal/tenant"
+	"service1/api/internal/timeout"
+	"service1/api/internal/tracing"
+	"service1/api/internal/validation"
+	"service1/api/internal/versioning"
+	"service1/api/internal/webhooks"
+	"service1/api/pkg/pb/customersv1"
 )
 
 func main() {
-	// Load .env file if it exists (optional - environment variables can also be set via docker-compose)
-	err := godotenv.Load()
+	// Everything, the standard log package included, goes out as JSON lines
+	logger := logging.New(os.Stdout)
+	slog.SetDefault(logger)
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	authConfig := cfg.Auth()
+	if !authConfig.Enabled() {
+		slog.Warn("Authentication disabled: set API_KEYS or JWT_SECRET to require credentials")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, "service1")
 	if err != nil {
-		log.Println("Warning: .env file not found, using environment variables")
+		log.Fatalf("Unable to set up tracing: %v", err)
 	}
-	ctx := context.Background()
-	conn, err := pgx.Connect(ctx, os.Getenv("DATABASE_URL"))
+	defer func() {
+		// Flush the spans still batched; the collector may be gone, so don't wait long
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Warn("Unable to flush traces", "error", err)
+		}
+	}()
+	pool, err := newPool(ctx, cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to connect to database: %v\n", err)
+		log.Fatalf("Unable to connect to database: %v", err)
 	}
-	defer conn.Close(context.Background())
+	defer pool.Close()
+	prometheus.MustRegister(metrics.NewPoolCollector(pool))
 
-	err = createCustomerTable(ctx, conn)
+	err = migrations.Up(ctx, pool)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to create customer table: %v\n", err)
+		slog.Error("Unable to migrate database", "error", err)
 	}
 
+	// The relay queues webhook deliveries for each event before publishing it
+	relay := outbox.NewRelay(pool, webhooks.NewPublisher(pool, newPublisher(cfg)), log.Default())
+	go relay.Run(ctx)
+
+	dispatcher := webhooks.NewDispatcher(pool, log.Default())
+	go dispatcher.Run(ctx)
+
 	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.HideBanner = true
+	e.HidePort = true
+	// Routes unversioned paths, the deprecated aliases, to an API version
+	e.Pre(versioning.Middleware())
+	e.Use(middleware.RequestID())
+	e.Use(tracing.Middleware("service1"))
+	e.Use(logging.Middleware(logger))
+	e.Use(echoprometheus.NewMiddleware("http"))
+	e.Use(middleware.Recover())
+	e.Use(security.Middleware(cfg.Security()))
+	e.Use(timeout.Middleware(cfg.Timeouts()))
+	e.Use(auth.Middleware(authConfig))
+	e.Use(tenant.Middleware())
+	limiter := newRateLimiter(cfg)
+	if limiter != nil {
+		e.Use(ratelimit.Middleware(limiter))
+	}
+	e.Use(actor.Middleware())
+
+	health.Routes(e, health.NewHealthHandler(pool))
+
+	// The API is served under /v1; a breaking change ships in a /v2 group next to it
+	v1 := e.Group(versioning.Prefix("v1"))
 
-	customersRepository := customers.NewCustomersRepository(conn)
+	customersRepository := customers.NewCustomersRepository(pool)
 	customersService := customers.NewCustomerService(customersRepository)
 	customersHandler := customers.NewCustomersHandler(customersService)
-	customers.Routes(e, customersHandler)
+	customers.Routes(v1, customersHandler)
 
-	e.Logger.Fatal(e.Start(":8081"))
-}
+	contactRepository := contacts.NewContactRepository(pool)
+	contactService := contacts.NewContactService(contactRepository)
+	contactHandler := contacts.NewContactHandler(contactService)
+	contacts.Routes(v1, contactHandler)
+
+	webhookRepository := webhooks.NewWebhookRepository(pool)
+	webhookService := webhooks.NewWebhookService(webhookRepository, customers.Events)
+	webhookHandler := webhooks.NewWebhookHandler(webhookService)
+	webhooks.Routes(v1, webhookHandler)
 
-func createCustomerTable(ctx context.Context, conn *pgx.Conn) error {
-	customersTable := `CREATE TABLE IF NOT EXISTS customers(
-		id uuid PRIMARY KEY,
-		name varchar,
-		email varchar,
-		created_at timestamp NOT NULL,
-		modified_at timestamp NOT NULL
-	)`
-	_, err := conn.Exec(ctx, customersTable)
+	doc, err := openapi.Document()
 	if err != nil {
+		log.Fatalf("Unable to build OpenAPI document: %v", err)
+	}
+	e.GET("/openapi.json", openapi.Handler(doc))
+	e.GET("/metrics", echoprometheus.NewHandler())
+
+	// The gRPC API shares the REST API's services, credentials and rate limit buckets
+	grpcServer := grpcserver.New(authConfig, limiter, logger)
+	customersv1.RegisterCustomerServiceServer(grpcServer, grpcserver.NewCustomerServer(customersService))
+	grpcDone := make(chan struct{})
+	go func() {
+		defer close(grpcDone)
+		if err := serveGRPC(ctx, grpcServer, cfg.GRPCAddr, cfg.ShutdownTimeout); err != nil {
+			slog.Error("gRPC server failed", "error", err)
+			stop() // take the REST API down with it rather than run half a service
+		}
+	}()
+
+	// Background jobs stop with ctx; the deferred pool.Close then waits for their connections
+	if err := serve(ctx, e, cfg.HTTPAddr, cfg.ShutdownTimeout); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+	<-grpcDone
+}
+
+// serve runs e on addr until ctx is cancelled by SIGINT or SIGTERM, then stops accepting
+// connections and gives in-flight requests up to timeout to finish
+func serve(ctx context.Context, e *echo.Echo, addr string, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	slog.Info("Listening", "addr", addr)
+	go func() {
+		errs <- e.Start(addr)
+	}()
+
+	select {
+	case err := <-errs:
 		return err
+	case <-ctx.Done():
 	}
 
-	addressTable := `CREATE TABLE IF NOT EXISTS addresses(id uuid PRIMARY KEY, customersId uuid, number int, street varchar, city varchar, province varchar, postalCode varchar)`
-	_, err = conn.Exec(ctx, addressTable)
+	slog.Info("Shutting down, draining in-flight requests", "timeout", timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return e.Shutdown(shutdownCtx)
+}
+
+// serveGRPC runs server on addr until ctx is cancelled, then lets in-flight calls finish,
+// closing their connections once timeout has passed
+func serveGRPC(ctx context.Context, server *grpc.Server, addr string, timeout time.Duration) error {
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
+	errs := make(chan error, 1)
+	slog.Info("Listening for gRPC", "addr", addr)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
 
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		server.Stop()
+	}
 	return nil
 }
+
+// newRateLimiter allows each caller RATE_LIMIT_RPS requests per second with bursts of
+// RATE_LIMIT_BURST, or twice the rate when unset. It returns nil when RATE_LIMIT_RPS
+// is 0, turning rate limiting off.
+func newRateLimiter(cfg config.Config) *ratelimit.Limiter {
+	if cfg.RateLimitRPS == 0 {
+		return nil
+	}
+	burst := cfg.RateLimitBurst
+	if burst == 0 {
+		burst = int(math.Ceil(cfg.RateLimitRPS * 2))
+	}
+	return ratelimit.NewLimiter(cfg.RateLimitRPS, burst)
+}
+
+// newPublisher publishes outbox events to OUTBOX_PUBLISH_URL, or to the log when unset
+func newPublisher(cfg config.Config) outbox.Publisher {
+	if cfg.OutboxPublishURL != "" {
+		return outbox.NewHTTPPublisher(cfg.OutboxPublishURL)
+	}
+	return outbox.NewLogPublisher(log.Default())
+}
+
+// newPool connects the pool for DATABASE_URL, sized by DB_MAX_CONNS and DB_MIN_CONNS
+// when set, waiting for the database as DB_CONNECT_ATTEMPTS allows. Request handlers
+// and background jobs share it, and its queries are timed for /metrics and traced as
+// children of the request's span.
+func newPool(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxConns > 0 {
+		poolConfig.MaxConns = cfg.DBMaxConns
+	}
+	if cfg.DBMinConns > 0 {
+		poolConfig.MinConns = cfg.DBMinConns
+	}
+	poolConfig.HealthCheckPeriod = cfg.DBHealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = multitracer.New(
+		metrics.NewQueryTracer(prometheus.DefaultRegisterer),
+		tracing.NewQueryTracer(otel.GetTracerProvider()),
+	)
+	return database.Connect(ctx, poolConfig, cfg.ConnectRetry())
+}
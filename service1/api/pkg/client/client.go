@@ -1,156 +1,218 @@
 package client
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"net/http"
-	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"service1/api/internal/contacts"
 	"service1/api/internal/customers"
+	"service1/api/pkg/client/internal/oapi"
 )
 
-const path = "/customers"
-
 type Customer = customers.Customer
+type CustomerFilter = customers.CustomerFilter
+type CustomerPatch = customers.CustomerPatch
+type KYCStatus = customers.KYCStatus
+type AnonymizationRequest = customers.AnonymizationRequest
+type AuditEntry = customers.AuditEntry
+type ContactChannel = contacts.ContactChannel
+type ChannelType = contacts.ChannelType
+
+const (
+	KYCUnverified = customers.KYCUnverified
+	KYCPending    = customers.KYCPending
+	KYCVerified   = customers.KYCVerified
+	KYCFailed     = customers.KYCFailed
+
+	ChannelEmail = contacts.ChannelEmail
+	ChannelPhone = contacts.ChannelPhone
+	ChannelSMS   = contacts.ChannelSMS
+)
 
 type Client struct {
-	baseURL    string
+	// api builds and sends the requests; it is generated from the service's OpenAPI
+	// document, so the paths and parameters follow the handlers
+	api        *oapi.Client
 	httpClient *http.Client
+	// transport connects to the service; the With options wrap it
+	transport *http.Transport
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+	// versions sends the client's version headers, innermost of the transports
+	versions *versionHeaders
 }
 
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
-	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	versions := &versionHeaders{userAgent: userAgent, next: transport}
+	c := &Client{
+		httpClient: &http.Client{Transport: versions},
+		transport:  transport,
+		timeout:    DefaultTimeout,
+		versions:   versions,
+	}
+	c.api = &oapi.Client{Server: strings.TrimSuffix(baseURL, "/") + "/", Client: doerFunc(c.do)}
+	return c
+}
+
+// Contacts calls the contact channel endpoints, with the client's transport and options
+func (c *Client) Contacts() *Contacts {
+	return &Contacts{client: c}
 }
 
 func (c *Client) Create(ctx context.Context, name, email string) (Customer, error) {
-	payload := struct {
+	body, err := jsonBody(struct {
 		Name  string `json:"name"`
 		Email string `json:"email"`
 	}{
 		Name:  name,
 		Email: email,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return Customer{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return Customer{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Customer{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return Customer{}, err
-	}
-	var customer Customer
-	err = json.NewDecoder(resp.Body).Decode(&customer)
+	})
 	if err != nil {
 		return Customer{}, err
 	}
-
-	return customer, nil
+	resp, err := c.api.CreateCustomerWithBody(ctx, contentTypeJSON, body)
+	return decode[Customer](resp, err, http.StatusCreated)
 }
 
 func (c *Client) Read(ctx context.Context, id uuid.UUID) (Customer, error) {
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
-	if err != nil {
-		return Customer{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return Customer{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Customer{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Customer{}, err
-	}
-	var customer Customer
-	err = json.NewDecoder(resp.Body).Decode(&customer)
-	if err != nil {
-		return Customer{}, err
-	}
-	return customer, nil
+	resp, err := c.api.GetCustomer(ctx, id)
+	return decode[Customer](resp, err, http.StatusOK)
 }
 
-func (c *Client) Update(ctx context.Context, id uuid.UUID, name, email string) (Customer, error) {
-	payload := struct {
+// Update replaces the customer, provided version is still its current version
+func (c *Client) Update(ctx context.Context, id uuid.UUID, version int, name, email string) (Customer, error) {
+	body, err := jsonBody(struct {
 		Name  string `json:"name"`
 		Email string `json:"email"`
 	}{
 		Name:  name,
 		Email: email,
-	}
-	jsonPayload, err := json.Marshal(payload)
+	})
 	if err != nil {
 		return Customer{}, err
 	}
+	resp, err := c.api.UpdateCustomerWithBody(ctx, id, contentTypeJSON, body, ifMatch(version))
+	return decode[Customer](resp, err, http.StatusOK)
+}
 
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
+// Patch updates only the fields set in patch
+func (c *Client) Patch(ctx context.Context, id uuid.UUID, patch CustomerPatch) (Customer, error) {
+	body, err := jsonBody(patch)
 	if err != nil {
 		return Customer{}, err
 	}
+	resp, err := c.api.PatchCustomerWithBody(ctx, id, contentTypeJSON, body)
+	return decode[Customer](resp, err, http.StatusOK)
+}
 
-	req, err := http.NewRequest(http.MethodPut, fullURL, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return Customer{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
+	resp, err := c.api.DeleteCustomer(ctx, id)
+	return noContent(resp, err)
+}
 
+// Anonymize irreversibly scrubs the customer's personal data, keeping the record
+// so loans and applications still point at it
+func (c *Client) Anonymize(ctx context.Context, id uuid.UUID, request AnonymizationRequest) (Customer, error) {
+	body, err := jsonBody(request)
 	if err != nil {
 		return Customer{}, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Customer{}, err
-	}
-	var customer Customer
-	err = json.NewDecoder(resp.Body).Decode(&customer)
+	resp, err := c.api.AnonymizeCustomerWithBody(ctx, id, contentTypeJSON, body)
+	return decode[Customer](resp, err, http.StatusOK)
+}
+
+// Merge folds the duplicate customer sourceId into the customer id and returns the
+// merged customer
+func (c *Client) Merge(ctx context.Context, id, sourceId uuid.UUID) (Customer, error) {
+	body, err := jsonBody(customers.MergeRequest{SourceId: sourceId})
 	if err != nil {
 		return Customer{}, err
 	}
-	return customer, nil
+	resp, err := c.api.MergeCustomerWithBody(ctx, id, contentTypeJSON, body)
+	return decode[Customer](resp, err, http.StatusOK)
 }
 
-func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
-	if err != nil {
-		return err
+// History returns the customer's changes, oldest first
+func (c *Client) History(ctx context.Context, id uuid.UUID) ([]AuditEntry, error) {
+	resp, err := c.api.GetCustomerHistory(ctx, id)
+	return decode[[]AuditEntry](resp, err, http.StatusOK)
+}
+
+// SubmitKYC submits the customer for KYC verification
+func (c *Client) SubmitKYC(ctx context.Context, id uuid.UUID) (Customer, error) {
+	resp, err := c.api.SubmitCustomerKYC(ctx, id)
+	return decode[Customer](resp, err, http.StatusOK)
+}
+
+// VerifyKYC marks the customer's KYC check verified
+func (c *Client) VerifyKYC(ctx context.Context, id uuid.UUID) (Customer, error) {
+	resp, err := c.api.VerifyCustomerKYC(ctx, id)
+	return decode[Customer](resp, err, http.StatusOK)
+}
+
+// FailKYC marks the customer's KYC check failed
+func (c *Client) FailKYC(ctx context.Context, id uuid.UUID) (Customer, error) {
+	resp, err := c.api.FailCustomerKYC(ctx, id)
+	return decode[Customer](resp, err, http.StatusOK)
+}
+
+func (c *Client) List(ctx context.Context, filter CustomerFilter) ([]Customer, error) {
+	resp, err := c.api.ListCustomers(ctx, customerParams(filter))
+	return decode[[]Customer](resp, err, http.StatusOK)
+}
+
+// ReadMany returns the customers with ids, up to MaxListLimit of them, in one
+// request and in the order asked for; IDs with no customer are left out
+func (c *Client) ReadMany(ctx context.Context, ids []uuid.UUID) ([]Customer, error) {
+	if len(ids) == 0 {
+		return []Customer{}, nil
 	}
+	resp, err := c.api.ListCustomers(ctx, &oapi.ListCustomersParams{}, withIds(ids))
+	return decode[[]Customer](resp, err, http.StatusOK)
+}
 
-	req, err := http.NewRequest(http.MethodDelete, fullURL, nil)
-	if err != nil {
-		return err
+// ListCustomers iterates over every customer matching filter, starting at
+// filter.Offset and fetching filter.Limit customers, or as many as the service
+// allows, per request
+func (c *Client) ListCustomers(ctx context.Context, filter CustomerFilter) *Iterator[Customer] {
+	limit := pageSize(filter.Limit, customers.MaxListLimit)
+	return newIterator[Customer](ctx, c, limit, filter.Offset, func(limit, offset int) (*http.Request, error) {
+		filter.Limit, filter.Offset = limit, offset
+		return oapi.NewListCustomersRequest(c.api.Server, customerParams(filter))
+	})
+}
+
+// customerParams sets the query parameters for the set fields of a customer filter
+func customerParams(filter CustomerFilter) *oapi.ListCustomersParams {
+	params := &oapi.ListCustomersParams{}
+	if filter.Name != "" {
+		params.Name = &filter.Name
 	}
-	req = req.WithContext(ctx)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
+	if filter.Email != "" {
+		params.Email = &filter.Email
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return err
+	if filter.Limit > 0 {
+		params.Limit = &filter.Limit
+	}
+	if filter.Offset > 0 {
+		params.Offset = &filter.Offset
+	}
+	return params
+}
+
+// withIds repeats the id query parameter once per ID, which the generated
+// parameters, having a single id, cannot express
+func withIds(ids []uuid.UUID) oapi.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		query := req.URL.Query()
+		for _, id := range ids {
+			query.Add("id", id.String())
+		}
+		req.URL.RawQuery = query.Encode()
+		return nil
 	}
-	return nil
 }
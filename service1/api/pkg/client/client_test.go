@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestReadMany_RepeatsIdParameter(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()["id"]
+		_ = json.NewEncoder(w).Encode([]Customer{{Id: ids[0]}, {Id: ids[1]}})
+	}))
+	defer server.Close()
+
+	customers, err := NewClient(server.URL).ReadMany(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0] != ids[0].String() || got[1] != ids[1].String() {
+		t.Errorf("Expected one id parameter per ID, got %v", got)
+	}
+	if len(customers) != 2 || customers[1].Id != ids[1] {
+		t.Errorf("Unexpected customers %+v", customers)
+	}
+}
+
+func TestReadMany_SendsNothingWithoutIds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request for no IDs")
+	}))
+	defer server.Close()
+
+	customers, err := NewClient(server.URL).ReadMany(context.Background(), nil)
+	if err != nil || customers == nil || len(customers) != 0 {
+		t.Errorf("Expected an empty list, got %v, %v", customers, err)
+	}
+}
+
+func TestContacts_Paths(t *testing.T) {
+	customerId, contactId := uuid.New(), uuid.New()
+	base := "/v1/customers/" + customerId.String() + "/contacts"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if r.URL.Path == base && r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`[]`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ContactChannel{Id: contactId, CustomerId: customerId})
+	}))
+	defer server.Close()
+
+	contacts := NewClient(server.URL).Contacts()
+	ctx := context.Background()
+	channel := ContactChannel{Type: ChannelEmail, Value: "john@makes.beats"}
+	if _, err := contacts.Create(ctx, customerId, channel); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := contacts.List(ctx, customerId); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if _, err := contacts.Get(ctx, customerId, contactId); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if _, err := contacts.Update(ctx, customerId, contactId, channel); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := contacts.Delete(ctx, customerId, contactId); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	want := []string{
+		"POST " + base,
+		"GET " + base,
+		"GET " + base + "/" + contactId.String(),
+		"PUT " + base + "/" + contactId.String(),
+		"DELETE " + base + "/" + contactId.String(),
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("Expected requests %v, got %v", want, requests)
+	}
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Errorf("Expected request %d to be %q, got %q", i, want[i], requests[i])
+		}
+	}
+}
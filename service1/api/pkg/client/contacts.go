@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Contacts calls the endpoints for a customer's contact channels; get one from
+// Client.Contacts
+type Contacts struct {
+	client *Client
+}
+
+// Create adds channel to the customer; its Id and CustomerId are set by the service
+func (c *Contacts) Create(ctx context.Context, customerId uuid.UUID, channel ContactChannel) (ContactChannel, error) {
+	body, err := jsonBody(channel)
+	if err != nil {
+		return ContactChannel{}, err
+	}
+	resp, err := c.client.api.CreateContactWithBody(ctx, customerId, contentTypeJSON, body)
+	return decode[ContactChannel](resp, err, http.StatusCreated)
+}
+
+func (c *Contacts) Get(ctx context.Context, customerId, id uuid.UUID) (ContactChannel, error) {
+	resp, err := c.client.api.GetContact(ctx, customerId, id)
+	return decode[ContactChannel](resp, err, http.StatusOK)
+}
+
+// List returns every contact channel of the customer
+func (c *Contacts) List(ctx context.Context, customerId uuid.UUID) ([]ContactChannel, error) {
+	resp, err := c.client.api.ListContacts(ctx, customerId)
+	return decode[[]ContactChannel](resp, err, http.StatusOK)
+}
+
+// Update replaces the channel's type, value, preference and consent
+func (c *Contacts) Update(ctx context.Context, customerId, id uuid.UUID, channel ContactChannel) (ContactChannel, error) {
+	body, err := jsonBody(channel)
+	if err != nil {
+		return ContactChannel{}, err
+	}
+	resp, err := c.client.api.UpdateContactWithBody(ctx, customerId, id, contentTypeJSON, body)
+	return decode[ContactChannel](resp, err, http.StatusOK)
+}
+
+func (c *Contacts) Delete(ctx context.Context, customerId, id uuid.UUID) error {
+	resp, err := c.client.api.DeleteContact(ctx, customerId, id)
+	return noContent(resp, err)
+}
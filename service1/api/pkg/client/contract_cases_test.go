@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service1/api/internal/contacts"
+	"service1/api/internal/customers"
+	"service1/api/internal/health"
+	"service1/api/internal/versioning"
+	"service1/api/internal/webhooks"
+)
+
+// contractRoutes registers the service's routes as main does; the handlers are
+// replaced by the replaying server, so they need no services
+func contractRoutes(e *echo.Echo) {
+	v1 := e.Group(versioning.Prefix("v1"))
+	customers.Routes(v1, customers.NewCustomersHandler(nil))
+	contacts.Routes(v1, contacts.NewContactHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+}
+
+var contractCases = []contractCase{
+	{"create_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Create(ctx, "John", "john@makes.beats")
+	}},
+	{"read_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Read(ctx, uuid.New())
+	}},
+	{"read_missing_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Read(ctx, uuid.New())
+	}},
+	{"update_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Update(ctx, uuid.New(), 1, "John", "john@makes.beats")
+	}},
+	{"update_stale_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Update(ctx, uuid.New(), 1, "John", "john@makes.beats")
+	}},
+	{"patch_customer", func(ctx context.Context, c *Client) (any, error) {
+		name := "Johnny"
+		return c.Patch(ctx, uuid.New(), CustomerPatch{Name: &name})
+	}},
+	{"delete_customer", func(ctx context.Context, c *Client) (any, error) {
+		return nil, c.Delete(ctx, uuid.New())
+	}},
+	{"list_customers", func(ctx context.Context, c *Client) (any, error) {
+		return c.List(ctx, CustomerFilter{Name: "John", Limit: 10})
+	}},
+	{"read_many_customers", func(ctx context.Context, c *Client) (any, error) {
+		return c.ReadMany(ctx, []uuid.UUID{uuid.New(), uuid.New()})
+	}},
+	{"anonymize_customer", func(ctx context.Context, c *Client) (any, error) {
+		return c.Anonymize(ctx, uuid.New(), AnonymizationRequest{RequestedBy: "privacy-team", Reason: "erasure request"})
+	}},
+	{"merge_customers", func(ctx context.Context, c *Client) (any, error) {
+		return c.Merge(ctx, uuid.New(), uuid.New())
+	}},
+	{"customer_history", func(ctx context.Context, c *Client) (any, error) {
+		return c.History(ctx, uuid.New())
+	}},
+	{"submit_customer_kyc", func(ctx context.Context, c *Client) (any, error) {
+		return c.SubmitKYC(ctx, uuid.New())
+	}},
+	{"create_contact", func(ctx context.Context, c *Client) (any, error) {
+		return c.Contacts().Create(ctx, uuid.New(), ContactChannel{Type: ChannelEmail, Value: "john@makes.beats", OptedIn: true})
+	}},
+	{"list_contacts", func(ctx context.Context, c *Client) (any, error) {
+		return c.Contacts().List(ctx, uuid.New())
+	}},
+	{"delete_contact", func(ctx context.Context, c *Client) (any, error) {
+		return nil, c.Contacts().Delete(ctx, uuid.New(), uuid.New())
+	}},
+}
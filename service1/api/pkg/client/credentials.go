@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// headerAPIKey matches auth.HeaderAPIKey; the client avoids importing the JWT dependency
+const headerAPIKey = "X-API-Key"
+
+// WithAPIKey sends key in the X-API-Key header of every request
+func (c *Client) WithAPIKey(key string) *Client {
+	c.httpClient.Transport = credentials{header: headerAPIKey, value: key, next: c.httpClient.Transport}
+	return c
+}
+
+// WithBearerToken sends token as an "Authorization: Bearer" header on every request
+func (c *Client) WithBearerToken(token string) *Client {
+	c.httpClient.Transport = credentials{header: "Authorization", value: "Bearer " + token, next: c.httpClient.Transport}
+	return c
+}
+
+// TokenSource hands out the bearer token to send with a request, for credentials
+// that expire and are refreshed, such as tokens from an identity provider or a
+// mounted file that is rotated. It is called for every request, so it should cache
+// the token until shortly before it expires.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// WithTokenSource sends the token source returns for each request as an
+// "Authorization: Bearer" header; a request fails if the source does
+func (c *Client) WithTokenSource(source TokenSource) *Client {
+	c.httpClient.Transport = bearerTokens{source: source, next: c.httpClient.Transport}
+	return c
+}
+
+// bearerTokens adds a bearer token from source to requests before sending them with next
+type bearerTokens struct {
+	source TokenSource
+	next   http.RoundTripper
+}
+
+func (t bearerTokens) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("bearer token: %w", err)
+	}
+	return credentials{header: "Authorization", value: "Bearer " + token, next: t.next}.RoundTrip(req)
+}
+
+// credentials adds an authentication header to requests before sending them with next
+type credentials struct {
+	header string
+	value  string
+	next   http.RoundTripper
+}
+
+func (t credentials) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.header, t.value)
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
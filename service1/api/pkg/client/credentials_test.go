@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWithTokenSource(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	tokens := []string{"first", "refreshed"}
+	c := NewClient(server.URL).WithTokenSource(TokenSourceFunc(func(ctx context.Context) (string, error) {
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token, nil
+	}))
+	for _, want := range []string{"Bearer first", "Bearer refreshed"} {
+		if err := c.Delete(context.Background(), uuid.New()); err != nil {
+			t.Fatal(err)
+		}
+		if authorization != want {
+			t.Errorf("Expected Authorization %q, got %q", want, authorization)
+		}
+	}
+}
+
+func TestWithTokenSource_Fails(t *testing.T) {
+	errExpired := errors.New("refresh token expired")
+	c := NewClient("http://unused").WithTokenSource(TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return "", errExpired
+	}))
+	if err := c.Delete(context.Background(), uuid.New()); !errors.Is(err, errExpired) {
+		t.Errorf("Expected the token source's error, got %v", err)
+	}
+}
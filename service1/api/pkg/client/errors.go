@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrNotFound is wrapped by an APIError for a 404, so errors.Is(err, ErrNotFound)
+	// tells a compensation the resource is already gone
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is wrapped by an APIError for a 409, e.g. a stale version or a
+	// state change the resource no longer allows
+	ErrConflict = errors.New("conflict")
+)
+
+// maxErrorBody bounds how much of an error response is kept in RawBody
+const maxErrorBody = 64 << 10
+
+// APIError is a response with a status the call did not expect. Code and Message
+// come from the service's error envelope, e.g. "conflict" or "not_found", so
+// callers can branch on them; RawBody keeps the body as sent, envelope or not.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	RawBody    []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns ErrNotFound or ErrConflict for those statuses, nil otherwise
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	}
+	return nil
+}
+
+// newAPIError reads resp's body into an APIError. The body is left to the caller
+// to close.
+func newAPIError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &APIError{StatusCode: resp.StatusCode, RawBody: body}
+	var envelope struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		apiErr.Code, apiErr.Message, apiErr.RequestID = envelope.Code, envelope.Message, envelope.RequestID
+	}
+	return apiErr
+}
+
+// IsStatus reports whether err is, or wraps, an APIError with status
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAPIError_ParsesEnvelope(t *testing.T) {
+	body := `{"code":"not_found","message":"resource not found","request_id":"req-1"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Delete(context.Background(), uuid.New())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" ||
+		apiErr.Message != "resource not found" || apiErr.RequestID != "req-1" || string(apiErr.RawBody) != body {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+	if !IsStatus(fmt.Errorf("compensate: %w", err), http.StatusNotFound) || IsStatus(err, http.StatusConflict) {
+		t.Error("Expected IsStatus to match the wrapped 404 only")
+	}
+}
+
+func TestAPIError_KeepsBodyWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream unavailable"))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Delete(context.Background(), uuid.New())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || apiErr.Code != "" ||
+		string(apiErr.RawBody) != "upstream unavailable" {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if got, want := err.Error(), "unexpected status code: 502"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAPIError_WrapsSentinels(t *testing.T) {
+	cases := map[int]error{http.StatusNotFound: ErrNotFound, http.StatusConflict: ErrConflict}
+	for status, want := range cases {
+		err := fmt.Errorf("call: %w", &APIError{StatusCode: status})
+		if !errors.Is(err, want) {
+			t.Errorf("Expected a %d to wrap %v", status, want)
+		}
+	}
+	err := &APIError{StatusCode: http.StatusInternalServerError}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Error("Expected a 500 to wrap neither sentinel")
+	}
+}
+
+// TestClient_EveryMethodReturnsAPIError guards against a method handing the saga a
+// zero-value customer with a nil error: every call answered with an error status
+// must return the envelope as an APIError
+func TestClient_EveryMethodReturnsAPIError(t *testing.T) {
+	calls := map[string]func(c *Client) error{
+		"Create": func(c *Client) error {
+			_, err := c.Create(context.Background(), "John", "john@makes.beats")
+			return err
+		},
+		"Read": func(c *Client) error {
+			_, err := c.Read(context.Background(), uuid.New())
+			return err
+		},
+		"Update": func(c *Client) error {
+			_, err := c.Update(context.Background(), uuid.New(), 1, "John", "john@makes.beats")
+			return err
+		},
+		"Patch": func(c *Client) error {
+			_, err := c.Patch(context.Background(), uuid.New(), CustomerPatch{})
+			return err
+		},
+		"Delete": func(c *Client) error {
+			return c.Delete(context.Background(), uuid.New())
+		},
+		"List": func(c *Client) error {
+			_, err := c.List(context.Background(), CustomerFilter{})
+			return err
+		},
+		"ReadMany": func(c *Client) error {
+			_, err := c.ReadMany(context.Background(), []uuid.UUID{uuid.New()})
+			return err
+		},
+		"Anonymize": func(c *Client) error {
+			_, err := c.Anonymize(context.Background(), uuid.New(), AnonymizationRequest{RequestedBy: "ops"})
+			return err
+		},
+		"Merge": func(c *Client) error {
+			_, err := c.Merge(context.Background(), uuid.New(), uuid.New())
+			return err
+		},
+		"History": func(c *Client) error {
+			_, err := c.History(context.Background(), uuid.New())
+			return err
+		},
+		"SubmitKYC": func(c *Client) error {
+			_, err := c.SubmitKYC(context.Background(), uuid.New())
+			return err
+		},
+		"VerifyKYC": func(c *Client) error {
+			_, err := c.VerifyKYC(context.Background(), uuid.New())
+			return err
+		},
+		"FailKYC": func(c *Client) error {
+			_, err := c.FailKYC(context.Background(), uuid.New())
+			return err
+		},
+		"Contacts.Create": func(c *Client) error {
+			_, err := c.Contacts().Create(context.Background(), uuid.New(), ContactChannel{})
+			return err
+		},
+		"Contacts.Get": func(c *Client) error {
+			_, err := c.Contacts().Get(context.Background(), uuid.New(), uuid.New())
+			return err
+		},
+		"Contacts.List": func(c *Client) error {
+			_, err := c.Contacts().List(context.Background(), uuid.New())
+			return err
+		},
+		"Contacts.Update": func(c *Client) error {
+			_, err := c.Contacts().Update(context.Background(), uuid.New(), uuid.New(), ContactChannel{})
+			return err
+		},
+		"Contacts.Delete": func(c *Client) error {
+			return c.Contacts().Delete(context.Background(), uuid.New(), uuid.New())
+		},
+		"ListCustomers": func(c *Client) error {
+			it := c.ListCustomers(context.Background(), CustomerFilter{})
+			for it.Next() {
+			}
+			return it.Err()
+		},
+	}
+	statuses := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError}
+
+	for name, call := range calls {
+		for _, status := range statuses {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"code":"code-` + strconv.Itoa(status) + `","message":"failed","request_id":"req-1"}`))
+			}))
+			err := call(NewClient(server.URL))
+			server.Close()
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) {
+				t.Errorf("%s: expected an APIError for a %d, got %v", name, status, err)
+				continue
+			}
+			if apiErr.StatusCode != status || apiErr.Code != "code-"+strconv.Itoa(status) || apiErr.RequestID != "req-1" {
+				t.Errorf("%s: unexpected error for a %d: %+v", name, status, apiErr)
+			}
+		}
+	}
+}
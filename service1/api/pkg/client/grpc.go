@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// metadataRequestID is the metadata key the gRPC server returns a call's request ID in
+const metadataRequestID = "x-request-id"
+
+// outgoingMetadata adds to the metadata sent with a call, like the transports the
+// Client's With options install add headers
+type outgoingMetadata func(ctx context.Context, md metadata.MD) error
+
+// WithTenantFrom sends the tenant tenantOf returns for a call's context, like
+// Client.WithTenantFrom
+func (c *GRPCClient) WithTenantFrom(tenantOf func(ctx context.Context) string) *GRPCClient {
+	c.metadata = append(c.metadata, func(ctx context.Context, md metadata.MD) error {
+		if tenant := tenantOf(ctx); tenant != "" {
+			md.Set(headerTenant, tenant)
+		}
+		return nil
+	})
+	return c
+}
+
+// WithTraceContextFrom sends the trace context traceOf returns for a call's context,
+// like Client.WithTraceContextFrom
+func (c *GRPCClient) WithTraceContextFrom(traceOf func(ctx context.Context) TraceContext) *GRPCClient {
+	c.metadata = append(c.metadata, func(ctx context.Context, md metadata.MD) error {
+		trace := traceOf(ctx)
+		if trace.TraceParent != "" {
+			md.Set(headerTraceParent, trace.TraceParent)
+		}
+		if trace.CorrelationID != "" {
+			md.Set(headerCorrelationID, trace.CorrelationID)
+		}
+		return nil
+	})
+	return c
+}
+
+// WithAPIKey sends key with every call, like Client.WithAPIKey
+func (c *GRPCClient) WithAPIKey(key string) *GRPCClient {
+	c.metadata = append(c.metadata, func(ctx context.Context, md metadata.MD) error {
+		md.Set(headerAPIKey, key)
+		return nil
+	})
+	return c
+}
+
+// WithBearerToken sends token as the authorization of every call, like
+// Client.WithBearerToken
+func (c *GRPCClient) WithBearerToken(token string) *GRPCClient {
+	return c.WithTokenSource(TokenSourceFunc(func(ctx context.Context) (string, error) {
+		return token, nil
+	}))
+}
+
+// WithTokenSource sends the token source returns for each call as its
+// authorization, like Client.WithTokenSource; a call fails if the source does
+func (c *GRPCClient) WithTokenSource(source TokenSource) *GRPCClient {
+	c.metadata = append(c.metadata, func(ctx context.Context, md metadata.MD) error {
+		token, err := source.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("bearer token: %w", err)
+		}
+		md.Set("authorization", "Bearer "+token)
+		return nil
+	})
+	return c
+}
+
+// WithTimeout bounds every call by timeout instead of DefaultTimeout, like
+// Client.WithTimeout
+func (c *GRPCClient) WithTimeout(timeout time.Duration) *GRPCClient {
+	c.timeout = timeout
+	return c
+}
+
+// invoke calls rpc with req and the client's metadata, within the call's timeout. A
+// failed call is returned as the APIError the REST API would have answered with.
+func invoke[Req, Resp any](ctx context.Context, c *GRPCClient, rpc func(context.Context, Req, ...grpc.CallOption) (Resp, error), req Req) (Resp, error) {
+	if timeout := callTimeout(ctx, c.timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	for _, add := range c.metadata {
+		if err := add(ctx, md); err != nil {
+			var zero Resp
+			return zero, err
+		}
+	}
+
+	var header, trailer metadata.MD
+	resp, err := rpc(metadata.NewOutgoingContext(ctx, md), req, grpc.Header(&header), grpc.Trailer(&trailer))
+	if err != nil {
+		if ctx.Err() != nil {
+			// Cancelled or timed out on this side, as the HTTP client reports it
+			return resp, ctx.Err()
+		}
+		return resp, grpcError(err, metadata.Join(header, trailer))
+	}
+	return resp, nil
+}
+
+// grpcError converts a failed call's status to an APIError with the REST status its
+// code stands for, so callers handle both transports' errors alike. Errors that are
+// not a status, such as a token source's, are returned as they are.
+func grpcError(err error, md metadata.MD) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	statusCode := httpStatus(st.Code())
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Code:       strings.ToLower(strings.ReplaceAll(http.StatusText(statusCode), " ", "_")),
+		Message:    st.Message(),
+	}
+	if values := md.Get(metadataRequestID); len(values) > 0 {
+		apiErr.RequestID = values[0]
+	}
+	return apiErr
+}
+
+// httpStatus is the REST status the gRPC server answers with code for
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// fromTimestamp converts t, leaving an unset timestamp the zero time
+func fromTimestamp(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+// fromOptionalTimestamp converts an optional timestamp
+func fromOptionalTimestamp(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	converted := t.AsTime()
+	return &converted
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"service1/api/pkg/pb/customersv1"
+)
+
+// GRPCClient calls the customer service's gRPC API. It has the Client methods the
+// gRPC API covers, taking and returning the same types, and its errors are the
+// APIErrors Client returns, so errors.Is(err, ErrNotFound) works with either.
+type GRPCClient struct {
+	customers customersv1.CustomerServiceClient
+	// metadata is added to every call; the With options append to it
+	metadata []outgoingMetadata
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+}
+
+// NewGRPCClient calls the service over conn, which is dialled with the transport
+// credentials, and any interceptors, the caller needs
+func NewGRPCClient(conn grpc.ClientConnInterface) *GRPCClient {
+	return &GRPCClient{customers: customersv1.NewCustomerServiceClient(conn), timeout: DefaultTimeout}
+}
+
+func (c *GRPCClient) Create(ctx context.Context, name, email string) (Customer, error) {
+	customer, err := invoke(ctx, c, c.customers.CreateCustomer, &customersv1.CreateCustomerRequest{Name: name, Email: email})
+	if err != nil {
+		return Customer{}, err
+	}
+	return fromCustomerMessage(customer)
+}
+
+func (c *GRPCClient) Read(ctx context.Context, id uuid.UUID) (Customer, error) {
+	customer, err := invoke(ctx, c, c.customers.GetCustomer, &customersv1.GetCustomerRequest{Id: id.String()})
+	if err != nil {
+		return Customer{}, err
+	}
+	return fromCustomerMessage(customer)
+}
+
+// Delete deletes the customer; deleting a missing customer is not an error
+func (c *GRPCClient) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := invoke(ctx, c, c.customers.DeleteCustomer, &customersv1.DeleteCustomerRequest{Id: id.String()})
+	return err
+}
+
+func fromCustomerMessage(message *customersv1.Customer) (Customer, error) {
+	id, err := uuid.Parse(message.GetId())
+	if err != nil {
+		return Customer{}, fmt.Errorf("invalid customer id %q: %w", message.GetId(), err)
+	}
+	customer := Customer{
+		Id:           id,
+		Name:         message.GetName(),
+		Email:        message.GetEmail(),
+		Version:      int(message.GetVersion()),
+		KYCStatus:    KYCStatus(message.GetKycStatus()),
+		CreatedAt:    fromTimestamp(message.GetCreatedAt()),
+		ModifiedAt:   fromTimestamp(message.GetModifiedAt()),
+		AnonymizedAt: fromOptionalTimestamp(message.GetAnonymizedAt()),
+	}
+	if mergedInto := message.GetMergedInto(); mergedInto != "" {
+		survivor, err := uuid.Parse(mergedInto)
+		if err != nil {
+			return Customer{}, fmt.Errorf("invalid merged_into %q: %w", mergedInto, err)
+		}
+		customer.MergedInto = &survivor
+	}
+	return customer, nil
+}
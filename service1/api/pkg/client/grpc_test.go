@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"service1/api/pkg/pb/customersv1"
+)
+
+// customerServer answers GetCustomer with customer, or NotFound when it is nil,
+// keeping the metadata of the last call
+type customerServer struct {
+	customersv1.UnimplementedCustomerServiceServer
+	customer *customersv1.Customer
+	md       metadata.MD
+}
+
+func (s *customerServer) GetCustomer(ctx context.Context, req *customersv1.GetCustomerRequest) (*customersv1.Customer, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, "req-1"))
+	if s.customer == nil {
+		return nil, status.Error(codes.NotFound, "customer not found")
+	}
+	return s.customer, nil
+}
+
+func dialCustomers(t *testing.T, server *customerServer) *GRPCClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	customersv1.RegisterCustomerServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGRPCClient(conn)
+}
+
+func TestGRPCClient_Read(t *testing.T) {
+	id := uuid.New()
+	server := &customerServer{customer: &customersv1.Customer{
+		Id: id.String(), Name: "Jane", Version: 2, KycStatus: string(KYCVerified), CreatedAt: timestamppb.Now(),
+	}}
+	client := dialCustomers(t, server).
+		WithAPIKey("s3cret").
+		WithTenantFrom(func(ctx context.Context) string { return "lender-a" })
+
+	customer, err := client.Read(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if customer.Id != id || customer.Name != "Jane" || customer.Version != 2 || customer.KYCStatus != KYCVerified ||
+		customer.CreatedAt.IsZero() || !customer.ModifiedAt.IsZero() || customer.AnonymizedAt != nil {
+		t.Errorf("Unexpected customer %+v", customer)
+	}
+	if got := server.md.Get("x-api-key"); len(got) != 1 || got[0] != "s3cret" {
+		t.Errorf("Expected the API key in the metadata, got %v", server.md)
+	}
+	if got := server.md.Get("x-tenant-id"); len(got) != 1 || got[0] != "lender-a" {
+		t.Errorf("Expected the tenant in the metadata, got %v", server.md)
+	}
+}
+
+func TestGRPCClient_ErrorsMatchREST(t *testing.T) {
+	_, err := dialCustomers(t, &customerServer{}).Read(context.Background(), uuid.New())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected a not found APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" || apiErr.Message != "customer not found" ||
+		apiErr.RequestID != "req-1" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type recordedRequests []RequestMetrics
+
+func (r *recordedRequests) RecordRequest(ctx context.Context, request RequestMetrics) {
+	*r = append(*r, request)
+}
+
+func TestWithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var recorded recordedRequests
+	c := NewClient(server.URL).WithMetrics(&recorded)
+	_ = c.Delete(context.Background(), uuid.New())
+
+	if len(recorded) != 1 {
+		t.Fatalf("Expected one request to be recorded, got %d", len(recorded))
+	}
+	got := recorded[0]
+	if got.Method != http.MethodDelete || got.Endpoint != "/v1/customers/:id" || got.Status != http.StatusServiceUnavailable ||
+		got.Duration <= 0 || !got.Failed() {
+		t.Errorf("Unexpected metrics %+v", got)
+	}
+}
+
+func TestWithMetrics_Unreachable(t *testing.T) {
+	var recorded recordedRequests
+	c := NewClient("http://127.0.0.1:1").WithMetrics(&recorded)
+	_ = c.Delete(context.Background(), uuid.New())
+
+	if len(recorded) != 1 || recorded[0].Err == nil || recorded[0].Status != 0 || !recorded[0].Failed() {
+		t.Errorf("Expected the failed request to be recorded, got %+v", recorded)
+	}
+}
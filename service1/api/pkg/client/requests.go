@@ -0,0 +1,63 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"service1/api/pkg/client/internal/oapi"
+)
+
+const contentTypeJSON = "application/json"
+
+// jsonBody encodes v as a request body. It is a *bytes.Reader so the request can be
+// rewound and resent by WithRetry.
+func jsonBody(v any) (io.Reader, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// decode reads the JSON body of a response with one of statuses into a T. Any other
+// status is returned as an APIError.
+func decode[T any](resp *http.Response, err error, statuses ...int) (T, error) {
+	var v T
+	if err != nil {
+		return v, err
+	}
+	defer resp.Body.Close()
+	if !slices.Contains(statuses, resp.StatusCode) {
+		return v, newAPIError(resp)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return v, nil
+}
+
+// noContent checks the response to a call that returns no body
+func noContent(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return newAPIError(resp)
+	}
+	return nil
+}
+
+// ifMatch makes a write conditional on the resource still being at version
+func ifMatch(version int) oapi.RequestEditorFn {
+	return func(ctx context.Context, req *http.Request) error {
+		req.Header.Set("If-Match", strconv.Quote(strconv.Itoa(version)))
+		return nil
+	}
+}
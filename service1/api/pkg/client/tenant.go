@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// headerTenant matches tenant.Header
+const headerTenant = "X-Tenant-ID"
+
+// WithTenantFrom sends the tenant tenantOf returns for a request's context in the
+// X-Tenant-ID header, so one client can act for several lenders. Requests for which
+// it returns "" name no tenant and act for the default tenant or the one the
+// credentials are bound to.
+func (c *Client) WithTenantFrom(tenantOf func(ctx context.Context) string) *Client {
+	c.httpClient.Transport = tenantHeader{tenantOf: tenantOf, next: c.httpClient.Transport}
+	return c
+}
+
+// tenantHeader adds the X-Tenant-ID header to requests before sending them with next
+type tenantHeader struct {
+	tenantOf func(ctx context.Context) string
+	next     http.RoundTripper
+}
+
+func (t tenantHeader) RoundTrip(req *http.Request) (*http.Response, error) {
+	if tenant := t.tenantOf(req.Context()); tenant != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(headerTenant, tenant)
+	}
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
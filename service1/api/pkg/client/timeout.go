@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds every call a client makes unless WithTimeout or
+// WithCallTimeout says otherwise
+var DefaultTimeout = 30 * time.Second
+
+// callTimeoutKey is the context key WithCallTimeout stores a call's budget under
+type callTimeoutKey struct{}
+
+// WithCallTimeout returns a context whose calls each get timeout, in place of the
+// client's timeout, from the moment they are made: retries and reading the
+// response included. Unlike context.WithTimeout it sets no deadline on ctx, so a
+// saga step can give every call it makes the same budget. A timeout of 0 or less
+// leaves the calls bounded by ctx alone.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeoutKey{}, timeout)
+}
+
+// callTimeout is the budget of a call made with ctx: the one WithCallTimeout set,
+// or fallback
+func callTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(callTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	return fallback
+}
+
+// WithTimeout bounds every call by timeout instead of DefaultTimeout; 0 leaves them
+// bounded by their context alone. WithCallTimeout overrides it for a single call.
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	c.timeout = timeout
+	return c
+}
+
+// do sends req with the HTTP client, cancelling it once the call's timeout passes.
+// It sits in front of the transports the With options install, so the timeout
+// covers every retry and the response body until it is closed.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	timeout := callTimeout(req.Context(), c.timeout)
+	if timeout <= 0 {
+		return c.httpClient.Do(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// doerFunc lets Client.do send the generated client's requests
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// cancelOnClose releases a call's timeout once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
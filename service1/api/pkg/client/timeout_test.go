@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stallingServer answers GETs to /stall only once the client gives up, and other
+// requests straight away
+func stallingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stall" {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func get(t *testing.T, c *Client, ctx context.Context, url string) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.do(req)
+}
+
+func TestWithTimeout_CancelsSlowCalls(t *testing.T) {
+	server := stallingServer(t)
+	c := NewClient(server.URL).WithTimeout(20 * time.Millisecond)
+	if _, err := get(t, c, context.Background(), server.URL+"/stall"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the call to time out, got %v", err)
+	}
+
+	resp, err := get(t, c, context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, err := io.ReadAll(resp.Body); err != nil || string(body) != "ok" {
+		t.Errorf("Expected the body to stay readable after the call returned, got %q, %v", body, err)
+	}
+}
+
+func TestWithCallTimeout_OverridesClientTimeout(t *testing.T) {
+	server := stallingServer(t)
+	c := NewClient(server.URL).WithTimeout(time.Hour)
+	ctx := WithCallTimeout(context.Background(), 20*time.Millisecond)
+	start := time.Now()
+	if _, err := get(t, c, ctx, server.URL+"/stall"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the call to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the call's own timeout to apply, took %v", elapsed)
+	}
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("Expected WithCallTimeout to leave the context without a deadline")
+	}
+}
+
+func TestWithCallTimeout_CoversRetries(t *testing.T) {
+	server, requests := flakyServer(t, 1000)
+	c := NewClient(server.URL).WithRetry(Retry{Attempts: 1000, InitialBackoff: 5 * time.Millisecond, MaxBackoff: 5 * time.Millisecond})
+	ctx := WithCallTimeout(context.Background(), 50*time.Millisecond)
+	if _, err := get(t, c, ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the retries to stop at the call's timeout, got %v", err)
+	}
+	if n := requests.Load(); n < 2 || n >= 1000 {
+		t.Errorf("Expected a few attempts within the timeout, got %d", n)
+	}
+}
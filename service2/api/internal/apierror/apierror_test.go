@@ -0,0 +1,95 @@
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func serve(t *testing.T, handler echo.HandlerFunc) (int, Response) {
+	e := echo.New()
+	e.HTTPErrorHandler = Handler
+	e.Use(middleware.RequestID())
+	e.Use(middleware.Recover())
+	e.GET("/test", handler)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	var response Response
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode error body %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, response
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"bad request", BadRequest("invalid application id", errors.New("invalid UUID length: 3")), http.StatusBadRequest, "bad_request", "invalid application id"},
+		{"http error", echo.NewHTTPError(http.StatusConflict, "stale version"), http.StatusConflict, "conflict", "stale version"},
+		{"api error", &Error{Status: http.StatusConflict, Code: "version_conflict", Message: "stale version"}, http.StatusConflict, "version_conflict", "stale version"},
+		{"no rows", pgx.ErrNoRows, http.StatusNotFound, "not_found", "resource not found"},
+		{"unknown error", errors.New("connection reset"), http.StatusInternalServerError, "internal_server_error", "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := serve(t, func(c echo.Context) error { return tt.err })
+			if status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, status)
+			}
+			if response.Code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, response.Code)
+			}
+			if response.Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, response.Message)
+			}
+			if response.RequestID == "" {
+				t.Error("Expected the request id in the error body")
+			}
+		})
+	}
+}
+
+func TestHandler_Validation(t *testing.T) {
+	type fieldError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+	status, response := serve(t, func(c echo.Context) error {
+		return Validation([]fieldError{{Field: "term_years", Message: "must be at most 30"}}, errors.New("validation failed"))
+	})
+	if status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d", status)
+	}
+	if response.Code != "validation_failed" {
+		t.Errorf("Expected code validation_failed, got %s", response.Code)
+	}
+	details, ok := response.Details.([]any)
+	if !ok || len(details) != 1 {
+		t.Errorf("Expected one field error in details, got %v", response.Details)
+	}
+}
+
+func TestHandler_Panic(t *testing.T) {
+	status, response := serve(t, func(c echo.Context) error {
+		panic("boom")
+	})
+	if status != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", status)
+	}
+	if response.Message != "internal server error" {
+		t.Errorf("Expected internals to be hidden, got %q", response.Message)
+	}
+}
@@ -0,0 +1,210 @@
+// Package auth authenticates requests with an API key (X-API-Key) or an HS256-signed
+// bearer JWT and authorizes them by role: reads need the read role, every other
+// method needs write, which includes read. Administrative routes also need admin,
+// which includes both. With no keys or secret configured the
+// middleware lets every request through, as the services did before auth existed.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey carries an API key
+const HeaderAPIKey = "X-API-Key"
+
+const (
+	RoleRead  = "read"
+	RoleWrite = "write"
+	RoleAdmin = "admin"
+)
+
+// publicPaths are served without credentials so probes, scrapers and tooling keep working
+var publicPaths = []string{"/healthz", "/readyz", "/openapi.json", "/metrics"}
+
+// Principal is the authenticated caller
+type Principal struct {
+	Subject string
+	Roles   []string
+	// Tenant is the tenant the credentials are bound to; empty credentials may act for
+	// any tenant (see tenant.Resolve)
+	Tenant string
+}
+
+// HasRole reports whether p was granted role; admin implies write and write implies read
+func (p Principal) HasRole(role string) bool {
+	switch {
+	case slices.Contains(p.Roles, role) || slices.Contains(p.Roles, RoleAdmin):
+		return true
+	case role == RoleRead:
+		return slices.Contains(p.Roles, RoleWrite)
+	}
+	return false
+}
+
+type Config struct {
+	// APIKeys maps each accepted key to the principal it authenticates
+	APIKeys map[string]Principal
+	// JWTSecret verifies HS256 bearer tokens; their sub, roles and optional tenant claims
+	// become the principal
+	JWTSecret []byte
+}
+
+// Enabled reports whether any credentials are configured
+func (c Config) Enabled() bool {
+	return len(c.APIKeys) > 0 || len(c.JWTSecret) > 0
+}
+
+// ParseAPIKeys parses comma-separated subject:key:roles entries with |-separated
+// roles, e.g. "saga-client:s3cret:read|write,reporting:r3port:read". An optional
+// fourth part binds the key to a tenant: "lender-a:s3cret:read|write:lender-a".
+func ParseAPIKeys(value string) (map[string]Principal, error) {
+	keys := map[string]Principal{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry for %q: expected subject:key:roles[:tenant]", parts[0])
+		}
+		roles := strings.Split(parts[2], "|")
+		for _, role := range roles {
+			if role != RoleRead && role != RoleWrite && role != RoleAdmin {
+				return nil, fmt.Errorf("invalid role %q for %s", role, parts[0])
+			}
+		}
+		principal := Principal{Subject: parts[0], Roles: roles}
+		if len(parts) == 4 {
+			if parts[3] == "" {
+				return nil, fmt.Errorf("empty tenant for %s", parts[0])
+			}
+			principal.Tenant = parts[3]
+		}
+		keys[parts[1]] = principal
+	}
+	return keys, nil
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx that carries the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal stored in ctx, if the request was authenticated
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
+
+// Middleware rejects requests without valid credentials with a 401 and requests
+// lacking the role their method needs with a 403
+func Middleware(config Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !config.Enabled() {
+			return next
+		}
+		return func(c echo.Context) error {
+			if slices.Contains(publicPaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			header := c.Request().Header
+			principal, err := config.Authenticate(header.Get(HeaderAPIKey), header.Get(echo.HeaderAuthorization))
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="api"`)
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid credentials").SetInternal(err)
+			}
+			if role := RequiredRole(c.Request().Method); !principal.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("the %s role is required", role))
+			}
+
+			c.SetRequest(c.Request().WithContext(WithPrincipal(c.Request().Context(), principal)))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole rejects requests whose principal lacks role with a 403, for routes that
+// need more than their method does. Like Middleware, it lets every request through
+// when no credentials are configured and so no principal was authenticated.
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := FromContext(c.Request().Context())
+			if ok && !principal.HasRole(role) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("the %s role is required", role))
+			}
+			return next(c)
+		}
+	}
+}
+
+// Authenticate resolves the principal from an API key or, when none was sent, an
+// Authorization header value. The gRPC server passes the same values from metadata.
+func (c Config) Authenticate(apiKey, authorization string) (Principal, error) {
+	if apiKey != "" {
+		return c.apiKey(apiKey)
+	}
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return c.bearer(strings.TrimSpace(token))
+	}
+	return Principal{}, fmt.Errorf("no credentials")
+}
+
+// apiKey compares against every configured key in constant time
+func (c Config) apiKey(key string) (Principal, error) {
+	var found Principal
+	ok := false
+	for candidate, principal := range c.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			found, ok = principal, true
+		}
+	}
+	if !ok {
+		return Principal{}, fmt.Errorf("unknown API key")
+	}
+	return found, nil
+}
+
+type claims struct {
+	Roles  []string `json:"roles"`
+	Tenant string   `json:"tenant"`
+	jwt.RegisteredClaims
+}
+
+func (c Config) bearer(token string) (Principal, error) {
+	if len(c.JWTSecret) == 0 {
+		return Principal{}, fmt.Errorf("bearer tokens are not accepted")
+	}
+	var parsed claims
+	_, err := jwt.ParseWithClaims(token, &parsed, func(*jwt.Token) (any, error) {
+		return c.JWTSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Principal{}, err
+	}
+	if parsed.Subject == "" {
+		return Principal{}, fmt.Errorf("token has no subject")
+	}
+	return Principal{Subject: parsed.Subject, Roles: parsed.Roles, Tenant: parsed.Tenant}, nil
+}
+
+// RequiredRole is the role an HTTP method needs: read for safe methods, write otherwise
+func RequiredRole(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return RoleRead
+	}
+	return RoleWrite
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+var secret = []byte("test-secret")
+
+func token(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("Unable to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestMiddleware(t *testing.T) {
+	keys, err := ParseAPIKeys("saga-client:writer-key:read|write,reporting:reader-key:read")
+	if err != nil {
+		t.Fatalf("ParseAPIKeys failed: %v", err)
+	}
+	config := Config{APIKeys: keys, JWTSecret: secret}
+	expires := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		status  int
+	}{
+		{"no credentials", http.MethodGet, "/applications", nil, http.StatusUnauthorized},
+		{"probe is public", http.MethodGet, "/readyz", nil, http.StatusOK},
+		{"metrics are public", http.MethodGet, "/metrics", nil, http.StatusOK},
+		{"unknown key", http.MethodGet, "/applications", map[string]string{HeaderAPIKey: "nope"}, http.StatusUnauthorized},
+		{"reader reads", http.MethodGet, "/applications", map[string]string{HeaderAPIKey: "reader-key"}, http.StatusOK},
+		{"reader writes", http.MethodPost, "/applications", map[string]string{HeaderAPIKey: "reader-key"}, http.StatusForbidden},
+		{"writer reads", http.MethodGet, "/applications", map[string]string{HeaderAPIKey: "writer-key"}, http.StatusOK},
+		{"writer writes", http.MethodPost, "/applications", map[string]string{HeaderAPIKey: "writer-key"}, http.StatusOK},
+		{"jwt with role", http.MethodPost, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodHS256, secret,
+				jwt.MapClaims{"sub": "saga-client", "roles": []string{"write"}, "exp": expires}),
+		}, http.StatusOK},
+		{"jwt without role", http.MethodPost, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodHS256, secret,
+				jwt.MapClaims{"sub": "reporting", "roles": []string{"read"}, "exp": expires}),
+		}, http.StatusForbidden},
+		{"jwt with wrong secret", http.MethodGet, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodHS256, []byte("other"),
+				jwt.MapClaims{"sub": "saga-client", "roles": []string{"read"}, "exp": expires}),
+		}, http.StatusUnauthorized},
+		{"expired jwt", http.MethodGet, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodHS256, secret,
+				jwt.MapClaims{"sub": "saga-client", "roles": []string{"read"}, "exp": time.Now().Add(-time.Minute).Unix()}),
+		}, http.StatusUnauthorized},
+		{"jwt without expiry", http.MethodGet, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodHS256, secret,
+				jwt.MapClaims{"sub": "saga-client", "roles": []string{"read"}}),
+		}, http.StatusUnauthorized},
+		{"unsigned jwt", http.MethodGet, "/applications", map[string]string{
+			echo.HeaderAuthorization: "Bearer " + token(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType,
+				jwt.MapClaims{"sub": "saga-client", "roles": []string{"read"}, "exp": expires}),
+		}, http.StatusUnauthorized},
+	}
+
+	e := echo.New()
+	e.Use(Middleware(config))
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/applications", ok)
+	e.POST("/applications", ok)
+	e.GET("/readyz", ok)
+	e.GET("/metrics", ok)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddleware_DisabledWithoutCredentials(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware(Config{}))
+	e.POST("/applications", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/applications", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected auth to be disabled, got %d", rec.Code)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	keys, err := ParseAPIKeys("saga-client:writer-key:read|write,operator:admin-key:admin")
+	if err != nil {
+		t.Fatalf("ParseAPIKeys failed: %v", err)
+	}
+	e := echo.New()
+	e.Use(Middleware(Config{APIKeys: keys}))
+	ok := func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.DELETE("/applications/:id", ok, RequireRole(RoleAdmin))
+	e.GET("/applications/:id", ok)
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		status int
+	}{
+		{"writer deletes", http.MethodDelete, "writer-key", http.StatusForbidden},
+		{"admin deletes", http.MethodDelete, "admin-key", http.StatusNoContent},
+		{"admin reads", http.MethodGet, "admin-key", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/applications/1", nil)
+			req.Header.Set(HeaderAPIKey, tt.key)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestParseAPIKeys_RejectsMalformedEntries(t *testing.T) {
+	for _, value := range []string{"saga-client:key", "saga-client:key:owner", ":key:read", "saga-client::read",
+		"lender-a:key:read:", "lender-a:key:read:lender-a:extra"} {
+		if _, err := ParseAPIKeys(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestAuthenticate_Tenant(t *testing.T) {
+	keys, err := ParseAPIKeys("saga-client:shared-key:write,lender-a:lender-key:read:lender-a")
+	if err != nil {
+		t.Fatalf("ParseAPIKeys failed: %v", err)
+	}
+	config := Config{APIKeys: keys, JWTSecret: secret}
+
+	if principal, _ := config.Authenticate("shared-key", ""); principal.Tenant != "" {
+		t.Errorf("Expected a key without a tenant to stay unbound, got %q", principal.Tenant)
+	}
+	if principal, _ := config.Authenticate("lender-key", ""); principal.Tenant != "lender-a" {
+		t.Errorf("Expected the key to be bound to lender-a, got %q", principal.Tenant)
+	}
+	bearer := "Bearer " + token(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{
+		"sub": "dashboard", "roles": []string{"read"}, "tenant": "lender-b", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if principal, err := config.Authenticate("", bearer); err != nil || principal.Tenant != "lender-b" {
+		t.Errorf("Expected the token to be bound to lender-b, got %q (%v)", principal.Tenant, err)
+	}
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Retry bounds how long Connect waits for a database that is not up yet, such as
+// one starting alongside the service or restarting
+type Retry struct {
+	Attempts       int
+	InitialBackoff time.Duration // doubled after each failed attempt
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetry gives the database about a minute to come up
+var DefaultRetry = Retry{Attempts: 10, InitialBackoff: 500 * time.Millisecond, MaxBackoff: 10 * time.Second}
+
+// Pinger is implemented by the connection pool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Connect creates a pool for config and waits until the database answers, so the
+// service neither starts with a pool it cannot use nor gives up on a database that
+// is a few seconds behind it. Once connected the pool replaces connections broken by
+// a database restart on its own: dead ones are dropped when they fail and its health
+// check, every config.HealthCheckPeriod, tops it back up to MinConns.
+func Connect(ctx context.Context, config *pgxpool.Config, retry Retry) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := WaitReady(ctx, pool, retry); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// WaitReady pings db until it answers, backing off exponentially between attempts.
+// It gives up after retry.Attempts pings or when ctx is done.
+func WaitReady(ctx context.Context, db Pinger, retry Retry) error {
+	backoff := retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := db.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= retry.Attempts {
+			return fmt.Errorf("database not reachable after %d attempts: %w", attempt, err)
+		}
+		slog.WarnContext(ctx, "Database not reachable, retrying",
+			"attempt", attempt, "backoff", backoff.String(), "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not reachable: %w", err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retry.MaxBackoff)
+	}
+}
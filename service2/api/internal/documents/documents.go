@@ -0,0 +1,232 @@
+package documents
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service2/api/internal/database"
+	"service2/api/internal/tenant"
+)
+
+// Document types an application can receive
+const (
+	TypeIdentity          = "identity"
+	TypeIncome            = "income"
+	TypeEmployment        = "employment"
+	TypeBankStatement     = "bank_statement"
+	TypeAppraisal         = "appraisal"
+	TypePurchaseAgreement = "purchase_agreement"
+	TypeOther             = "other"
+)
+
+var documentTypes = map[string]bool{
+	TypeIdentity:          true,
+	TypeIncome:            true,
+	TypeEmployment:        true,
+	TypeBankStatement:     true,
+	TypeAppraisal:         true,
+	TypePurchaseAgreement: true,
+	TypeOther:             true,
+}
+
+// Document is the metadata of a file received for a mortgage application. The file
+// itself lives in external storage at StorageURL.
+type Document struct {
+	Id            uuid.UUID `json:"id"`
+	ApplicationId uuid.UUID `json:"application_id"`
+	Type          string    `json:"type"`
+	Filename      string    `json:"filename"`
+	StorageURL    string    `json:"storage_url"`
+	Checksum      string    `json:"checksum"` // hex-encoded SHA-256 of the file
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+var (
+	// ErrNotFound is returned when the application has no document with the requested ID
+	ErrNotFound = errors.New("document not found")
+	// ErrApplicationNotFound is returned when registering a document for an application that does not exist
+	ErrApplicationNotFound = errors.New("mortgage application not found")
+	// ErrInvalidDocument is returned when document metadata is missing or malformed
+	ErrInvalidDocument = errors.New("invalid document")
+)
+
+// Validate normalizes the document metadata and checks it is complete and well-formed
+func (d *Document) Validate() error {
+	d.Type = strings.ToLower(strings.TrimSpace(d.Type))
+	d.Filename = strings.TrimSpace(d.Filename)
+	d.StorageURL = strings.TrimSpace(d.StorageURL)
+	d.Checksum = strings.ToLower(strings.TrimSpace(d.Checksum))
+
+	if !documentTypes[d.Type] {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidDocument, d.Type)
+	}
+	if d.Filename == "" {
+		return fmt.Errorf("%w: filename is required", ErrInvalidDocument)
+	}
+	if u, err := url.Parse(d.StorageURL); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%w: storage_url must be an absolute URL", ErrInvalidDocument)
+	}
+	if sum, err := hex.DecodeString(d.Checksum); err != nil || len(sum) != 32 {
+		return fmt.Errorf("%w: checksum must be a hex-encoded SHA-256", ErrInvalidDocument)
+	}
+	return nil
+}
+
+type Repository interface {
+	Create(ctx context.Context, document Document) (Document, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (Document, error)
+	Delete(ctx context.Context, applicationId, id uuid.UUID) error
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Document, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, document Document) (Document, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (Document, error)
+	Delete(ctx context.Context, applicationId, id uuid.UUID) error
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Document, error)
+}
+
+const documentColumns = "id, application_id, type, filename, storage_url, checksum, created_at"
+
+// scanDocument scans a row selected with documentColumns
+func scanDocument(row pgx.Row) (Document, error) {
+	var document Document
+	err := row.Scan(&document.Id, &document.ApplicationId, &document.Type, &document.Filename,
+		&document.StorageURL, &document.Checksum, &document.CreatedAt)
+	return document, err
+}
+
+type DocumentRepository struct {
+	db database.DB
+}
+
+func NewDocumentRepository(pool *pgxpool.Pool) *DocumentRepository {
+	return &DocumentRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *DocumentRepository) WithTx(tx pgx.Tx) *DocumentRepository {
+	return &DocumentRepository{tx}
+}
+
+func (r *DocumentRepository) Create(ctx context.Context, document Document) (Document, error) {
+	if err := applicationInTenant(ctx, r.db, document.ApplicationId); err != nil {
+		return Document{}, err
+	}
+	sql := `INSERT INTO application_documents
+		(id, application_id, type, filename, storage_url, checksum, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + documentColumns
+	created, err := scanDocument(r.db.QueryRow(ctx, sql,
+		document.Id,
+		document.ApplicationId,
+		document.Type,
+		document.Filename,
+		document.StorageURL,
+		document.Checksum,
+	))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return Document{}, ErrApplicationNotFound
+	}
+	if err != nil {
+		return Document{}, err
+	}
+	return created, nil
+}
+
+// inTenant is a condition limiting application_documents to the applications of the tenant passed
+// as parameter n
+func inTenant(n int) string {
+	return fmt.Sprintf("application_id IN (SELECT id FROM mortgage_applications WHERE tenant_id = $%d)", n)
+}
+
+// applicationInTenant returns ErrApplicationNotFound unless the application belongs
+// to the tenant ctx acts for
+func applicationInTenant(ctx context.Context, db database.DB, applicationId uuid.UUID) error {
+	var exists bool
+	sql := "SELECT EXISTS (SELECT 1 FROM mortgage_applications WHERE id = $1 AND tenant_id = $2)"
+	if err := db.QueryRow(ctx, sql, applicationId, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrApplicationNotFound
+	}
+	return nil
+}
+
+func (r *DocumentRepository) Read(ctx context.Context, applicationId, id uuid.UUID) (Document, error) {
+	sql := "SELECT " + documentColumns + " FROM application_documents WHERE id = $1 AND application_id = $2 AND " + inTenant(3)
+	document, err := scanDocument(r.db.QueryRow(ctx, sql, id, applicationId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Document{}, ErrNotFound
+	}
+	if err != nil {
+		return Document{}, err
+	}
+	return document, nil
+}
+
+// Delete removes the document metadata. Deleting a missing document is not an error.
+func (r *DocumentRepository) Delete(ctx context.Context, applicationId, id uuid.UUID) error {
+	sql := "DELETE FROM application_documents WHERE id = $1 AND application_id = $2 AND " + inTenant(3)
+	_, err := r.db.Exec(ctx, sql, id, applicationId, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *DocumentRepository) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Document, error) {
+	sql := "SELECT " + documentColumns + ` FROM application_documents
+		WHERE application_id = $1 AND ` + inTenant(2) + `
+		ORDER BY created_at`
+	rows, err := r.db.Query(ctx, sql, applicationId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	documents := []Document{}
+	for rows.Next() {
+		document, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+	return documents, rows.Err()
+}
+
+type DocumentService struct {
+	repo Repository
+}
+
+func NewDocumentService(repo Repository) *DocumentService {
+	return &DocumentService{repo}
+}
+
+func (s *DocumentService) Create(ctx context.Context, document Document) (Document, error) {
+	return s.repo.Create(ctx, document)
+}
+
+func (s *DocumentService) Read(ctx context.Context, applicationId, id uuid.UUID) (Document, error) {
+	return s.repo.Read(ctx, applicationId, id)
+}
+
+func (s *DocumentService) Delete(ctx context.Context, applicationId, id uuid.UUID) error {
+	return s.repo.Delete(ctx, applicationId, id)
+}
+
+func (s *DocumentService) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Document, error) {
+	return s.repo.GetByApplicationId(ctx, applicationId)
+}
@@ -0,0 +1,35 @@
+package documents
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDocument_Validate(t *testing.T) {
+	const checksum = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	tests := []struct {
+		name     string
+		document Document
+		valid    bool
+	}{
+		{"valid", Document{Type: "income", Filename: "t4.pdf", StorageURL: "s3://docs/t4.pdf", Checksum: checksum}, true},
+		{"type case and spacing", Document{Type: " Appraisal ", Filename: "appraisal.pdf", StorageURL: "https://files.example.com/a.pdf", Checksum: checksum}, true},
+		{"unknown type", Document{Type: "selfie", Filename: "me.jpg", StorageURL: "s3://docs/me.jpg", Checksum: checksum}, false},
+		{"missing filename", Document{Type: "income", Filename: " ", StorageURL: "s3://docs/t4.pdf", Checksum: checksum}, false},
+		{"relative storage url", Document{Type: "income", Filename: "t4.pdf", StorageURL: "docs/t4.pdf", Checksum: checksum}, false},
+		{"short checksum", Document{Type: "income", Filename: "t4.pdf", StorageURL: "s3://docs/t4.pdf", Checksum: "9f86d081"}, false},
+		{"non-hex checksum", Document{Type: "income", Filename: "t4.pdf", StorageURL: "s3://docs/t4.pdf", Checksum: "z" + checksum[1:]}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.document.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected document to be valid, got: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("Expected ErrInvalidDocument, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package documents
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewDocumentHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+	document := new(Document)
+	if err := c.Bind(document); err != nil {
+		return err
+	}
+	if err := document.Validate(); err != nil {
+		return httpError(err)
+	}
+
+	document.Id = uuid.New()
+	document.ApplicationId = applicationId
+	created, err := h.service.Create(c.Request().Context(), *document)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := h.service.Read(c.Request().Context(), applicationId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, document)
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), applicationId, id); err != nil {
+		return httpError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetByApplicationId(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+
+	documents, err := h.service.GetByApplicationId(c.Request().Context(), applicationId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, documents)
+}
+
+func parseIDs(c echo.Context) (applicationId, id uuid.UUID, err error) {
+	applicationId, err = parseID(c, "id", "invalid application id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err = parseID(c, "documentId", "invalid document id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return applicationId, id, nil
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrApplicationNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidDocument) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return err
+}
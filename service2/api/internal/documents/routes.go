@@ -0,0 +1,10 @@
+package documents
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/applications/:id/documents", handler.Create)
+	g.GET("/applications/:id/documents", handler.GetByApplicationId)
+	g.GET("/applications/:id/documents/:documentId", handler.Read)
+	g.DELETE("/applications/:id/documents/:documentId", handler.Delete)
+}
@@ -0,0 +1,261 @@
+package fees
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service2/api/internal/database"
+	"service2/api/internal/tenant"
+)
+
+// Fee types charged on an application
+const (
+	TypeApplication = "application"
+	TypeAppraisal   = "appraisal"
+)
+
+// Fee statuses. A fee starts due and is settled by being paid or waived.
+const (
+	StatusDue    = "due"
+	StatusPaid   = "paid"
+	StatusWaived = "waived"
+)
+
+// Fee is a charge on a mortgage application. An application has at most one fee of each type.
+type Fee struct {
+	Id            uuid.UUID       `json:"id"`
+	ApplicationId uuid.UUID       `json:"application_id"`
+	Type          string          `json:"type"`
+	Amount        decimal.Decimal `json:"amount"`
+	Status        string          `json:"status"`
+	PaidAt        *time.Time      `json:"paid_at"`
+	WaivedAt      *time.Time      `json:"waived_at"`
+	WaivedBy      *string         `json:"waived_by"`
+	WaivedReason  *string         `json:"waived_reason"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
+}
+
+// Waiver records who waived a fee and why
+type Waiver struct {
+	WaivedBy string `json:"waived_by"`
+	Reason   string `json:"reason"`
+}
+
+var (
+	// ErrNotFound is returned when the application has no fee with the requested ID
+	ErrNotFound = errors.New("fee not found")
+	// ErrApplicationNotFound is returned when charging a fee on an application that does not exist
+	ErrApplicationNotFound = errors.New("mortgage application not found")
+	// ErrInvalidFee is returned when the fee type or amount is missing or malformed
+	ErrInvalidFee = errors.New("invalid fee")
+	// ErrFeeExists is returned when the application already has a fee of the same type
+	ErrFeeExists = errors.New("application already has a fee of this type")
+	// ErrFeeSettled is returned when paying or waiving a fee that is no longer due
+	ErrFeeSettled = errors.New("fee is already paid or waived")
+)
+
+// Validate normalizes the fee and checks its type and amount
+func (f *Fee) Validate() error {
+	f.Type = strings.ToLower(strings.TrimSpace(f.Type))
+	if f.Type != TypeApplication && f.Type != TypeAppraisal {
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidFee, f.Type)
+	}
+	if !f.Amount.IsPositive() {
+		return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidFee)
+	}
+	return nil
+}
+
+type Repository interface {
+	Create(ctx context.Context, fee Fee) (Fee, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (Fee, error)
+	Pay(ctx context.Context, applicationId, id uuid.UUID) (Fee, error)
+	Waive(ctx context.Context, applicationId, id uuid.UUID, waiver Waiver) (Fee, error)
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Fee, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, fee Fee) (Fee, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (Fee, error)
+	Pay(ctx context.Context, applicationId, id uuid.UUID) (Fee, error)
+	Waive(ctx context.Context, applicationId, id uuid.UUID, waiver Waiver) (Fee, error)
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Fee, error)
+}
+
+const feeColumns = `id, application_id, type, amount, status, paid_at, waived_at, waived_by, waived_reason,
+	created_at, modified_at`
+
+// scanFee scans a row selected with feeColumns
+func scanFee(row pgx.Row) (Fee, error) {
+	var fee Fee
+	err := row.Scan(&fee.Id, &fee.ApplicationId, &fee.Type, &fee.Amount, &fee.Status, &fee.PaidAt,
+		&fee.WaivedAt, &fee.WaivedBy, &fee.WaivedReason, &fee.CreatedAt, &fee.ModifiedAt)
+	return fee, err
+}
+
+type FeeRepository struct {
+	db database.DB
+}
+
+func NewFeeRepository(pool *pgxpool.Pool) *FeeRepository {
+	return &FeeRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *FeeRepository) WithTx(tx pgx.Tx) *FeeRepository {
+	return &FeeRepository{tx}
+}
+
+// Create charges a due fee on the application
+func (r *FeeRepository) Create(ctx context.Context, fee Fee) (Fee, error) {
+	if err := applicationInTenant(ctx, r.db, fee.ApplicationId); err != nil {
+		return Fee{}, err
+	}
+	sql := `INSERT INTO application_fees (id, application_id, type, amount, status, created_at, modified_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING ` + feeColumns
+	created, err := scanFee(r.db.QueryRow(ctx, sql, fee.Id, fee.ApplicationId, fee.Type, fee.Amount, StatusDue))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23503": // foreign_key_violation
+			return Fee{}, ErrApplicationNotFound
+		case "23505": // unique_violation
+			return Fee{}, ErrFeeExists
+		}
+	}
+	if err != nil {
+		return Fee{}, err
+	}
+	return created, nil
+}
+
+func (r *FeeRepository) Read(ctx context.Context, applicationId, id uuid.UUID) (Fee, error) {
+	sql := "SELECT " + feeColumns + " FROM application_fees WHERE id = $1 AND application_id = $2 AND " + inTenant(3)
+	fee, err := scanFee(r.db.QueryRow(ctx, sql, id, applicationId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Fee{}, ErrNotFound
+	}
+	if err != nil {
+		return Fee{}, err
+	}
+	return fee, nil
+}
+
+// Pay marks a due fee as paid
+func (r *FeeRepository) Pay(ctx context.Context, applicationId, id uuid.UUID) (Fee, error) {
+	sql := `UPDATE application_fees SET status = $1, paid_at = NOW(), modified_at = NOW()
+		WHERE id = $2 AND application_id = $3 AND status = $4 AND ` + inTenant(5) + `
+		RETURNING ` + feeColumns
+	return r.settle(ctx, applicationId, id, sql, StatusPaid, id, applicationId, StatusDue, tenant.FromContext(ctx))
+}
+
+// Waive marks a due fee as waived, recording who waived it and why
+func (r *FeeRepository) Waive(ctx context.Context, applicationId, id uuid.UUID, waiver Waiver) (Fee, error) {
+	sql := `UPDATE application_fees
+		SET status = $1, waived_at = NOW(), waived_by = $2, waived_reason = $3, modified_at = NOW()
+		WHERE id = $4 AND application_id = $5 AND status = $6 AND ` + inTenant(7) + `
+		RETURNING ` + feeColumns
+	return r.settle(ctx, applicationId, id, sql, StatusWaived, nullIfEmpty(waiver.WaivedBy), nullIfEmpty(waiver.Reason),
+		id, applicationId, StatusDue, tenant.FromContext(ctx))
+}
+
+// inTenant is a condition limiting application_fees to the applications of the tenant passed
+// as parameter n
+func inTenant(n int) string {
+	return fmt.Sprintf("application_id IN (SELECT id FROM mortgage_applications WHERE tenant_id = $%d)", n)
+}
+
+// applicationInTenant returns ErrApplicationNotFound unless the application belongs
+// to the tenant ctx acts for
+func applicationInTenant(ctx context.Context, db database.DB, applicationId uuid.UUID) error {
+	var exists bool
+	sql := "SELECT EXISTS (SELECT 1 FROM mortgage_applications WHERE id = $1 AND tenant_id = $2)"
+	if err := db.QueryRow(ctx, sql, applicationId, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrApplicationNotFound
+	}
+	return nil
+}
+
+// settle runs an update that only matches a due fee, telling a missing fee apart
+// from one that was already settled
+func (r *FeeRepository) settle(ctx context.Context, applicationId, id uuid.UUID, sql string, args ...any) (Fee, error) {
+	fee, err := scanFee(r.db.QueryRow(ctx, sql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := r.Read(ctx, applicationId, id); err != nil {
+			return Fee{}, err
+		}
+		return Fee{}, ErrFeeSettled
+	}
+	if err != nil {
+		return Fee{}, err
+	}
+	return fee, nil
+}
+
+func (r *FeeRepository) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Fee, error) {
+	sql := "SELECT " + feeColumns + " FROM application_fees WHERE application_id = $1 AND " + inTenant(2) + " ORDER BY created_at"
+	rows, err := r.db.Query(ctx, sql, applicationId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fees := []Fee{}
+	for rows.Next() {
+		fee, err := scanFee(rows)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// nullIfEmpty stores an empty string as SQL NULL
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+type FeeService struct {
+	repo Repository
+}
+
+func NewFeeService(repo Repository) *FeeService {
+	return &FeeService{repo}
+}
+
+func (s *FeeService) Create(ctx context.Context, fee Fee) (Fee, error) {
+	return s.repo.Create(ctx, fee)
+}
+
+func (s *FeeService) Read(ctx context.Context, applicationId, id uuid.UUID) (Fee, error) {
+	return s.repo.Read(ctx, applicationId, id)
+}
+
+func (s *FeeService) Pay(ctx context.Context, applicationId, id uuid.UUID) (Fee, error) {
+	return s.repo.Pay(ctx, applicationId, id)
+}
+
+func (s *FeeService) Waive(ctx context.Context, applicationId, id uuid.UUID, waiver Waiver) (Fee, error) {
+	return s.repo.Waive(ctx, applicationId, id, waiver)
+}
+
+func (s *FeeService) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]Fee, error) {
+	return s.repo.GetByApplicationId(ctx, applicationId)
+}
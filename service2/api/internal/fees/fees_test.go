@@ -0,0 +1,34 @@
+package fees
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFee_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		fee   Fee
+		valid bool
+	}{
+		{"application fee", Fee{Type: "application", Amount: decimal.NewFromInt(250)}, true},
+		{"type case and spacing", Fee{Type: " Appraisal ", Amount: decimal.NewFromInt(450)}, true},
+		{"unknown type", Fee{Type: "courier", Amount: decimal.NewFromInt(20)}, false},
+		{"zero amount", Fee{Type: "application", Amount: decimal.NewFromInt(0)}, false},
+		{"negative amount", Fee{Type: "appraisal", Amount: decimal.NewFromInt(-450)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fee.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected fee to be valid, got: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidFee) {
+				t.Errorf("Expected ErrInvalidFee, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package fees
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewFeeHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+	fee := new(Fee)
+	if err := c.Bind(fee); err != nil {
+		return err
+	}
+	if err := fee.Validate(); err != nil {
+		return httpError(err)
+	}
+
+	fee.Id = uuid.New()
+	fee.ApplicationId = applicationId
+	created, err := h.service.Create(c.Request().Context(), *fee)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	fee, err := h.service.Read(c.Request().Context(), applicationId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fee)
+}
+
+func (h *Handler) Pay(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	fee, err := h.service.Pay(c.Request().Context(), applicationId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fee)
+}
+
+// Waive waives a due fee; the reason is required
+func (h *Handler) Waive(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+	waiver := new(Waiver)
+	if err := c.Bind(waiver); err != nil {
+		return err
+	}
+	waiver.WaivedBy = strings.TrimSpace(waiver.WaivedBy)
+	waiver.Reason = strings.TrimSpace(waiver.Reason)
+	if waiver.Reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "reason is required to waive a fee")
+	}
+
+	fee, err := h.service.Waive(c.Request().Context(), applicationId, id, *waiver)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fee)
+}
+
+func (h *Handler) GetByApplicationId(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+
+	fees, err := h.service.GetByApplicationId(c.Request().Context(), applicationId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fees)
+}
+
+func parseIDs(c echo.Context) (applicationId, id uuid.UUID, err error) {
+	applicationId, err = parseID(c, "id", "invalid application id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err = parseID(c, "feeId", "invalid fee id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return applicationId, id, nil
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrApplicationNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidFee) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrFeeExists) || errors.Is(err, ErrFeeSettled) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
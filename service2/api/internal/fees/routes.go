@@ -0,0 +1,11 @@
+package fees
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/applications/:id/fees", handler.Create)
+	g.GET("/applications/:id/fees", handler.GetByApplicationId)
+	g.GET("/applications/:id/fees/:feeId", handler.Read)
+	g.POST("/applications/:id/fees/:feeId/pay", handler.Pay)
+	g.POST("/applications/:id/fees/:feeId/waive", handler.Waive)
+}
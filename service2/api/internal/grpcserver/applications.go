@@ -0,0 +1,146 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/validation"
+	"service2/api/pkg/pb/applicationsv1"
+)
+
+// maxIdempotencyKeyLength matches the limit on the REST Idempotency-Key header
+const maxIdempotencyKeyLength = 255
+
+// applicationSentinels mirror the mortgages handlers' httpError
+var applicationSentinels = []sentinel{
+	{mortgages.ErrNotFound, codes.NotFound},
+	{mortgages.ErrInvalidTransition, codes.FailedPrecondition},
+	{mortgages.ErrIdempotencyKeyReused, codes.FailedPrecondition},
+	{mortgages.ErrVersionConflict, codes.Aborted},
+}
+
+type ApplicationServer struct {
+	applicationsv1.UnimplementedApplicationServiceServer
+	service   mortgages.Service
+	validator *validation.Validator
+}
+
+func NewApplicationServer(service mortgages.Service) *ApplicationServer {
+	return &ApplicationServer{service: service, validator: validation.New()}
+}
+
+func (s *ApplicationServer) CreateApplication(ctx context.Context, req *applicationsv1.CreateApplicationRequest) (*applicationsv1.Application, error) {
+	customerID, err := parseID("customer_id", req.GetCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	loanAmount, err := parseAmount("loan_amount", req.GetLoanAmount())
+	if err != nil {
+		return nil, err
+	}
+	propertyValue, err := parseAmount("property_value", req.GetPropertyValue())
+	if err != nil {
+		return nil, err
+	}
+	application := mortgages.MortgageApplication{
+		Id:            uuid.New(),
+		CustomerId:    customerID,
+		LoanAmount:    loanAmount,
+		PropertyValue: propertyValue,
+		InterestRate:  req.GetInterestRate(),
+		TermYears:     int(req.GetTermYears()),
+		Status:        mortgages.StatusPending,
+	}
+	if err := s.validator.Validate(application); err != nil {
+		return nil, applicationError(err)
+	}
+
+	if key := strings.TrimSpace(req.GetIdempotencyKey()); key != "" {
+		if len(key) > maxIdempotencyKeyLength {
+			return nil, invalidArgument("invalid idempotency_key", []*errdetails.BadRequest_FieldViolation{
+				{Field: "idempotency_key", Description: "must be at most 255 characters"},
+			})
+		}
+		created, _, err := s.service.CreateIdempotent(ctx, key, application)
+		if err != nil {
+			return nil, applicationError(err)
+		}
+		return toApplication(created), nil
+	}
+
+	created, err := s.service.Create(ctx, application)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	return toApplication(created), nil
+}
+
+func (s *ApplicationServer) GetApplication(ctx context.Context, req *applicationsv1.GetApplicationRequest) (*applicationsv1.Application, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	application, err := s.service.Read(ctx, id)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	return toApplication(application), nil
+}
+
+func (s *ApplicationServer) CancelApplication(ctx context.Context, req *applicationsv1.CancelApplicationRequest) (*applicationsv1.Application, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	decision := mortgages.Decision{
+		DecidedBy: strings.TrimSpace(req.GetDecidedBy()),
+		Reason:    strings.TrimSpace(req.GetReason()),
+		Version:   int(req.GetVersion()),
+	}
+	application, err := s.service.Cancel(ctx, id, decision)
+	if err != nil {
+		return nil, applicationError(err)
+	}
+	return toApplication(application), nil
+}
+
+// applicationError adds the domain rule violations from the service to statusError
+func applicationError(err error) error {
+	var validationErr *mortgages.ValidationError
+	if errors.As(err, &validationErr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Fields))
+		for _, field := range validationErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message})
+		}
+		return invalidArgument(validationErr.Error(), violations)
+	}
+	return statusError(err, applicationSentinels)
+}
+
+func toApplication(application mortgages.MortgageApplication) *applicationsv1.Application {
+	message := &applicationsv1.Application{
+		Id:            application.Id.String(),
+		CustomerId:    application.CustomerId.String(),
+		LoanAmount:    application.LoanAmount.String(),
+		PropertyValue: application.PropertyValue.String(),
+		InterestRate:  application.InterestRate,
+		TermYears:     int32(application.TermYears),
+		Status:        application.Status,
+		DecidedAt:     optionalTimestamp(application.DecidedAt),
+		Version:       int32(application.Version),
+		CreatedAt:     timestamp(application.CreatedAt),
+		ModifiedAt:    timestamp(application.ModifiedAt),
+	}
+	if application.DecidedBy != nil {
+		message.DecidedBy = *application.DecidedBy
+	}
+	if application.Reason != nil {
+		message.Reason = *application.Reason
+	}
+	return message
+}
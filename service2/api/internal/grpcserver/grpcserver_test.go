@@ -0,0 +1,161 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"service2/api/internal/auth"
+	"service2/api/internal/mortgages"
+	"service2/api/pkg/pb/applicationsv1"
+)
+
+// fakeApplications implements the calls the gRPC server makes; the embedded interface
+// panics on anything else
+type fakeApplications struct {
+	mortgages.Service
+	created    []mortgages.MortgageApplication
+	idempotent map[string]mortgages.MortgageApplication
+}
+
+func (f *fakeApplications) Create(ctx context.Context, application mortgages.MortgageApplication) (mortgages.MortgageApplication, error) {
+	application.Version = 1
+	f.created = append(f.created, application)
+	return application, nil
+}
+
+func (f *fakeApplications) CreateIdempotent(ctx context.Context, key string, application mortgages.MortgageApplication) (mortgages.MortgageApplication, bool, error) {
+	if previous, ok := f.idempotent[key]; ok {
+		return previous, false, nil
+	}
+	application.Version = 1
+	f.idempotent[key] = application
+	return application, true, nil
+}
+
+func (f *fakeApplications) Cancel(ctx context.Context, id uuid.UUID, decision mortgages.Decision) (mortgages.MortgageApplication, error) {
+	for _, application := range f.created {
+		if application.Id == id {
+			if decision.Version != 0 && decision.Version != application.Version {
+				return mortgages.MortgageApplication{}, mortgages.ErrVersionConflict
+			}
+			application.Status = mortgages.StatusCancelled
+			application.Reason = &decision.Reason
+			return application, nil
+		}
+	}
+	return mortgages.MortgageApplication{}, mortgages.ErrNotFound
+}
+
+func dial(t *testing.T, service mortgages.Service) applicationsv1.ApplicationServiceClient {
+	t.Helper()
+	server := New(auth.Config{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	applicationsv1.RegisterApplicationServiceServer(server, NewApplicationServer(service))
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return applicationsv1.NewApplicationServiceClient(conn)
+}
+
+func validRequest() *applicationsv1.CreateApplicationRequest {
+	return &applicationsv1.CreateApplicationRequest{
+		CustomerId:    uuid.NewString(),
+		LoanAmount:    "300000",
+		PropertyValue: "400000",
+		InterestRate:  5.5,
+		TermYears:     30,
+	}
+}
+
+func TestApplicationServer_CreateAndCancel(t *testing.T) {
+	client := dial(t, &fakeApplications{})
+	ctx := context.Background()
+
+	created, err := client.CreateApplication(ctx, validRequest())
+	if err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	if created.GetStatus() != mortgages.StatusPending || created.GetVersion() != 1 {
+		t.Errorf("Unexpected application: %v", created)
+	}
+
+	cancelled, err := client.CancelApplication(ctx, &applicationsv1.CancelApplicationRequest{
+		Id: created.GetId(), DecidedBy: "saga", Reason: " saga_compensation ",
+	})
+	if err != nil {
+		t.Fatalf("CancelApplication failed: %v", err)
+	}
+	if cancelled.GetStatus() != mortgages.StatusCancelled || cancelled.GetReason() != "saga_compensation" {
+		t.Errorf("Unexpected cancelled application: %v", cancelled)
+	}
+
+	_, err = client.CancelApplication(ctx, &applicationsv1.CancelApplicationRequest{Id: created.GetId(), Version: 7})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("Expected Aborted on a version conflict, got %v", err)
+	}
+	_, err = client.CancelApplication(ctx, &applicationsv1.CancelApplicationRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+}
+
+func TestApplicationServer_CreateIdempotent(t *testing.T) {
+	client := dial(t, &fakeApplications{idempotent: map[string]mortgages.MortgageApplication{}})
+	request := validRequest()
+	request.IdempotencyKey = "onboarding-1"
+
+	first, err := client.CreateApplication(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CreateApplication failed: %v", err)
+	}
+	replayed, err := client.CreateApplication(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Replayed CreateApplication failed: %v", err)
+	}
+	if replayed.GetId() != first.GetId() {
+		t.Errorf("Expected the replay to return %s, got %s", first.GetId(), replayed.GetId())
+	}
+}
+
+func TestApplicationServer_InvalidArgument(t *testing.T) {
+	client := dial(t, &fakeApplications{})
+
+	tests := []struct {
+		name   string
+		modify func(*applicationsv1.CreateApplicationRequest)
+	}{
+		{"bad customer id", func(r *applicationsv1.CreateApplicationRequest) { r.CustomerId = "nope" }},
+		{"zero amount", func(r *applicationsv1.CreateApplicationRequest) { r.LoanAmount = "0" }},
+		{"malformed amount", func(r *applicationsv1.CreateApplicationRequest) { r.LoanAmount = "300k" }},
+		{"long idempotency key", func(r *applicationsv1.CreateApplicationRequest) { r.IdempotencyKey = strings.Repeat("k", 256) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := validRequest()
+			tt.modify(request)
+			_, err := client.CreateApplication(context.Background(), request)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Package health serves the liveness and readiness probes used by docker-compose,
+// Kubernetes and the saga client to sequence startup
+package health
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Pinger is implemented by the connection pool
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Status is the body of both probes
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+type Handler struct {
+	db      Pinger
+	timeout time.Duration
+}
+
+func NewHealthHandler(db Pinger) Handler {
+	return Handler{db: db, timeout: 2 * time.Second}
+}
+
+// Liveness reports that the process is serving requests; it never touches the database
+func (h *Handler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, Status{Status: "ok"})
+}
+
+// Readiness reports whether the database answers. Migrations run before the server
+// starts listening, so a ready service also has its tables.
+func (h *Handler) Readiness(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.timeout)
+	defer cancel()
+
+	if err := h.db.Ping(ctx); err != nil {
+		return c.JSON(http.StatusServiceUnavailable, Status{
+			Status: "not_ready",
+			Checks: map[string]string{"database": err.Error()},
+		})
+	}
+	return c.JSON(http.StatusOK, Status{Status: "ready", Checks: map[string]string{"database": "ok"}})
+}
@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type pinger struct {
+	err error
+}
+
+func (p pinger) Ping(ctx context.Context) error {
+	return p.err
+}
+
+func serve(t *testing.T, db Pinger, path string) (int, Status) {
+	t.Helper()
+	e := echo.New()
+	Routes(e, NewHealthHandler(db))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var status Status
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Invalid JSON response: %v", err)
+	}
+	return rec.Code, status
+}
+
+func TestLiveness_IgnoresDatabase(t *testing.T) {
+	code, status := serve(t, pinger{err: errors.New("connection refused")}, "/healthz")
+	if code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("Expected 200 ok, got %d %+v", code, status)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	code, status := serve(t, pinger{}, "/readyz")
+	if code != http.StatusOK || status.Checks["database"] != "ok" {
+		t.Errorf("Expected 200 with a healthy database, got %d %+v", code, status)
+	}
+
+	code, status = serve(t, pinger{err: errors.New("connection refused")}, "/readyz")
+	if code != http.StatusServiceUnavailable || status.Status != "not_ready" {
+		t.Errorf("Expected 503 not_ready, got %d %+v", code, status)
+	}
+	if status.Checks["database"] != "connection refused" {
+		t.Errorf("Expected the ping error, got %q", status.Checks["database"])
+	}
+}
@@ -0,0 +1,8 @@
+package health
+
+import "github.com/labstack/echo/v4"
+
+func Routes(e *echo.Echo, handler Handler) {
+	e.GET("/healthz", handler.Liveness)
+	e.GET("/readyz", handler.Readiness)
+}
@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func TestMiddleware_LogsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(middleware.RequestID())
+	e.Use(Middleware(New(&buf)))
+	e.GET("/things/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "thing not found")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/things/42", nil)
+	req.Header.Set(echo.HeaderXRequestID, "saga-run-1")
+	req.Header.Set(HeaderCorrelationID, "saga-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(echo.HeaderXRequestID); got != "saga-run-1" {
+		t.Errorf("Expected the request id to be echoed, got %q", got)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":          "WARN",
+		"msg":            "request",
+		"method":         "GET",
+		"path":           "/things/42",
+		"route":          "/things/:id",
+		"status":         float64(http.StatusNotFound),
+		"request_id":     "saga-run-1",
+		"correlation_id": "saga-1",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("Expected %s=%v, got %v", key, value, entry[key])
+		}
+	}
+	if _, ok := entry["latency"]; !ok {
+		t.Error("Expected latency to be logged")
+	}
+}
+
+func TestMiddleware_LogsServerErrors(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	e.Use(Middleware(New(&buf)))
+	e.GET("/boom", func(c echo.Context) error {
+		return errors.New("connection reset")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "ERROR" || entry["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("Expected an ERROR entry with status 500, got %v", entry)
+	}
+	if entry["error"] != "connection reset" {
+		t.Errorf("Expected the handler error, got %v", entry["error"])
+	}
+}
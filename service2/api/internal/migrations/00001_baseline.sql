@@ -0,0 +1,110 @@
+-- Baseline: the schema the service used to create at startup. The statements are
+-- idempotent so databases created that way are adopted unchanged.
+
+-- +goose Up
+-- mortgage application
+CREATE TABLE IF NOT EXISTS mortgage_applications(
+    id uuid PRIMARY KEY,
+    customer_id uuid NOT NULL,
+    loan_amount numeric NOT NULL,
+    property_value numeric NOT NULL,
+    interest_rate numeric NOT NULL,
+    term_years int NOT NULL,
+    status varchar NOT NULL,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+ALTER TABLE mortgage_applications
+    ADD COLUMN IF NOT EXISTS decided_by varchar,
+    ADD COLUMN IF NOT EXISTS decided_at timestamp,
+    ADD COLUMN IF NOT EXISTS reason varchar,
+    ADD COLUMN IF NOT EXISTS version int NOT NULL DEFAULT 1;
+
+-- status history
+CREATE TABLE IF NOT EXISTS application_status_history(
+    id uuid PRIMARY KEY,
+    application_id uuid NOT NULL,
+    from_status varchar,
+    to_status varchar NOT NULL,
+    changed_by varchar,
+    reason varchar,
+    changed_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS application_status_history_application_idx
+    ON application_status_history (application_id, changed_at);
+
+-- idempotency keys
+CREATE TABLE IF NOT EXISTS application_idempotency_keys(
+    key varchar PRIMARY KEY,
+    application_id uuid NOT NULL REFERENCES mortgage_applications (id) ON DELETE CASCADE,
+    request_hash varchar NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+-- application documents
+CREATE TABLE IF NOT EXISTS application_documents(
+    id uuid PRIMARY KEY,
+    application_id uuid NOT NULL REFERENCES mortgage_applications (id) ON DELETE CASCADE,
+    type varchar NOT NULL,
+    filename varchar NOT NULL,
+    storage_url varchar NOT NULL,
+    checksum varchar NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS application_documents_application_idx ON application_documents (application_id);
+
+-- application fees
+CREATE TABLE IF NOT EXISTS application_fees(
+    id uuid PRIMARY KEY,
+    application_id uuid NOT NULL REFERENCES mortgage_applications (id) ON DELETE CASCADE,
+    type varchar NOT NULL,
+    amount numeric NOT NULL,
+    status varchar NOT NULL,
+    paid_at timestamp,
+    waived_at timestamp,
+    waived_by varchar,
+    waived_reason varchar,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL,
+    UNIQUE (application_id, type)
+);
+
+-- rate locks
+CREATE TABLE IF NOT EXISTS rate_locks(
+    id uuid PRIMARY KEY,
+    application_id uuid NOT NULL REFERENCES mortgage_applications (id) ON DELETE CASCADE,
+    rate numeric NOT NULL,
+    status varchar NOT NULL,
+    locked_at timestamp NOT NULL,
+    expires_at timestamp NOT NULL,
+    used_at timestamp
+);
+
+-- At most one active lock per application
+CREATE UNIQUE INDEX IF NOT EXISTS rate_locks_active_idx
+    ON rate_locks (application_id) WHERE status = 'active';
+
+-- outbox
+CREATE TABLE IF NOT EXISTS outbox(
+    id uuid PRIMARY KEY,
+    aggregate_type varchar NOT NULL,
+    aggregate_id uuid NOT NULL,
+    event_type varchar NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp NOT NULL,
+    published_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS rate_locks;
+DROP TABLE IF EXISTS application_fees;
+DROP TABLE IF EXISTS application_documents;
+DROP TABLE IF EXISTS application_idempotency_keys;
+DROP TABLE IF EXISTS application_status_history;
+DROP TABLE IF EXISTS mortgage_applications;
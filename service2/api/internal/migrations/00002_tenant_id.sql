@@ -0,0 +1,14 @@
+-- Tenancy: every application belongs to a tenant (a lender). Rows created before
+-- tenancy belong to the default tenant. The status history records the tenant too, so
+-- a deleted application's history stays scoped to it.
+
+-- +goose Up
+ALTER TABLE mortgage_applications ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+CREATE INDEX mortgage_applications_tenant_idx ON mortgage_applications (tenant_id, created_at, id);
+
+ALTER TABLE application_status_history ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+-- +goose Down
+ALTER TABLE application_status_history DROP COLUMN tenant_id;
+DROP INDEX mortgage_applications_tenant_idx;
+ALTER TABLE mortgage_applications DROP COLUMN tenant_id;
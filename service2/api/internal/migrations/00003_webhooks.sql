@@ -0,0 +1,44 @@
+-- Webhooks: tenants subscribe URLs to the service's events. Outbox events record the
+-- tenant they were raised for so they only reach that tenant's subscriptions. The
+-- relay turns each event into a delivery per matching subscription, and the
+-- dispatcher posts deliveries until they succeed or run out of attempts.
+
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+
+CREATE TABLE webhook_subscriptions(
+    id uuid PRIMARY KEY,
+    tenant_id varchar NOT NULL,
+    url varchar NOT NULL,
+    secret varchar NOT NULL,
+    event_types varchar[] NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+CREATE INDEX webhook_subscriptions_tenant_idx ON webhook_subscriptions (tenant_id, created_at, id);
+
+CREATE TABLE webhook_deliveries(
+    id uuid PRIMARY KEY,
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type varchar NOT NULL,
+    payload jsonb NOT NULL,
+    status varchar NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    next_attempt_at timestamp NOT NULL,
+    last_status_code int,
+    last_error varchar,
+    delivered_at timestamp,
+    created_at timestamp NOT NULL,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_idx ON webhook_deliveries (subscription_id, created_at, id);
+
+-- +goose Down
+DROP TABLE webhook_deliveries;
+DROP TABLE webhook_subscriptions;
+ALTER TABLE outbox DROP COLUMN tenant_id;
@@ -0,0 +1,19 @@
+-- Idempotency keys are scoped to the tenant that sent them, so tenants cannot collide
+-- on, or learn about, each other's keys. A key is claimed before its application is
+-- inserted, so the application reference is only checked at commit.
+
+-- +goose Up
+ALTER TABLE application_idempotency_keys ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+UPDATE application_idempotency_keys k SET tenant_id = a.tenant_id
+    FROM mortgage_applications a WHERE a.id = k.application_id;
+ALTER TABLE application_idempotency_keys
+    DROP CONSTRAINT application_idempotency_keys_pkey,
+    ADD PRIMARY KEY (tenant_id, key),
+    ALTER CONSTRAINT application_idempotency_keys_application_id_fkey DEFERRABLE INITIALLY DEFERRED;
+
+-- +goose Down
+ALTER TABLE application_idempotency_keys
+    ALTER CONSTRAINT application_idempotency_keys_application_id_fkey NOT DEFERRABLE,
+    DROP CONSTRAINT application_idempotency_keys_pkey,
+    ADD PRIMARY KEY (key);
+ALTER TABLE application_idempotency_keys DROP COLUMN tenant_id;
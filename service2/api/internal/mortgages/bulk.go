@@ -0,0 +1,92 @@
+package mortgages
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"service2/api/internal/outbox"
+)
+
+// MaxBulkApplications is how many applications one bulk request may create
+const MaxBulkApplications = 100
+
+// ErrInvalidBulk is returned for a bulk request with no applications or more than MaxBulkApplications
+var ErrInvalidBulk = fmt.Errorf("a bulk request must hold between 1 and %d applications", MaxBulkApplications)
+
+// BulkError is returned when applications of a bulk request were rejected. None of
+// the applications was created. Errors holds each application's error, in request
+// order, and is nil for the applications that would have been created.
+type BulkError struct {
+	Errors []error
+}
+
+func (e *BulkError) Error() string {
+	rejected := 0
+	for _, err := range e.Errors {
+		if err != nil {
+			rejected++
+		}
+	}
+	return fmt.Sprintf("%d of %d applications rejected", rejected, len(e.Errors))
+}
+
+// bulkError returns a *BulkError for errs, or nil when no application failed
+func bulkError(errs []error) error {
+	if slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		return &BulkError{Errors: errs}
+	}
+	return nil
+}
+
+// CreateBulk creates the applications in one transaction, each with its history entry
+// and ApplicationCreated event. The inserts are sent as one batch and the events as
+// another, so the number of applications does not add round trips.
+func (m *MortgageRepository) CreateBulk(ctx context.Context, applications []MortgageApplication) ([]MortgageApplication, error) {
+	created := make([]MortgageApplication, len(applications))
+	err := m.withTx(ctx, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for i, application := range applications {
+			batch.Queue(insertSQL, insertArgs(ctx, application)...).QueryRow(func(row pgx.Row) error {
+				var err error
+				created[i], err = scanApplication(row)
+				return err
+			})
+			batch.Queue(statusChangeSQL, statusChangeArgs(ctx, application.Id, nil, application.Status, Decision{})...)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+
+		events := &pgx.Batch{}
+		for _, application := range created {
+			event, err := outbox.NewEvent(AggregateType, application.Id, EventApplicationCreated, application)
+			if err != nil {
+				return err
+			}
+			outbox.Queue(ctx, events, event)
+		}
+		return tx.SendBatch(ctx, events).Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// CreateBulk validates every application as Create would and creates them together.
+// Invalid applications are reported before anything is written.
+func (m *MortgageService) CreateBulk(ctx context.Context, applications []MortgageApplication) ([]MortgageApplication, error) {
+	if len(applications) == 0 || len(applications) > MaxBulkApplications {
+		return nil, ErrInvalidBulk
+	}
+	errs := make([]error, len(applications))
+	for i, application := range applications {
+		errs[i] = m.validateNew(application)
+	}
+	if err := bulkError(errs); err != nil {
+		return nil, err
+	}
+	return m.repo.CreateBulk(ctx, applications)
+}
@@ -0,0 +1,122 @@
+package mortgages
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service2/api/internal/tenant"
+)
+
+// Expiry decisions are recorded with this decider and reason
+const (
+	ExpiryDecidedBy   = "pending-expiry"
+	ExpiryReasonStale = "pending_timeout"
+)
+
+// DefaultPendingTTL is how long an application may stay pending before it is expired
+const DefaultPendingTTL = 30 * 24 * time.Hour
+
+// Expirer periodically expires applications that have been pending longer than
+// their time to live, e.g. because the saga that created them was abandoned.
+// Each expiry goes through Transition, so it is recorded in the status history and
+// the outbox like any other decision, acting as the tenant the application belongs to.
+type Expirer struct {
+	pool      *pgxpool.Pool
+	repo      *MortgageRepository
+	ttl       time.Duration
+	interval  time.Duration
+	batchSize int
+	logger    *log.Logger
+}
+
+// NewExpirer creates an expirer.
+func NewExpirer(pool *pgxpool.Pool, ttl time.Duration, logger *log.Logger) *Expirer {
+	return &Expirer{
+		pool:      pool,
+		repo:      NewMortgageRepository(pool),
+		ttl:       ttl,
+		interval:  time.Hour,
+		batchSize: 100,
+		logger:    logger,
+	}
+}
+
+// Run expires stale applications every interval until ctx is cancelled
+func (e *Expirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if expired, err := e.ExpireStale(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Printf("application expirer: %v", err)
+		} else if expired > 0 {
+			e.logger.Printf("application expirer: expired %d applications", expired)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireStale expires applications that were created more than ttl ago and are still
+// pending, one batch at a time, and returns how many it expired. Applications decided
+// concurrently are skipped.
+func (e *Expirer) ExpireStale(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		stale, err := e.stale(ctx)
+		if err != nil {
+			return expired, err
+		}
+		batchExpired := 0
+		for _, application := range stale {
+			_, err := e.repo.Transition(tenant.WithTenant(ctx, application.tenantId), application.id, StatusExpired, Decision{DecidedBy: ExpiryDecidedBy, Reason: ExpiryReasonStale})
+			if errors.Is(err, ErrInvalidTransition) || errors.Is(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return expired, err
+			}
+			expired++
+			batchExpired++
+		}
+		if len(stale) < e.batchSize || batchExpired == 0 {
+			return expired, nil
+		}
+	}
+}
+
+// staleApplication is a pending application past its time to live
+type staleApplication struct {
+	id       uuid.UUID
+	tenantId string
+}
+
+// stale returns the oldest batch of pending applications created before the cutoff,
+// across all tenants
+func (e *Expirer) stale(ctx context.Context) ([]staleApplication, error) {
+	sql := `SELECT id, tenant_id FROM mortgage_applications
+		WHERE status = $1 AND created_at <= $2
+		ORDER BY created_at, id
+		LIMIT $3`
+	rows, err := e.pool.Query(ctx, sql, StatusPending, time.Now().Add(-e.ttl), e.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stale := []staleApplication{}
+	for rows.Next() {
+		var application staleApplication
+		if err := rows.Scan(&application.id, &application.tenantId); err != nil {
+			return nil, err
+		}
+		stale = append(stale, application)
+	}
+	return stale, rows.Err()
+}
@@ -1,10 +1,26 @@
 package mortgages
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"service2/api/internal/apierror"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry POST /applications without creating duplicates
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses that replay an earlier request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
 )
 
 type Handler struct {
@@ -23,58 +39,167 @@ func (h *Handler) Create(c echo.Context) error {
 
 	application.Id = uuid.New()
 	if application.Status == "" {
-		application.Status = "pending"
+		application.Status = StatusPending
 	}
-	if err := h.service.Create(c.Request().Context(), *application); err != nil {
+	if err := c.Validate(application); err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusCreated, application)
+	if key := strings.TrimSpace(c.Request().Header.Get(IdempotencyKeyHeader)); key != "" {
+		if len(key) > maxIdempotencyKeyLength {
+			return echo.NewHTTPError(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+		}
+		created, isNew, err := h.service.CreateIdempotent(c.Request().Context(), key, *application)
+		if err != nil {
+			return httpError(err)
+		}
+		if !isNew {
+			c.Response().Header().Set(IdempotentReplayedHeader, "true")
+		}
+		setETag(c, created.Version)
+		return c.JSON(http.StatusCreated, created)
+	}
+
+	created, err := h.service.Create(c.Request().Context(), *application)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, created.Version)
+	return c.JSON(http.StatusCreated, created)
+}
+
+// BulkResult reports what happened to one application of a bulk request. Status is
+// the status the application would have been answered with on its own.
+type BulkResult struct {
+	Index       int                  `json:"index"`
+	Status      int                  `json:"status"`
+	Application *MortgageApplication `json:"application,omitempty"`
+	Error       *apierror.Response   `json:"error,omitempty"`
+}
+
+// CreateBulk creates an array of applications atomically. It returns 201 with a
+// result for every application or, when any application is rejected, creates
+// nothing and returns a 422 whose details hold the results.
+func (h *Handler) CreateBulk(c echo.Context) error {
+	var applications []MortgageApplication
+	if err := c.Bind(&applications); err != nil {
+		return err
+	}
+	errs := make([]error, len(applications))
+	for i := range applications {
+		applications[i].Id = uuid.New()
+		if applications[i].Status == "" {
+			applications[i].Status = StatusPending
+		}
+		errs[i] = c.Validate(&applications[i])
+	}
+	err := bulkError(errs)
+	var created []MortgageApplication
+	if err == nil {
+		created, err = h.service.CreateBulk(c.Request().Context(), applications)
+	}
+	if err != nil {
+		return bulkHTTPError(err)
+	}
+
+	results := make([]BulkResult, len(created))
+	for i := range created {
+		results[i] = BulkResult{Index: i, Status: http.StatusCreated, Application: &created[i]}
+	}
+	return c.JSON(http.StatusCreated, results)
+}
+
+// bulkHTTPError reports a rejected bulk request with a result for each application:
+// the error it would have got on its own, or a 424 when only others failed
+func bulkHTTPError(err error) error {
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) {
+		return httpError(err)
+	}
+	results := make([]BulkResult, len(bulkErr.Errors))
+	for i, itemErr := range bulkErr.Errors {
+		results[i] = BulkResult{Index: i, Status: http.StatusFailedDependency, Error: &apierror.Response{
+			Code:    "not_created",
+			Message: "not created because other applications in the request were rejected",
+		}}
+		if itemErr != nil {
+			status, response := apierror.Describe(httpError(itemErr))
+			results[i].Status, results[i].Error = status, &response
+		}
+	}
+	return &apierror.Error{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "bulk_rejected",
+		Message: bulkErr.Error() + "; nothing was created",
+		Details: results,
+		Err:     err,
+	}
 }
 
 func (h *Handler) Read(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c, "id", "invalid application id")
 	if err != nil {
 		return err
 	}
 
 	application, err := h.service.Read(c.Request().Context(), id)
 	if err != nil {
-		return err
+		return httpError(err)
+	}
+	if notModified(c, setETag(c, application.Version)) {
+		return c.NoContent(http.StatusNotModified)
 	}
 	return c.JSON(http.StatusOK, application)
 }
 
 func (h *Handler) Update(c echo.Context) error {
-	id := c.Param("id")
+	id, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
 	application := new(MortgageApplication)
 	if err := c.Bind(application); err != nil {
 		return err
 	}
-	var err error
-	application.Id, err = uuid.Parse(id)
-	if err != nil {
+	application.Id = id
+	if err := c.Validate(application); err != nil {
 		return err
 	}
-	if err := h.service.Update(c.Request().Context(), *application); err != nil {
+
+	version, ok, err := ifMatchVersion(c)
+	if err != nil {
 		return err
 	}
-	return c.JSON(http.StatusOK, application)
+	if ok {
+		application.Version = version
+	}
+	if application.Version == 0 {
+		return echo.NewHTTPError(http.StatusPreconditionRequired, "If-Match header or version is required")
+	}
+
+	updated, err := h.service.Update(c.Request().Context(), *application)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, updated.Version)
+	return c.JSON(http.StatusOK, updated)
 }
 
+// Delete hard-deletes an application and its documents. It is meant for administrative
+// cleanup; rolling back an application should use Cancel so the attempt stays on record.
 func (h *Handler) Delete(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c, "id", "invalid application id")
 	if err != nil {
 		return err
 	}
 	if err := h.service.Delete(c.Request().Context(), id); err != nil {
-		return err
+		return httpError(err)
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
 func (h *Handler) GetByCustomerId(c echo.Context) error {
-	customerId, err := uuid.Parse(c.Param("customerId"))
+	customerId, err := parseID(c, "customerId", "invalid customer id")
 	if err != nil {
 		return err
 	}
@@ -85,3 +210,184 @@ func (h *Handler) GetByCustomerId(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, applications)
 }
+
+func (h *Handler) List(c echo.Context) error {
+	var filter ApplicationFilter
+	err := echo.QueryParamsBinder(c).
+		String("status", &filter.Status).
+		CustomFunc("created_from", timeParam(&filter.CreatedFrom)).
+		CustomFunc("created_to", timeParam(&filter.CreatedTo)).
+		CustomFunc("min_amount", amountParam(&filter.MinAmount)).
+		CustomFunc("max_amount", amountParam(&filter.MaxAmount)).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
+	if err != nil {
+		return err
+	}
+
+	applications, err := h.service.List(c.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, applications)
+}
+
+// timeParam binds a query parameter given as an RFC 3339 timestamp or a YYYY-MM-DD date
+func timeParam(dest *time.Time) func(values []string) []error {
+	return func(values []string) []error {
+		value := values[0]
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, value)
+		}
+		if err != nil {
+			return []error{echo.NewHTTPError(http.StatusBadRequest, "expected an RFC 3339 timestamp or YYYY-MM-DD date: "+value)}
+		}
+		*dest = t.UTC()
+		return nil
+	}
+}
+
+// amountParam binds a query parameter given as a decimal amount
+func amountParam(dest *decimal.Decimal) func(values []string) []error {
+	return func(values []string) []error {
+		amount, err := decimal.NewFromString(values[0])
+		if err != nil {
+			return []error{echo.NewHTTPError(http.StatusBadRequest, "expected a decimal amount: "+values[0])}
+		}
+		*dest = amount
+		return nil
+	}
+}
+
+// History returns the application's status changes, oldest first
+func (h *Handler) History(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+
+	changes, err := h.service.History(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, changes)
+}
+
+// Approve approves a pending application
+func (h *Handler) Approve(c echo.Context) error {
+	return h.decide(c, h.service.Approve, false)
+}
+
+// Reject rejects a pending application; a reason is required
+func (h *Handler) Reject(c echo.Context) error {
+	return h.decide(c, h.service.Reject, true)
+}
+
+// Withdraw withdraws a pending or approved application on the applicant's behalf
+func (h *Handler) Withdraw(c echo.Context) error {
+	return h.decide(c, h.service.Withdraw, false)
+}
+
+// Cancel cancels a pending or approved application, e.g. as a saga compensation
+func (h *Handler) Cancel(c echo.Context) error {
+	return h.decide(c, h.service.Cancel, false)
+}
+
+type decisionFunc func(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error)
+
+// decide binds the decision metadata and applies the status transition made by fn
+func (h *Handler) decide(c echo.Context, fn decisionFunc, requireReason bool) error {
+	id, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+	decision := new(Decision)
+	if err := c.Bind(decision); err != nil {
+		return err
+	}
+	version, ok, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+	if ok {
+		decision.Version = version
+	}
+	decision.DecidedBy = strings.TrimSpace(decision.DecidedBy)
+	decision.Reason = strings.TrimSpace(decision.Reason)
+	if requireReason && decision.Reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "reason is required to reject an application")
+	}
+
+	application, err := fn(c.Request().Context(), id, *decision)
+	if err != nil {
+		return httpError(err)
+	}
+	setETag(c, application.Version)
+	return c.JSON(http.StatusOK, application)
+}
+
+// setETag exposes the application version as a weak ETag, which clients send back in
+// If-Match to update and in If-None-Match to poll. It returns the ETag.
+func setETag(c echo.Context, version int) string {
+	etag := "W/" + strconv.Quote(strconv.Itoa(version))
+	c.Response().Header().Set("ETag", etag)
+	return etag
+}
+
+// notModified reports whether If-None-Match names etag or "*", meaning the client's
+// copy is current. ETags are compared weakly, so quoted and W/ forms both match.
+func notModified(c echo.Context, etag string) bool {
+	for _, candidate := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion reads the expected version from the If-Match header, accepting
+// quoted, weak and bare values. ok is false when the header is absent.
+func ifMatchVersion(c echo.Context) (version int, ok bool, err error) {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" {
+		return 0, false, nil
+	}
+	value := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err = strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, false, echo.NewHTTPError(http.StatusBadRequest, "If-Match must be an application version")
+	}
+	return version, true, nil
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest(message, err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return apierror.Validation(validationErr.Fields, err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidBulk) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidTransition) ||
+		errors.Is(err, ErrIdempotencyKeyReused) ||
+		errors.Is(err, ErrVersionConflict) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package mortgages
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"service2/api/internal/tenant"
+)
+
+// StatusChange records one move of an application between statuses. FromStatus is
+// nil for the status the application was created with.
+type StatusChange struct {
+	Id            uuid.UUID `json:"id"`
+	ApplicationId uuid.UUID `json:"application_id"`
+	FromStatus    *string   `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	ChangedBy     *string   `json:"changed_by"`
+	Reason        *string   `json:"reason"`
+	ChangedAt     time.Time `json:"changed_at"`
+}
+
+// recordStatusChange writes a status history entry in the caller's transaction, under
+// the tenant ctx acts for
+func recordStatusChange(ctx context.Context, tx pgx.Tx, id uuid.UUID, from *string, to string, decision Decision) error {
+	_, err := tx.Exec(ctx, statusChangeSQL, statusChangeArgs(ctx, id, from, to, decision)...)
+	return err
+}
+
+const statusChangeSQL = `INSERT INTO application_status_history
+	(id, tenant_id, application_id, from_status, to_status, changed_by, reason, changed_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+
+// statusChangeArgs returns the arguments of statusChangeSQL for a change in the tenant of ctx
+func statusChangeArgs(ctx context.Context, id uuid.UUID, from *string, to string, decision Decision) []any {
+	return []any{uuid.New(), tenant.FromContext(ctx), id, from, to,
+		nullIfZero(decision.DecidedBy), nullIfZero(decision.Reason)}
+}
+
+// History returns the application's status changes, oldest first. It is kept after
+// the application is deleted.
+func (m *MortgageRepository) History(ctx context.Context, id uuid.UUID) ([]StatusChange, error) {
+	sql := `SELECT id, application_id, from_status, to_status, changed_by, reason, changed_at
+		FROM application_status_history WHERE application_id = $1 AND tenant_id = $2
+		ORDER BY changed_at, id`
+	rows, err := m.db.Query(ctx, sql, id, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []StatusChange{}
+	for rows.Next() {
+		var change StatusChange
+		err := rows.Scan(&change.Id, &change.ApplicationId, &change.FromStatus, &change.ToStatus,
+			&change.ChangedBy, &change.Reason, &change.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
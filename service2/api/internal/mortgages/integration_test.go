@@ -0,0 +1,199 @@
+//go:build integration
+
+package mortgages_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service2/api/internal/apierror"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/ratelocks"
+	"service2/api/internal/tenant"
+	"service2/api/internal/testdb"
+	"service2/api/internal/validation"
+	"service2/api/internal/versioning"
+)
+
+func TestMain(m *testing.M) {
+	testdb.Main(m)
+}
+
+// client calls the applications and rate locks APIs served over httptest on a database of its own,
+// wired the way main.go wires it
+type client struct {
+	t      *testing.T
+	url    string
+	header http.Header
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	pool := testdb.New(t)
+	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.Pre(versioning.Middleware())
+	e.Use(tenant.Middleware())
+	service := mortgages.NewMortgageService(mortgages.NewMortgageRepository(pool))
+	v1 := e.Group(versioning.Prefix("v1"))
+	mortgages.Routes(v1, mortgages.NewMortgageHandler(service))
+	ratelocks.Routes(v1, ratelocks.NewRateLockHandler(ratelocks.NewRateLockService(ratelocks.NewRateLockRepository(pool))))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return &client{t: t, url: server.URL, header: http.Header{}}
+}
+
+// with returns a client that also sends the header key
+func (c *client) with(key, value string) *client {
+	header := c.header.Clone()
+	header.Set(key, value)
+	return &client{t: c.t, url: c.url, header: header}
+}
+
+// do sends body, if not nil, as JSON and decodes a JSON response into out, if not nil
+func (c *client) do(method, path string, body, out any) *http.Response {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header = c.header.Clone()
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			c.t.Fatalf("%s %s: decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+// application is the body of a valid application for a new customer
+func application() map[string]any {
+	return map[string]any{
+		"customer_id":    uuid.New(),
+		"loan_amount":    "350000",
+		"property_value": "500000",
+		"interest_rate":  5.25,
+		"term_years":     30,
+	}
+}
+
+func TestAPI_IdempotentCreate(t *testing.T) {
+	t.Parallel()
+	c := newClient(t).with(mortgages.IdempotencyKeyHeader, "checkout-42")
+	body := application()
+
+	var created mortgages.MortgageApplication
+	if resp := c.do(http.MethodPost, "/v1/applications", body, &created); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	var replayed mortgages.MortgageApplication
+	resp := c.do(http.MethodPost, "/v1/applications", body, &replayed)
+	if resp.StatusCode != http.StatusCreated || replayed.Id != created.Id {
+		t.Fatalf("Expected the retry to replay application %s, got %d %s", created.Id, resp.StatusCode, replayed.Id)
+	}
+	if resp.Header.Get(mortgages.IdempotentReplayedHeader) != "true" {
+		t.Errorf("Expected the retry to be marked as replayed")
+	}
+
+	body["loan_amount"] = "400000"
+	if resp := c.do(http.MethodPost, "/v1/applications", body, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected reusing the key for another application to get 409, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPI_ApplicationDecision(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+
+	var created mortgages.MortgageApplication
+	if resp := c.do(http.MethodPost, "/v1/applications", application(), &created); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	path := "/v1/applications/" + created.Id.String()
+
+	decision := mortgages.Decision{DecidedBy: "underwriter@example.com"}
+	var approved mortgages.MortgageApplication
+	if resp := c.do(http.MethodPost, path+"/approve", decision, &approved); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if approved.Status != mortgages.StatusApproved {
+		t.Errorf("Expected approved, got %s", approved.Status)
+	}
+	if resp := c.do(http.MethodPost, path+"/reject", mortgages.Decision{Reason: "too late"}, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected rejecting an approved application to get 409, got %d", resp.StatusCode)
+	}
+
+	var history []mortgages.StatusChange
+	if resp := c.do(http.MethodGet, path+"/history", nil, &history); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if len(history) != 2 || history[1].ToStatus != mortgages.StatusApproved {
+		t.Errorf("Expected creation and approval in the history, got %+v", history)
+	}
+}
+
+func TestAPI_RateLockOfClosedApplication(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+
+	var created mortgages.MortgageApplication
+	if resp := c.do(http.MethodPost, "/v1/applications", application(), &created); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	path := "/v1/applications/" + created.Id.String()
+	body := map[string]any{"rate": 4.75, "expires_at": time.Now().Add(30 * 24 * time.Hour)}
+	var lock ratelocks.RateLock
+	if resp := c.do(http.MethodPost, path+"/rate-locks", body, &lock); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 locking the rate, got %d", resp.StatusCode)
+	}
+
+	if resp := c.do(http.MethodPost, path+"/cancel", mortgages.Decision{Reason: "customer request"}, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200 cancelling, got %d", resp.StatusCode)
+	}
+	if resp := c.do(http.MethodPost, path+"/rate-locks/"+lock.Id.String()+"/use", nil, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected using the lock of a cancelled application to get 409, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPI_TenantsAreIsolated(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	lenderA, lenderB := c.with(tenant.Header, "lender-a"), c.with(tenant.Header, "lender-b")
+
+	var created mortgages.MortgageApplication
+	if resp := lenderA.do(http.MethodPost, "/v1/applications", application(), &created); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	if resp := lenderB.do(http.MethodGet, "/v1/applications/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected another tenant's application to be 404, got %d", resp.StatusCode)
+	}
+	if resp := lenderB.do(http.MethodDelete, "/v1/applications/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected deleting another tenant's application to be 404, got %d", resp.StatusCode)
+	}
+	if resp := lenderA.do(http.MethodGet, "/v1/applications/"+created.Id.String(), nil, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected another tenant's delete to leave the application, got %d", resp.StatusCode)
+	}
+}
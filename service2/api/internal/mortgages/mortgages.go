@@ -2,123 +2,417 @@ package mortgages
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service2/api/internal/database"
+	"service2/api/internal/fees"
+	"service2/api/internal/outbox"
+	"service2/api/internal/pagination"
+	"service2/api/internal/tenant"
 )
 
 type MortgageApplication struct {
-	Id            uuid.UUID `json:"id"`
-	CustomerId    uuid.UUID `json:"customer_id"`
-	LoanAmount    float64   `json:"loan_amount"`
-	PropertyValue float64   `json:"property_value"`
-	InterestRate  float64   `json:"interest_rate"`
-	TermYears     int       `json:"term_years"`
-	Status        string    `json:"status"` // pending, approved, rejected
-	CreatedAt     time.Time `json:"created_at"`
-	ModifiedAt    time.Time `json:"modified_at"`
+	Id uuid.UUID `json:"id"`
+	// TenantId is the lender the application belongs to, taken from the creating request
+	TenantId      string          `json:"tenant_id"`
+	CustomerId    uuid.UUID       `json:"customer_id" validate:"required"`
+	LoanAmount    decimal.Decimal `json:"loan_amount" validate:"gt=0"`
+	PropertyValue decimal.Decimal `json:"property_value" validate:"gt=0"`
+	InterestRate  float64         `json:"interest_rate" validate:"gt=0"`
+	TermYears     int             `json:"term_years" validate:"gt=0"`
+	Status        string          `json:"status" validate:"omitempty,oneof=pending approved rejected withdrawn cancelled expired"`
+	DecidedBy     *string         `json:"decided_by"`
+	DecidedAt     *time.Time      `json:"decided_at"`
+	Reason        *string         `json:"reason"`
+	Version       int             `json:"version"`
+	Fees          *FeeTotals      `json:"fees,omitempty"` // set when reading a single application
+	CreatedAt     time.Time       `json:"created_at"`
+	ModifiedAt    time.Time       `json:"modified_at"`
 }
 
+// FeeTotals sums the fees charged on an application. Outstanding is what is still
+// due; an application with nothing outstanding has settled its fees.
+type FeeTotals struct {
+	Total       decimal.Decimal `json:"total"`
+	Paid        decimal.Decimal `json:"paid"`
+	Waived      decimal.Decimal `json:"waived"`
+	Outstanding decimal.Decimal `json:"outstanding"`
+}
+
+// Settled reports whether every fee on the application has been paid or waived
+func (t FeeTotals) Settled() bool {
+	return t.Outstanding.IsZero()
+}
+
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusWithdrawn = "withdrawn"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+// CancelReasonSagaCompensation is the reason recorded when a saga rolls back the
+// onboarding attempt that created the application
+const CancelReasonSagaCompensation = "saga_compensation"
+
+// transitions lists the statuses each decision can be made from. Decisions are final
+// except that an approved application can still be withdrawn by the applicant.
+var transitions = map[string][]string{
+	StatusApproved:  {StatusPending},
+	StatusRejected:  {StatusPending},
+	StatusWithdrawn: {StatusPending, StatusApproved},
+	StatusCancelled: {StatusPending, StatusApproved},
+	StatusExpired:   {StatusPending},
+}
+
+// CanTransition reports whether an application in status from can move to status to
+func CanTransition(from, to string) bool {
+	for _, allowed := range transitions[to] {
+		if allowed == from {
+			return true
+		}
+	}
+	return false
+}
+
+// Domain events written to the outbox alongside application changes
+const (
+	AggregateType             = "mortgage_application"
+	EventApplicationCreated   = "ApplicationCreated"
+	EventApplicationUpdated   = "ApplicationUpdated"
+	EventApplicationApproved  = "ApplicationApproved"
+	EventApplicationRejected  = "ApplicationRejected"
+	EventApplicationWithdrawn = "ApplicationWithdrawn"
+	EventApplicationCancelled = "ApplicationCancelled"
+	EventApplicationExpired   = "ApplicationExpired"
+)
+
+// Events lists the event types above; webhooks can be subscribed to them
+var Events = []string{EventApplicationCreated, EventApplicationUpdated, EventApplicationApproved, EventApplicationRejected,
+	EventApplicationWithdrawn, EventApplicationCancelled, EventApplicationExpired}
+
+// statusEvents names the event recorded when an application moves to each status
+var statusEvents = map[string]string{
+	StatusApproved:  EventApplicationApproved,
+	StatusRejected:  EventApplicationRejected,
+	StatusWithdrawn: EventApplicationWithdrawn,
+	StatusCancelled: EventApplicationCancelled,
+	StatusExpired:   EventApplicationExpired,
+}
+
+// Decision records who moved an application to a new status and why. A non-zero
+// Version makes the transition conditional on the application still being at it.
+type Decision struct {
+	DecidedBy string `json:"decided_by"`
+	Reason    string `json:"reason"`
+	Version   int    `json:"version,omitempty"`
+}
+
+var (
+	// ErrNotFound is returned when no mortgage application exists with the requested ID
+	ErrNotFound = errors.New("mortgage application not found")
+	// ErrInvalidTransition is returned when a decision is not allowed from the application's status
+	ErrInvalidTransition = errors.New("invalid application status transition")
+	// ErrVersionConflict is returned when the application was changed since the version the caller read
+	ErrVersionConflict = errors.New("mortgage application was modified by another request")
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different application
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different application")
+)
+
+// ApplicationFilter narrows and pages an application listing. Zero values match
+// everything; CreatedFrom is inclusive and CreatedTo exclusive.
+type ApplicationFilter struct {
+	Status      string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   decimal.Decimal
+	MaxAmount   decimal.Decimal
+	Limit       int
+	Offset      int
+}
+
+const (
+	DefaultListLimit = pagination.DefaultLimit
+	MaxListLimit     = pagination.MaxLimit
+)
+
 type Repository interface {
-	Create(ctx context.Context, application MortgageApplication) error
+	Create(ctx context.Context, application MortgageApplication) (MortgageApplication, error)
+	CreateBulk(ctx context.Context, applications []MortgageApplication) ([]MortgageApplication, error)
+	CreateIdempotent(ctx context.Context, key string, application MortgageApplication) (MortgageApplication, bool, error)
 	Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error)
-	Update(ctx context.Context, application MortgageApplication) error
+	Update(ctx context.Context, application MortgageApplication) (MortgageApplication, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error)
+	List(ctx context.Context, filter ApplicationFilter) ([]MortgageApplication, error)
+	Transition(ctx context.Context, id uuid.UUID, to string, decision Decision) (MortgageApplication, error)
+	History(ctx context.Context, id uuid.UUID) ([]StatusChange, error)
 }
 
 type Service interface {
-	Create(ctx context.Context, application MortgageApplication) error
+	Create(ctx context.Context, application MortgageApplication) (MortgageApplication, error)
+	CreateBulk(ctx context.Context, applications []MortgageApplication) ([]MortgageApplication, error)
+	CreateIdempotent(ctx context.Context, key string, application MortgageApplication) (MortgageApplication, bool, error)
 	Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error)
-	Update(ctx context.Context, application MortgageApplication) error
+	Update(ctx context.Context, application MortgageApplication) (MortgageApplication, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error)
+	List(ctx context.Context, filter ApplicationFilter) ([]MortgageApplication, error)
+	Approve(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error)
+	Reject(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error)
+	Withdraw(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error)
+	Cancel(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error)
+	History(ctx context.Context, id uuid.UUID) ([]StatusChange, error)
+}
+
+const applicationColumns = `id, tenant_id, customer_id, loan_amount, property_value, interest_rate, term_years, status,
+	decided_by, decided_at, reason, version, created_at, modified_at`
+
+// scanApplication scans a row selected with applicationColumns
+func scanApplication(row pgx.Row) (MortgageApplication, error) {
+	var application MortgageApplication
+	err := row.Scan(
+		&application.Id,
+		&application.TenantId,
+		&application.CustomerId,
+		&application.LoanAmount,
+		&application.PropertyValue,
+		&application.InterestRate,
+		&application.TermYears,
+		&application.Status,
+		&application.DecidedBy,
+		&application.DecidedAt,
+		&application.Reason,
+		&application.Version,
+		&application.CreatedAt,
+		&application.ModifiedAt,
+	)
+	return application, err
 }
 
 type MortgageRepository struct {
-	conn *pgx.Conn
+	db database.DB
+}
+
+func NewMortgageRepository(pool *pgxpool.Pool) *MortgageRepository {
+	return &MortgageRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (m *MortgageRepository) WithTx(tx pgx.Tx) *MortgageRepository {
+	return &MortgageRepository{tx}
+}
+
+// Create inserts the application and returns it as stored, with its tenant, version
+// and timestamps
+func (m *MortgageRepository) Create(ctx context.Context, application MortgageApplication) (MortgageApplication, error) {
+	var created MortgageApplication
+	err := m.withTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		created, err = insertApplication(ctx, tx, application)
+		return err
+	})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return created, nil
 }
 
-func NewMortgageRepository(conn *pgx.Conn) *MortgageRepository {
-	return &MortgageRepository{conn}
+// CreateIdempotent creates the application unless one was already created with the
+// same idempotency key by the same tenant, in which case that application is returned
+// and created is false. The key is claimed in the same transaction as the insert, so concurrent
+// retries wait for the first request and then replay its result.
+func (m *MortgageRepository) CreateIdempotent(ctx context.Context, key string, application MortgageApplication) (MortgageApplication, bool, error) {
+	var result MortgageApplication
+	created := false
+	err := m.withTx(ctx, func(tx pgx.Tx) error {
+		hash := requestHash(application)
+		claim := `INSERT INTO application_idempotency_keys (tenant_id, key, application_id, request_hash, created_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (tenant_id, key) DO NOTHING`
+		tag, err := tx.Exec(ctx, claim, tenant.FromContext(ctx), key, application.Id, hash)
+		if err != nil {
+			return err
+		}
+
+		if tag.RowsAffected() == 0 {
+			var existingId uuid.UUID
+			var existingHash string
+			sql := "SELECT application_id, request_hash FROM application_idempotency_keys WHERE tenant_id = $1 AND key = $2"
+			err := tx.QueryRow(ctx, sql, tenant.FromContext(ctx), key).Scan(&existingId, &existingHash)
+			if err != nil {
+				return err
+			}
+			if existingHash != hash {
+				return ErrIdempotencyKeyReused
+			}
+			sql = "SELECT " + applicationColumns + " FROM mortgage_applications WHERE id = $1 AND tenant_id = $2"
+			result, err = scanApplication(tx.QueryRow(ctx, sql, existingId, tenant.FromContext(ctx)))
+			return err
+		}
+
+		result, err = insertApplication(ctx, tx, application)
+		created = err == nil
+		return err
+	})
+	if err != nil {
+		return MortgageApplication{}, false, err
+	}
+	return result, created, nil
 }
 
-func (m *MortgageRepository) Create(ctx context.Context, application MortgageApplication) error {
-	sql := `INSERT INTO mortgage_applications
-		(id, customer_id, loan_amount, property_value, interest_rate, term_years, status, created_at, modified_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())`
+const insertSQL = `INSERT INTO mortgage_applications
+	(id, tenant_id, customer_id, loan_amount, property_value, interest_rate, term_years, status, created_at, modified_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+	RETURNING ` + applicationColumns
 
-	_, err := m.conn.Exec(ctx, sql,
+// insertArgs returns the arguments of insertSQL for application in the tenant of ctx
+func insertArgs(ctx context.Context, application MortgageApplication) []any {
+	return []any{
 		application.Id,
+		tenant.FromContext(ctx),
 		application.CustomerId,
 		application.LoanAmount,
 		application.PropertyValue,
 		application.InterestRate,
 		application.TermYears,
 		application.Status,
-	)
-	if err != nil {
-		return err
 	}
-	return nil
 }
 
-func (m *MortgageRepository) Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error) {
-	sql := `SELECT id, customer_id, loan_amount, property_value, interest_rate, term_years, status, created_at, modified_at
-		FROM mortgage_applications WHERE id = $1`
-	row := m.conn.QueryRow(ctx, sql, id)
-	var application MortgageApplication
-	err := row.Scan(
-		&application.Id,
-		&application.CustomerId,
-		&application.LoanAmount,
-		&application.PropertyValue,
-		&application.InterestRate,
-		&application.TermYears,
-		&application.Status,
-		&application.CreatedAt,
-		&application.ModifiedAt,
-	)
+// insertApplication inserts the application and records ApplicationCreated in tx
+func insertApplication(ctx context.Context, tx pgx.Tx, application MortgageApplication) (MortgageApplication, error) {
+	created, err := scanApplication(tx.QueryRow(ctx, insertSQL, insertArgs(ctx, application)...))
 	if err != nil {
 		return MortgageApplication{}, err
 	}
-	return application, nil
+	if err := recordStatusChange(ctx, tx, created.Id, nil, created.Status, Decision{}); err != nil {
+		return MortgageApplication{}, err
+	}
+	if err := recordEvent(ctx, tx, created.Id, EventApplicationCreated, created); err != nil {
+		return MortgageApplication{}, err
+	}
+	return created, nil
 }
 
-func (m *MortgageRepository) Update(ctx context.Context, application MortgageApplication) error {
-	sql := `UPDATE mortgage_applications
-		SET customer_id = $1, loan_amount = $2, property_value = $3, interest_rate = $4,
-			term_years = $5, status = $6, modified_at = NOW()
-		WHERE id = $7`
-	_, err := m.conn.Exec(ctx, sql,
+// requestHash fingerprints the client-supplied fields of an application so a reused
+// idempotency key can be told apart from a retry of the same request
+func requestHash(application MortgageApplication) string {
+	fields := fmt.Sprintf("%s|%v|%v|%v|%d|%s",
 		application.CustomerId,
 		application.LoanAmount,
 		application.PropertyValue,
 		application.InterestRate,
 		application.TermYears,
 		application.Status,
-		application.Id,
 	)
+	sum := sha256.Sum256([]byte(fields))
+	return hex.EncodeToString(sum[:])
+}
+
+// Read returns the application with the totals of its fees
+func (m *MortgageRepository) Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error) {
+	sql := "SELECT " + applicationColumns + " FROM mortgage_applications WHERE id = $1 AND tenant_id = $2"
+	application, err := scanApplication(m.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MortgageApplication{}, ErrNotFound
+	}
 	if err != nil {
-		return err
+		return MortgageApplication{}, err
 	}
-	return nil
+
+	totals, err := m.feeTotals(ctx, id)
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	application.Fees = &totals
+	return application, nil
 }
 
+// feeTotals sums the application's fees by status
+func (m *MortgageRepository) feeTotals(ctx context.Context, id uuid.UUID) (FeeTotals, error) {
+	sql := `SELECT COALESCE(SUM(amount), 0),
+			COALESCE(SUM(amount) FILTER (WHERE status = $2), 0),
+			COALESCE(SUM(amount) FILTER (WHERE status = $3), 0),
+			COALESCE(SUM(amount) FILTER (WHERE status = $4), 0)
+		FROM application_fees WHERE application_id = $1`
+	var totals FeeTotals
+	err := m.db.QueryRow(ctx, sql, id, fees.StatusPaid, fees.StatusWaived, fees.StatusDue).
+		Scan(&totals.Total, &totals.Paid, &totals.Waived, &totals.Outstanding)
+	return totals, err
+}
+
+// Update replaces the application's terms if application.Version is still current and
+// returns it with its new version. ErrVersionConflict is returned for a stale version.
+// The status is kept: a status other than the stored one is ErrInvalidTransition, as
+// status changes go through Transition.
+func (m *MortgageRepository) Update(ctx context.Context, application MortgageApplication) (MortgageApplication, error) {
+	var updated MortgageApplication
+	err := m.withTx(ctx, func(tx pgx.Tx) error {
+		current, err := lockApplication(ctx, tx, application.Id)
+		if err != nil {
+			return err
+		}
+		if current.Version != application.Version {
+			return ErrVersionConflict
+		}
+		if application.Status != "" && application.Status != current.Status {
+			return fmt.Errorf("%w: %s to %s must be approved, rejected, withdrawn or cancelled instead",
+				ErrInvalidTransition, current.Status, application.Status)
+		}
+
+		sql := `UPDATE mortgage_applications
+			SET customer_id = $1, loan_amount = $2, property_value = $3, interest_rate = $4,
+				term_years = $5, modified_at = NOW(), version = version + 1
+			WHERE id = $6
+			RETURNING ` + applicationColumns
+		updated, err = scanApplication(tx.QueryRow(ctx, sql,
+			application.CustomerId,
+			application.LoanAmount,
+			application.PropertyValue,
+			application.InterestRate,
+			application.TermYears,
+			application.Id,
+		))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, updated.Id, EventApplicationUpdated, updated)
+	})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return updated, nil
+}
+
+// Delete removes the application, returning ErrNotFound if it does not exist
 func (m *MortgageRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	sql := "DELETE FROM mortgage_applications WHERE id = $1"
-	_, err := m.conn.Exec(ctx, sql, id)
+	sql := "DELETE FROM mortgage_applications WHERE id = $1 AND tenant_id = $2"
+	tag, err := m.db.Exec(ctx, sql, id, tenant.FromContext(ctx))
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
 
 func (m *MortgageRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error) {
-	sql := `SELECT id, customer_id, loan_amount, property_value, interest_rate, term_years, status, created_at, modified_at
-		FROM mortgage_applications WHERE customer_id = $1 ORDER BY created_at DESC`
-	rows, err := m.conn.Query(ctx, sql, customerId)
+	sql := "SELECT " + applicationColumns + ` FROM mortgage_applications
+		WHERE customer_id = $1 AND tenant_id = $2
+		ORDER BY created_at DESC`
+	rows, err := m.db.Query(ctx, sql, customerId, tenant.FromContext(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -126,18 +420,7 @@ func (m *MortgageRepository) GetByCustomerId(ctx context.Context, customerId uui
 
 	var applications []MortgageApplication
 	for rows.Next() {
-		var app MortgageApplication
-		err := rows.Scan(
-			&app.Id,
-			&app.CustomerId,
-			&app.LoanAmount,
-			&app.PropertyValue,
-			&app.InterestRate,
-			&app.TermYears,
-			&app.Status,
-			&app.CreatedAt,
-			&app.ModifiedAt,
-		)
+		app, err := scanApplication(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -146,23 +429,184 @@ func (m *MortgageRepository) GetByCustomerId(ctx context.Context, customerId uui
 	return applications, nil
 }
 
+func (m *MortgageRepository) List(ctx context.Context, filter ApplicationFilter) ([]MortgageApplication, error) {
+	sql := "SELECT " + applicationColumns + ` FROM mortgage_applications
+		WHERE tenant_id = $8
+			AND ($1 = '' OR status = $1)
+			AND ($2::timestamp IS NULL OR created_at >= $2)
+			AND ($3::timestamp IS NULL OR created_at < $3)
+			AND ($4::numeric IS NULL OR loan_amount >= $4)
+			AND ($5::numeric IS NULL OR loan_amount <= $5)
+		ORDER BY created_at DESC, id
+		LIMIT $6 OFFSET $7`
+	rows, err := m.db.Query(ctx, sql,
+		filter.Status,
+		nullIfZero(filter.CreatedFrom),
+		nullIfZero(filter.CreatedTo),
+		nullIfZeroAmount(filter.MinAmount),
+		nullIfZeroAmount(filter.MaxAmount),
+		filter.Limit,
+		filter.Offset,
+		tenant.FromContext(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applications := []MortgageApplication{}
+	for rows.Next() {
+		application, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	return applications, rows.Err()
+}
+
+// Transition moves the application to status to and records the decision, returning
+// ErrInvalidTransition if the application's current status does not allow it.
+// Cancelling an already cancelled application is a no-op.
+func (m *MortgageRepository) Transition(ctx context.Context, id uuid.UUID, to string, decision Decision) (MortgageApplication, error) {
+	var application MortgageApplication
+	err := m.withTx(ctx, func(tx pgx.Tx) error {
+		current, err := lockApplication(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		// Cancelling is a compensation, so repeating it returns the cancelled application
+		if current.Status == StatusCancelled && to == StatusCancelled {
+			application = current
+			return nil
+		}
+		if decision.Version != 0 && decision.Version != current.Version {
+			return ErrVersionConflict
+		}
+		if !CanTransition(current.Status, to) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, current.Status, to)
+		}
+
+		sql := `UPDATE mortgage_applications
+			SET status = $1, decided_by = $2, decided_at = NOW(), reason = $3, modified_at = NOW(), version = version + 1
+			WHERE id = $4
+			RETURNING ` + applicationColumns
+		application, err = scanApplication(tx.QueryRow(ctx, sql, to, nullIfZero(decision.DecidedBy), nullIfZero(decision.Reason), id))
+		if err != nil {
+			return err
+		}
+		if err := recordStatusChange(ctx, tx, id, &current.Status, to, decision); err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, id, statusEvents[to], application)
+	})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return application, nil
+}
+
+// lockApplication reads the application and locks its row until the transaction ends.
+// An application of another tenant is not found, so the statements run after the lock
+// only touch the request's tenant.
+func lockApplication(ctx context.Context, tx pgx.Tx, id uuid.UUID) (MortgageApplication, error) {
+	sql := "SELECT " + applicationColumns + " FROM mortgage_applications WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+	application, err := scanApplication(tx.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return MortgageApplication{}, ErrNotFound
+	}
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return application, nil
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (m *MortgageRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, m.db, fn)
+}
+
+// recordEvent writes an application event to the outbox in the caller's transaction
+func recordEvent(ctx context.Context, tx pgx.Tx, id uuid.UUID, eventType string, payload any) error {
+	event, err := outbox.NewEvent(AggregateType, id, eventType, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Insert(ctx, tx, event)
+}
+
+// nullIfZero passes the zero value of an optional filter or field as SQL NULL
+func nullIfZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// nullIfZeroAmount is nullIfZero for decimals, which compare by value rather than with ==
+func nullIfZeroAmount(amount decimal.Decimal) *decimal.Decimal {
+	if amount.IsZero() {
+		return nil
+	}
+	return &amount
+}
+
 type MortgageService struct {
-	repo Repository
+	repo   Repository
+	bounds Bounds
 }
 
 func NewMortgageService(repo Repository) *MortgageService {
-	return &MortgageService{repo}
+	return &MortgageService{repo: repo, bounds: DefaultBounds}
+}
+
+// WithBounds sets the interest rate and term bounds applications are validated against
+func (m *MortgageService) WithBounds(bounds Bounds) *MortgageService {
+	m.bounds = bounds
+	return m
+}
+
+// validateNew checks a new application against the bounds and requires it to be
+// pending, since only the decision endpoints move an application on
+func (m *MortgageService) validateNew(application MortgageApplication) error {
+	err := m.bounds.Validate(application)
+	if application.Status == StatusPending {
+		return err
+	}
+	validationErr := &ValidationError{}
+	if err != nil && !errors.As(err, &validationErr) {
+		return err
+	}
+	validationErr.Fields = append(validationErr.Fields, FieldError{
+		Field:   "status",
+		Message: "new applications are pending; decide them with approve, reject, withdraw or cancel",
+	})
+	return validationErr
 }
 
-func (m *MortgageService) Create(ctx context.Context, application MortgageApplication) error {
+func (m *MortgageService) Create(ctx context.Context, application MortgageApplication) (MortgageApplication, error) {
+	if err := m.validateNew(application); err != nil {
+		return MortgageApplication{}, err
+	}
 	return m.repo.Create(ctx, application)
 }
 
+func (m *MortgageService) CreateIdempotent(ctx context.Context, key string, application MortgageApplication) (MortgageApplication, bool, error) {
+	if err := m.validateNew(application); err != nil {
+		return MortgageApplication{}, false, err
+	}
+	return m.repo.CreateIdempotent(ctx, key, application)
+}
+
 func (m *MortgageService) Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error) {
 	return m.repo.Read(ctx, id)
 }
 
-func (m *MortgageService) Update(ctx context.Context, application MortgageApplication) error {
+func (m *MortgageService) Update(ctx context.Context, application MortgageApplication) (MortgageApplication, error) {
+	if err := m.bounds.Validate(application); err != nil {
+		return MortgageApplication{}, err
+	}
 	return m.repo.Update(ctx, application)
 }
 
@@ -172,4 +616,34 @@ func (m *MortgageService) Delete(ctx context.Context, id uuid.UUID) error {
 
 func (m *MortgageService) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error) {
 	return m.repo.GetByCustomerId(ctx, customerId)
-}
\ No newline at end of file
+}
+
+func (m *MortgageService) List(ctx context.Context, filter ApplicationFilter) ([]MortgageApplication, error) {
+	filter.Limit = pagination.ClampLimit(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return m.repo.List(ctx, filter)
+}
+
+func (m *MortgageService) Approve(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return m.repo.Transition(ctx, id, StatusApproved, decision)
+}
+
+func (m *MortgageService) Reject(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return m.repo.Transition(ctx, id, StatusRejected, decision)
+}
+
+func (m *MortgageService) Withdraw(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return m.repo.Transition(ctx, id, StatusWithdrawn, decision)
+}
+
+// Cancel rolls back an application that has not been decided against, keeping the row
+// so abandoned onboarding attempts stay visible
+func (m *MortgageService) Cancel(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return m.repo.Transition(ctx, id, StatusCancelled, decision)
+}
+
+func (m *MortgageService) History(ctx context.Context, id uuid.UUID) ([]StatusChange, error) {
+	return m.repo.History(ctx, id)
+}
@@ -1,11 +1,22 @@
 package mortgages
 
-import "github.com/labstack/echo/v4"
+import (
+	"github.com/labstack/echo/v4"
+	"service2/api/internal/auth"
+)
 
-func Routes(e *echo.Echo, handler Handler) {
-	e.POST("/applications", handler.Create)
-	e.GET("/applications/:id", handler.Read)
-	e.PUT("/applications/:id", handler.Update)
-	e.DELETE("/applications/:id", handler.Delete)
-	e.GET("/customers/:customerId/applications", handler.GetByCustomerId)
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/applications", handler.Create)
+	g.POST("/applications/bulk", handler.CreateBulk)
+	g.GET("/applications", handler.List)
+	g.GET("/applications/:id", handler.Read)
+	g.PUT("/applications/:id", handler.Update)
+	// Hard deletes erase the audit trail, so only admins may make them
+	g.DELETE("/applications/:id", handler.Delete, auth.RequireRole(auth.RoleAdmin))
+	g.POST("/applications/:id/approve", handler.Approve)
+	g.POST("/applications/:id/reject", handler.Reject)
+	g.POST("/applications/:id/withdraw", handler.Withdraw)
+	g.POST("/applications/:id/cancel", handler.Cancel)
+	g.GET("/applications/:id/history", handler.History)
+	g.GET("/customers/:customerId/applications", handler.GetByCustomerId)
 }
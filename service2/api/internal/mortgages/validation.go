@@ -0,0 +1,76 @@
+package mortgages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Bounds are the interest rates and terms the lender offers. Applications outside
+// them are rejected before they are stored.
+type Bounds struct {
+	MinInterestRate float64
+	MaxInterestRate float64
+	MinTermYears    int
+	MaxTermYears    int
+}
+
+// DefaultBounds is used unless the service is configured with other bounds
+var DefaultBounds = Bounds{
+	MinInterestRate: 0.01,
+	MaxInterestRate: 25,
+	MinTermYears:    1,
+	MaxTermYears:    40,
+}
+
+// FieldError describes why a single application field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when an application payload breaks a domain rule
+type ValidationError struct {
+	Fields []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validate checks the application's customer, amounts, rate and term, reporting every
+// rule it breaks
+func (b Bounds) Validate(application MortgageApplication) error {
+	var fields []FieldError
+	fail := func(field, format string, args ...any) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if application.CustomerId == uuid.Nil {
+		fail("customer_id", "is required")
+	}
+	if !application.LoanAmount.IsPositive() {
+		fail("loan_amount", "must be greater than 0")
+	}
+	if !application.PropertyValue.IsPositive() {
+		fail("property_value", "must be greater than 0")
+	} else if application.LoanAmount.GreaterThan(application.PropertyValue) {
+		fail("loan_amount", "must not exceed property_value")
+	}
+	if application.InterestRate < b.MinInterestRate || application.InterestRate > b.MaxInterestRate {
+		fail("interest_rate", "must be between %v and %v", b.MinInterestRate, b.MaxInterestRate)
+	}
+	if application.TermYears < b.MinTermYears || application.TermYears > b.MaxTermYears {
+		fail("term_years", "must be between %d and %d", b.MinTermYears, b.MaxTermYears)
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service2/api/internal/documents"
+	"service2/api/internal/fees"
+	"service2/api/internal/health"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/ratelocks"
+	"service2/api/internal/versioning"
+	"service2/api/internal/webhooks"
+)
+
+func TestDocument_Valid(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Errorf("Document is not valid OpenAPI: %v", err)
+	}
+	if len(doc.Security) != 2 {
+		t.Errorf("Expected API key and bearer security, got %v", doc.Security)
+	}
+	if readyz := doc.Paths.Value("/readyz").Get; readyz.Security == nil || len(*readyz.Security) != 0 {
+		t.Error("Expected /readyz to be served without credentials")
+	}
+
+	application := doc.Components.Schemas["MortgageApplication"]
+	if application == nil {
+		t.Fatal("Expected a MortgageApplication component schema")
+	}
+	amount := application.Value.Properties["loan_amount"].Value
+	if amount.Min == nil || *amount.Min != 0 || !amount.ExclusiveMin {
+		t.Errorf("Expected loan_amount to be greater than 0, got %+v", amount)
+	}
+	if !amount.Type.Is("string") || amount.Format != "decimal" {
+		t.Errorf("Expected loan_amount as a decimal string, got %+v", amount)
+	}
+	if status := application.Value.Properties["status"].Value; len(status.Enum) != 6 {
+		t.Errorf("Expected the six statuses as an enum, got %v", status.Enum)
+	}
+}
+
+// TestDocument_CoversRoutes keeps the document in step with the routes: every
+// registered route must be documented, and every documented operation registered
+func TestDocument_CoversRoutes(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+
+	e := echo.New()
+	v1 := e.Group(versioning.Prefix("v1"))
+	mortgages.Routes(v1, mortgages.NewMortgageHandler(nil))
+	documents.Routes(v1, documents.NewDocumentHandler(nil))
+	fees.Routes(v1, fees.NewFeeHandler(nil))
+	ratelocks.Routes(v1, ratelocks.NewRateLockHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+
+	for _, route := range e.Routes() {
+		path, _ := pathParams(route.Path)
+		item := doc.Paths.Value(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s is not documented", route.Method, route.Path)
+		}
+	}
+	if documented := len(operations); documented != len(e.Routes()) {
+		t.Errorf("Expected %d documented operations, got %d", len(e.Routes()), documented)
+	}
+}
+
+// TestDocument_MatchesClient keeps the client in step with the document: the client
+// package is generated from a copy of it, which must be regenerated when it changes
+func TestDocument_MatchesClient(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	generated, err := os.ReadFile("../../pkg/client/internal/oapi/openapi.json")
+	if err != nil {
+		t.Fatalf("Unable to read the client's document: %v", err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(generated, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("The client was generated from a stale document; run go generate ./api/pkg/client/...")
+	}
+}
@@ -0,0 +1,138 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"service2/api/internal/documents"
+	"service2/api/internal/fees"
+	"service2/api/internal/health"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/ratelocks"
+	"service2/api/internal/webhooks"
+)
+
+var operations = []Operation{
+	{ID: "createApplication", Method: http.MethodPost, Path: "/v1/applications", Tag: "applications",
+		Summary: "Create a mortgage application; send an Idempotency-Key header to make retries safe",
+		Request: mortgages.MortgageApplication{}, Status: http.StatusCreated, Response: mortgages.MortgageApplication{}},
+	{ID: "createApplicationsBulk", Method: http.MethodPost, Path: "/v1/applications/bulk", Tag: "applications",
+		Summary: "Create up to 100 applications atomically; 422 with a result per application if any is rejected",
+		Request: []mortgages.MortgageApplication{}, Status: http.StatusCreated, Response: []mortgages.BulkResult{}},
+	{ID: "listApplications", Method: http.MethodGet, Path: "/v1/applications", Tag: "applications",
+		Summary: "List applications, newest first",
+		Query: []Param{
+			{Name: "status", Type: "string", Description: "application status"},
+			{Name: "created_from", Type: "string", Description: "RFC 3339 timestamp or YYYY-MM-DD date"},
+			{Name: "created_to", Type: "string", Description: "RFC 3339 timestamp or YYYY-MM-DD date"},
+			{Name: "min_amount", Type: "string", Format: "decimal", Description: "minimum loan amount"},
+			{Name: "max_amount", Type: "string", Format: "decimal", Description: "maximum loan amount"},
+			{Name: "limit", Type: "integer", Description: "page size"},
+			{Name: "offset", Type: "integer", Description: "number of results to skip"},
+		},
+		Status: http.StatusOK, Response: []mortgages.MortgageApplication{}},
+	{ID: "getApplication", Method: http.MethodGet, Path: "/v1/applications/:id", Tag: "applications",
+		Summary: "Get an application with its fee totals; 304 if If-None-Match names its ETag",
+		Status:  http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "updateApplication", Method: http.MethodPut, Path: "/v1/applications/:id", Tag: "applications",
+		Summary: `Update an application; requires If-Match: "<version>" or version in the body`,
+		Request: mortgages.MortgageApplication{}, Status: http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "deleteApplication", Method: http.MethodDelete, Path: "/v1/applications/:id", Tag: "applications",
+		Summary: "Hard-delete an application; needs the admin role",
+		Status:  http.StatusNoContent},
+	{ID: "approveApplication", Method: http.MethodPost, Path: "/v1/applications/:id/approve", Tag: "applications",
+		Summary: "Approve a pending application",
+		Request: mortgages.Decision{}, Status: http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "rejectApplication", Method: http.MethodPost, Path: "/v1/applications/:id/reject", Tag: "applications",
+		Summary: "Reject a pending application; a reason is required",
+		Request: mortgages.Decision{}, Status: http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "withdrawApplication", Method: http.MethodPost, Path: "/v1/applications/:id/withdraw", Tag: "applications",
+		Summary: "Withdraw a pending or approved application",
+		Request: mortgages.Decision{}, Status: http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "cancelApplication", Method: http.MethodPost, Path: "/v1/applications/:id/cancel", Tag: "applications",
+		Summary: "Cancel a pending or approved application, keeping the row",
+		Request: mortgages.Decision{}, Status: http.StatusOK, Response: mortgages.MortgageApplication{}},
+	{ID: "getApplicationHistory", Method: http.MethodGet, Path: "/v1/applications/:id/history", Tag: "applications",
+		Summary: "List an application's status changes, oldest first",
+		Status:  http.StatusOK, Response: []mortgages.StatusChange{}},
+	{ID: "listCustomerApplications", Method: http.MethodGet, Path: "/v1/customers/:customerId/applications", Tag: "applications",
+		Summary: "List a customer's applications",
+		Status:  http.StatusOK, Response: []mortgages.MortgageApplication{}},
+
+	{ID: "createDocument", Method: http.MethodPost, Path: "/v1/applications/:id/documents", Tag: "documents",
+		Summary: "Register a received document",
+		Request: documents.Document{}, Status: http.StatusCreated, Response: documents.Document{}},
+	{ID: "listDocuments", Method: http.MethodGet, Path: "/v1/applications/:id/documents", Tag: "documents",
+		Summary: "List an application's documents",
+		Status:  http.StatusOK, Response: []documents.Document{}},
+	{ID: "getDocument", Method: http.MethodGet, Path: "/v1/applications/:id/documents/:documentId", Tag: "documents",
+		Summary: "Get a document's metadata",
+		Status:  http.StatusOK, Response: documents.Document{}},
+	{ID: "deleteDocument", Method: http.MethodDelete, Path: "/v1/applications/:id/documents/:documentId", Tag: "documents",
+		Summary: "Remove a document's metadata",
+		Status:  http.StatusNoContent},
+
+	{ID: "createFee", Method: http.MethodPost, Path: "/v1/applications/:id/fees", Tag: "fees",
+		Summary: "Charge a fee",
+		Request: fees.Fee{}, Status: http.StatusCreated, Response: fees.Fee{}},
+	{ID: "listFees", Method: http.MethodGet, Path: "/v1/applications/:id/fees", Tag: "fees",
+		Summary: "List an application's fees",
+		Status:  http.StatusOK, Response: []fees.Fee{}},
+	{ID: "getFee", Method: http.MethodGet, Path: "/v1/applications/:id/fees/:feeId", Tag: "fees",
+		Summary: "Get a fee",
+		Status:  http.StatusOK, Response: fees.Fee{}},
+	{ID: "payFee", Method: http.MethodPost, Path: "/v1/applications/:id/fees/:feeId/pay", Tag: "fees",
+		Summary: "Mark a due fee paid",
+		Status:  http.StatusOK, Response: fees.Fee{}},
+	{ID: "waiveFee", Method: http.MethodPost, Path: "/v1/applications/:id/fees/:feeId/waive", Tag: "fees",
+		Summary: "Waive a due fee; a reason is required",
+		Request: fees.Waiver{}, Status: http.StatusOK, Response: fees.Fee{}},
+
+	{ID: "createRateLock", Method: http.MethodPost, Path: "/v1/applications/:id/rate-locks", Tag: "rate-locks",
+		Summary: "Lock an interest rate for a pending or approved application",
+		Request: ratelocks.RateLock{}, Status: http.StatusCreated, Response: ratelocks.RateLock{}},
+	{ID: "listRateLocks", Method: http.MethodGet, Path: "/v1/applications/:id/rate-locks", Tag: "rate-locks",
+		Summary: "List an application's rate locks, newest first",
+		Status:  http.StatusOK, Response: []ratelocks.RateLock{}},
+	{ID: "getRateLock", Method: http.MethodGet, Path: "/v1/applications/:id/rate-locks/:lockId", Tag: "rate-locks",
+		Summary: "Get a rate lock",
+		Status:  http.StatusOK, Response: ratelocks.RateLock{}},
+	{ID: "useRateLock", Method: http.MethodPost, Path: "/v1/applications/:id/rate-locks/:lockId/use", Tag: "rate-locks",
+		Summary: "Consume a rate lock when funding",
+		Status:  http.StatusOK, Response: ratelocks.RateLock{}},
+
+	{ID: "createWebhook", Method: http.MethodPost, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "Subscribe a URL to events; the response is the only one carrying the signing secret",
+		Request: webhooks.Subscription{}, Status: http.StatusCreated, Response: webhooks.Subscription{}},
+	{ID: "listWebhooks", Method: http.MethodGet, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "List the tenant's webhook subscriptions",
+		Status:  http.StatusOK, Response: []webhooks.Subscription{}},
+	{ID: "getWebhook", Method: http.MethodGet, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Get a webhook subscription",
+		Status:  http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "updateWebhook", Method: http.MethodPut, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Change a subscription's URL or event types, or pause it with active false",
+		Request: webhooks.Subscription{}, Status: http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "deleteWebhook", Method: http.MethodDelete, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Delete a webhook subscription and its delivery log",
+		Status:  http.StatusNoContent},
+	{ID: "listWebhookDeliveries", Method: http.MethodGet, Path: "/v1/webhooks/:id/deliveries", Tag: "webhooks",
+		Summary: "List a subscription's deliveries, newest first",
+		Query: []Param{
+			{Name: "limit", Type: "integer", Description: "page size"},
+			{Name: "offset", Type: "integer", Description: "number of results to skip"},
+		},
+		Status: http.StatusOK, Response: []webhooks.Delivery{}},
+
+	{ID: "getLiveness", Method: http.MethodGet, Path: "/healthz", Tag: "health", Public: true,
+		Summary: "Liveness probe; 200 while the process is serving",
+		Status:  http.StatusOK, Response: health.Status{}},
+	{ID: "getReadiness", Method: http.MethodGet, Path: "/readyz", Tag: "health", Public: true,
+		Summary: "Readiness probe; 503 with the failing check until the database answers",
+		Status:  http.StatusOK, Response: health.Status{}},
+}
+
+// Document returns the mortgage application service's OpenAPI document
+func Document() (*openapi3.T, error) {
+	return Build(openapi3.Info{Title: "Mortgage Application Service", Version: "1.0.0"}, operations)
+}
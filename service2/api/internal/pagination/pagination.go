@@ -0,0 +1,102 @@
+// Package pagination holds the paging rules the list endpoints share, so every
+// listing clamps its limit the same way. Keyset listings hand out opaque cursors:
+// clients pass the last one back as ?cursor= and find the next page's URL in the
+// Link header.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued by Cursor.Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ClampLimit replaces a missing or negative limit with DefaultLimit and caps it at MaxLimit
+func ClampLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return min(limit, MaxLimit)
+}
+
+// Cursor points just past the last row of a page ordered by (Key, Id): Key is the
+// value of the sort column and Id breaks ties between rows sharing it
+type Cursor struct {
+	Key string    `json:"k"`
+	Id  uuid.UUID `json:"i"`
+}
+
+// TimeCursor points past a row sorted by a timestamp such as created_at
+func TimeCursor(t time.Time, id uuid.UUID) Cursor {
+	return Cursor{Key: t.UTC().Format(time.RFC3339Nano), Id: id}
+}
+
+// Time returns the timestamp of a cursor made by TimeCursor
+func (c Cursor) Time() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Key)
+	if err != nil {
+		return time.Time{}, ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// Encode returns the cursor as an opaque, URL-safe token
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a token made by Encode. An empty token asks for the first page and
+// decodes to nil.
+func Decode(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Id == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// Trim cuts rows fetched with a limit of limit+1 back to limit and reports whether
+// there is a page after them
+func Trim[T any](rows []T, limit int) ([]T, bool) {
+	if len(rows) <= limit {
+		return rows, false
+	}
+	return rows[:limit], true
+}
+
+// NextLink returns a Link header value for the page after next: the request's path
+// and query with its cursor parameter replaced
+func NextLink(request *url.URL, next Cursor) string {
+	query := request.Query()
+	query.Set("cursor", next.Encode())
+	link := url.URL{Path: request.Path, RawQuery: query.Encode()}
+	return "<" + link.String() + `>; rel="next"`
+}
+
+// SetNextLink adds the Link header for the next page; a nil next marks the last page
+// and leaves the header out
+func SetNextLink(c echo.Context, next *Cursor) {
+	if next != nil {
+		c.Response().Header().Add("Link", NextLink(c.Request().URL, *next))
+	}
+}
@@ -0,0 +1,118 @@
+// Package ratelimit throttles callers with a token bucket each, keyed by the
+// authenticated subject or, for anonymous requests, the client IP. Callers over their
+// limit get a 429 with a Retry-After header, so a runaway retry loop in one caller
+// cannot starve the others.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+	"service2/api/internal/auth"
+)
+
+// probePaths are never throttled so orchestrators and Prometheus can always check the service
+var probePaths = []string{"/healthz", "/readyz", "/metrics"}
+
+// idleExpiry is how long a caller's bucket is kept after its last request
+const idleExpiry = 3 * time.Minute
+
+type Limiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewLimiter allows each caller rps requests per second on average, and bursts of
+// up to burst requests
+func NewLimiter(rps float64, burst int) *Limiter {
+	return &Limiter{
+		limit:   rate.Limit(rps),
+		burst:   burst,
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it reports how long
+// until the next token is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// cleanup drops idle buckets at most once per idleExpiry
+func (l *Limiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < idleExpiry {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > idleExpiry {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastCleanup = now
+}
+
+// Middleware rejects requests over the caller's limit. It must be registered after
+// auth.Middleware so authenticated callers are limited by subject rather than IP.
+func Middleware(limiter *Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if slices.Contains(probePaths, c.Request().URL.Path) {
+				return next(c)
+			}
+
+			allowed, retryAfter := limiter.Allow(Key(c.Request().Context(), c.RealIP()))
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+				return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded, retry after "+strconv.Itoa(seconds)+"s")
+			}
+			return next(c)
+		}
+	}
+}
+
+// Key identifies the caller of a request: its authenticated subject, or else its IP
+func Key(ctx context.Context, ip string) string {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return "subject:" + principal.Subject
+	}
+	return "ip:" + ip
+}
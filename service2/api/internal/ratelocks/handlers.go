@@ -0,0 +1,116 @@
+package ratelocks
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewRateLockHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+	lock := new(RateLock)
+	if err := c.Bind(lock); err != nil {
+		return err
+	}
+	if err := lock.Validate(time.Now()); err != nil {
+		return httpError(err)
+	}
+
+	lock.Id = uuid.New()
+	lock.ApplicationId = applicationId
+	created, err := h.service.Create(c.Request().Context(), *lock)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	lock, err := h.service.Read(c.Request().Context(), applicationId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, lock)
+}
+
+// Use consumes the lock for funding; expired and already used locks are rejected with 409
+func (h *Handler) Use(c echo.Context) error {
+	applicationId, id, err := parseIDs(c)
+	if err != nil {
+		return err
+	}
+
+	lock, err := h.service.Use(c.Request().Context(), applicationId, id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, lock)
+}
+
+func (h *Handler) GetByApplicationId(c echo.Context) error {
+	applicationId, err := parseID(c, "id", "invalid application id")
+	if err != nil {
+		return err
+	}
+
+	locks, err := h.service.GetByApplicationId(c.Request().Context(), applicationId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, locks)
+}
+
+func parseIDs(c echo.Context) (applicationId, id uuid.UUID, err error) {
+	applicationId, err = parseID(c, "id", "invalid application id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err = parseID(c, "lockId", "invalid rate lock id")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return applicationId, id, nil
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrApplicationNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrInvalidRateLock):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	case errors.Is(err, ErrApplicationClosed),
+		errors.Is(err, ErrActiveLockExists),
+		errors.Is(err, ErrLockNotActive):
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,299 @@
+package ratelocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service2/api/internal/database"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/tenant"
+)
+
+const (
+	StatusActive  = "active"
+	StatusUsed    = "used"
+	StatusExpired = "expired"
+)
+
+// MaxLockPeriod is the longest a rate can be locked for
+const MaxLockPeriod = 180 * 24 * time.Hour
+
+// RateLock guarantees an application's interest rate until ExpiresAt. An application
+// has at most one active lock; funding must use an active, unexpired one.
+type RateLock struct {
+	Id            uuid.UUID  `json:"id"`
+	ApplicationId uuid.UUID  `json:"application_id"`
+	Rate          float64    `json:"rate"`
+	Status        string     `json:"status"` // active, used, expired
+	LockedAt      time.Time  `json:"locked_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	UsedAt        *time.Time `json:"used_at"`
+}
+
+var (
+	// ErrNotFound is returned when the application has no rate lock with the requested ID
+	ErrNotFound = errors.New("rate lock not found")
+	// ErrApplicationNotFound is returned when locking a rate for an application that does not exist
+	ErrApplicationNotFound = errors.New("mortgage application not found")
+	// ErrApplicationClosed is returned when locking a rate for an application that is no longer open
+	ErrApplicationClosed = errors.New("mortgage application is not open")
+	// ErrActiveLockExists is returned when the application already has an unexpired lock
+	ErrActiveLockExists = errors.New("application already has an active rate lock")
+	// ErrLockNotActive is returned when funding with a lock that has expired or was already used
+	ErrLockNotActive = errors.New("rate lock is expired or already used")
+	// ErrInvalidRateLock is returned when the rate or expiry is out of range
+	ErrInvalidRateLock = errors.New("invalid rate lock")
+)
+
+// Validate checks the locked rate and that the expiry is in the future and within MaxLockPeriod
+func (l RateLock) Validate(now time.Time) error {
+	if l.Rate <= 0 || l.Rate > 100 {
+		return fmt.Errorf("%w: rate must be between 0 and 100", ErrInvalidRateLock)
+	}
+	if !l.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidRateLock)
+	}
+	if l.ExpiresAt.Sub(now) > MaxLockPeriod {
+		return fmt.Errorf("%w: rates can be locked for at most %d days", ErrInvalidRateLock, int(MaxLockPeriod.Hours()/24))
+	}
+	return nil
+}
+
+type Repository interface {
+	Create(ctx context.Context, lock RateLock) (RateLock, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error)
+	Use(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error)
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]RateLock, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, lock RateLock) (RateLock, error)
+	Read(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error)
+	Use(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error)
+	GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]RateLock, error)
+}
+
+const lockColumns = "id, application_id, rate, status, locked_at, expires_at, used_at"
+
+// scanLock scans a row selected with lockColumns
+func scanLock(row pgx.Row) (RateLock, error) {
+	var lock RateLock
+	err := row.Scan(&lock.Id, &lock.ApplicationId, &lock.Rate, &lock.Status, &lock.LockedAt, &lock.ExpiresAt, &lock.UsedAt)
+	return lock, err
+}
+
+type RateLockRepository struct {
+	db database.DB
+}
+
+func NewRateLockRepository(pool *pgxpool.Pool) *RateLockRepository {
+	return &RateLockRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *RateLockRepository) WithTx(tx pgx.Tx) *RateLockRepository {
+	return &RateLockRepository{tx}
+}
+
+// Create locks the rate for an open application. A lock that has passed its expiry but
+// not yet been marked expired by the Expirer does not block a new one.
+func (r *RateLockRepository) Create(ctx context.Context, lock RateLock) (RateLock, error) {
+	var created RateLock
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		if err := lockOpenApplication(ctx, tx, lock.ApplicationId); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, expireSQL+" AND application_id = $1", lock.ApplicationId); err != nil {
+			return err
+		}
+		var active bool
+		err := tx.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM rate_locks WHERE application_id = $1 AND status = $2)",
+			lock.ApplicationId, StatusActive).Scan(&active)
+		if err != nil {
+			return err
+		}
+		if active {
+			return ErrActiveLockExists
+		}
+
+		sql := `INSERT INTO rate_locks (id, application_id, rate, status, locked_at, expires_at)
+			VALUES ($1, $2, $3, $4, NOW(), $5)
+			RETURNING ` + lockColumns
+		created, err = scanLock(tx.QueryRow(ctx, sql, lock.Id, lock.ApplicationId, lock.Rate, StatusActive, lock.ExpiresAt))
+		return err
+	})
+	if err != nil {
+		return RateLock{}, err
+	}
+	return created, nil
+}
+
+func (r *RateLockRepository) Read(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error) {
+	sql := "SELECT " + lockColumns + " FROM rate_locks WHERE id = $1 AND application_id = $2 AND " + inTenant(3)
+	lock, err := scanLock(r.db.QueryRow(ctx, sql, id, applicationId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return RateLock{}, ErrNotFound
+	}
+	if err != nil {
+		return RateLock{}, err
+	}
+	return lock, nil
+}
+
+// Use marks the lock as used for funding. It fails with ErrApplicationClosed if the
+// application was cancelled, rejected, withdrawn or expired meanwhile, and with
+// ErrLockNotActive if the lock has expired, even when the Expirer has not caught up
+// with it yet.
+func (r *RateLockRepository) Use(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error) {
+	var lock RateLock
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		if err := lockOpenApplication(ctx, tx, applicationId); err != nil {
+			return err
+		}
+
+		sql := `UPDATE rate_locks SET status = $1, used_at = NOW()
+			WHERE id = $2 AND application_id = $3 AND status = $4 AND expires_at > NOW()
+			RETURNING ` + lockColumns
+		var err error
+		lock, err = scanLock(tx.QueryRow(ctx, sql, StatusUsed, id, applicationId, StatusActive))
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := r.WithTx(tx).Read(ctx, applicationId, id); err != nil {
+				return err
+			}
+			return ErrLockNotActive
+		}
+		return err
+	})
+	if err != nil {
+		return RateLock{}, err
+	}
+	return lock, nil
+}
+
+// lockOpenApplication locks the application row for the rest of the transaction,
+// returning ErrApplicationNotFound if the tenant has no such application and
+// ErrApplicationClosed unless it is pending or approved
+func lockOpenApplication(ctx context.Context, tx pgx.Tx, applicationId uuid.UUID) error {
+	var status string
+	sql := "SELECT status FROM mortgage_applications WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+	err := tx.QueryRow(ctx, sql, applicationId, tenant.FromContext(ctx)).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrApplicationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != mortgages.StatusPending && status != mortgages.StatusApproved {
+		return fmt.Errorf("%w: status is %s", ErrApplicationClosed, status)
+	}
+	return nil
+}
+
+func (r *RateLockRepository) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]RateLock, error) {
+	sql := "SELECT " + lockColumns + ` FROM rate_locks
+		WHERE application_id = $1 AND ` + inTenant(2) + `
+		ORDER BY locked_at DESC`
+	rows, err := r.db.Query(ctx, sql, applicationId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locks := []RateLock{}
+	for rows.Next() {
+		lock, err := scanLock(rows)
+		if err != nil {
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	return locks, rows.Err()
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (r *RateLockRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, r.db, fn)
+}
+
+// inTenant is a condition limiting rate_locks to the applications of the tenant passed
+// as parameter n
+func inTenant(n int) string {
+	return fmt.Sprintf("application_id IN (SELECT id FROM mortgage_applications WHERE tenant_id = $%d)", n)
+}
+
+// expireSQL marks active locks past their expiry as expired
+const expireSQL = "UPDATE rate_locks SET status = 'expired' WHERE status = 'active' AND expires_at <= NOW()"
+
+// Expirer periodically marks active rate locks past their expiry as expired
+type Expirer struct {
+	pool     *pgxpool.Pool
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewExpirer creates an expirer.
+func NewExpirer(pool *pgxpool.Pool, logger *log.Logger) *Expirer {
+	return &Expirer{
+		pool:     pool,
+		interval: time.Minute,
+		logger:   logger,
+	}
+}
+
+// Run expires due locks every interval until ctx is cancelled
+func (e *Expirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		if expired, err := e.ExpireDue(ctx); err != nil && ctx.Err() == nil {
+			e.logger.Printf("rate lock expirer: %v", err)
+		} else if expired > 0 {
+			e.logger.Printf("rate lock expirer: expired %d locks", expired)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue marks every active lock past its expiry as expired and returns how many it changed
+func (e *Expirer) ExpireDue(ctx context.Context) (int64, error) {
+	tag, err := e.pool.Exec(ctx, expireSQL)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+type RateLockService struct {
+	repo Repository
+}
+
+func NewRateLockService(repo Repository) *RateLockService {
+	return &RateLockService{repo}
+}
+
+func (s *RateLockService) Create(ctx context.Context, lock RateLock) (RateLock, error) {
+	return s.repo.Create(ctx, lock)
+}
+
+func (s *RateLockService) Read(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error) {
+	return s.repo.Read(ctx, applicationId, id)
+}
+
+func (s *RateLockService) Use(ctx context.Context, applicationId, id uuid.UUID) (RateLock, error) {
+	return s.repo.Use(ctx, applicationId, id)
+}
+
+func (s *RateLockService) GetByApplicationId(ctx context.Context, applicationId uuid.UUID) ([]RateLock, error) {
+	return s.repo.GetByApplicationId(ctx, applicationId)
+}
@@ -0,0 +1,36 @@
+package ratelocks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLock_Validate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		lock  RateLock
+		valid bool
+	}{
+		{"valid", RateLock{Rate: 4.25, ExpiresAt: now.Add(60 * 24 * time.Hour)}, true},
+		{"longest lock", RateLock{Rate: 4.25, ExpiresAt: now.Add(MaxLockPeriod)}, true},
+		{"zero rate", RateLock{Rate: 0, ExpiresAt: now.Add(time.Hour)}, false},
+		{"rate above 100", RateLock{Rate: 101, ExpiresAt: now.Add(time.Hour)}, false},
+		{"expiry in the past", RateLock{Rate: 4.25, ExpiresAt: now.Add(-time.Minute)}, false},
+		{"missing expiry", RateLock{Rate: 4.25}, false},
+		{"expiry too far out", RateLock{Rate: 4.25, ExpiresAt: now.Add(MaxLockPeriod + time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.lock.Validate(now)
+			if tt.valid && err != nil {
+				t.Errorf("Expected lock to be valid, got: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidRateLock) {
+				t.Errorf("Expected ErrInvalidRateLock, got %v", err)
+			}
+		})
+	}
+}
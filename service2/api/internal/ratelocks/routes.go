@@ -0,0 +1,10 @@
+package ratelocks
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/applications/:id/rate-locks", handler.Create)
+	g.GET("/applications/:id/rate-locks", handler.GetByApplicationId)
+	g.GET("/applications/:id/rate-locks/:lockId", handler.Read)
+	g.POST("/applications/:id/rate-locks/:lockId/use", handler.Use)
+}
@@ -0,0 +1,61 @@
+// Package security sets the headers browsers act on: CORS headers, so a dashboard
+// served from another origin can call the API directly, and the standard security
+// headers on every response.
+package security
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"service2/api/internal/versioning"
+)
+
+// DefaultContentSecurityPolicy suits a JSON API: responses load nothing and may not be framed
+const DefaultContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// exposedHeaders are the response headers a cross-origin script may read
+var exposedHeaders = []string{"ETag", "Link", echo.HeaderRetryAfter, echo.HeaderXRequestID, versioning.HeaderVersion, "Deprecation"}
+
+type Config struct {
+	// AllowOrigins lists the origins allowed to call the API from a browser, such as
+	// https://dashboard.example.com, or "*" for any. Empty disables CORS.
+	AllowOrigins []string
+	// ContentSecurityPolicy replaces DefaultContentSecurityPolicy when set
+	ContentSecurityPolicy string
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds; 0 leaves the header
+	// out. Browsers only honour it on HTTPS responses.
+	HSTSMaxAge int
+}
+
+// Middleware sets the security headers and, when origins are allowed, answers CORS
+// preflight requests and adds the CORS headers to the others. It runs before
+// authentication, since browsers send preflights without credentials.
+func Middleware(config Config) echo.MiddlewareFunc {
+	csp := config.ContentSecurityPolicy
+	if csp == "" {
+		csp = DefaultContentSecurityPolicy
+	}
+	secure := middleware.SecureWithConfig(middleware.SecureConfig{
+		XSSProtection:         "0",
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         "DENY",
+		HSTSMaxAge:            config.HSTSMaxAge,
+		ContentSecurityPolicy: csp,
+		ReferrerPolicy:        "no-referrer",
+	})
+	if len(config.AllowOrigins) == 0 {
+		return secure
+	}
+
+	cors := middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins: config.AllowOrigins,
+		AllowMethods: []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+			http.MethodPatch, http.MethodDelete},
+		ExposeHeaders: exposedHeaders,
+		MaxAge:        600,
+	})
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return secure(cors(next))
+	}
+}
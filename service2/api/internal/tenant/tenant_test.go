@@ -0,0 +1,85 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service2/api/internal/auth"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		bound, requested string
+		want             string
+		err              error
+	}{
+		{"", "", Default, nil},
+		{"", "lender-a", "lender-a", nil},
+		{"", " lender-a ", "lender-a", nil},
+		{"lender-a", "", "lender-a", nil},
+		{"lender-a", "lender-a", "lender-a", nil},
+		{"lender-a", "lender-b", "", ErrMismatch},
+		{"", "lender a", "", ErrInvalid},
+		{"", "-lender", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		got, err := Resolve(tt.bound, tt.requested)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("Resolve(%q, %q) = %q, %v; want %q, %v", tt.bound, tt.requested, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *auth.Principal
+		header    string
+		status    int
+		tenant    string
+	}{
+		{"no tenant", nil, "", http.StatusOK, Default},
+		{"header", nil, "lender-a", http.StatusOK, "lender-a"},
+		{"bound credentials", &auth.Principal{Subject: "dashboard", Tenant: "lender-b"}, "", http.StatusOK, "lender-b"},
+		{"bound credentials asking for another tenant", &auth.Principal{Subject: "dashboard", Tenant: "lender-b"}, "lender-a", http.StatusForbidden, ""},
+		{"malformed header", nil, "lender/a", http.StatusBadRequest, ""},
+	}
+
+	e := echo.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(Header, tt.header)
+			}
+			if tt.principal != nil {
+				req = req.WithContext(auth.WithPrincipal(req.Context(), *tt.principal))
+			}
+			rec := httptest.NewRecorder()
+			var seen string
+			err := Middleware()(func(c echo.Context) error {
+				seen = FromContext(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			})(e.NewContext(req, rec))
+
+			status := rec.Code
+			var httpErr *echo.HTTPError
+			if errors.As(err, &httpErr) {
+				status = httpErr.Code
+			}
+			if status != tt.status || seen != tt.tenant {
+				t.Errorf("Expected %d for tenant %q, got %d for %q", tt.status, tt.tenant, status, seen)
+			}
+		})
+	}
+}
+
+func TestFromContext_DefaultsWithoutTenant(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("Expected %q, got %q", Default, got)
+	}
+}
@@ -0,0 +1,52 @@
+// Package timeout puts a deadline on every request's context, so a stuck query
+// cannot hold a request, and the saga step waiting on it, open indefinitely. pgx
+// cancels a query when its context ends, and the request fails with a 503.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Config struct {
+	// Default bounds every route without an entry in Routes
+	Default time.Duration
+	// Routes holds per-route timeouts keyed by method and registered path, e.g.
+	// "GET /loans/:loanId/statement"
+	Routes map[string]time.Duration
+}
+
+// For returns the timeout of the route registered as method and path
+func (c Config) For(method, path string) time.Duration {
+	if timeout, ok := c.Routes[method+" "+path]; ok {
+		return timeout
+	}
+	return c.Default
+}
+
+// Middleware runs each request with its route's timeout as the context deadline.
+// A handler that fails once the deadline has passed gets a 503 in place of its
+// error; whatever the handler wrote before that is kept.
+func Middleware(config Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			timeout := config.For(c.Request().Method, c.Path())
+			if timeout <= 0 {
+				return next(c)
+			}
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "request timed out after "+timeout.String()).SetInternal(err)
+			}
+			return err
+		}
+	}
+}
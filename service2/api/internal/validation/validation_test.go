@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+type payload struct {
+	Name   string          `json:"name" validate:"required,max=5"`
+	Amount decimal.Decimal `json:"amount" validate:"gt=0"`
+}
+
+func TestValidator_Valid(t *testing.T) {
+	v := New()
+	if err := v.Validate(payload{Name: "John", Amount: decimal.RequireFromString("0.01")}); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+}
+
+func TestValidator_FieldErrors(t *testing.T) {
+	v := New()
+	err := v.Validate(payload{Name: "Johnathan", Amount: decimal.Zero})
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("Expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d", httpErr.Code)
+	}
+
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected wrapped validation Error, got %v", err)
+	}
+	fields := map[string]string{}
+	for _, field := range validationErr.Fields {
+		fields[field.Field] = field.Message
+	}
+	if fields["name"] != "must be at most 5 characters" {
+		t.Errorf("Unexpected name error: %q", fields["name"])
+	}
+	if fields["amount"] != "must be greater than 0" {
+		t.Errorf("Unexpected amount error: %q", fields["amount"])
+	}
+}
@@ -0,0 +1,240 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service2/api/internal/outbox"
+)
+
+// Headers of a delivery. Webhook-Id stays the same across retries, so consumers can
+// drop deliveries they already handled.
+const (
+	HeaderId        = "Webhook-Id"
+	HeaderEvent     = "Webhook-Event"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+const (
+	// MaxAttempts is how often a delivery is tried before it is marked failed
+	MaxAttempts = 8
+	// initialBackoff is the wait after the first failed attempt; it doubles with each
+	// further attempt, so the last try comes about an hour after the first
+	initialBackoff = 30 * time.Second
+)
+
+// Sign returns the Webhook-Signature of body sent at timestamp: "sha256=" and the hex
+// HMAC-SHA256 of "<unix seconds>.<body>" keyed with the subscription's secret.
+// Consumers recompute it to check a delivery came from this service.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publisher queues a delivery of each outbox event to every active subscription of
+// the event's tenant that wants it, then hands the event on to next. The relay
+// retries an event whose Publish failed; each subscription still gets it once.
+type Publisher struct {
+	pool *pgxpool.Pool
+	next outbox.Publisher
+}
+
+// NewPublisher wraps the relay's publisher
+func NewPublisher(pool *pgxpool.Pool, next outbox.Publisher) *Publisher {
+	return &Publisher{pool: pool, next: next}
+}
+
+func (p *Publisher) Publish(ctx context.Context, event outbox.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	sql := `INSERT INTO webhook_deliveries
+		(id, subscription_id, event_id, event_type, payload, status, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), id, $1, $2, $3, $4, NOW(), NOW() FROM webhook_subscriptions
+		WHERE tenant_id = $5 AND active AND ($2 = ANY(event_types) OR $6 = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	_, err = p.pool.Exec(ctx, sql, event.Id, event.EventType, body, StatusPending, event.TenantId, AllEvents)
+	if err != nil {
+		return fmt.Errorf("queue webhook deliveries: %w", err)
+	}
+	return p.next.Publish(ctx, event)
+}
+
+// Dispatcher posts due deliveries to their subscriptions' URLs. A delivery succeeds
+// on any 2xx response; otherwise it is retried with backoff until MaxAttempts. The
+// deliveries of a deactivated subscription wait until it is active again.
+type Dispatcher struct {
+	pool       *pgxpool.Pool
+	httpClient *http.Client
+	interval   time.Duration
+	batchSize  int
+	logger     *log.Logger
+}
+
+// NewDispatcher creates a dispatcher.
+func NewDispatcher(pool *pgxpool.Pool, logger *log.Logger) *Dispatcher {
+	return &Dispatcher{
+		pool:       pool,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		interval:   5 * time.Second,
+		batchSize:  20,
+		logger:     logger,
+	}
+}
+
+// Run delivers due deliveries every interval until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Printf("webhook dispatcher: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// due is a delivery claimed for an attempt, with where to send it
+type due struct {
+	id        uuid.UUID
+	eventType string
+	payload   []byte
+	attempts  int
+	url       string
+	secret    string
+}
+
+// DeliverDue makes one attempt at a batch of due deliveries and returns how many
+// succeeded. The deliveries stay locked until their outcomes are recorded, so
+// several instances can dispatch side by side.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `SELECT d.id, d.event_type, d.payload, d.attempts, s.url, s.secret
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = $1 AND d.next_attempt_at <= NOW() AND s.active
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT $2
+		FOR UPDATE OF d SKIP LOCKED`
+	rows, err := tx.Query(ctx, sql, StatusPending, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	pending := []due{}
+	for rows.Next() {
+		var delivery due
+		err := rows.Scan(&delivery.id, &delivery.eventType, &delivery.payload, &delivery.attempts, &delivery.url, &delivery.secret)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range pending {
+		statusCode, postErr := d.post(ctx, delivery)
+		if ctx.Err() != nil {
+			// Shutting down: nothing is recorded and the batch is sent again, which
+			// consumers deduplicate by Webhook-Id
+			return 0, ctx.Err()
+		}
+		if err := record(ctx, tx, delivery, statusCode, postErr); err != nil {
+			return 0, err
+		}
+		if postErr == nil {
+			delivered++
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return delivered, nil
+}
+
+// post sends the delivery and returns the response status, 0 if there was none
+func (d *Dispatcher) post(ctx context.Context, delivery due) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.url, bytes.NewReader(delivery.payload))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderId, delivery.id.String())
+	req.Header.Set(HeaderEvent, delivery.eventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.secret, now, delivery.payload))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// record stores the outcome of an attempt at delivery: delivered, pending with its
+// next attempt after Backoff, or failed once MaxAttempts are used up
+func record(ctx context.Context, tx pgx.Tx, delivery due, statusCode int, postErr error) error {
+	attempts := delivery.attempts + 1
+	if postErr == nil {
+		sql := `UPDATE webhook_deliveries
+			SET status = $1, attempts = $2, last_status_code = $3, last_error = NULL, delivered_at = NOW()
+			WHERE id = $4`
+		_, err := tx.Exec(ctx, sql, StatusDelivered, attempts, statusCode, delivery.id)
+		return err
+	}
+
+	status := StatusPending
+	if attempts >= MaxAttempts {
+		status = StatusFailed
+	}
+	var code *int
+	if statusCode != 0 {
+		code = &statusCode
+	}
+	sql := `UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, last_status_code = $3, last_error = $4,
+			next_attempt_at = NOW() + $5 * interval '1 second'
+		WHERE id = $6`
+	_, err := tx.Exec(ctx, sql, status, attempts, code, postErr.Error(), Backoff(attempts).Seconds(), delivery.id)
+	return err
+}
+
+// Backoff returns how long to wait after a delivery's attempts-th failed attempt
+func Backoff(attempts int) time.Duration {
+	return initialBackoff << (attempts - 1)
+}
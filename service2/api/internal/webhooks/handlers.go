@@ -0,0 +1,132 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service2/api/internal/apierror"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewWebhookHandler(service Service) Handler {
+	return Handler{service}
+}
+
+// Create subscribes a URL to events. The response is the only one carrying the
+// subscription's signing secret.
+func (h *Handler) Create(c echo.Context) error {
+	subscription := new(Subscription)
+	if err := c.Bind(subscription); err != nil {
+		return err
+	}
+	if err := c.Validate(subscription); err != nil {
+		return err
+	}
+
+	subscription.Id = uuid.New()
+	created, err := h.service.Create(c.Request().Context(), *subscription)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) List(c echo.Context) error {
+	subscriptions, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, subscriptions)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	subscription, err := h.service.Read(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, subscription)
+}
+
+// Update replaces the URL and event types. Send active false to pause deliveries
+// without losing them; a subscription is active unless the body says otherwise.
+func (h *Handler) Update(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	subscription := &Subscription{Active: true}
+	if err := c.Bind(subscription); err != nil {
+		return err
+	}
+	if err := c.Validate(subscription); err != nil {
+		return err
+	}
+
+	subscription.Id = id
+	updated, err := h.service.Update(c.Request().Context(), *subscription)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		return httpError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Deliveries pages through the subscription's delivery log, newest first
+func (h *Handler) Deliveries(c echo.Context) error {
+	id, err := parseID(c)
+	if err != nil {
+		return err
+	}
+	var limit, offset int
+	err = echo.QueryParamsBinder(c).
+		Int("limit", &limit).
+		Int("offset", &offset).
+		BindError()
+	if err != nil {
+		return err
+	}
+
+	deliveries, err := h.service.Deliveries(c.Request().Context(), id, limit, offset)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
+
+func parseID(c echo.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest("invalid webhook subscription id", err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidSubscription) {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+package webhooks
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/webhooks", handler.Create)
+	g.GET("/webhooks", handler.List)
+	g.GET("/webhooks/:id", handler.Read)
+	g.PUT("/webhooks/:id", handler.Update)
+	g.DELETE("/webhooks/:id", handler.Delete)
+	g.GET("/webhooks/:id/deliveries", handler.Deliveries)
+}
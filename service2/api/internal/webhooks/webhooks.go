@@ -0,0 +1,300 @@
+// Package webhooks lets tenants subscribe URLs to the service's domain events, so
+// consumers outside the saga hear about changes without the orchestrator forwarding
+// them. Subscriptions are managed over REST. Publisher hooks into the outbox relay
+// and queues a delivery for every subscription an event matches, and Dispatcher
+// posts the deliveries, signed with the subscription's secret, retrying failures
+// with backoff. The deliveries table doubles as the delivery log.
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service2/api/internal/database"
+	"service2/api/internal/pagination"
+	"service2/api/internal/tenant"
+)
+
+// AllEvents subscribes to every event type the service raises
+const AllEvents = "*"
+
+// Subscription asks for the events of EventTypes to be posted to URL. Secret signs
+// the deliveries; it is only returned when the subscription is created.
+type Subscription struct {
+	Id         uuid.UUID `json:"id"`
+	TenantId   string    `json:"tenant_id"`
+	URL        string    `json:"url" validate:"required"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types" validate:"required,min=1"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// Delivery statuses. A pending delivery is retried until it is delivered or has
+// used MaxAttempts.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Delivery is one event sent, or still to be sent, to one subscription
+type Delivery struct {
+	Id             uuid.UUID  `json:"id"`
+	SubscriptionId uuid.UUID  `json:"subscription_id"`
+	EventId        uuid.UUID  `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"` // nil once delivered or failed
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+var (
+	// ErrNotFound is returned when the tenant has no subscription with the requested ID
+	ErrNotFound = errors.New("webhook subscription not found")
+	// ErrInvalidSubscription is returned for a subscription with a bad URL or unknown event type
+	ErrInvalidSubscription = errors.New("invalid webhook subscription")
+)
+
+type Repository interface {
+	Create(ctx context.Context, subscription Subscription) (Subscription, error)
+	Read(ctx context.Context, id uuid.UUID) (Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Update(ctx context.Context, subscription Subscription) (Subscription, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Deliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]Delivery, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, subscription Subscription) (Subscription, error)
+	Read(ctx context.Context, id uuid.UUID) (Subscription, error)
+	List(ctx context.Context) ([]Subscription, error)
+	Update(ctx context.Context, subscription Subscription) (Subscription, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Deliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]Delivery, error)
+}
+
+const subscriptionColumns = "id, tenant_id, url, event_types, active, created_at, modified_at"
+
+// scanSubscription scans a row selected with subscriptionColumns
+func scanSubscription(row pgx.Row) (Subscription, error) {
+	var subscription Subscription
+	err := row.Scan(
+		&subscription.Id,
+		&subscription.TenantId,
+		&subscription.URL,
+		&subscription.EventTypes,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.ModifiedAt,
+	)
+	return subscription, err
+}
+
+// notFoundOr maps a missing row to ErrNotFound
+func notFoundOr(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+type WebhookRepository struct {
+	db database.DB
+}
+
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool}
+}
+
+// Create stores the subscription for the tenant ctx acts for
+func (r *WebhookRepository) Create(ctx context.Context, subscription Subscription) (Subscription, error) {
+	sql := `INSERT INTO webhook_subscriptions (id, tenant_id, url, secret, event_types, active, created_at, modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING ` + subscriptionColumns
+	created, err := scanSubscription(r.db.QueryRow(ctx, sql, subscription.Id, tenant.FromContext(ctx),
+		subscription.URL, subscription.Secret, subscription.EventTypes, subscription.Active))
+	if err != nil {
+		return Subscription{}, err
+	}
+	created.Secret = subscription.Secret
+	return created, nil
+}
+
+func (r *WebhookRepository) Read(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	sql := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2"
+	subscription, err := scanSubscription(r.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if err != nil {
+		return Subscription{}, notFoundOr(err)
+	}
+	return subscription, nil
+}
+
+// List returns the tenant's subscriptions, oldest first
+func (r *WebhookRepository) List(ctx context.Context) ([]Subscription, error) {
+	sql := "SELECT " + subscriptionColumns + " FROM webhook_subscriptions WHERE tenant_id = $1 ORDER BY created_at, id"
+	rows, err := r.db.Query(ctx, sql, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []Subscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// Update replaces the subscription's URL, event types and active flag; its secret is kept
+func (r *WebhookRepository) Update(ctx context.Context, subscription Subscription) (Subscription, error) {
+	sql := `UPDATE webhook_subscriptions SET url = $1, event_types = $2, active = $3, modified_at = NOW()
+		WHERE id = $4 AND tenant_id = $5
+		RETURNING ` + subscriptionColumns
+	updated, err := scanSubscription(r.db.QueryRow(ctx, sql, subscription.URL, subscription.EventTypes,
+		subscription.Active, subscription.Id, tenant.FromContext(ctx)))
+	if err != nil {
+		return Subscription{}, notFoundOr(err)
+	}
+	return updated, nil
+}
+
+// Delete removes the subscription together with its deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1 AND tenant_id = $2", id, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Deliveries returns a page of the subscription's deliveries, newest first
+func (r *WebhookRepository) Deliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]Delivery, error) {
+	sql := `SELECT d.id, d.subscription_id, d.event_id, d.event_type, d.status, d.attempts,
+			CASE WHEN d.status = $1 THEN d.next_attempt_at END, d.last_status_code, d.last_error, d.delivered_at, d.created_at
+		FROM webhook_deliveries d JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.subscription_id = $2 AND s.tenant_id = $3
+		ORDER BY d.created_at DESC, d.id
+		LIMIT $4 OFFSET $5`
+	rows, err := r.db.Query(ctx, sql, StatusPending, id, tenant.FromContext(ctx), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var delivery Delivery
+		err := rows.Scan(&delivery.Id, &delivery.SubscriptionId, &delivery.EventId, &delivery.EventType,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt, &delivery.LastStatusCode,
+			&delivery.LastError, &delivery.DeliveredAt, &delivery.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+type WebhookService struct {
+	repo       Repository
+	eventTypes []string
+}
+
+// NewWebhookService creates a service accepting subscriptions to eventTypes, the
+// events the service raises
+func NewWebhookService(repo Repository, eventTypes []string) *WebhookService {
+	return &WebhookService{repo: repo, eventTypes: eventTypes}
+}
+
+// Validate checks the subscription's URL and event types
+func (s *WebhookService) Validate(subscription Subscription) error {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	if len(subscription.EventTypes) == 0 {
+		return fmt.Errorf("%w: event_types must name at least one event type or %q", ErrInvalidSubscription, AllEvents)
+	}
+	for _, eventType := range subscription.EventTypes {
+		if eventType != AllEvents && !slices.Contains(s.eventTypes, eventType) {
+			return fmt.Errorf("%w: unknown event type %q, expected one of %v or %q",
+				ErrInvalidSubscription, eventType, s.eventTypes, AllEvents)
+		}
+	}
+	return nil
+}
+
+// Create stores an active subscription, generating its signing secret unless one is given
+func (s *WebhookService) Create(ctx context.Context, subscription Subscription) (Subscription, error) {
+	if err := s.Validate(subscription); err != nil {
+		return Subscription{}, err
+	}
+	if subscription.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return Subscription{}, err
+		}
+		subscription.Secret = secret
+	}
+	subscription.Active = true
+	return s.repo.Create(ctx, subscription)
+}
+
+func (s *WebhookService) Read(ctx context.Context, id uuid.UUID) (Subscription, error) {
+	return s.repo.Read(ctx, id)
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]Subscription, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *WebhookService) Update(ctx context.Context, subscription Subscription) (Subscription, error) {
+	if err := s.Validate(subscription); err != nil {
+		return Subscription{}, err
+	}
+	return s.repo.Update(ctx, subscription)
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Deliveries returns a page of the subscription's delivery log, ErrNotFound if the
+// tenant has no such subscription
+func (s *WebhookService) Deliveries(ctx context.Context, id uuid.UUID, limit, offset int) ([]Delivery, error) {
+	if _, err := s.repo.Read(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.Deliveries(ctx, id, pagination.ClampLimit(limit), max(offset, 0))
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestWebhookService_Validate(t *testing.T) {
+	service := NewWebhookService(nil, []string{"ThingCreated", "ThingDeleted"})
+	valid := []Subscription{
+		{URL: "https://example.com/hooks", EventTypes: []string{"ThingCreated"}},
+		{URL: "http://consumer:8080/events", EventTypes: []string{AllEvents}},
+	}
+	for _, subscription := range valid {
+		if err := service.Validate(subscription); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", subscription, err)
+		}
+	}
+
+	invalid := []Subscription{
+		{URL: "ftp://example.com", EventTypes: []string{"ThingCreated"}},
+		{URL: "/relative", EventTypes: []string{"ThingCreated"}},
+		{URL: "https://example.com"},
+		{URL: "https://example.com", EventTypes: []string{"ThingCreated", "ThingRenamed"}},
+	}
+	for _, subscription := range invalid {
+		if err := service.Validate(subscription); !errors.Is(err, ErrInvalidSubscription) {
+			t.Errorf("Expected ErrInvalidSubscription for %+v, got %v", subscription, err)
+		}
+	}
+}
+
+func TestDispatcher_post(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher(nil, log.New(io.Discard, "", 0))
+	delivery := due{id: uuid.New(), eventType: "ThingCreated", payload: []byte(`{"id":"1"}`), url: server.URL, secret: "whsec_test"}
+	if code, err := dispatcher.post(context.Background(), delivery); err != nil || code != http.StatusNoContent {
+		t.Fatalf("Expected the delivery to succeed, got %d, %v", code, err)
+	}
+	if string(body) != `{"id":"1"}` || request.Header.Get(HeaderId) != delivery.id.String() ||
+		request.Header.Get(HeaderEvent) != "ThingCreated" {
+		t.Errorf("Unexpected delivery %v: %s", request.Header, body)
+	}
+	unix, err := strconv.ParseInt(request.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("Expected a unix timestamp, got %q", request.Header.Get(HeaderTimestamp))
+	}
+	if got, want := request.Header.Get(HeaderSignature), Sign("whsec_test", time.Unix(unix, 0), body); got != want {
+		t.Errorf("Signature = %q, want %q", got, want)
+	}
+	if Sign("another secret", time.Unix(unix, 0), body) == request.Header.Get(HeaderSignature) {
+		t.Errorf("Expected the signature to depend on the secret")
+	}
+
+	status = http.StatusBadGateway
+	if code, err := dispatcher.post(context.Background(), delivery); err == nil || code != http.StatusBadGateway {
+		t.Errorf("Expected a failed delivery with the response status, got %d, %v", code, err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	if Backoff(1) != 30*time.Second || Backoff(2) != time.Minute {
+		t.Errorf("Expected backoff to start at 30s and double, got %v, %v", Backoff(1), Backoff(2))
+	}
+	total := time.Duration(0)
+	for attempts := 1; attempts < MaxAttempts; attempts++ {
+		total += Backoff(attempts)
+	}
+	if total < time.Hour || total > 2*time.Hour {
+		t.Errorf("Expected retries to span about an hour, got %v", total)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"service2/api/internal/mortgages"
+)
+
+// BulkResult is what happened to one application of CreateBulk: its Status is the
+// one the application would have been answered with on its own, with Application
+// set when it was created and Error when it was not
+type BulkResult = mortgages.BulkResult
+
+// MaxBulkApplications is how many applications one CreateBulk call may create
+const MaxBulkApplications = mortgages.MaxBulkApplications
+
+// CreateBulk creates up to MaxBulkApplications applications in one request, and
+// atomically, returning a result for each in order. When any application is
+// rejected none is created: err is the 422's APIError and the results say which
+// applications failed and why, the others having a 424.
+func (c *Client) CreateBulk(ctx context.Context, applications []MortgageApplication) ([]BulkResult, error) {
+	body, err := jsonBody(applications)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.api.CreateApplicationsBulkWithBody(ctx, contentTypeJSON, body)
+	return decodeBulk[BulkResult](resp, err)
+}
+
+// decodeBulk reads the results of a bulk request, from the 201's body or, when the
+// request was rejected, the details of its 422
+func decodeBulk[T any](resp *http.Response, err error) ([]T, error) {
+	results, err := decode[[]T](resp, err, http.StatusCreated)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		var envelope struct {
+			Details []T `json:"details"`
+		}
+		if json.Unmarshal(apiErr.RawBody, &envelope) == nil {
+			results = envelope.Details
+		}
+	}
+	return results, err
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateBulk_ReturnsResultPerApplication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var applications []MortgageApplication
+		if r.URL.Path != "/v1/applications/bulk" || json.NewDecoder(r.Body).Decode(&applications) != nil {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		results := make([]BulkResult, len(applications))
+		for i := range applications {
+			applications[i].Id = uuid.New()
+			results[i] = BulkResult{Index: i, Status: http.StatusCreated, Application: &applications[i]}
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL).CreateBulk(context.Background(), []MortgageApplication{{}, {}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[1].Index != 1 || results[1].Application == nil || results[1].Application.Id == uuid.Nil {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestCreateBulk_ReturnsResultsOfRejectedRequest(t *testing.T) {
+	body := `{"code":"bulk_rejected","message":"1 of 2 applications rejected; nothing was created","details":[` +
+		`{"index":0,"status":424,"error":{"code":"not_created","message":"not created"}},` +
+		`{"index":1,"status":400,"error":{"code":"bad_request","message":"loan_amount is required"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL).CreateBulk(context.Background(), []MortgageApplication{{}, {}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "bulk_rejected" {
+		t.Fatalf("Expected the 422 as an APIError, got %v", err)
+	}
+	if len(results) != 2 || results[0].Status != http.StatusFailedDependency ||
+		results[1].Status != http.StatusBadRequest || results[1].Error == nil || results[1].Error.Message != "loan_amount is required" {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxCachedBody bounds the size of a response WithCache keeps; larger ones are
+// passed through uncached
+const maxCachedBody = 1 << 20
+
+// WithCache keeps the last entries GET responses that carry an ETag, such as a
+// customer, application or loan read, and revalidates them with If-None-Match: a
+// 304 is answered with the kept response, so a saga re-reading the same entity
+// during retries gets it without the service encoding it again. Every read still
+// reaches the service, so it never returns a stale copy. Entries are kept per
+// tenant and credentials; call it before the With options that add those headers.
+// With entries of 0 or less nothing is kept.
+func (c *Client) WithCache(entries int) *Client {
+	c.httpClient.Transport = &etagCache{
+		entries: entries,
+		byKey:   make(map[string]*list.Element),
+		lru:     list.New(),
+		next:    c.httpClient.Transport,
+	}
+	return c
+}
+
+// etagCache revalidates GETs against the responses it kept for them before sending
+// them with next, keeping up to entries of them and dropping the least recently used
+type etagCache struct {
+	entries int
+	next    http.RoundTripper
+
+	mu    sync.Mutex
+	byKey map[string]*list.Element
+	lru   *list.List // of *cachedResponse, most recently used first
+}
+
+// cachedResponse is a 200 response kept for the request key identifies
+type cachedResponse struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func (t *etagCache) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || t.entries <= 0 {
+		return next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return cached.response(req, resp.Header), nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		return t.keep(key, resp)
+	case resp.StatusCode == http.StatusNotFound:
+		t.remove(key)
+	}
+	return resp, nil
+}
+
+// cacheKey identifies what a GET reads: its URL, as the tenant and credentials it
+// is sent with see it
+func cacheKey(req *http.Request) string {
+	return strings.Join([]string{
+		req.URL.String(), req.Header.Get(headerTenant), req.Header.Get("Authorization"), req.Header.Get(headerAPIKey),
+	}, "\x00")
+}
+
+// keep reads resp's body, keeps it under key when it is small enough and returns
+// resp with the body to read again
+func (t *etagCache) keep(key string, resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if element, ok := t.byKey[key]; ok {
+		t.lru.Remove(element)
+	}
+	t.byKey[key] = t.lru.PushFront(&cachedResponse{key: key, etag: resp.Header.Get("ETag"), header: resp.Header.Clone(), body: body})
+	for t.lru.Len() > t.entries {
+		delete(t.byKey, t.lru.Remove(t.lru.Back()).(*cachedResponse).key)
+	}
+	return resp, nil
+}
+
+func (t *etagCache) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	element, ok := t.byKey[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(element)
+	return element.Value.(*cachedResponse)
+}
+
+func (t *etagCache) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if element, ok := t.byKey[key]; ok {
+		t.lru.Remove(element)
+		delete(t.byKey, key)
+	}
+}
+
+// response rebuilds the kept response to req, with the headers of the 304 that
+// confirmed it, such as its request ID, taking precedence
+func (r *cachedResponse) response(req *http.Request, notModified http.Header) *http.Response {
+	header := r.header.Clone()
+	for name, values := range notModified {
+		header[name] = values
+	}
+	header.Set("Content-Length", strconv.Itoa(len(r.body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// readCloser reads from Reader and closes Closer
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
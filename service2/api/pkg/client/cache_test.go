@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// versionedServer serves body at any path under ETag W/"<version>", answering a
+// matching If-None-Match with a 304, and records the If-None-Match of each request
+func versionedServer(t *testing.T, version *string, body string) (*httptest.Server, *[]string) {
+	var conditions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditions = append(conditions, r.Header.Get("If-None-Match"))
+		etag := `W/"` + *version + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-Request-ID", "req-"+strconv.Itoa(len(conditions)))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(body + *version))
+	}))
+	t.Cleanup(server.Close)
+	return server, &conditions
+}
+
+// read GETs url with c and returns the body, failing on anything but a 200
+func read(t *testing.T, c *Client, url string) (string, http.Header) {
+	t.Helper()
+	resp, err := get(t, c, context.Background(), url)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a 200, got %d: %v", resp.StatusCode, err)
+	}
+	return string(body), resp.Header
+}
+
+func TestWithCache_RevalidatesWithETag(t *testing.T) {
+	version := "1"
+	server, conditions := versionedServer(t, &version, "entity v")
+	c := NewClient(server.URL).WithCache(8)
+
+	if body, _ := read(t, c, server.URL+"/a"); body != "entity v1" {
+		t.Fatalf("Unexpected body %q", body)
+	}
+	body, header := read(t, c, server.URL+"/a")
+	if body != "entity v1" || header.Get("X-Request-ID") != "req-2" {
+		t.Errorf("Expected the kept body with the 304's request ID, got %q, %v", body, header)
+	}
+	version = "2"
+	if body, _ := read(t, c, server.URL+"/a"); body != "entity v2" {
+		t.Errorf("Expected the changed entity, got %q", body)
+	}
+
+	want := []string{"", `W/"1"`, `W/"1"`}
+	for i := range want {
+		if (*conditions)[i] != want[i] {
+			t.Errorf("Expected request %d to send If-None-Match %q, got %q", i, want[i], (*conditions)[i])
+		}
+	}
+}
+
+func TestWithCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	version := "1"
+	server, conditions := versionedServer(t, &version, "entity v")
+	c := NewClient(server.URL).WithCache(1)
+
+	read(t, c, server.URL+"/a")
+	read(t, c, server.URL+"/b")
+	read(t, c, server.URL+"/a")
+	if got := (*conditions)[2]; got != "" {
+		t.Errorf("Expected /a to have been evicted by /b, got If-None-Match %q", got)
+	}
+}
+
+func TestWithCache_KeepsEntriesPerTenant(t *testing.T) {
+	version := "1"
+	server, conditions := versionedServer(t, &version, "entity v")
+	tenant := "a"
+	c := NewClient(server.URL).WithCache(8).WithTenantFrom(func(ctx context.Context) string { return tenant })
+
+	read(t, c, server.URL+"/a")
+	tenant = "b"
+	read(t, c, server.URL+"/a")
+	if got := (*conditions)[1]; got != "" {
+		t.Errorf("Expected another tenant's read not to be revalidated, got If-None-Match %q", got)
+	}
+}
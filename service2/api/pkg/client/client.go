@@ -1,112 +1,97 @@
 package client
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
+	"io"
 	"net/http"
-	"net/url"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"service2/api/internal/fees"
 	"service2/api/internal/mortgages"
+	"service2/api/internal/ratelocks"
+	"service2/api/pkg/client/internal/oapi"
 )
 
-const path = "/applications"
-
 type MortgageApplication = mortgages.MortgageApplication
+type Decision = mortgages.Decision
+type ApplicationFilter = mortgages.ApplicationFilter
+type RateLock = ratelocks.RateLock
+type Fee = fees.Fee
+type FeeTotals = mortgages.FeeTotals
+
+const CancelReasonSagaCompensation = mortgages.CancelReasonSagaCompensation
 
 type Client struct {
-	baseURL    string
+	// api builds and sends the requests; it is generated from the service's OpenAPI
+	// document, so the paths and parameters follow the handlers
+	api        *oapi.Client
 	httpClient *http.Client
+	// transport connects to the service; the With options wrap it
+	transport *http.Transport
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+	// versions sends the client's version headers, innermost of the transports
+	versions *versionHeaders
 }
 
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
-	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	versions := &versionHeaders{userAgent: userAgent, next: transport}
+	c := &Client{
+		httpClient: &http.Client{Transport: versions},
+		transport:  transport,
+		timeout:    DefaultTimeout,
+		versions:   versions,
+	}
+	c.api = &oapi.Client{Server: strings.TrimSuffix(baseURL, "/") + "/", Client: doerFunc(c.do)}
+	return c
+}
+
+func (c *Client) Create(ctx context.Context, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int) (MortgageApplication, error) {
+	return c.CreateIdempotent(ctx, "", customerId, loanAmount, propertyValue, interestRate, termYears)
 }
 
-func (c *Client) Create(ctx context.Context, customerId uuid.UUID, loanAmount, propertyValue, interestRate float64, termYears int) (MortgageApplication, error) {
-	payload := struct {
-		CustomerId    uuid.UUID `json:"customer_id"`
-		LoanAmount    float64   `json:"loan_amount"`
-		PropertyValue float64   `json:"property_value"`
-		InterestRate  float64   `json:"interest_rate"`
-		TermYears     int       `json:"term_years"`
+// CreateIdempotent creates an application, sending idempotencyKey so that retrying
+// with the same key returns the application created by the first attempt instead of
+// a duplicate. An empty key sends no Idempotency-Key header.
+func (c *Client) CreateIdempotent(ctx context.Context, idempotencyKey string, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int) (MortgageApplication, error) {
+	body, err := jsonBody(struct {
+		CustomerId    uuid.UUID       `json:"customer_id"`
+		LoanAmount    decimal.Decimal `json:"loan_amount"`
+		PropertyValue decimal.Decimal `json:"property_value"`
+		InterestRate  float64         `json:"interest_rate"`
+		TermYears     int             `json:"term_years"`
 	}{
 		CustomerId:    customerId,
 		LoanAmount:    loanAmount,
 		PropertyValue: propertyValue,
 		InterestRate:  interestRate,
 		TermYears:     termYears,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return MortgageApplication{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var application MortgageApplication
-	err = json.NewDecoder(resp.Body).Decode(&application)
+	})
 	if err != nil {
 		return MortgageApplication{}, err
 	}
-
-	return application, nil
+	resp, err := c.api.CreateApplicationWithBody(ctx, contentTypeJSON, body, idempotencyKeyHeader(idempotencyKey))
+	return decode[MortgageApplication](resp, err, http.StatusCreated)
 }
 
 func (c *Client) Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error) {
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return MortgageApplication{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var application MortgageApplication
-	err = json.NewDecoder(resp.Body).Decode(&application)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	return application, nil
+	resp, err := c.api.GetApplication(ctx, id)
+	return decode[MortgageApplication](resp, err, http.StatusOK)
 }
 
-func (c *Client) Update(ctx context.Context, id uuid.UUID, customerId uuid.UUID, loanAmount, propertyValue, interestRate float64, termYears int, status string) (MortgageApplication, error) {
-	payload := struct {
-		CustomerId    uuid.UUID `json:"customer_id"`
-		LoanAmount    float64   `json:"loan_amount"`
-		PropertyValue float64   `json:"property_value"`
-		InterestRate  float64   `json:"interest_rate"`
-		TermYears     int       `json:"term_years"`
-		Status        string    `json:"status"`
+// Update replaces the application if it is still at version, sending it as If-Match
+func (c *Client) Update(ctx context.Context, id uuid.UUID, version int, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int, status string) (MortgageApplication, error) {
+	body, err := jsonBody(struct {
+		CustomerId    uuid.UUID       `json:"customer_id"`
+		LoanAmount    decimal.Decimal `json:"loan_amount"`
+		PropertyValue decimal.Decimal `json:"property_value"`
+		InterestRate  float64         `json:"interest_rate"`
+		TermYears     int             `json:"term_years"`
+		Status        string          `json:"status"`
 	}{
 		CustomerId:    customerId,
 		LoanAmount:    loanAmount,
@@ -114,86 +99,124 @@ func (c *Client) Update(ctx context.Context, id uuid.UUID, customerId uuid.UUID,
 		InterestRate:  interestRate,
 		TermYears:     termYears,
 		Status:        status,
-	}
-	jsonPayload, err := json.Marshal(payload)
+	})
 	if err != nil {
 		return MortgageApplication{}, err
 	}
+	resp, err := c.api.UpdateApplicationWithBody(ctx, id, contentTypeJSON, body, ifMatch(version))
+	return decode[MortgageApplication](resp, err, http.StatusOK)
+}
 
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
-	if err != nil {
-		return MortgageApplication{}, err
-	}
+func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
+	resp, err := c.api.DeleteApplication(ctx, id)
+	return noContent(resp, err)
+}
 
-	req, err := http.NewRequest(http.MethodPut, fullURL, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+func (c *Client) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error) {
+	resp, err := c.api.ListCustomerApplications(ctx, customerId)
+	return decode[[]MortgageApplication](resp, err, http.StatusOK)
+}
 
+func (c *Client) List(ctx context.Context, filter ApplicationFilter) ([]MortgageApplication, error) {
+	resp, err := c.api.ListApplications(ctx, applicationParams(filter))
+	return decode[[]MortgageApplication](resp, err, http.StatusOK)
+}
+
+// Approve approves a pending application
+func (c *Client) Approve(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return c.decide(ctx, id, c.api.ApproveApplicationWithBody, decision)
+}
+
+// Reject rejects a pending application; decision.Reason is required
+func (c *Client) Reject(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return c.decide(ctx, id, c.api.RejectApplicationWithBody, decision)
+}
+
+// Withdraw withdraws a pending or approved application
+func (c *Client) Withdraw(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return c.decide(ctx, id, c.api.WithdrawApplicationWithBody, decision)
+}
+
+// Cancel cancels a pending or approved application; cancelling twice is not an error
+func (c *Client) Cancel(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	return c.decide(ctx, id, c.api.CancelApplicationWithBody, decision)
+}
+
+// decide posts decision with one of the generated decision operations, which all
+// share a signature
+func (c *Client) decide(ctx context.Context, id uuid.UUID, action func(context.Context, uuid.UUID, string, io.Reader, ...oapi.RequestEditorFn) (*http.Response, error), decision Decision) (MortgageApplication, error) {
+	body, err := jsonBody(decision)
 	if err != nil {
 		return MortgageApplication{}, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return MortgageApplication{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var application MortgageApplication
-	err = json.NewDecoder(resp.Body).Decode(&application)
-	if err != nil {
-		return MortgageApplication{}, err
-	}
-	return application, nil
+	resp, err := action(ctx, id, contentTypeJSON, body)
+	return decode[MortgageApplication](resp, err, http.StatusOK)
 }
 
-func (c *Client) Delete(ctx context.Context, id uuid.UUID) error {
-	fullURL, err := url.JoinPath(c.baseURL, path, id.String())
-	if err != nil {
-		return err
-	}
+// Fees lists the fees charged on the application. Read returns their totals, which
+// is enough to check that nothing is outstanding.
+func (c *Client) Fees(ctx context.Context, applicationId uuid.UUID) ([]Fee, error) {
+	resp, err := c.api.ListFees(ctx, applicationId)
+	return decode[[]Fee](resp, err, http.StatusOK)
+}
 
-	req, err := http.NewRequest(http.MethodDelete, fullURL, nil)
-	if err != nil {
-		return err
-	}
-	req = req.WithContext(ctx)
-	resp, err := c.httpClient.Do(req)
+// LockRate locks rate for the application until expiresAt
+func (c *Client) LockRate(ctx context.Context, applicationId uuid.UUID, rate float64, expiresAt time.Time) (RateLock, error) {
+	body, err := jsonBody(struct {
+		Rate      float64   `json:"rate"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{
+		Rate:      rate,
+		ExpiresAt: expiresAt,
+	})
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return RateLock{}, err
 	}
-	return nil
+	resp, err := c.api.CreateRateLockWithBody(ctx, applicationId, contentTypeJSON, body)
+	return decode[RateLock](resp, err, http.StatusCreated)
 }
 
-func (c *Client) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]MortgageApplication, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/customers", customerId.String(), "applications")
-	if err != nil {
-		return nil, err
-	}
+// UseRateLock consumes the lock when the application is funded. It fails if the lock
+// has expired or was already used.
+func (c *Client) UseRateLock(ctx context.Context, applicationId, lockId uuid.UUID) (RateLock, error) {
+	resp, err := c.api.UseRateLock(ctx, applicationId, lockId)
+	return decode[RateLock](resp, err, http.StatusOK)
+}
 
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, err
+// ListApplications iterates over every application matching filter, starting at
+// filter.Offset and fetching filter.Limit applications, or as many as the service
+// allows, per request
+func (c *Client) ListApplications(ctx context.Context, filter ApplicationFilter) *Iterator[MortgageApplication] {
+	limit := pageSize(filter.Limit, mortgages.MaxListLimit)
+	return newIterator[MortgageApplication](ctx, c, limit, filter.Offset, func(limit, offset int) (*http.Request, error) {
+		filter.Limit, filter.Offset = limit, offset
+		return oapi.NewListApplicationsRequest(c.api.Server, applicationParams(filter))
+	})
+}
+
+// applicationParams sets the query parameters for the set fields of an application filter
+func applicationParams(filter ApplicationFilter) *oapi.ListApplicationsParams {
+	params := &oapi.ListApplicationsParams{}
+	if filter.Status != "" {
+		params.Status = &filter.Status
 	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	if !filter.CreatedFrom.IsZero() {
+		params.CreatedFrom = ptr(filter.CreatedFrom.Format(time.RFC3339))
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if !filter.CreatedTo.IsZero() {
+		params.CreatedTo = ptr(filter.CreatedTo.Format(time.RFC3339))
 	}
-	var applications []MortgageApplication
-	err = json.NewDecoder(resp.Body).Decode(&applications)
-	if err != nil {
-		return nil, err
+	if !filter.MinAmount.IsZero() {
+		params.MinAmount = ptr(filter.MinAmount.String())
+	}
+	if !filter.MaxAmount.IsZero() {
+		params.MaxAmount = ptr(filter.MaxAmount.String())
+	}
+	if filter.Limit > 0 {
+		params.Limit = &filter.Limit
+	}
+	if filter.Offset > 0 {
+		params.Offset = &filter.Offset
 	}
-	return applications, nil
+	return params
 }
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestDecisions_PostToActionEndpoints checks each decision is posted to its own
+// action endpoint rather than sent as a full-object PUT
+func TestDecisions_PostToActionEndpoints(t *testing.T) {
+	calls := map[string]func(c *Client, ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error){
+		"approve":  (*Client).Approve,
+		"reject":   (*Client).Reject,
+		"withdraw": (*Client).Withdraw,
+		"cancel":   (*Client).Cancel,
+	}
+	for action, call := range calls {
+		id := uuid.New()
+		var method, path string
+		var got Decision
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			_ = json.NewEncoder(w).Encode(MortgageApplication{Id: id, Status: action})
+		}))
+		decision := Decision{DecidedBy: "underwriter", Reason: "checked", Version: 3}
+		application, err := call(NewClient(server.URL), context.Background(), id, decision)
+		server.Close()
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", action, err)
+			continue
+		}
+		if want := "/v1/applications/" + id.String() + "/" + action; method != http.MethodPost || path != want {
+			t.Errorf("%s: expected POST %s, got %s %s", action, want, method, path)
+		}
+		if got != decision {
+			t.Errorf("%s: expected the decision %+v to be sent, got %+v", action, decision, got)
+		}
+		if application.Id != id || application.Status != action {
+			t.Errorf("%s: unexpected application %+v", action, application)
+		}
+	}
+}
+
+func TestDecisions_ReturnConflictForStaleVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"code":"conflict","message":"version mismatch"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).Approve(context.Background(), uuid.New(), Decision{DecidedBy: "underwriter", Version: 1})
+	if !IsStatus(err, http.StatusConflict) {
+		t.Errorf("Expected a 409 APIError, got %v", err)
+	}
+}
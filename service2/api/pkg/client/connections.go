@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net"
+	"time"
+)
+
+// Connections tunes how the client connects to the service. Zero fields keep the
+// settings of http.DefaultTransport, which keeps only two idle connections per host:
+// callers running many requests at once should raise MaxIdleConnsPerHost, or most
+// of their requests open a new connection and close it again.
+type Connections struct {
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int           // including those in use; 0 is no limit
+	IdleConnTimeout     time.Duration // how long an idle connection is kept
+	DisableKeepAlives   bool          // one connection per request
+	KeepAlive           time.Duration // between TCP keep-alive probes; negative turns them off
+	DialTimeout         time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+// WithConnections applies connections to the client's transport. Call it before the
+// client makes its first request.
+func (c *Client) WithConnections(connections Connections) *Client {
+	t := c.transport
+	if connections.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = connections.MaxIdleConnsPerHost
+		// The pool across hosts must not be smaller than the pool for the one host
+		if t.MaxIdleConns > 0 && t.MaxIdleConns < connections.MaxIdleConnsPerHost {
+			t.MaxIdleConns = connections.MaxIdleConnsPerHost
+		}
+	}
+	if connections.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = connections.MaxConnsPerHost
+	}
+	if connections.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = connections.IdleConnTimeout
+	}
+	if connections.DisableKeepAlives {
+		t.DisableKeepAlives = true
+	}
+	if connections.TLSHandshakeTimeout > 0 {
+		t.TLSHandshakeTimeout = connections.TLSHandshakeTimeout
+	}
+	if connections.DialTimeout != 0 || connections.KeepAlive != 0 {
+		// The same dialer http.DefaultTransport uses, with the fields set here replaced
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if connections.DialTimeout > 0 {
+			dialer.Timeout = connections.DialTimeout
+		}
+		if connections.KeepAlive != 0 {
+			dialer.KeepAlive = connections.KeepAlive
+		}
+		t.DialContext = dialer.DialContext
+	}
+	return c
+}
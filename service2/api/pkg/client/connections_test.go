@@ -0,0 +1,69 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithConnections_SetsTransport(t *testing.T) {
+	c := NewClient("http://localhost").WithConnections(Connections{
+		MaxIdleConnsPerHost: 200,
+		MaxConnsPerHost:     300,
+		IdleConnTimeout:     time.Minute,
+		TLSHandshakeTimeout: 3 * time.Second,
+	})
+	tr := c.transport
+	if tr.MaxIdleConnsPerHost != 200 || tr.MaxIdleConns != 200 || tr.MaxConnsPerHost != 300 ||
+		tr.IdleConnTimeout != time.Minute || tr.TLSHandshakeTimeout != 3*time.Second || tr.DisableKeepAlives {
+		t.Errorf("Unexpected transport settings %+v", tr)
+	}
+
+	defaults := http.DefaultTransport.(*http.Transport)
+	tr = NewClient("http://localhost").WithConnections(Connections{}).transport
+	if tr.MaxIdleConnsPerHost != defaults.MaxIdleConnsPerHost || tr.IdleConnTimeout != defaults.IdleConnTimeout ||
+		tr.TLSHandshakeTimeout != defaults.TLSHandshakeTimeout {
+		t.Errorf("Expected zero fields to keep the default settings, got %+v", tr)
+	}
+}
+
+func TestWithConnections_ReusesConnectionsForConcurrentCalls(t *testing.T) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	const parallel = 8
+	c := NewClient(server.URL).WithConnections(Connections{MaxIdleConnsPerHost: parallel})
+	for range 3 {
+		var wg sync.WaitGroup
+		for range parallel {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				resp, err := c.httpClient.Get(server.URL)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				resp.Body.Close()
+			}()
+		}
+		wg.Wait()
+	}
+	if n := conns.Load(); n > parallel {
+		t.Errorf("Expected at most %d connections for %d rounds of %d calls, got %d", parallel, 3, parallel, n)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"service2/api/internal/documents"
+	"service2/api/internal/fees"
+	"service2/api/internal/health"
+	"service2/api/internal/mortgages"
+	"service2/api/internal/ratelocks"
+	"service2/api/internal/versioning"
+	"service2/api/internal/webhooks"
+)
+
+// contractRoutes registers the service's routes as main does; the handlers are
+// replaced by the replaying server, so they need no services
+func contractRoutes(e *echo.Echo) {
+	v1 := e.Group(versioning.Prefix("v1"))
+	mortgages.Routes(v1, mortgages.NewMortgageHandler(nil))
+	documents.Routes(v1, documents.NewDocumentHandler(nil))
+	fees.Routes(v1, fees.NewFeeHandler(nil))
+	ratelocks.Routes(v1, ratelocks.NewRateLockHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+}
+
+var contractCases = []contractCase{
+	{"create_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.CreateIdempotent(ctx, "saga-1:create-application", uuid.New(), decimal.NewFromInt(300000),
+			decimal.NewFromInt(400000), 5.5, 30)
+	}},
+	{"read_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Read(ctx, uuid.New())
+	}},
+	{"read_missing_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Read(ctx, uuid.New())
+	}},
+	{"update_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Update(ctx, uuid.New(), 1, uuid.New(), decimal.NewFromInt(300000), decimal.NewFromInt(400000), 5.5, 30, "pending")
+	}},
+	{"delete_application", func(ctx context.Context, c *Client) (any, error) {
+		return nil, c.Delete(ctx, uuid.New())
+	}},
+	{"list_customer_applications", func(ctx context.Context, c *Client) (any, error) {
+		return c.GetByCustomerId(ctx, uuid.New())
+	}},
+	{"list_applications", func(ctx context.Context, c *Client) (any, error) {
+		return c.List(ctx, ApplicationFilter{Status: "pending", Limit: 10})
+	}},
+	{"approve_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Approve(ctx, uuid.New(), Decision{DecidedBy: "underwriter", Version: 1})
+	}},
+	{"reject_application_without_reason", func(ctx context.Context, c *Client) (any, error) {
+		return c.Reject(ctx, uuid.New(), Decision{DecidedBy: "underwriter"})
+	}},
+	{"cancel_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Cancel(ctx, uuid.New(), Decision{DecidedBy: "saga", Reason: CancelReasonSagaCompensation})
+	}},
+	{"cancel_decided_application", func(ctx context.Context, c *Client) (any, error) {
+		return c.Cancel(ctx, uuid.New(), Decision{DecidedBy: "saga", Reason: CancelReasonSagaCompensation})
+	}},
+	{"create_applications_bulk", func(ctx context.Context, c *Client) (any, error) {
+		return c.CreateBulk(ctx, []MortgageApplication{{CustomerId: uuid.New(), LoanAmount: decimal.NewFromInt(300000),
+			PropertyValue: decimal.NewFromInt(400000), InterestRate: 5.5, TermYears: 30}})
+	}},
+	{"list_fees", func(ctx context.Context, c *Client) (any, error) {
+		return c.Fees(ctx, uuid.New())
+	}},
+	{"lock_rate", func(ctx context.Context, c *Client) (any, error) {
+		return c.LockRate(ctx, uuid.New(), 5.25, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC))
+	}},
+	{"use_expired_rate_lock", func(ctx context.Context, c *Client) (any, error) {
+		return c.UseRateLock(ctx, uuid.New(), uuid.New())
+	}},
+}
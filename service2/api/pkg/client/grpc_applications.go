@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"service2/api/pkg/pb/applicationsv1"
+)
+
+// GRPCClient calls the mortgage application service's gRPC API. It has the Client
+// methods the gRPC API covers, taking and returning the same types, and its errors
+// are the APIErrors Client returns, so errors.Is(err, ErrNotFound) works with either.
+type GRPCClient struct {
+	applications applicationsv1.ApplicationServiceClient
+	// metadata is added to every call; the With options append to it
+	metadata []outgoingMetadata
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+}
+
+// NewGRPCClient calls the service over conn, which is dialled with the transport
+// credentials, and any interceptors, the caller needs
+func NewGRPCClient(conn grpc.ClientConnInterface) *GRPCClient {
+	return &GRPCClient{applications: applicationsv1.NewApplicationServiceClient(conn), timeout: DefaultTimeout}
+}
+
+func (c *GRPCClient) Create(ctx context.Context, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int) (MortgageApplication, error) {
+	return c.CreateIdempotent(ctx, "", customerId, loanAmount, propertyValue, interestRate, termYears)
+}
+
+// CreateIdempotent creates an application, sending idempotencyKey so that retrying
+// with the same key returns the application created by the first attempt instead of
+// a duplicate. An empty key sends none.
+func (c *GRPCClient) CreateIdempotent(ctx context.Context, idempotencyKey string, customerId uuid.UUID, loanAmount, propertyValue decimal.Decimal, interestRate float64, termYears int) (MortgageApplication, error) {
+	application, err := invoke(ctx, c, c.applications.CreateApplication, &applicationsv1.CreateApplicationRequest{
+		CustomerId:     customerId.String(),
+		LoanAmount:     loanAmount.String(),
+		PropertyValue:  propertyValue.String(),
+		InterestRate:   interestRate,
+		TermYears:      int32(termYears),
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return fromApplicationMessage(application)
+}
+
+func (c *GRPCClient) Read(ctx context.Context, id uuid.UUID) (MortgageApplication, error) {
+	application, err := invoke(ctx, c, c.applications.GetApplication, &applicationsv1.GetApplicationRequest{Id: id.String()})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return fromApplicationMessage(application)
+}
+
+// Cancel cancels a pending or approved application; cancelling twice is not an error
+func (c *GRPCClient) Cancel(ctx context.Context, id uuid.UUID, decision Decision) (MortgageApplication, error) {
+	application, err := invoke(ctx, c, c.applications.CancelApplication, &applicationsv1.CancelApplicationRequest{
+		Id:        id.String(),
+		DecidedBy: decision.DecidedBy,
+		Reason:    decision.Reason,
+		Version:   int32(decision.Version),
+	})
+	if err != nil {
+		return MortgageApplication{}, err
+	}
+	return fromApplicationMessage(application)
+}
+
+func fromApplicationMessage(message *applicationsv1.Application) (MortgageApplication, error) {
+	id, err := uuid.Parse(message.GetId())
+	if err != nil {
+		return MortgageApplication{}, fmt.Errorf("invalid application id %q: %w", message.GetId(), err)
+	}
+	customerId, err := uuid.Parse(message.GetCustomerId())
+	if err != nil {
+		return MortgageApplication{}, fmt.Errorf("invalid customer_id %q: %w", message.GetCustomerId(), err)
+	}
+	loanAmount, err := decimal.NewFromString(message.GetLoanAmount())
+	if err != nil {
+		return MortgageApplication{}, fmt.Errorf("invalid loan_amount %q: %w", message.GetLoanAmount(), err)
+	}
+	propertyValue, err := decimal.NewFromString(message.GetPropertyValue())
+	if err != nil {
+		return MortgageApplication{}, fmt.Errorf("invalid property_value %q: %w", message.GetPropertyValue(), err)
+	}
+	application := MortgageApplication{
+		Id:            id,
+		CustomerId:    customerId,
+		LoanAmount:    loanAmount,
+		PropertyValue: propertyValue,
+		InterestRate:  message.GetInterestRate(),
+		TermYears:     int(message.GetTermYears()),
+		Status:        message.GetStatus(),
+		DecidedAt:     fromOptionalTimestamp(message.GetDecidedAt()),
+		Version:       int(message.GetVersion()),
+		CreatedAt:     fromTimestamp(message.GetCreatedAt()),
+		ModifiedAt:    fromTimestamp(message.GetModifiedAt()),
+	}
+	if decidedBy := message.GetDecidedBy(); decidedBy != "" {
+		application.DecidedBy = &decidedBy
+	}
+	if reason := message.GetReason(); reason != "" {
+		application.Reason = &reason
+	}
+	return application, nil
+}
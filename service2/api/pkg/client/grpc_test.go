@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"service2/api/pkg/pb/applicationsv1"
+)
+
+// applicationServer creates the application it is asked for and fails cancels with
+// cancelErr, keeping the last request and its metadata
+type applicationServer struct {
+	applicationsv1.UnimplementedApplicationServiceServer
+	created   *applicationsv1.CreateApplicationRequest
+	md        metadata.MD
+	cancelErr error
+}
+
+func (s *applicationServer) CreateApplication(ctx context.Context, req *applicationsv1.CreateApplicationRequest) (*applicationsv1.Application, error) {
+	s.created = req
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	return &applicationsv1.Application{
+		Id: uuid.NewString(), CustomerId: req.GetCustomerId(), LoanAmount: req.GetLoanAmount(), PropertyValue: req.GetPropertyValue(),
+		InterestRate: req.GetInterestRate(), TermYears: req.GetTermYears(), Status: "pending", Version: 1,
+	}, nil
+}
+
+func (s *applicationServer) CancelApplication(ctx context.Context, req *applicationsv1.CancelApplicationRequest) (*applicationsv1.Application, error) {
+	_ = grpc.SetHeader(ctx, metadata.Pairs(metadataRequestID, "req-1"))
+	return nil, s.cancelErr
+}
+
+func dialApplications(t *testing.T, server *applicationServer) *GRPCClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	applicationsv1.RegisterApplicationServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGRPCClient(conn)
+}
+
+func TestGRPCClient_CreateIdempotent(t *testing.T) {
+	server := &applicationServer{}
+	client := dialApplications(t, server).
+		WithBearerToken("t0ken").
+		WithTraceContextFrom(func(ctx context.Context) TraceContext { return TraceContext{CorrelationID: "saga-1"} })
+
+	customerId := uuid.New()
+	application, err := client.CreateIdempotent(context.Background(), "saga-1:application", customerId,
+		decimal.RequireFromString("300000.00"), decimal.RequireFromString("450000"), 5.5, 30)
+	if err != nil {
+		t.Fatalf("CreateIdempotent failed: %v", err)
+	}
+	if server.created.GetIdempotencyKey() != "saga-1:application" || server.created.GetLoanAmount() != "300000" {
+		t.Errorf("Unexpected request %v", server.created)
+	}
+	if application.CustomerId != customerId || !application.LoanAmount.Equal(decimal.NewFromInt(300000)) ||
+		application.Status != "pending" || application.DecidedBy != nil || application.DecidedAt != nil {
+		t.Errorf("Unexpected application %+v", application)
+	}
+	if got := server.md.Get("authorization"); len(got) != 1 || got[0] != "Bearer t0ken" {
+		t.Errorf("Expected the bearer token in the metadata, got %v", server.md)
+	}
+	if got := server.md.Get("x-correlation-id"); len(got) != 1 || got[0] != "saga-1" {
+		t.Errorf("Expected the correlation ID in the metadata, got %v", server.md)
+	}
+}
+
+func TestGRPCClient_ErrorsMatchREST(t *testing.T) {
+	cases := map[codes.Code]int{
+		codes.NotFound:           http.StatusNotFound,
+		codes.FailedPrecondition: http.StatusConflict,
+		codes.InvalidArgument:    http.StatusBadRequest,
+		codes.Unavailable:        http.StatusServiceUnavailable,
+	}
+	for code, want := range cases {
+		client := dialApplications(t, &applicationServer{cancelErr: status.Error(code, "refused")})
+		_, err := client.Cancel(context.Background(), uuid.New(), Decision{DecidedBy: "saga"})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != want || apiErr.Message != "refused" || apiErr.RequestID != "req-1" {
+			t.Errorf("Expected %v to be a %d APIError, got %#v", code, want, err)
+		}
+	}
+}
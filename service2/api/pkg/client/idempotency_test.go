@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testKey struct{}
+
+func TestWithIdempotencyKeyFrom(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.Header.Get(headerIdempotencyKey))
+	}))
+	defer server.Close()
+	c := NewClient(server.URL).WithIdempotencyKeyFrom(func(ctx context.Context) string {
+		key, _ := ctx.Value(testKey{}).(string)
+		return key
+	})
+
+	ctx := context.WithValue(context.Background(), testKey{}, "saga-1:CreateCustomer")
+	requests := []struct {
+		ctx    context.Context
+		method string
+		key    string
+	}{
+		{ctx, http.MethodPost, ""},
+		{ctx, http.MethodPost, "explicit"},
+		{ctx, http.MethodGet, ""},
+		{context.Background(), http.MethodPost, ""},
+	}
+	for _, r := range requests {
+		req, _ := http.NewRequestWithContext(r.ctx, r.method, server.URL, nil)
+		if r.key != "" {
+			req.Header.Set(headerIdempotencyKey, r.key)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	want := []string{"POST saga-1:CreateCustomer", "POST explicit", "GET ", "POST "}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("Expected requests %q, got %q", want, got)
+		}
+	}
+}
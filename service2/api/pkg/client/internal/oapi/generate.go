@@ -0,0 +1,9 @@
+package oapi
+
+// The document is written by the service itself, so regenerating after a route or
+// a request or response type changes keeps the client in step with the handlers:
+//
+//	go generate ./api/pkg/client/...
+//
+//go:generate go run ../../../../cmd/openapi -o openapi.json
+//go:generate go run github.com/deepmap/oapi-codegen/cmd/oapi-codegen@v1.12.4 -generate types,client -package oapi -o oapi.gen.go openapi.json
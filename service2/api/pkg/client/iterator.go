@@ -0,0 +1,131 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Iterator walks a listing one item at a time, fetching pages as it goes, so callers
+// need no paging loop of their own:
+//
+//	it := c.ListCustomers(ctx, filter)
+//	for it.Next() {
+//		customer := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// It follows the Link rel="next" header of cursor-paginated listings and otherwise
+// pages by offset until a page comes back short.
+type Iterator[T any] struct {
+	ctx    context.Context
+	client *Client
+	// pageRequest builds the request for the page of limit items at offset
+	pageRequest func(limit, offset int) (*http.Request, error)
+	limit       int
+	offset      int
+	next        string // the next page's URL from a Link header
+	done        bool
+	page        []T
+	value       T
+	err         error
+}
+
+func newIterator[T any](ctx context.Context, c *Client, limit, offset int, pageRequest func(limit, offset int) (*http.Request, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, client: c, pageRequest: pageRequest, limit: limit, offset: offset}
+}
+
+// pageSize is the limit to page by: the requested one, or the largest page the
+// service serves when none was requested or more than that
+func pageSize(limit, max int) int {
+	if limit <= 0 || limit > max {
+		return max
+	}
+	return limit
+}
+
+// Next advances to the next item, fetching the next page when the current one is
+// used up. It returns false after the last item or on an error; see Err.
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.err = it.fetch()
+	}
+	it.value, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Value returns the item Next advanced to
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, or nil once every item was read
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// fetch reads the next page into it.page and works out where the one after starts
+func (it *Iterator[T]) fetch() error {
+	var req *http.Request
+	var err error
+	if it.next != "" {
+		req, err = http.NewRequest(http.MethodGet, it.next, nil)
+	} else {
+		req, err = it.pageRequest(it.limit, it.offset)
+	}
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(it.ctx)
+	resp, err := it.client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	var page []T
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return err
+	}
+
+	next := nextLink(req.URL, resp.Header)
+	switch {
+	case next != "":
+		it.next = next
+	case it.next != "" || len(page) < it.limit:
+		// The last linked page, or a short page that leaves nothing after it
+		it.done = true
+	default:
+		it.offset += len(page)
+	}
+	it.page = page
+	return nil
+}
+
+// nextLink returns the absolute URL of the Link rel="next" in header, resolved
+// against the request's URL, or "" when there is none
+func nextLink(request *url.URL, header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+			ref, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+			if err != nil {
+				continue
+			}
+			return request.ResolveReference(ref).String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// pagedServer lists total items limit at a time, by offset or, with cursors set,
+// by handing out the next page's URL in a Link header
+func pagedServer(t *testing.T, total int, cursors bool) (*httptest.Server, []MortgageApplication, *[]string) {
+	all := make([]MortgageApplication, total)
+	for i := range all {
+		all[i] = MortgageApplication{Id: uuid.New()}
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		if cursors {
+			offset, _ = strconv.Atoi(query.Get("cursor"))
+		}
+		end := min(offset+limit, total)
+		if cursors && end < total {
+			next := r.URL.Path + "?cursor=" + strconv.Itoa(end) + "&limit=" + strconv.Itoa(limit)
+			w.Header().Set("Link", "<"+next+`>; rel="next"`)
+		}
+		_ = json.NewEncoder(w).Encode(all[offset:end])
+	}))
+	t.Cleanup(server.Close)
+	return server, all, &queries
+}
+
+// expectAll reads it to the end and checks it returned want, in order
+func expectAll(t *testing.T, it *Iterator[MortgageApplication], want []MortgageApplication) {
+	t.Helper()
+	var got []uuid.UUID
+	for it.Next() {
+		got = append(got, it.Value().Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d items, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i].Id {
+			t.Errorf("Expected item %d to be %s, got %s", i, want[i].Id, got[i])
+		}
+	}
+}
+
+func TestListApplications_PagesByOffset(t *testing.T) {
+	server, all, queries := pagedServer(t, 5, false)
+	expectAll(t, NewClient(server.URL).ListApplications(context.Background(), ApplicationFilter{Limit: 2}), all)
+	want := []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"}
+	if len(*queries) != len(want) {
+		t.Fatalf("Expected queries %v, got %v", want, *queries)
+	}
+	for i := range want {
+		if (*queries)[i] != want[i] {
+			t.Errorf("Expected query %d to be %q, got %q", i, want[i], (*queries)[i])
+		}
+	}
+}
+
+func TestListApplications_FollowsNextLink(t *testing.T) {
+	server, all, queries := pagedServer(t, 4, true)
+	expectAll(t, NewClient(server.URL).ListApplications(context.Background(), ApplicationFilter{Limit: 2}), all)
+	if len(*queries) != 2 || (*queries)[1] != "cursor=2&limit=2" {
+		t.Errorf("Expected the second page to be fetched from the Link header, got %v", *queries)
+	}
+}
+
+func TestListApplications_StopsOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+	it := NewClient(server.URL).ListApplications(context.Background(), ApplicationFilter{})
+	if it.Next() || !IsStatus(it.Err(), http.StatusBadRequest) {
+		t.Errorf("Expected the 400 to stop the iteration, got %v", it.Err())
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequestMetrics describes a request the client made, for a MetricsRecorder
+type RequestMetrics struct {
+	Method string
+	// Endpoint is the request path with its IDs replaced by :id, e.g.
+	// /v1/customers/:id, so requests to the same endpoint are counted together
+	Endpoint string
+	// Status is the response status, or 0 when no response arrived
+	Status   int
+	Duration time.Duration
+	// Err is why no response arrived, nil when one did
+	Err error
+}
+
+// Failed reports whether the request got no response or a server error
+func (m RequestMetrics) Failed() bool {
+	return m.Err != nil || m.Status >= http.StatusInternalServerError
+}
+
+// MetricsRecorder records the requests a client makes, e.g. as Prometheus or expvar
+// metrics. RecordRequest is called once per request, after the response headers
+// arrived or the request failed, from the goroutine making it.
+type MetricsRecorder interface {
+	RecordRequest(ctx context.Context, request RequestMetrics)
+}
+
+// WithMetrics reports every request to recorder. Requests resent by WithRetry are
+// reported once, with the time spent on every attempt, when WithMetrics is called
+// after it, and once per attempt otherwise.
+func (c *Client) WithMetrics(recorder MetricsRecorder) *Client {
+	c.httpClient.Transport = metrics{recorder: recorder, next: c.httpClient.Transport}
+	return c
+}
+
+// metrics times requests sent with next and reports them to recorder
+type metrics struct {
+	recorder MetricsRecorder
+	next     http.RoundTripper
+}
+
+func (t metrics) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	request := RequestMetrics{Method: req.Method, Endpoint: endpoint(req.URL.Path), Duration: time.Since(start), Err: err}
+	if resp != nil {
+		request.Status = resp.StatusCode
+	}
+	t.recorder.RecordRequest(req.Context(), request)
+	return resp, err
+}
+
+// endpoint replaces the UUIDs in path with :id
+func endpoint(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if _, err := uuid.Parse(segment); err == nil {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Retry bounds how often WithRetry resends a request that failed on a transient error
+type Retry struct {
+	Attempts       int           // including the first; 1 or less sends requests once
+	InitialBackoff time.Duration // doubled after each failed attempt
+	MaxBackoff     time.Duration
+	// IdempotentPosts also retries POSTs carrying an Idempotency-Key header, which
+	// the service answers with the original result instead of creating a duplicate
+	IdempotentPosts bool
+	// MaxRetryAfter caps the wait a 429 or 503's Retry-After header asks for; 0
+	// caps it at MaxBackoff
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetry rides out a service restart or a dropped connection in about a second,
+// and a rate limit in up to five seconds per attempt
+var DefaultRetry = Retry{Attempts: 4, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second,
+	MaxRetryAfter: 5 * time.Second}
+
+// WithRetry resends GETs and DELETEs, and with retry.IdempotentPosts POSTs carrying
+// an Idempotency-Key, that fail with a 5xx, a reset connection or a timeout, backing
+// off exponentially between attempts. A blip then costs a short wait rather than a
+// compensated saga. Any request the service throttled with a 429 is resent too,
+// since it was turned away before it was acted on. A 429 or 503 with a Retry-After
+// header is resent after the wait it asks for, up to retry.MaxRetryAfter. Other
+// requests are sent once; the request's context still bounds every attempt and wait.
+func (c *Client) WithRetry(retry Retry) *Client {
+	c.httpClient.Transport = retrying{retry: retry, next: c.httpClient.Transport}
+	return c
+}
+
+// retrying resends retryable requests with next until one succeeds or the attempts
+// are used up
+type retrying struct {
+	retry Retry
+	next  http.RoundTripper
+}
+
+func (t retrying) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if t.retry.Attempts <= 1 || req.Body != nil && req.GetBody == nil {
+		return next.RoundTrip(req)
+	}
+
+	idempotent := t.idempotent(req)
+	backoff := t.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := next.RoundTrip(req)
+		retryable := throttled(resp, err) || idempotent && transient(resp, err)
+		if attempt >= t.retry.Attempts || !retryable || req.Context().Err() != nil {
+			return resp, err
+		}
+		wait := backoff
+		if after, ok := retryAfter(resp); ok {
+			wait = min(max(after, backoff), t.maxRetryAfter())
+		}
+		if resp != nil {
+			// Drain the body so the connection can be reused for the next attempt
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, t.retry.MaxBackoff)
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// idempotent reports whether req is safe to send more than once
+func (t retrying) idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return t.retry.IdempotentPosts && req.Header.Get(headerIdempotencyKey) != ""
+	}
+	return false
+}
+
+// maxRetryAfter is the longest a Retry-After header may make an attempt wait
+func (t retrying) maxRetryAfter() time.Duration {
+	if t.retry.MaxRetryAfter > 0 {
+		return t.retry.MaxRetryAfter
+	}
+	return t.retry.MaxBackoff
+}
+
+// rewind returns req with a fresh copy of its body to send again
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
+
+// transient reports whether an attempt failed in a way a later attempt may not: a
+// server error, a connection reset or closed by the server, or a timeout
+func transient(resp *http.Response, err error) bool {
+	if err == nil {
+		return resp.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// throttled reports whether the service turned a request away with a 429, which it
+// does before acting on it, so any request may be sent again
+func throttled(resp *http.Response, err error) bool {
+	return err == nil && resp.StatusCode == http.StatusTooManyRequests
+}
+
+// retryAfter returns the wait a 429 or 503's Retry-After header asks for, in seconds
+// or as a date, and whether there was one
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,153 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetry = Retry{Attempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// flakyServer fails the first failures requests with a 503 and then echoes the
+// request body; it returns the number of requests it received
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func send(t *testing.T, client *http.Client, method, url, body string, header http.Header) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestWithRetry_RetriesIdempotentRequests(t *testing.T) {
+	for _, method := range []string{http.MethodGet, http.MethodDelete} {
+		server, requests := flakyServer(t, 2)
+		c := NewClient(server.URL).WithRetry(testRetry)
+		if resp := send(t, c.httpClient, method, server.URL, "", nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: expected the third attempt to succeed, got %d", method, resp.StatusCode)
+		}
+		if got := requests.Load(); got != 3 {
+			t.Errorf("%s: expected 3 attempts, got %d", method, got)
+		}
+	}
+}
+
+func TestWithRetry_GivesUpAfterAttempts(t *testing.T) {
+	server, requests := flakyServer(t, 5)
+	c := NewClient(server.URL).WithRetry(testRetry)
+	if resp := send(t, c.httpClient, http.MethodGet, server.URL, "", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected the last 503 to be returned, got %d", resp.StatusCode)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestWithRetry_PostsOnlyWithIdempotencyKeyAndOptIn(t *testing.T) {
+	keyed := http.Header{headerIdempotencyKey: {"key-1"}}
+	cases := []struct {
+		name     string
+		retry    Retry
+		header   http.Header
+		attempts int32
+	}{
+		{"no opt-in", testRetry, keyed, 1},
+		{"no key", Retry{Attempts: 3, IdempotentPosts: true}, nil, 1},
+		{"key and opt-in", Retry{Attempts: 3, InitialBackoff: time.Millisecond, IdempotentPosts: true}, keyed, 2},
+	}
+	for _, tc := range cases {
+		server, requests := flakyServer(t, 1)
+		c := NewClient(server.URL).WithRetry(tc.retry)
+		resp := send(t, c.httpClient, http.MethodPost, server.URL, `{"name":"Jane"}`, tc.header)
+		if got := requests.Load(); got != tc.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", tc.name, tc.attempts, got)
+		}
+		if tc.attempts == 2 {
+			if body, _ := io.ReadAll(resp.Body); string(body) != `{"name":"Jane"}` {
+				t.Errorf("%s: expected the retry to resend the body, got %q", tc.name, body)
+			}
+		}
+	}
+}
+
+// throttlingServer answers the first request with status and a Retry-After of
+// retryAfter, and the rest with a 200; it returns the times requests arrived
+func throttlingServer(t *testing.T, status int, retryAfter string) (*httptest.Server, *[]time.Time) {
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrivals = append(arrivals, time.Now())
+		if len(arrivals) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(status)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &arrivals
+}
+
+func TestWithRetry_WaitsOutRetryAfterUpToCap(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		server, arrivals := throttlingServer(t, status, "1")
+		retry := Retry{Attempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRetryAfter: 50 * time.Millisecond}
+		c := NewClient(server.URL).WithRetry(retry)
+		if resp := send(t, c.httpClient, http.MethodGet, server.URL, "", nil); resp.StatusCode != http.StatusOK {
+			t.Errorf("%d: expected the retry to succeed, got %d", status, resp.StatusCode)
+		}
+		if len(*arrivals) != 2 {
+			t.Fatalf("%d: expected 2 attempts, got %d", status, len(*arrivals))
+		}
+		if wait := (*arrivals)[1].Sub((*arrivals)[0]); wait < 50*time.Millisecond || wait > 900*time.Millisecond {
+			t.Errorf("%d: expected the 1s Retry-After to be capped at 50ms, waited %v", status, wait)
+		}
+	}
+}
+
+func TestWithRetry_ResendsThrottledPosts(t *testing.T) {
+	server, arrivals := throttlingServer(t, http.StatusTooManyRequests, "0")
+	c := NewClient(server.URL).WithRetry(testRetry)
+	if resp := send(t, c.httpClient, http.MethodPost, server.URL, `{"name":"Jane"}`, nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the throttled POST to be resent, got %d", resp.StatusCode)
+	}
+	if len(*arrivals) != 2 {
+		t.Errorf("Expected 2 attempts, got %d", len(*arrivals))
+	}
+
+	server, arrivals = throttlingServer(t, http.StatusServiceUnavailable, "0")
+	c = NewClient(server.URL).WithRetry(testRetry)
+	if resp := send(t, c.httpClient, http.MethodPost, server.URL, `{"name":"Jane"}`, nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected a POST's 503 to be returned, got %d", resp.StatusCode)
+	}
+	if len(*arrivals) != 1 {
+		t.Errorf("Expected 1 attempt, got %d", len(*arrivals))
+	}
+}
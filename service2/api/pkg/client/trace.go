@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// headerTraceParent is the W3C Trace Context header the services continue traces from
+	headerTraceParent = "traceparent"
+	// headerCorrelationID ties the requests of one business operation together in the logs
+	headerCorrelationID = "X-Correlation-ID"
+)
+
+// TraceContext is what WithTraceContextFrom sends with a request: a W3C traceparent
+// such as 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01 and a correlation ID
+type TraceContext struct {
+	TraceParent   string
+	CorrelationID string
+}
+
+// WithTraceContextFrom sends the trace context traceOf returns for a request's context
+// in the traceparent and X-Correlation-ID headers, so the service continues the
+// caller's trace and logs the correlation ID. Empty fields are left out.
+func (c *Client) WithTraceContextFrom(traceOf func(ctx context.Context) TraceContext) *Client {
+	c.httpClient.Transport = traceHeaders{traceOf: traceOf, next: c.httpClient.Transport}
+	return c
+}
+
+// traceHeaders adds the trace context headers to requests before sending them with next
+type traceHeaders struct {
+	traceOf func(ctx context.Context) TraceContext
+	next    http.RoundTripper
+}
+
+func (t traceHeaders) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := t.traceOf(req.Context())
+	if trace.TraceParent != "" || trace.CorrelationID != "" {
+		req = req.Clone(req.Context())
+		if trace.TraceParent != "" {
+			req.Header.Set(headerTraceParent, trace.TraceParent)
+		}
+		if trace.CorrelationID != "" {
+			req.Header.Set(headerCorrelationID, trace.CorrelationID)
+		}
+	}
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
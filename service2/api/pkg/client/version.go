@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync/atomic"
+)
+
+// APIVersion is the REST API version the client was generated against. Every request
+// names it in the Api-Version header.
+const APIVersion = "v1"
+
+// headerAPIVersion matches versioning.HeaderVersion
+const headerAPIVersion = "Api-Version"
+
+// userAgent names the client's module and its version, as the binary was built with
+// it, and the Go version and platform, e.g.
+// "service1-client/v0.0.0 (go1.24.5; linux/amd64)"
+var userAgent = buildUserAgent()
+
+func buildUserAgent() string {
+	pkg := reflect.TypeOf(Client{}).PkgPath()
+	module, _, _ := strings.Cut(pkg, "/")
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, m := range append([]*debug.Module{&info.Main}, info.Deps...) {
+			if m.Path != "" && (pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/")) {
+				module = m.Path
+				if m.Version != "" {
+					version = m.Version
+				}
+				break
+			}
+		}
+	}
+	return fmt.Sprintf("%s-client/%s (%s; %s/%s)", module, version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+// WithUserAgent names the calling product, e.g. "saga-client/1.4", ahead of the
+// client's own User-Agent, so the services' logs tell callers apart
+func (c *Client) WithUserAgent(product string) *Client {
+	c.versions.userAgent = product + " " + userAgent
+	return c
+}
+
+// WithVersionMismatch calls report for every response served by an API version
+// other than APIVersion, or marked deprecated, with the version that served it, so
+// a saga notices it is running against a mixed-version deployment
+func (c *Client) WithVersionMismatch(report func(ctx context.Context, served string, deprecated bool)) *Client {
+	c.versions.report = report
+	return c
+}
+
+// ServerVersion returns the API version the service last answered with, or "" until
+// a response named one
+func (c *Client) ServerVersion() string {
+	served, _ := c.versions.served.Load().(string)
+	return served
+}
+
+// versionHeaders sends the User-Agent and Api-Version headers with next and notes
+// the version each response was served by
+type versionHeaders struct {
+	userAgent string
+	report    func(ctx context.Context, served string, deprecated bool)
+	served    atomic.Value // string
+	next      http.RoundTripper
+}
+
+func (t *versionHeaders) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	req.Header.Set(headerAPIVersion, APIVersion)
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	served, deprecated := resp.Header.Get(headerAPIVersion), resp.Header.Get("Deprecation") != ""
+	if served != "" {
+		t.served.Store(served)
+	}
+	if t.report != nil && (served != "" && served != APIVersion || deprecated) {
+		t.report(req.Context(), served, deprecated)
+	}
+	return resp, nil
+}
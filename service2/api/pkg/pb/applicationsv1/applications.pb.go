@@ -0,0 +1,470 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: applications/v1/applications.proto
+
+package applicationsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Application struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CustomerId    string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	LoanAmount    string                 `protobuf:"bytes,3,opt,name=loan_amount,json=loanAmount,proto3" json:"loan_amount,omitempty"`
+	PropertyValue string                 `protobuf:"bytes,4,opt,name=property_value,json=propertyValue,proto3" json:"property_value,omitempty"`
+	InterestRate  float64                `protobuf:"fixed64,5,opt,name=interest_rate,json=interestRate,proto3" json:"interest_rate,omitempty"`
+	TermYears     int32                  `protobuf:"varint,6,opt,name=term_years,json=termYears,proto3" json:"term_years,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	DecidedBy     string                 `protobuf:"bytes,8,opt,name=decided_by,json=decidedBy,proto3" json:"decided_by,omitempty"`
+	DecidedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=decided_at,json=decidedAt,proto3" json:"decided_at,omitempty"`
+	Reason        string                 `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	Version       int32                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ModifiedAt    *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Application) Reset() {
+	*x = Application{}
+	mi := &file_applications_v1_applications_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Application) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Application) ProtoMessage() {}
+
+func (x *Application) ProtoReflect() protoreflect.Message {
+	mi := &file_applications_v1_applications_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Application.ProtoReflect.Descriptor instead.
+func (*Application) Descriptor() ([]byte, []int) {
+	return file_applications_v1_applications_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Application) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Application) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Application) GetLoanAmount() string {
+	if x != nil {
+		return x.LoanAmount
+	}
+	return ""
+}
+
+func (x *Application) GetPropertyValue() string {
+	if x != nil {
+		return x.PropertyValue
+	}
+	return ""
+}
+
+func (x *Application) GetInterestRate() float64 {
+	if x != nil {
+		return x.InterestRate
+	}
+	return 0
+}
+
+func (x *Application) GetTermYears() int32 {
+	if x != nil {
+		return x.TermYears
+	}
+	return 0
+}
+
+func (x *Application) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Application) GetDecidedBy() string {
+	if x != nil {
+		return x.DecidedBy
+	}
+	return ""
+}
+
+func (x *Application) GetDecidedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DecidedAt
+	}
+	return nil
+}
+
+func (x *Application) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Application) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Application) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Application) GetModifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModifiedAt
+	}
+	return nil
+}
+
+type CreateApplicationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId     string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	LoanAmount     string                 `protobuf:"bytes,2,opt,name=loan_amount,json=loanAmount,proto3" json:"loan_amount,omitempty"`
+	PropertyValue  string                 `protobuf:"bytes,3,opt,name=property_value,json=propertyValue,proto3" json:"property_value,omitempty"`
+	InterestRate   float64                `protobuf:"fixed64,4,opt,name=interest_rate,json=interestRate,proto3" json:"interest_rate,omitempty"`
+	TermYears      int32                  `protobuf:"varint,5,opt,name=term_years,json=termYears,proto3" json:"term_years,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateApplicationRequest) Reset() {
+	*x = CreateApplicationRequest{}
+	mi := &file_applications_v1_applications_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApplicationRequest) ProtoMessage() {}
+
+func (x *CreateApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_applications_v1_applications_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApplicationRequest.ProtoReflect.Descriptor instead.
+func (*CreateApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_applications_v1_applications_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateApplicationRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreateApplicationRequest) GetLoanAmount() string {
+	if x != nil {
+		return x.LoanAmount
+	}
+	return ""
+}
+
+func (x *CreateApplicationRequest) GetPropertyValue() string {
+	if x != nil {
+		return x.PropertyValue
+	}
+	return ""
+}
+
+func (x *CreateApplicationRequest) GetInterestRate() float64 {
+	if x != nil {
+		return x.InterestRate
+	}
+	return 0
+}
+
+func (x *CreateApplicationRequest) GetTermYears() int32 {
+	if x != nil {
+		return x.TermYears
+	}
+	return 0
+}
+
+func (x *CreateApplicationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type GetApplicationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetApplicationRequest) Reset() {
+	*x = GetApplicationRequest{}
+	mi := &file_applications_v1_applications_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetApplicationRequest) ProtoMessage() {}
+
+func (x *GetApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_applications_v1_applications_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetApplicationRequest.ProtoReflect.Descriptor instead.
+func (*GetApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_applications_v1_applications_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetApplicationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelApplicationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DecidedBy     string                 `protobuf:"bytes,2,opt,name=decided_by,json=decidedBy,proto3" json:"decided_by,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelApplicationRequest) Reset() {
+	*x = CancelApplicationRequest{}
+	mi := &file_applications_v1_applications_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelApplicationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelApplicationRequest) ProtoMessage() {}
+
+func (x *CancelApplicationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_applications_v1_applications_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelApplicationRequest.ProtoReflect.Descriptor instead.
+func (*CancelApplicationRequest) Descriptor() ([]byte, []int) {
+	return file_applications_v1_applications_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelApplicationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelApplicationRequest) GetDecidedBy() string {
+	if x != nil {
+		return x.DecidedBy
+	}
+	return ""
+}
+
+func (x *CancelApplicationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelApplicationRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_applications_v1_applications_proto protoreflect.FileDescriptor
+
+const file_applications_v1_applications_proto_rawDesc = "" +
+	"\n" +
+	"\"applications/v1/applications.proto\x12\x0fapplications.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x03\n" +
+	"\vApplication\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12\x1f\n" +
+	"\vloan_amount\x18\x03 \x01(\tR\n" +
+	"loanAmount\x12%\n" +
+	"\x0eproperty_value\x18\x04 \x01(\tR\rpropertyValue\x12#\n" +
+	"\rinterest_rate\x18\x05 \x01(\x01R\finterestRate\x12\x1d\n" +
+	"\n" +
+	"term_years\x18\x06 \x01(\x05R\ttermYears\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"decided_by\x18\b \x01(\tR\tdecidedBy\x129\n" +
+	"\n" +
+	"decided_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdecidedAt\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x12\x18\n" +
+	"\aversion\x18\v \x01(\x05R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vmodified_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"modifiedAt\"\xf0\x01\n" +
+	"\x18CreateApplicationRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x1f\n" +
+	"\vloan_amount\x18\x02 \x01(\tR\n" +
+	"loanAmount\x12%\n" +
+	"\x0eproperty_value\x18\x03 \x01(\tR\rpropertyValue\x12#\n" +
+	"\rinterest_rate\x18\x04 \x01(\x01R\finterestRate\x12\x1d\n" +
+	"\n" +
+	"term_years\x18\x05 \x01(\x05R\ttermYears\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\"'\n" +
+	"\x15GetApplicationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"{\n" +
+	"\x18CancelApplicationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
+	"\n" +
+	"decided_by\x18\x02 \x01(\tR\tdecidedBy\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion2\xa8\x02\n" +
+	"\x12ApplicationService\x12\\\n" +
+	"\x11CreateApplication\x12).applications.v1.CreateApplicationRequest\x1a\x1c.applications.v1.Application\x12V\n" +
+	"\x0eGetApplication\x12&.applications.v1.GetApplicationRequest\x1a\x1c.applications.v1.Application\x12\\\n" +
+	"\x11CancelApplication\x12).applications.v1.CancelApplicationRequest\x1a\x1c.applications.v1.ApplicationB$Z\"service2/api/pkg/pb/applicationsv1b\x06proto3"
+
+var (
+	file_applications_v1_applications_proto_rawDescOnce sync.Once
+	file_applications_v1_applications_proto_rawDescData []byte
+)
+
+func file_applications_v1_applications_proto_rawDescGZIP() []byte {
+	file_applications_v1_applications_proto_rawDescOnce.Do(func() {
+		file_applications_v1_applications_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_applications_v1_applications_proto_rawDesc), len(file_applications_v1_applications_proto_rawDesc)))
+	})
+	return file_applications_v1_applications_proto_rawDescData
+}
+
+var file_applications_v1_applications_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_applications_v1_applications_proto_goTypes = []any{
+	(*Application)(nil),              // 0: applications.v1.Application
+	(*CreateApplicationRequest)(nil), // 1: applications.v1.CreateApplicationRequest
+	(*GetApplicationRequest)(nil),    // 2: applications.v1.GetApplicationRequest
+	(*CancelApplicationRequest)(nil), // 3: applications.v1.CancelApplicationRequest
+	(*timestamppb.Timestamp)(nil),    // 4: google.protobuf.Timestamp
+}
+var file_applications_v1_applications_proto_depIdxs = []int32{
+	4, // 0: applications.v1.Application.decided_at:type_name -> google.protobuf.Timestamp
+	4, // 1: applications.v1.Application.created_at:type_name -> google.protobuf.Timestamp
+	4, // 2: applications.v1.Application.modified_at:type_name -> google.protobuf.Timestamp
+	1, // 3: applications.v1.ApplicationService.CreateApplication:input_type -> applications.v1.CreateApplicationRequest
+	2, // 4: applications.v1.ApplicationService.GetApplication:input_type -> applications.v1.GetApplicationRequest
+	3, // 5: applications.v1.ApplicationService.CancelApplication:input_type -> applications.v1.CancelApplicationRequest
+	0, // 6: applications.v1.ApplicationService.CreateApplication:output_type -> applications.v1.Application
+	0, // 7: applications.v1.ApplicationService.GetApplication:output_type -> applications.v1.Application
+	0, // 8: applications.v1.ApplicationService.CancelApplication:output_type -> applications.v1.Application
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_applications_v1_applications_proto_init() }
+func file_applications_v1_applications_proto_init() {
+	if File_applications_v1_applications_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_applications_v1_applications_proto_rawDesc), len(file_applications_v1_applications_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_applications_v1_applications_proto_goTypes,
+		DependencyIndexes: file_applications_v1_applications_proto_depIdxs,
+		MessageInfos:      file_applications_v1_applications_proto_msgTypes,
+	}.Build()
+	File_applications_v1_applications_proto = out.File
+	file_applications_v1_applications_proto_goTypes = nil
+	file_applications_v1_applications_proto_depIdxs = nil
+}
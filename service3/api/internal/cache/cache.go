@@ -0,0 +1,63 @@
+// Package cache keeps hot reads, such as the loans the saga and dashboards poll, out
+// of the database. Values are stored as JSON under a key until they expire or the
+// code changing them deletes the key.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache is a read-through cache. Callers treat its errors as misses: the database
+// stays the source of truth and a failing cache only costs the extra reads.
+type Cache interface {
+	// Get decodes the value stored under key into dest and reports whether there was one
+	Get(ctx context.Context, key string, dest any) (bool, error)
+	Set(ctx context.Context, key string, value any) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Nop caches nothing; it stands in when no Redis is configured
+type Nop struct{}
+
+func (Nop) Get(ctx context.Context, key string, dest any) (bool, error) { return false, nil }
+func (Nop) Set(ctx context.Context, key string, value any) error        { return nil }
+func (Nop) Delete(ctx context.Context, keys ...string) error            { return nil }
+
+// Redis stores values in Redis for ttl. The ttl bounds how long changes made outside
+// the invalidating code paths, such as the background jobs, take to show.
+type Redis struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+func NewRedis(client redis.UniversalClient, ttl time.Duration) *Redis {
+	return &Redis{client: client, ttl: ttl}
+}
+
+func (r *Redis) Get(ctx context.Context, key string, dest any) (bool, error) {
+	data, err := r.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, dest)
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, key, data, r.ttl).Err()
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
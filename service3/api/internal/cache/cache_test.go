@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type entry struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), time.Minute)
+	ctx := context.Background()
+
+	var got entry
+	if found, err := c.Get(ctx, "loan:1", &got); found || err != nil {
+		t.Fatalf("Expected a miss, got found %v, err %v", found, err)
+	}
+
+	if err := c.Set(ctx, "loan:1", entry{Name: "one", Count: 1}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if found, err := c.Get(ctx, "loan:1", &got); !found || err != nil || got != (entry{Name: "one", Count: 1}) {
+		t.Errorf("Expected the stored entry, got %+v (found %v, err %v)", got, found, err)
+	}
+	if ttl := server.TTL("loan:1"); ttl != time.Minute {
+		t.Errorf("Expected a one minute TTL, got %v", ttl)
+	}
+
+	if err := c.Delete(ctx, "loan:1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if found, _ := c.Get(ctx, "loan:1", &got); found {
+		t.Errorf("Expected the entry to be gone after Delete")
+	}
+
+	server.Set("loan:2", "not json")
+	if _, err := c.Get(ctx, "loan:2", &got); err == nil {
+		t.Errorf("Expected an error for a value that is not JSON")
+	}
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// fakeTx records how the transaction ended; the embedded interface panics on queries
+type fakeTx struct {
+	pgx.Tx
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	if !t.committed {
+		t.rolledBack = true
+	}
+	return nil
+}
+
+type fakeDB struct {
+	DB
+	begun []*fakeTx
+}
+
+func (db *fakeDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx := &fakeTx{}
+	db.begun = append(db.begun, tx)
+	return tx, nil
+}
+
+// boundRepos stands in for a service's repositories bound with WithTx
+type boundRepos struct {
+	tx pgx.Tx
+}
+
+func TestUnitOfWork_Do(t *testing.T) {
+	db := &fakeDB{}
+	uow := NewUnitOfWork(db, func(tx pgx.Tx) boundRepos { return boundRepos{tx} })
+	ctx := context.Background()
+
+	err := uow.Do(ctx, func(repos boundRepos) error {
+		if repos.tx != db.begun[0] {
+			t.Errorf("Expected the repositories bound to the open transaction")
+		}
+		return nil
+	})
+	if err != nil || !db.begun[0].committed {
+		t.Errorf("Expected a commit, got err %v", err)
+	}
+
+	failure := errors.New("balance update failed")
+	err = uow.Do(ctx, func(repos boundRepos) error { return failure })
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the callback's error, got %v", err)
+	}
+	if db.begun[1].committed || !db.begun[1].rolledBack {
+		t.Errorf("Expected a rollback without commit")
+	}
+}
+
+func TestUnitOfWork_BeginTx(t *testing.T) {
+	db := &fakeDB{}
+	uow := NewUnitOfWork(db, func(tx pgx.Tx) boundRepos { return boundRepos{tx} })
+	ctx := context.Background()
+
+	tx, err := uow.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer tx.Rollback(ctx)
+	if tx.Repos.tx != db.begun[0] {
+		t.Errorf("Expected the repositories bound to the returned transaction")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	tx.Rollback(ctx)
+	if db.begun[0].rolledBack {
+		t.Errorf("Expected Rollback after Commit to leave the transaction committed")
+	}
+}
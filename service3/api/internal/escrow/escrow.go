@@ -0,0 +1,245 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/database"
+	"service3/api/internal/loans"
+	"service3/api/internal/tenant"
+)
+
+// Disbursement types: what the escrow account pays on the borrower's behalf
+const (
+	TypeTax       = "tax"
+	TypeInsurance = "insurance"
+)
+
+// Account holds the escrow portions of a loan's payments until they are disbursed
+// for property tax and insurance. A loan has at most one escrow account.
+type Account struct {
+	Id         uuid.UUID       `json:"id"`
+	LoanId     uuid.UUID       `json:"loan_id"`
+	Balance    decimal.Decimal `json:"balance"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ModifiedAt time.Time       `json:"modified_at"`
+}
+
+// Disbursement is a tax or insurance bill paid from an escrow account
+type Disbursement struct {
+	Id          uuid.UUID       `json:"id"`
+	AccountId   uuid.UUID       `json:"account_id"`
+	LoanId      uuid.UUID       `json:"loan_id"`
+	Type        string          `json:"type"` // tax, insurance
+	Amount      decimal.Decimal `json:"amount"`
+	Payee       string          `json:"payee"`
+	DisbursedAt time.Time       `json:"disbursed_at"`
+}
+
+var (
+	// ErrNotFound is returned when the loan has no escrow account
+	ErrNotFound = errors.New("escrow account not found")
+	// ErrLoanNotFound is returned when opening an escrow account for a loan that does not exist
+	ErrLoanNotFound = errors.New("loan not found")
+	// ErrAccountExists is returned when opening a second escrow account for a loan
+	ErrAccountExists = errors.New("loan already has an escrow account")
+	// ErrInvalidDisbursement is returned when a disbursement's type, amount or payee is invalid
+	ErrInvalidDisbursement = errors.New("invalid escrow disbursement")
+	// ErrInsufficientFunds is returned when a disbursement is more than the account's balance
+	ErrInsufficientFunds = errors.New("escrow balance is insufficient for the disbursement")
+)
+
+// Validate checks the disbursement's type, amount and payee
+func (d Disbursement) Validate() error {
+	if d.Type != TypeTax && d.Type != TypeInsurance {
+		return fmt.Errorf("%w: type must be %s or %s", ErrInvalidDisbursement, TypeTax, TypeInsurance)
+	}
+	if !d.Amount.IsPositive() {
+		return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidDisbursement)
+	}
+	if d.Payee == "" {
+		return fmt.Errorf("%w: payee is required", ErrInvalidDisbursement)
+	}
+	return nil
+}
+
+type Repository interface {
+	Open(ctx context.Context, loanId uuid.UUID) (Account, error)
+	Read(ctx context.Context, loanId uuid.UUID) (Account, error)
+	Disburse(ctx context.Context, disbursement Disbursement) (Disbursement, error)
+	GetDisbursements(ctx context.Context, loanId uuid.UUID) ([]Disbursement, error)
+}
+
+type Service interface {
+	Open(ctx context.Context, loanId uuid.UUID) (Account, error)
+	Read(ctx context.Context, loanId uuid.UUID) (Account, error)
+	Disburse(ctx context.Context, disbursement Disbursement) (Disbursement, error)
+	GetDisbursements(ctx context.Context, loanId uuid.UUID) ([]Disbursement, error)
+}
+
+const (
+	accountColumns      = "id, loan_id, balance, created_at, modified_at"
+	disbursementColumns = "id, account_id, loan_id, type, amount, payee, disbursed_at"
+)
+
+// scanAccount scans a row selected with accountColumns
+func scanAccount(row pgx.Row) (Account, error) {
+	var account Account
+	err := row.Scan(&account.Id, &account.LoanId, &account.Balance, &account.CreatedAt, &account.ModifiedAt)
+	return account, err
+}
+
+// scanDisbursement scans a row selected with disbursementColumns
+func scanDisbursement(row pgx.Row) (Disbursement, error) {
+	var disbursement Disbursement
+	err := row.Scan(&disbursement.Id, &disbursement.AccountId, &disbursement.LoanId, &disbursement.Type,
+		&disbursement.Amount, &disbursement.Payee, &disbursement.DisbursedAt)
+	return disbursement, err
+}
+
+type EscrowRepository struct {
+	db database.DB
+}
+
+func NewEscrowRepository(pool *pgxpool.Pool) *EscrowRepository {
+	return &EscrowRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *EscrowRepository) WithTx(tx pgx.Tx) *EscrowRepository {
+	return &EscrowRepository{tx}
+}
+
+// Open creates an empty escrow account for the loan
+func (r *EscrowRepository) Open(ctx context.Context, loanId uuid.UUID) (Account, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM loans WHERE id = $1 AND tenant_id = $2)",
+		loanId, tenant.FromContext(ctx)).Scan(&exists)
+	if err != nil {
+		return Account{}, err
+	}
+	if !exists {
+		return Account{}, ErrLoanNotFound
+	}
+
+	sql := `INSERT INTO escrow_accounts (id, loan_id, balance, created_at, modified_at)
+		VALUES ($1, $2, 0, NOW(), NOW())
+		RETURNING ` + accountColumns
+	account, err := scanAccount(r.db.QueryRow(ctx, sql, uuid.New(), loanId))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return Account{}, ErrAccountExists
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+func (r *EscrowRepository) Read(ctx context.Context, loanId uuid.UUID) (Account, error) {
+	sql := "SELECT " + accountColumns + " FROM escrow_accounts WHERE loan_id = $1 AND " + loans.InTenant(2)
+	account, err := scanAccount(r.db.QueryRow(ctx, sql, loanId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Account{}, ErrNotFound
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+// Disburse pays a bill from the loan's escrow account and reduces its balance
+func (r *EscrowRepository) Disburse(ctx context.Context, disbursement Disbursement) (Disbursement, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return Disbursement{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := "SELECT " + accountColumns + " FROM escrow_accounts WHERE loan_id = $1 AND " + loans.InTenant(2) + " FOR UPDATE"
+	account, err := scanAccount(tx.QueryRow(ctx, sql, disbursement.LoanId, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Disbursement{}, ErrNotFound
+	}
+	if err != nil {
+		return Disbursement{}, err
+	}
+	if account.Balance.LessThan(disbursement.Amount) {
+		return Disbursement{}, fmt.Errorf("%w: balance is %s", ErrInsufficientFunds, account.Balance.StringFixed(2))
+	}
+
+	sql = "UPDATE escrow_accounts SET balance = balance - $1, modified_at = NOW() WHERE id = $2"
+	if _, err := tx.Exec(ctx, sql, disbursement.Amount, account.Id); err != nil {
+		return Disbursement{}, err
+	}
+	sql = `INSERT INTO escrow_disbursements (id, account_id, loan_id, type, amount, payee, disbursed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + disbursementColumns
+	created, err := scanDisbursement(tx.QueryRow(ctx, sql,
+		disbursement.Id,
+		account.Id,
+		disbursement.LoanId,
+		disbursement.Type,
+		disbursement.Amount,
+		disbursement.Payee,
+	))
+	if err != nil {
+		return Disbursement{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return Disbursement{}, err
+	}
+	return created, nil
+}
+
+// GetDisbursements lists the loan's escrow disbursements, most recent first
+func (r *EscrowRepository) GetDisbursements(ctx context.Context, loanId uuid.UUID) ([]Disbursement, error) {
+	sql := "SELECT " + disbursementColumns + " FROM escrow_disbursements WHERE loan_id = $1 AND " + loans.InTenant(2) +
+		" ORDER BY disbursed_at DESC"
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	disbursements := []Disbursement{}
+	for rows.Next() {
+		disbursement, err := scanDisbursement(rows)
+		if err != nil {
+			return nil, err
+		}
+		disbursements = append(disbursements, disbursement)
+	}
+	return disbursements, rows.Err()
+}
+
+type EscrowService struct {
+	repo Repository
+}
+
+func NewEscrowService(repo Repository) *EscrowService {
+	return &EscrowService{repo}
+}
+
+func (s *EscrowService) Open(ctx context.Context, loanId uuid.UUID) (Account, error) {
+	return s.repo.Open(ctx, loanId)
+}
+
+func (s *EscrowService) Read(ctx context.Context, loanId uuid.UUID) (Account, error) {
+	return s.repo.Read(ctx, loanId)
+}
+
+func (s *EscrowService) Disburse(ctx context.Context, disbursement Disbursement) (Disbursement, error) {
+	return s.repo.Disburse(ctx, disbursement)
+}
+
+func (s *EscrowService) GetDisbursements(ctx context.Context, loanId uuid.UUID) ([]Disbursement, error) {
+	return s.repo.GetDisbursements(ctx, loanId)
+}
@@ -0,0 +1,26 @@
+package escrow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDisbursement_Validate(t *testing.T) {
+	valid := Disbursement{Type: TypeTax, Amount: decimal.NewFromInt(1200), Payee: "County Treasurer"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected a valid disbursement, got %v", err)
+	}
+
+	invalid := []Disbursement{
+		{Type: "hoa", Amount: decimal.NewFromInt(100), Payee: "Association"},
+		{Type: TypeInsurance, Amount: decimal.NewFromInt(0), Payee: "Insurer"},
+		{Type: TypeInsurance, Amount: decimal.NewFromInt(100)},
+	}
+	for _, disbursement := range invalid {
+		if err := disbursement.Validate(); !errors.Is(err, ErrInvalidDisbursement) {
+			t.Errorf("Expected ErrInvalidDisbursement for %+v, got %v", disbursement, err)
+		}
+	}
+}
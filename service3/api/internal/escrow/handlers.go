@@ -0,0 +1,105 @@
+package escrow
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewEscrowHandler(service Service) Handler {
+	return Handler{service}
+}
+
+// Open creates the loan's escrow account with a zero balance
+func (h *Handler) Open(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	account, err := h.service.Open(c.Request().Context(), loanId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, account)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	account, err := h.service.Read(c.Request().Context(), loanId)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) Disburse(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	disbursement := new(Disbursement)
+	if err := c.Bind(disbursement); err != nil {
+		return err
+	}
+	disbursement.Type = strings.ToLower(strings.TrimSpace(disbursement.Type))
+	disbursement.Payee = strings.TrimSpace(disbursement.Payee)
+	if err := disbursement.Validate(); err != nil {
+		return httpError(err)
+	}
+
+	disbursement.Id = uuid.New()
+	disbursement.LoanId = loanId
+	created, err := h.service.Disburse(c.Request().Context(), *disbursement)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) GetDisbursements(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	disbursements, err := h.service.GetDisbursements(c.Request().Context(), loanId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, disbursements)
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrLoanNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidDisbursement) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrAccountExists) || errors.Is(err, ErrInsufficientFunds) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,10 @@
+package escrow
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/loans/:loanId/escrow", handler.Open)
+	g.GET("/loans/:loanId/escrow", handler.Read)
+	g.POST("/loans/:loanId/escrow/disbursements", handler.Disburse)
+	g.GET("/loans/:loanId/escrow/disbursements", handler.GetDisbursements)
+}
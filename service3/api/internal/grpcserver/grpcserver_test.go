@@ -0,0 +1,183 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"service3/api/internal/auth"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/pkg/pb/servicingv1"
+)
+
+// fakeLoans and fakePaymentRepository implement the calls the gRPC servers make; the
+// embedded interfaces panic on anything else
+type fakeLoans struct {
+	loans.Service
+	created []loans.Loan
+}
+
+func (f *fakeLoans) Create(ctx context.Context, loan loans.Loan) (loans.Loan, error) {
+	f.created = append(f.created, loan)
+	return loan, nil
+}
+
+func (f *fakeLoans) Read(ctx context.Context, id uuid.UUID) (loans.Loan, error) {
+	for _, loan := range f.created {
+		if loan.Id == id {
+			return loan, nil
+		}
+	}
+	return loans.Loan{}, loans.ErrNotFound
+}
+
+type fakePaymentRepository struct {
+	payments.Repository
+	created []payments.Payment
+}
+
+func (f *fakePaymentRepository) Create(ctx context.Context, payment payments.Payment) (payments.Payment, error) {
+	f.created = append(f.created, payment)
+	return payment, nil
+}
+
+func (f *fakePaymentRepository) GetByLoanId(ctx context.Context, loanId uuid.UUID, filter payments.PaymentFilter) ([]payments.Payment, error) {
+	var found []payments.Payment
+	for _, payment := range f.created {
+		if payment.LoanId == loanId {
+			found = append(found, payment)
+		}
+	}
+	return found, nil
+}
+
+func dial(t *testing.T, loanService loans.Service, paymentService payments.Service) *grpc.ClientConn {
+	t.Helper()
+	server := New(auth.Config{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	servicingv1.RegisterLoanServiceServer(server, NewLoanServer(loanService))
+	servicingv1.RegisterPaymentServiceServer(server, NewPaymentServer(paymentService))
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestLoanServer(t *testing.T) {
+	client := servicingv1.NewLoanServiceClient(dial(t, &fakeLoans{}, nil))
+	ctx := context.Background()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	created, err := client.CreateLoan(ctx, &servicingv1.CreateLoanRequest{
+		CustomerId:         uuid.NewString(),
+		MortgageId:         uuid.NewString(),
+		LoanAmount:         "300000",
+		InterestRate:       5.5,
+		TermYears:          30,
+		MonthlyPayment:     "1703.37",
+		OutstandingBalance: "300000",
+		StartDate:          timestamppb.New(start),
+		MaturityDate:       timestamppb.New(start.AddDate(30, 0, 0)),
+	})
+	if err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+	if created.GetStatus() != loans.StatusActive || !created.GetStartDate().AsTime().Equal(start) {
+		t.Errorf("Unexpected loan: %v", created)
+	}
+
+	if _, err := client.GetLoan(ctx, &servicingv1.GetLoanRequest{Id: created.GetId()}); err != nil {
+		t.Errorf("GetLoan failed: %v", err)
+	}
+	_, err = client.GetLoan(ctx, &servicingv1.GetLoanRequest{Id: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	_, err = client.CreateLoan(ctx, &servicingv1.CreateLoanRequest{CustomerId: uuid.NewString(), MortgageId: uuid.NewString()})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument without amounts and dates, got %v", err)
+	}
+}
+
+func TestPaymentServer(t *testing.T) {
+	repo := &fakePaymentRepository{}
+	client := servicingv1.NewPaymentServiceClient(dial(t, nil, payments.NewPaymentService(repo)))
+	ctx := context.Background()
+	loanID := uuid.NewString()
+
+	created, err := client.CreatePayment(ctx, &servicingv1.CreatePaymentRequest{
+		LoanId:          loanID,
+		CustomerId:      uuid.NewString(),
+		PaymentAmount:   "1700",
+		PrincipalAmount: "400",
+		InterestAmount:  "1300",
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment failed: %v", err)
+	}
+	if created.GetPaymentType() != payments.TypeRegular || created.GetPaymentDate() == nil {
+		t.Errorf("Expected the service defaults, got %v", created)
+	}
+
+	list, err := client.ListLoanPayments(ctx, &servicingv1.ListLoanPaymentsRequest{LoanId: loanID})
+	if err != nil {
+		t.Fatalf("ListLoanPayments failed: %v", err)
+	}
+	if len(list.GetPayments()) != 1 || list.GetPayments()[0].GetId() != created.GetId() {
+		t.Errorf("Expected the created payment, got %v", list.GetPayments())
+	}
+
+	_, err = client.CreatePayment(ctx, &servicingv1.CreatePaymentRequest{LoanId: loanID, CustomerId: uuid.NewString(), PaymentAmount: "1,700"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a malformed amount, got %v", err)
+	}
+}
+
+func TestPaymentServer_DomainValidation(t *testing.T) {
+	client := servicingv1.NewPaymentServiceClient(dial(t, nil, payments.NewPaymentService(&fakePaymentRepository{})))
+
+	_, err := client.CreatePayment(context.Background(), &servicingv1.CreatePaymentRequest{
+		LoanId:          uuid.NewString(),
+		CustomerId:      uuid.NewString(),
+		PaymentAmount:   "100",
+		PrincipalAmount: "100",
+		PaymentDate:     timestamppb.New(time.Now().AddDate(1, 0, 0)),
+	})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument, got %v", err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	if len(fields) != 1 || fields[0] != "payment_date" {
+		t.Errorf("Expected a payment_date violation, got %v", fields)
+	}
+}
@@ -0,0 +1,132 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"service3/api/internal/loans"
+	"service3/api/internal/validation"
+	"service3/api/pkg/pb/servicingv1"
+)
+
+// loanSentinels mirror the loans handlers' httpError
+var loanSentinels = []sentinel{
+	{loans.ErrNotFound, codes.NotFound},
+	{loans.ErrInvalidModification, codes.InvalidArgument},
+	{loans.ErrInvalidBucket, codes.InvalidArgument},
+	{loans.ErrNotActive, codes.FailedPrecondition},
+}
+
+type LoanServer struct {
+	servicingv1.UnimplementedLoanServiceServer
+	service   loans.Service
+	validator *validation.Validator
+}
+
+func NewLoanServer(service loans.Service) *LoanServer {
+	return &LoanServer{service: service, validator: validation.New()}
+}
+
+func (s *LoanServer) CreateLoan(ctx context.Context, req *servicingv1.CreateLoanRequest) (*servicingv1.Loan, error) {
+	customerID, err := parseID("customer_id", req.GetCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	mortgageID, err := parseID("mortgage_id", req.GetMortgageId())
+	if err != nil {
+		return nil, err
+	}
+	loanAmount, err := parseAmount("loan_amount", req.GetLoanAmount())
+	if err != nil {
+		return nil, err
+	}
+	monthlyPayment, err := parseAmount("monthly_payment", req.GetMonthlyPayment())
+	if err != nil {
+		return nil, err
+	}
+	outstandingBalance, err := parseAmount("outstanding_balance", req.GetOutstandingBalance())
+	if err != nil {
+		return nil, err
+	}
+	loan := loans.Loan{
+		Id:                 uuid.New(),
+		CustomerId:         customerID,
+		MortgageId:         mortgageID,
+		LoanAmount:         loanAmount,
+		InterestRate:       req.GetInterestRate(),
+		TermYears:          int(req.GetTermYears()),
+		MonthlyPayment:     monthlyPayment,
+		OutstandingBalance: outstandingBalance,
+		Status:             loans.StatusActive,
+	}
+	if req.GetStartDate() != nil {
+		loan.StartDate = req.GetStartDate().AsTime()
+	}
+	if req.GetMaturityDate() != nil {
+		loan.MaturityDate = req.GetMaturityDate().AsTime()
+	}
+	if err := s.validator.Validate(loan); err != nil {
+		return nil, statusError(err, loanSentinels)
+	}
+	created, err := s.service.Create(ctx, loan)
+	if err != nil {
+		return nil, statusError(err, loanSentinels)
+	}
+	return toLoan(created), nil
+}
+
+func (s *LoanServer) GetLoan(ctx context.Context, req *servicingv1.GetLoanRequest) (*servicingv1.Loan, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	loan, err := s.service.Read(ctx, id)
+	if err != nil {
+		return nil, statusError(err, loanSentinels)
+	}
+	return toLoan(loan), nil
+}
+
+func (s *LoanServer) CancelLoan(ctx context.Context, req *servicingv1.CancelLoanRequest) (*servicingv1.Loan, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	cancellation := loans.Cancellation{
+		CancelledBy: strings.TrimSpace(req.GetCancelledBy()),
+		Reason:      strings.TrimSpace(req.GetReason()),
+	}
+	loan, err := s.service.Cancel(ctx, id, cancellation)
+	if err != nil {
+		return nil, statusError(err, loanSentinels)
+	}
+	return toLoan(loan), nil
+}
+
+func toLoan(loan loans.Loan) *servicingv1.Loan {
+	message := &servicingv1.Loan{
+		Id:                 loan.Id.String(),
+		CustomerId:         loan.CustomerId.String(),
+		MortgageId:         loan.MortgageId.String(),
+		LoanAmount:         loan.LoanAmount.String(),
+		InterestRate:       loan.InterestRate,
+		TermYears:          int32(loan.TermYears),
+		MonthlyPayment:     loan.MonthlyPayment.String(),
+		OutstandingBalance: loan.OutstandingBalance.String(),
+		Status:             loan.Status,
+		StartDate:          timestamp(loan.StartDate),
+		MaturityDate:       timestamp(loan.MaturityDate),
+		CancelledAt:        optionalTimestamp(loan.CancelledAt),
+		CreatedAt:          timestamp(loan.CreatedAt),
+		ModifiedAt:         timestamp(loan.ModifiedAt),
+	}
+	if loan.CancelledBy != nil {
+		message.CancelledBy = *loan.CancelledBy
+	}
+	if loan.CancellationReason != nil {
+		message.CancellationReason = *loan.CancellationReason
+	}
+	return message
+}
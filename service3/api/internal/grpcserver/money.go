@@ -0,0 +1,21 @@
+package grpcserver
+
+import (
+	"github.com/shopspring/decimal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// parseAmount parses a decimal string request field, named for the error. An empty
+// field is zero, like an amount left out of a JSON body.
+func parseAmount(field, value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, invalidArgument("invalid "+field, []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: "must be a decimal number"},
+		})
+	}
+	return amount, nil
+}
@@ -0,0 +1,136 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"service3/api/internal/payments"
+	"service3/api/internal/validation"
+	"service3/api/pkg/pb/servicingv1"
+)
+
+// paymentSentinels mirror the payments handlers' httpError
+var paymentSentinels = []sentinel{
+	{payments.ErrNotFound, codes.NotFound},
+	{payments.ErrLoanNotFound, codes.NotFound},
+	{payments.ErrInvalidFilter, codes.InvalidArgument},
+	{payments.ErrLoanNotActive, codes.FailedPrecondition},
+	{payments.ErrExceedsBalance, codes.FailedPrecondition},
+	{payments.ErrNotReversible, codes.FailedPrecondition},
+	{payments.ErrNoEscrowAccount, codes.FailedPrecondition},
+}
+
+type PaymentServer struct {
+	servicingv1.UnimplementedPaymentServiceServer
+	service   payments.Service
+	validator *validation.Validator
+}
+
+func NewPaymentServer(service payments.Service) *PaymentServer {
+	return &PaymentServer{service: service, validator: validation.New()}
+}
+
+func (s *PaymentServer) CreatePayment(ctx context.Context, req *servicingv1.CreatePaymentRequest) (*servicingv1.Payment, error) {
+	loanID, err := parseID("loan_id", req.GetLoanId())
+	if err != nil {
+		return nil, err
+	}
+	customerID, err := parseID("customer_id", req.GetCustomerId())
+	if err != nil {
+		return nil, err
+	}
+	payment := payments.Payment{LoanId: loanID, CustomerId: customerID, PaymentType: req.GetPaymentType()}
+	amounts := []struct {
+		field string
+		value string
+		dest  *decimal.Decimal
+	}{
+		{"payment_amount", req.GetPaymentAmount(), &payment.PaymentAmount},
+		{"principal_amount", req.GetPrincipalAmount(), &payment.PrincipalAmount},
+		{"interest_amount", req.GetInterestAmount(), &payment.InterestAmount},
+		{"escrow_amount", req.GetEscrowAmount(), &payment.EscrowAmount},
+	}
+	for _, amount := range amounts {
+		if *amount.dest, err = parseAmount(amount.field, amount.value); err != nil {
+			return nil, err
+		}
+	}
+	if req.GetPaymentDate() != nil {
+		payment.PaymentDate = req.GetPaymentDate().AsTime()
+	}
+	if err := s.validator.Validate(payment); err != nil {
+		return nil, paymentError(err)
+	}
+
+	payment.Id = uuid.New()
+	created, err := s.service.Create(ctx, payment)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+	return toPayment(created), nil
+}
+
+func (s *PaymentServer) GetPayment(ctx context.Context, req *servicingv1.GetPaymentRequest) (*servicingv1.Payment, error) {
+	id, err := parseID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.service.Read(ctx, id)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+	return toPayment(payment), nil
+}
+
+func (s *PaymentServer) ListLoanPayments(ctx context.Context, req *servicingv1.ListLoanPaymentsRequest) (*servicingv1.ListLoanPaymentsResponse, error) {
+	loanID, err := parseID("loan_id", req.GetLoanId())
+	if err != nil {
+		return nil, err
+	}
+	filter := payments.PaymentFilter{Limit: int(req.GetLimit()), Offset: int(req.GetOffset())}
+	list, err := s.service.GetByLoanId(ctx, loanID, filter)
+	if err != nil {
+		return nil, paymentError(err)
+	}
+	resp := &servicingv1.ListLoanPaymentsResponse{Payments: make([]*servicingv1.Payment, 0, len(list))}
+	for _, payment := range list {
+		resp.Payments = append(resp.Payments, toPayment(payment))
+	}
+	return resp, nil
+}
+
+// paymentError adds the domain rule violations from the service to statusError
+func paymentError(err error) error {
+	var validationErr *payments.ValidationError
+	if errors.As(err, &validationErr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Fields))
+		for _, field := range validationErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message})
+		}
+		return invalidArgument(validationErr.Error(), violations)
+	}
+	return statusError(err, paymentSentinels)
+}
+
+func toPayment(payment payments.Payment) *servicingv1.Payment {
+	message := &servicingv1.Payment{
+		Id:              payment.Id.String(),
+		LoanId:          payment.LoanId.String(),
+		CustomerId:      payment.CustomerId.String(),
+		PaymentAmount:   payment.PaymentAmount.String(),
+		PrincipalAmount: payment.PrincipalAmount.String(),
+		InterestAmount:  payment.InterestAmount.String(),
+		EscrowAmount:    payment.EscrowAmount.String(),
+		PaymentDate:     timestamp(payment.PaymentDate),
+		PaymentType:     payment.PaymentType,
+		CreatedAt:       timestamp(payment.CreatedAt),
+	}
+	if payment.ReversalOf != nil {
+		message.ReversalOf = payment.ReversalOf.String()
+	}
+	return message
+}
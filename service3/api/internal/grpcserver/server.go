@@ -0,0 +1,239 @@
+// Package grpcserver serves the gRPC API defined in proto/ next to the REST API. Its
+// servers adapt the same domain services as the echo handlers, and its interceptors
+// apply the REST middleware's request logging, authentication and rate limiting.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"service3/api/internal/auth"
+	"service3/api/internal/ratelimit"
+	"service3/api/internal/tenant"
+	"service3/api/internal/validation"
+)
+
+// Metadata keys, the lower-cased HTTP headers of the REST API
+const (
+	requestIDKey     = "x-request-id"
+	apiKeyKey        = "x-api-key"
+	authorizationKey = "authorization"
+	tenantKey        = "x-tenant-id"
+	retryAfterKey    = "retry-after"
+)
+
+// New returns a server with the interceptors installed; register the services on it.
+// A nil limiter turns rate limiting off.
+func New(config auth.Config, limiter *ratelimit.Limiter, logger *slog.Logger) *grpc.Server {
+	return grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logging(logger),
+		recovery(logger),
+		authenticate(config),
+		tenancy(),
+		rateLimit(limiter),
+	))
+}
+
+// logging logs each call like logging.Middleware, keeping or generating its request id
+func logging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		requestID := first(ctx, requestIDKey)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		attrs := []slog.Attr{
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("request_id", requestID),
+		}
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(ctx, level, "grpc request", attrs...)
+		return resp, err
+	}
+}
+
+// recovery turns a panicking handler into an Internal error, like middleware.Recover
+func recovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("grpc handler panicked", "method", info.FullMethod, "panic", r)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// authenticate applies auth.Middleware's rules: Get and List methods need the read
+// role, every other method write
+func authenticate(config auth.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !config.Enabled() {
+			return handler(ctx, req)
+		}
+		principal, err := config.Authenticate(first(ctx, apiKeyKey), first(ctx, authorizationKey))
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+		}
+		if role := requiredRole(info.FullMethod); !principal.HasRole(role) {
+			return nil, status.Errorf(codes.PermissionDenied, "the %s role is required", role)
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// tenancy applies tenant.Middleware's rules to the x-tenant-id metadata
+func tenancy() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		principal, _ := auth.FromContext(ctx)
+		id, err := tenant.Resolve(principal.Tenant, first(ctx, tenantKey))
+		if errors.Is(err, tenant.ErrInvalid) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return handler(tenant.WithTenant(ctx, id), req)
+	}
+}
+
+func requiredRole(fullMethod string) string {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if strings.HasPrefix(method, "Get") || strings.HasPrefix(method, "List") {
+		return auth.RoleRead
+	}
+	return auth.RoleWrite
+}
+
+// rateLimit shares the REST API's buckets, answering ResourceExhausted with a
+// retry-after header in seconds
+func rateLimit(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if limiter == nil {
+			return handler(ctx, req)
+		}
+		var ip string
+		if p, ok := peer.FromContext(ctx); ok {
+			ip, _, _ = net.SplitHostPort(p.Addr.String())
+		}
+		allowed, retryAfter := limiter.Allow(ratelimit.Key(ctx, ip))
+		if !allowed {
+			seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+			_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterKey, seconds))
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", seconds)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// first returns the first value of an incoming metadata key
+func first(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// sentinel maps a domain error to the status code its REST status corresponds to
+type sentinel struct {
+	err  error
+	code codes.Code
+}
+
+// statusError converts err to a gRPC status the way apierror.Handler renders REST
+// errors: validation failures list their fields, known domain errors keep their
+// message, and anything else is logged and reported as Internal without details
+func statusError(err error, sentinels []sentinel) error {
+	var validationErr *validation.Error
+	if errors.As(err, &validationErr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(validationErr.Fields))
+		for _, field := range validationErr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field.Field, Description: field.Message})
+		}
+		return invalidArgument(validationErr.Error(), violations)
+	}
+	for _, known := range sentinels {
+		if errors.Is(err, known.err) {
+			return status.Error(known.code, err.Error())
+		}
+	}
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return status.Error(codes.NotFound, "not found")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	slog.Error("grpc internal error", "error", err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// invalidArgument returns an InvalidArgument status with a BadRequest detail
+func invalidArgument(message string, violations []*errdetails.BadRequest_FieldViolation) error {
+	st := status.New(codes.InvalidArgument, message)
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// timestamp converts t, leaving unset times unset rather than sending year 1
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalTimestamp converts an optional time
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// parseID parses a UUID request field, named for the error
+func parseID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument("invalid "+field, []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: "must be a UUID"},
+		})
+	}
+	return id, nil
+}
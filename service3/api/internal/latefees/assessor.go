@@ -0,0 +1,75 @@
+package latefees
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"service3/api/internal/loans"
+	"service3/api/internal/schedules"
+)
+
+// Assessor charges a late fee on every installment of an active loan that is still
+// unpaid after the policy's grace period, adding it to the loan's late fees due
+type Assessor struct {
+	pool     *pgxpool.Pool
+	policy   Policy
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewAssessor creates an assessor.
+func NewAssessor(pool *pgxpool.Pool, policy Policy, logger *log.Logger) *Assessor {
+	return &Assessor{
+		pool:     pool,
+		policy:   policy,
+		interval: time.Hour,
+		logger:   logger,
+	}
+}
+
+// Run assesses late fees every interval until ctx is cancelled
+func (a *Assessor) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if assessed, err := a.Assess(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			a.logger.Printf("late fee assessment: %v", err)
+		} else if assessed > 0 {
+			a.logger.Printf("late fee assessment: assessed %d late fees", assessed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// assessSQL charges each late, unpaid installment of an active loan once and adds the
+// fees to their loans
+const assessSQL = `WITH assessed AS (
+		INSERT INTO late_fees (id, loan_id, due_payment_id, amount, status, assessed_at)
+		SELECT gen_random_uuid(), d.loan_id, d.id, $1, $2, NOW()
+		FROM due_payments d JOIN loans l ON l.id = d.loan_id
+		WHERE d.status = $3 AND d.due_date < $4 AND l.status = $5
+		ON CONFLICT (due_payment_id) DO NOTHING
+		RETURNING loan_id, amount
+	), applied AS (
+		UPDATE loans l SET late_fees_due = l.late_fees_due + a.total, modified_at = NOW()
+		FROM (SELECT loan_id, SUM(amount) AS total FROM assessed GROUP BY loan_id) a
+		WHERE l.id = a.loan_id
+	)
+	SELECT COUNT(*) FROM assessed`
+
+// Assess charges the late fees due on asOf and returns how many it charged
+func (a *Assessor) Assess(ctx context.Context, asOf time.Time) (int, error) {
+	var assessed int
+	err := a.pool.QueryRow(ctx, assessSQL, a.policy.Amount, StatusAssessed, schedules.DueStatusDue,
+		a.policy.Cutoff(asOf), loans.StatusActive).Scan(&assessed)
+	if err != nil {
+		return 0, err
+	}
+	return assessed, nil
+}
@@ -0,0 +1,87 @@
+package latefees
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewLateFeeHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid late fee id")
+	if err != nil {
+		return err
+	}
+
+	fee, err := h.service.Read(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fee)
+}
+
+// Waive waives an assessed late fee; the reason is required
+func (h *Handler) Waive(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid late fee id")
+	if err != nil {
+		return err
+	}
+	waiver := new(Waiver)
+	if err := c.Bind(waiver); err != nil {
+		return err
+	}
+	waiver.WaivedBy = strings.TrimSpace(waiver.WaivedBy)
+	waiver.Reason = strings.TrimSpace(waiver.Reason)
+	if waiver.Reason == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "reason is required to waive a late fee")
+	}
+
+	fee, err := h.service.Waive(c.Request().Context(), id, *waiver)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, fee)
+}
+
+func (h *Handler) GetByLoanId(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	fees, err := h.service.GetByLoanId(c.Request().Context(), loanId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, fees)
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrAlreadyWaived) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,209 @@
+package latefees
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/cache"
+	"service3/api/internal/database"
+	"service3/api/internal/loans"
+	"service3/api/internal/tenant"
+)
+
+// Late fee statuses. An assessed fee is owed on the loan until it is waived.
+const (
+	StatusAssessed = "assessed"
+	StatusWaived   = "waived"
+)
+
+// LateFee is charged on a loan when a scheduled installment is still unpaid after the
+// grace period. Each installment is charged at most once.
+type LateFee struct {
+	Id           uuid.UUID       `json:"id"`
+	LoanId       uuid.UUID       `json:"loan_id"`
+	DuePaymentId uuid.UUID       `json:"due_payment_id"`
+	Amount       decimal.Decimal `json:"amount"`
+	Status       string          `json:"status"`
+	AssessedAt   time.Time       `json:"assessed_at"`
+	WaivedAt     *time.Time      `json:"waived_at"`
+	WaivedBy     *string         `json:"waived_by"`
+	WaivedReason *string         `json:"waived_reason"`
+}
+
+// Waiver records who waived a late fee and why
+type Waiver struct {
+	WaivedBy string `json:"waived_by"`
+	Reason   string `json:"reason"`
+}
+
+// Policy is how long an installment may stay unpaid and what is charged after that
+type Policy struct {
+	GraceDays int
+	Amount    decimal.Decimal
+}
+
+// DefaultPolicy charges 50 on installments unpaid 15 days after their due date
+var DefaultPolicy = Policy{GraceDays: 15, Amount: decimal.NewFromInt(50)}
+
+// Cutoff returns the due date installments must be older than to be late on asOf
+func (p Policy) Cutoff(asOf time.Time) time.Time {
+	year, month, day := asOf.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -p.GraceDays)
+}
+
+var (
+	// ErrNotFound is returned when no late fee exists with the requested ID
+	ErrNotFound = errors.New("late fee not found")
+	// ErrAlreadyWaived is returned when waiving a fee that was already waived
+	ErrAlreadyWaived = errors.New("late fee is already waived")
+)
+
+type Repository interface {
+	Read(ctx context.Context, id uuid.UUID) (LateFee, error)
+	Waive(ctx context.Context, id uuid.UUID, waiver Waiver) (LateFee, error)
+	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]LateFee, error)
+}
+
+type Service interface {
+	Read(ctx context.Context, id uuid.UUID) (LateFee, error)
+	Waive(ctx context.Context, id uuid.UUID, waiver Waiver) (LateFee, error)
+	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]LateFee, error)
+}
+
+const lateFeeColumns = "id, loan_id, due_payment_id, amount, status, assessed_at, waived_at, waived_by, waived_reason"
+
+// scanLateFee scans a row selected with lateFeeColumns
+func scanLateFee(row pgx.Row) (LateFee, error) {
+	var fee LateFee
+	err := row.Scan(&fee.Id, &fee.LoanId, &fee.DuePaymentId, &fee.Amount, &fee.Status, &fee.AssessedAt,
+		&fee.WaivedAt, &fee.WaivedBy, &fee.WaivedReason)
+	return fee, err
+}
+
+type LateFeeRepository struct {
+	db database.DB
+}
+
+func NewLateFeeRepository(pool *pgxpool.Pool) *LateFeeRepository {
+	return &LateFeeRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *LateFeeRepository) WithTx(tx pgx.Tx) *LateFeeRepository {
+	return &LateFeeRepository{tx}
+}
+
+func (r *LateFeeRepository) Read(ctx context.Context, id uuid.UUID) (LateFee, error) {
+	sql := "SELECT " + lateFeeColumns + " FROM late_fees WHERE id = $1 AND " + loans.InTenant(2)
+	fee, err := scanLateFee(r.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LateFee{}, ErrNotFound
+	}
+	if err != nil {
+		return LateFee{}, err
+	}
+	return fee, nil
+}
+
+// Waive waives an assessed fee and removes it from the loan's late fees due
+func (r *LateFeeRepository) Waive(ctx context.Context, id uuid.UUID, waiver Waiver) (LateFee, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return LateFee{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := "SELECT " + lateFeeColumns + " FROM late_fees WHERE id = $1 AND " + loans.InTenant(2) + " FOR UPDATE"
+	fee, err := scanLateFee(tx.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LateFee{}, ErrNotFound
+	}
+	if err != nil {
+		return LateFee{}, err
+	}
+	if fee.Status == StatusWaived {
+		return LateFee{}, ErrAlreadyWaived
+	}
+
+	sql = `UPDATE late_fees SET status = $1, waived_at = NOW(), waived_by = $2, waived_reason = $3
+		WHERE id = $4
+		RETURNING ` + lateFeeColumns
+	fee, err = scanLateFee(tx.QueryRow(ctx, sql, StatusWaived, nullIfEmpty(waiver.WaivedBy), waiver.Reason, id))
+	if err != nil {
+		return LateFee{}, err
+	}
+	sql = "UPDATE loans SET late_fees_due = GREATEST(late_fees_due - $1, 0), modified_at = NOW() WHERE id = $2"
+	if _, err := tx.Exec(ctx, sql, fee.Amount, fee.LoanId); err != nil {
+		return LateFee{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return LateFee{}, err
+	}
+	return fee, nil
+}
+
+// GetByLoanId lists the loan's late fees, most recent first
+func (r *LateFeeRepository) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]LateFee, error) {
+	sql := "SELECT " + lateFeeColumns + " FROM late_fees WHERE loan_id = $1 AND " + loans.InTenant(2) +
+		" ORDER BY assessed_at DESC"
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fees := []LateFee{}
+	for rows.Next() {
+		fee, err := scanLateFee(rows)
+		if err != nil {
+			return nil, err
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
+
+// nullIfEmpty stores an empty string as SQL NULL
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+type LateFeeService struct {
+	repo  Repository
+	cache cache.Cache
+}
+
+func NewLateFeeService(repo Repository) *LateFeeService {
+	return &LateFeeService{repo: repo, cache: cache.Nop{}}
+}
+
+// WithCache drops the fee's loan from c when a waiver changes its late_fees_due
+func (s *LateFeeService) WithCache(c cache.Cache) *LateFeeService {
+	s.cache = c
+	return s
+}
+
+func (s *LateFeeService) Read(ctx context.Context, id uuid.UUID) (LateFee, error) {
+	return s.repo.Read(ctx, id)
+}
+
+func (s *LateFeeService) Waive(ctx context.Context, id uuid.UUID, waiver Waiver) (LateFee, error) {
+	fee, err := s.repo.Waive(ctx, id, waiver)
+	if err != nil {
+		return LateFee{}, err
+	}
+	loans.Invalidate(ctx, s.cache, fee.LoanId)
+	return fee, nil
+}
+
+func (s *LateFeeService) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]LateFee, error) {
+	return s.repo.GetByLoanId(ctx, loanId)
+}
@@ -0,0 +1,31 @@
+package latefees
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestPolicy_Cutoff(t *testing.T) {
+	policy := Policy{GraceDays: 15, Amount: decimal.NewFromInt(50)}
+	asOf := time.Date(2025, 3, 20, 18, 45, 0, 0, time.UTC)
+
+	cutoff := policy.Cutoff(asOf)
+	if want := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC); !cutoff.Equal(want) {
+		t.Fatalf("Cutoff(%s) = %s, want %s", asOf, cutoff, want)
+	}
+	// An installment due on the 5th is late from the 21st, after 15 full days of grace
+	dueOn5th := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	if dueOn5th.Before(cutoff) {
+		t.Errorf("Installment due %s should still be in its grace period on %s", dueOn5th, asOf)
+	}
+	if !dueOn5th.Before(policy.Cutoff(asOf.AddDate(0, 0, 1))) {
+		t.Errorf("Installment due %s should be late the day after the grace period", dueOn5th)
+	}
+
+	noGrace := Policy{GraceDays: 0}
+	if got := noGrace.Cutoff(asOf); !got.Equal(time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected a zero grace period to cut off at the start of the day, got %s", got)
+	}
+}
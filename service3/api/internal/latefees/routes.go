@@ -0,0 +1,9 @@
+package latefees
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.GET("/loans/:loanId/late-fees", handler.GetByLoanId)
+	g.GET("/late-fees/:id", handler.Read)
+	g.POST("/late-fees/:id/waive", handler.Waive)
+}
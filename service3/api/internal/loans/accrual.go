@@ -0,0 +1,211 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/tenant"
+)
+
+// InterestFor returns the simple interest on balance at the annual ratePercent for
+// days, counting actual days over a 365-day year, rounded to cents
+func InterestFor(balance decimal.Decimal, ratePercent float64, days int) decimal.Decimal {
+	interest := balance.Mul(decimal.NewFromFloat(ratePercent)).Mul(decimal.NewFromInt(int64(days)))
+	return interest.Div(decimal.NewFromInt(100 * 365)).Round(2)
+}
+
+// Accrual is interest accrued on a loan for the days from PeriodStart up to but not
+// including PeriodEnd, on the balance and rate at the time it was computed
+type Accrual struct {
+	Id           uuid.UUID       `json:"id"`
+	LoanId       uuid.UUID       `json:"loan_id"`
+	PeriodStart  time.Time       `json:"period_start"`
+	PeriodEnd    time.Time       `json:"period_end"`
+	Days         int             `json:"days"`
+	Balance      decimal.Decimal `json:"balance"`
+	InterestRate float64         `json:"interest_rate"`
+	Amount       decimal.Decimal `json:"amount"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// accruedThrough returns the first day the loan has not accrued interest for
+func accruedThrough(loan Loan) time.Time {
+	if loan.InterestAccruedThrough != nil {
+		return *loan.InterestAccruedThrough
+	}
+	return startOfDay(loan.StartDate)
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween counts whole days from from to to, or 0 if to is not after from
+func daysBetween(from, to time.Time) int {
+	days := int(to.Sub(from).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
+
+// GetAccruals lists the loan's accruals, most recent first
+func (r *LoanRepository) GetAccruals(ctx context.Context, loanId uuid.UUID) ([]Accrual, error) {
+	sql := `SELECT id, loan_id, period_start, period_end, days, balance, interest_rate, amount, created_at
+		FROM loan_accruals WHERE loan_id = $1 AND ` + InTenant(2) + `
+		ORDER BY period_start DESC`
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accruals := []Accrual{}
+	for rows.Next() {
+		var accrual Accrual
+		err := rows.Scan(&accrual.Id, &accrual.LoanId, &accrual.PeriodStart, &accrual.PeriodEnd, &accrual.Days,
+			&accrual.Balance, &accrual.InterestRate, &accrual.Amount, &accrual.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		accruals = append(accruals, accrual)
+	}
+	return accruals, rows.Err()
+}
+
+// Accruer accrues daily interest on active loans, adding it to the loan's
+// accrued_interest and recording each run in loan_accruals. Days missed while the
+// service was down are caught up on the next run.
+type Accruer struct {
+	pool      *pgxpool.Pool
+	interval  time.Duration
+	batchSize int
+	logger    *log.Logger
+}
+
+// NewAccruer creates an accruer.
+func NewAccruer(pool *pgxpool.Pool, logger *log.Logger) *Accruer {
+	return &Accruer{
+		pool:      pool,
+		interval:  time.Hour,
+		batchSize: 100,
+		logger:    logger,
+	}
+}
+
+// Run accrues interest every interval until ctx is cancelled. Only whole days are
+// accrued, so each loan accrues once a day however often it runs.
+func (a *Accruer) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	for {
+		if accrued, err := a.AccrueThrough(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			a.logger.Printf("interest accrual: %v", err)
+		} else if accrued > 0 {
+			a.logger.Printf("interest accrual: accrued interest on %d loans", accrued)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccrueThrough accrues interest on every active loan for the whole days before asOf
+// it has not accrued yet and returns how many loans it accrued
+func (a *Accruer) AccrueThrough(ctx context.Context, asOf time.Time) (int, error) {
+	through := startOfDay(asOf)
+	accrued := 0
+	for {
+		ids, err := a.dueLoanIDs(ctx, through)
+		if err != nil {
+			return accrued, err
+		}
+
+		for _, id := range ids {
+			if err := a.accrueLoan(ctx, id, through); err != nil {
+				return accrued, err
+			}
+			accrued++
+		}
+		if len(ids) < a.batchSize {
+			return accrued, nil
+		}
+	}
+}
+
+// dueLoanIDs returns a batch of active loans that have not accrued interest up to through
+func (a *Accruer) dueLoanIDs(ctx context.Context, through time.Time) ([]uuid.UUID, error) {
+	sql := `SELECT id FROM loans
+		WHERE status = $1 AND COALESCE(interest_accrued_through, start_date::date) < $2
+		ORDER BY id
+		LIMIT $3`
+	rows, err := a.pool.Query(ctx, sql, StatusActive, through, a.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// accrueLoan accrues the loan's interest up to through in one transaction
+func (a *Accruer) accrueLoan(ctx context.Context, id uuid.UUID, through time.Time) error {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	loan, err := scanLoan(tx.QueryRow(ctx, "SELECT "+loanColumns+" FROM loans WHERE id = $1 FOR UPDATE", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil // deleted since it was selected
+	}
+	if err != nil {
+		return err
+	}
+	if err := accrue(ctx, tx, loan, through); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// accrue adds the interest the locked loan has accrued up to through at its current
+// rate, in the caller's transaction. Loans that are not active do not accrue.
+func accrue(ctx context.Context, tx pgx.Tx, loan Loan, through time.Time) error {
+	from := accruedThrough(loan)
+	days := daysBetween(from, through)
+	if loan.Status != StatusActive || days == 0 {
+		return nil
+	}
+
+	amount := InterestFor(loan.OutstandingBalance, loan.InterestRate, days)
+	sql := `INSERT INTO loan_accruals
+		(id, loan_id, period_start, period_end, days, balance, interest_rate, amount, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())`
+	_, err := tx.Exec(ctx, sql, uuid.New(), loan.Id, from, through, days, loan.OutstandingBalance, loan.InterestRate, amount)
+	if err != nil {
+		return err
+	}
+
+	sql = `UPDATE loans SET accrued_interest = accrued_interest + $1, interest_accrued_through = $2, modified_at = NOW()
+		WHERE id = $3`
+	_, err = tx.Exec(ctx, sql, amount, through, loan.Id)
+	return err
+}
@@ -0,0 +1,68 @@
+package loans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestInterestFor(t *testing.T) {
+	tests := []struct {
+		balance string
+		rate    float64
+		days    int
+		want    string
+	}{
+		{"365000", 5, 1, "50"},
+		{"365000", 5, 30, "1500"},
+		{"200000", 4.5, 1, "24.66"},
+		{"200000", 4.5, 0, "0"},
+		{"0", 4.5, 30, "0"},
+		{"1000.01", 3.25, 1, "0.09"},
+	}
+	for _, tt := range tests {
+		if got := InterestFor(decimal.RequireFromString(tt.balance), tt.rate, tt.days); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("InterestFor(%v, %v, %d) = %v, want %v", tt.balance, tt.rate, tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestNewPayoffQuote(t *testing.T) {
+	through := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	loan := Loan{
+		OutstandingBalance:     decimal.NewFromInt(365000),
+		InterestRate:           5,
+		Status:                 StatusActive,
+		StartDate:              time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		AccruedInterest:        decimal.NewFromInt(120),
+		InterestAccruedThrough: &through,
+	}
+
+	quote := NewPayoffQuote(loan, time.Date(2025, 3, 11, 16, 30, 0, 0, time.UTC))
+	if !quote.AsOf.Equal(time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected quote as of the start of the day, got %s", quote.AsOf)
+	}
+	// 120 already accrued plus 10 days at 50 a day
+	if !quote.AccruedInterest.Equal(decimal.NewFromInt(620)) || !quote.PerDiem.Equal(decimal.NewFromInt(50)) || !quote.PayoffAmount.Equal(decimal.NewFromInt(365620)) {
+		t.Errorf("Unexpected quote: %+v", quote)
+	}
+
+	loan.LateFeesDue = decimal.NewFromInt(50)
+	withFees := NewPayoffQuote(loan, time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC))
+	if !withFees.LateFeesDue.Equal(decimal.NewFromInt(50)) || !withFees.PayoffAmount.Equal(decimal.NewFromInt(365670)) {
+		t.Errorf("Expected late fees in the payoff amount, got %+v", withFees)
+	}
+	loan.LateFeesDue = decimal.Zero
+
+	stale := NewPayoffQuote(loan, through.AddDate(0, 0, -5))
+	if !stale.AccruedInterest.Equal(decimal.NewFromInt(120)) {
+		t.Errorf("Expected no interest before the accrued-through date, got %v", stale.AccruedInterest)
+	}
+
+	loan.InterestAccruedThrough = nil
+	fromStart := NewPayoffQuote(loan, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))
+	if !fromStart.AccruedInterest.Equal(decimal.NewFromInt(220)) {
+		t.Errorf("Expected accrual from the start date, got %v", fromStart.AccruedInterest)
+	}
+}
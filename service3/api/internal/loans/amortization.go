@@ -0,0 +1,81 @@
+package loans
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"service3/api/internal/pagination"
+)
+
+// Installment is one month of a loan's amortization schedule: Payment splits into the
+// Interest on the balance for the month and the Principal it repays, leaving Balance.
+type Installment struct {
+	Number    int             `json:"number"`
+	DueDate   time.Time       `json:"due_date"`
+	Payment   decimal.Decimal `json:"payment"`
+	Principal decimal.Decimal `json:"principal"`
+	Interest  decimal.Decimal `json:"interest"`
+	Balance   decimal.Decimal `json:"balance"`
+}
+
+// AmortizationFilter pages through an amortization schedule
+type AmortizationFilter struct {
+	Limit  int
+	Offset int
+}
+
+// normalize bounds the paging
+func (f *AmortizationFilter) normalize() {
+	f.Limit = pagination.ClampLimit(f.Limit)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+}
+
+// Amortize projects the loan's outstanding balance as of the asOf day forward at its
+// current rate and monthly payment. Installments fall due on the start date's day of
+// each month after asOf up to maturity, and the last one pays off whatever is left.
+// Loans that are not active have no schedule.
+func Amortize(loan Loan, asOf time.Time) []Installment {
+	schedule := []Installment{}
+	if loan.Status != StatusActive {
+		return schedule
+	}
+	asOf = startOfDay(asOf)
+	start, maturity := startOfDay(loan.StartDate), startOfDay(loan.MaturityDate)
+	rate := monthlyRate(loan.InterestRate)
+	balance := loan.OutstandingBalance
+
+	for month := 1; balance.IsPositive(); month++ {
+		due := start.AddDate(0, month, 0)
+		if due.After(maturity) && len(schedule) > 0 {
+			break
+		}
+		if !due.After(asOf) {
+			continue
+		}
+		interest := balance.Mul(rate).Round(2)
+		principal := loan.MonthlyPayment.Sub(interest)
+		if principal.GreaterThan(balance) || !due.Before(maturity) {
+			principal = balance
+		}
+		balance = balance.Sub(principal)
+		schedule = append(schedule, Installment{
+			Number:    len(schedule) + 1,
+			DueDate:   due,
+			Payment:   principal.Add(interest),
+			Principal: principal,
+			Interest:  interest,
+			Balance:   balance,
+		})
+	}
+	return schedule
+}
+
+// page returns the installments filter selects
+func (f AmortizationFilter) page(schedule []Installment) []Installment {
+	if f.Offset >= len(schedule) {
+		return []Installment{}
+	}
+	return schedule[f.Offset:min(f.Offset+f.Limit, len(schedule))]
+}
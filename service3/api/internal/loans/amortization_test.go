@@ -0,0 +1,74 @@
+package loans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAmortize_RepaysBalanceByMaturity(t *testing.T) {
+	loan := Loan{
+		InterestRate:       6,
+		MonthlyPayment:     MonthlyPayment(decimal.NewFromInt(200000), 6, 360),
+		OutstandingBalance: decimal.NewFromInt(200000),
+		Status:             StatusActive,
+		StartDate:          time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC),
+		MaturityDate:       time.Date(2050, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+	schedule := Amortize(loan, loan.StartDate)
+	if len(schedule) != 360 {
+		t.Fatalf("Expected 360 installments, got %d", len(schedule))
+	}
+
+	first, last := schedule[0], schedule[len(schedule)-1]
+	if !first.DueDate.Equal(time.Date(2020, 2, 15, 0, 0, 0, 0, time.UTC)) || !first.Interest.Equal(decimal.NewFromInt(1000)) ||
+		!first.Principal.Equal(decimal.RequireFromString("199.10")) {
+		t.Errorf("Unexpected first installment %+v", first)
+	}
+	if !last.DueDate.Equal(loan.MaturityDate) || !last.Balance.IsZero() {
+		t.Errorf("Expected the last installment to clear the balance at maturity, got %+v", last)
+	}
+	repaid := decimal.Zero
+	for _, installment := range schedule {
+		repaid = repaid.Add(installment.Principal)
+	}
+	if !repaid.Equal(loan.OutstandingBalance) {
+		t.Errorf("Expected the principal to add up to %v, got %v", loan.OutstandingBalance, repaid)
+	}
+}
+
+func TestAmortize_StartsAfterAsOf(t *testing.T) {
+	loan := Loan{
+		MonthlyPayment:     decimal.NewFromInt(100),
+		OutstandingBalance: decimal.NewFromInt(600),
+		Status:             StatusActive,
+		StartDate:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaturityDate:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	schedule := Amortize(loan, time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC))
+	if len(schedule) != 6 || !schedule[0].DueDate.Equal(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)) || schedule[0].Number != 1 {
+		t.Fatalf("Expected six installments from August, got %+v", schedule)
+	}
+	if !schedule[5].Balance.IsZero() {
+		t.Errorf("Expected the balance to be repaid, got %v", schedule[5].Balance)
+	}
+
+	loan.Status = StatusCancelled
+	if schedule := Amortize(loan, loan.StartDate); len(schedule) != 0 {
+		t.Errorf("Expected no schedule for a cancelled loan, got %d installments", len(schedule))
+	}
+}
+
+func TestAmortizationFilter_Pages(t *testing.T) {
+	schedule := make([]Installment, 5)
+	filter := AmortizationFilter{Limit: 2, Offset: 4}
+	filter.normalize()
+	if page := filter.page(schedule); len(page) != 1 {
+		t.Errorf("Expected the last installment, got %d", len(page))
+	}
+	filter.Offset = 10
+	if page := filter.page(schedule); page == nil || len(page) != 0 {
+		t.Errorf("Expected an empty page past the end, got %v", page)
+	}
+}
@@ -0,0 +1,23 @@
+package loans
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"service3/api/internal/cache"
+)
+
+// CacheKey is the key a loan is cached under by LoanService.Read
+func CacheKey(id uuid.UUID) string {
+	return "loan:" + id.String()
+}
+
+// Invalidate drops the cached loan once it has changed, so the next read goes to the
+// database. Call it after the change, outside the transaction making it: a read
+// landing in between would cache the old loan again.
+func Invalidate(ctx context.Context, c cache.Cache, id uuid.UUID) {
+	if err := c.Delete(ctx, CacheKey(id)); err != nil {
+		slog.WarnContext(ctx, "Unable to invalidate cached loan", "loan_id", id, "error", err)
+	}
+}
@@ -0,0 +1,87 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/cache"
+	"service3/api/internal/tenant"
+)
+
+// countingRepository serves one loan and counts the reads that reach it; the
+// embedded interface panics on anything else
+type countingRepository struct {
+	Repository
+	loan  Loan
+	reads int
+}
+
+func (r *countingRepository) Read(ctx context.Context, id uuid.UUID) (Loan, error) {
+	r.reads++
+	if id != r.loan.Id {
+		return Loan{}, ErrNotFound
+	}
+	return r.loan, nil
+}
+
+func (r *countingRepository) Update(ctx context.Context, loan Loan) (Loan, error) {
+	r.loan = loan
+	return loan, nil
+}
+
+func TestLoanService_ReadThroughCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	loanCache := cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1}), time.Minute)
+	repo := &countingRepository{loan: Loan{Id: uuid.New(), TenantId: tenant.Default, OutstandingBalance: decimal.NewFromInt(1000), Status: StatusActive}}
+	service := NewLoanService(repo).WithCache(loanCache)
+	ctx := context.Background()
+
+	for range 2 {
+		loan, err := service.Read(ctx, repo.loan.Id)
+		if err != nil || !loan.OutstandingBalance.Equal(decimal.NewFromInt(1000)) {
+			t.Fatalf("Unexpected read: %+v, %v", loan, err)
+		}
+	}
+	if _, err := service.PayoffQuote(ctx, repo.loan.Id, time.Now()); err != nil {
+		t.Fatalf("PayoffQuote failed: %v", err)
+	}
+	if repo.reads != 1 {
+		t.Errorf("Expected the cache to serve all but the first read, got %d repository reads", repo.reads)
+	}
+
+	updated := repo.loan
+	updated.OutstandingBalance = decimal.NewFromInt(900)
+	if _, err := service.Update(ctx, updated); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	loan, err := service.Read(ctx, repo.loan.Id)
+	if err != nil || !loan.OutstandingBalance.Equal(decimal.NewFromInt(900)) || repo.reads != 2 {
+		t.Errorf("Expected the update to invalidate the cached loan, got %v after %d reads", loan.OutstandingBalance, repo.reads)
+	}
+
+	// A cache that is down only costs the extra reads
+	server.Close()
+	if _, err := service.Read(ctx, repo.loan.Id); err != nil || repo.reads != 3 {
+		t.Errorf("Expected reads to fall back to the repository, got %v after %d reads", err, repo.reads)
+	}
+}
+
+func TestLoanService_CachedLoanOfAnotherTenant(t *testing.T) {
+	server := miniredis.RunT(t)
+	loanCache := cache.NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), time.Minute)
+	repo := &countingRepository{loan: Loan{Id: uuid.New(), TenantId: "lender-a", Status: StatusActive}}
+	service := NewLoanService(repo).WithCache(loanCache)
+
+	if _, err := service.Read(tenant.WithTenant(context.Background(), "lender-a"), repo.loan.Id); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if _, err := service.Read(tenant.WithTenant(context.Background(), "lender-b"), repo.loan.Id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected another tenant's cached loan to be not found, got %v", err)
+	}
+}
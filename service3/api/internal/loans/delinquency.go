@@ -0,0 +1,198 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/pagination"
+	"service3/api/internal/tenant"
+)
+
+// Delinquency buckets, by how many days the loan's oldest unpaid installment is past
+// due. Loans less than 30 days behind are current.
+const (
+	BucketCurrent = "current"
+	Bucket30      = "30"
+	Bucket60      = "60"
+	Bucket90      = "90"
+)
+
+// ErrInvalidBucket is returned when the delinquency report is asked for an unknown bucket
+var ErrInvalidBucket = errors.New("invalid delinquency bucket")
+
+// DelinquencyBucket returns the bucket of a loan daysPastDue days behind
+func DelinquencyBucket(daysPastDue int) string {
+	switch {
+	case daysPastDue >= 90:
+		return Bucket90
+	case daysPastDue >= 60:
+		return Bucket60
+	case daysPastDue >= 30:
+		return Bucket30
+	default:
+		return BucketCurrent
+	}
+}
+
+// DelinquentLoan is a line of the aging report: a loan flagged delinquent and the
+// installments it has not paid
+type DelinquentLoan struct {
+	LoanId             uuid.UUID       `json:"loan_id"`
+	CustomerId         uuid.UUID       `json:"customer_id"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+	DaysPastDue        int             `json:"days_past_due"`
+	Bucket             string          `json:"delinquency_bucket"`
+	OldestDueDate      *time.Time      `json:"oldest_due_date"`
+	MissedPayments     int             `json:"missed_payments"`
+	AmountPastDue      decimal.Decimal `json:"amount_past_due"`
+}
+
+// DelinquencyFilter narrows and pages the aging report. An empty Bucket matches every
+// delinquent loan.
+type DelinquencyFilter struct {
+	Bucket string
+	Limit  int
+	Offset int
+}
+
+// normalize bounds the paging, returning ErrInvalidBucket for an unknown bucket
+func (f *DelinquencyFilter) normalize() error {
+	if f.Bucket != "" && f.Bucket != Bucket30 && f.Bucket != Bucket60 && f.Bucket != Bucket90 {
+		return fmt.Errorf("%w: bucket must be 30, 60 or 90", ErrInvalidBucket)
+	}
+	f.Limit = pagination.ClampLimit(f.Limit)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return nil
+}
+
+// GetDelinquent lists active loans flagged delinquent, furthest behind first
+func (r *LoanRepository) GetDelinquent(ctx context.Context, filter DelinquencyFilter) ([]DelinquentLoan, error) {
+	sql := `SELECT l.id, l.customer_id, l.outstanding_balance, l.days_past_due, l.delinquency_bucket,
+			MIN(d.due_date)::timestamp, COUNT(d.id), COALESCE(SUM(d.amount), 0)
+		FROM loans l LEFT JOIN due_payments d ON d.loan_id = l.id AND d.status = 'due' AND d.due_date < CURRENT_DATE
+		WHERE l.status = $1 AND l.delinquency_bucket <> $2 AND ($3 = '' OR l.delinquency_bucket = $3) AND l.tenant_id = $4
+		GROUP BY l.id
+		ORDER BY l.days_past_due DESC, l.id
+		LIMIT $5 OFFSET $6`
+	rows, err := r.db.Query(ctx, sql, StatusActive, BucketCurrent, filter.Bucket, tenant.FromContext(ctx),
+		filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delinquent := []DelinquentLoan{}
+	for rows.Next() {
+		var loan DelinquentLoan
+		err := rows.Scan(&loan.LoanId, &loan.CustomerId, &loan.OutstandingBalance, &loan.DaysPastDue, &loan.Bucket,
+			&loan.OldestDueDate, &loan.MissedPayments, &loan.AmountPastDue)
+		if err != nil {
+			return nil, err
+		}
+		delinquent = append(delinquent, loan)
+	}
+	return delinquent, rows.Err()
+}
+
+// DelinquencyDetector flags active loans whose oldest unpaid installment is past due,
+// storing the days past due and the bucket on the loan. Loans that catch up or stop
+// being active go back to current.
+type DelinquencyDetector struct {
+	pool     *pgxpool.Pool
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewDelinquencyDetector creates a detector.
+func NewDelinquencyDetector(pool *pgxpool.Pool, logger *log.Logger) *DelinquencyDetector {
+	return &DelinquencyDetector{
+		pool:     pool,
+		interval: time.Hour,
+		logger:   logger,
+	}
+}
+
+// Run detects delinquency every interval until ctx is cancelled
+func (d *DelinquencyDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		if changed, err := d.Detect(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			d.logger.Printf("delinquency detection: %v", err)
+		} else if changed > 0 {
+			d.logger.Printf("delinquency detection: %d loans changed bucket", changed)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pastDueSQL selects the loans whose days past due as of $1 differ from what is stored,
+// with the recomputed value. Only installments due before $1 count.
+const pastDueSQL = `SELECT id, delinquency_bucket, days FROM (
+		SELECT l.id, l.delinquency_bucket, l.days_past_due,
+			CASE WHEN l.status = $2 THEN COALESCE($1::date - MIN(d.due_date), 0) ELSE 0 END AS days
+		FROM loans l LEFT JOIN due_payments d ON d.loan_id = l.id AND d.status = 'due' AND d.due_date < $1::date
+		GROUP BY l.id
+	) p
+	WHERE days <> days_past_due`
+
+// Detect recomputes every loan's days past due as of asOf and returns how many loans
+// moved to another bucket
+func (d *DelinquencyDetector) Detect(ctx context.Context, asOf time.Time) (int, error) {
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	type pastDue struct {
+		id     uuid.UUID
+		bucket string
+		days   int
+	}
+	rows, err := tx.Query(ctx, pastDueSQL, startOfDay(asOf), StatusActive)
+	if err != nil {
+		return 0, err
+	}
+	pending := []pastDue{}
+	for rows.Next() {
+		var loan pastDue
+		if err := rows.Scan(&loan.id, &loan.bucket, &loan.days); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		pending = append(pending, loan)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, loan := range pending {
+		bucket := DelinquencyBucket(loan.days)
+		sql := "UPDATE loans SET days_past_due = $1, delinquency_bucket = $2, modified_at = NOW() WHERE id = $3"
+		if _, err := tx.Exec(ctx, sql, loan.days, bucket, loan.id); err != nil {
+			return 0, err
+		}
+		if bucket != loan.bucket {
+			changed++
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,42 @@
+package loans
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDelinquencyBucket(t *testing.T) {
+	cases := []struct {
+		days int
+		want string
+	}{
+		{0, BucketCurrent},
+		{29, BucketCurrent},
+		{30, Bucket30},
+		{59, Bucket30},
+		{60, Bucket60},
+		{89, Bucket60},
+		{90, Bucket90},
+		{400, Bucket90},
+	}
+	for _, c := range cases {
+		if got := DelinquencyBucket(c.days); got != c.want {
+			t.Errorf("DelinquencyBucket(%d) = %q, want %q", c.days, got, c.want)
+		}
+	}
+}
+
+func TestDelinquencyFilter_normalize(t *testing.T) {
+	filter := DelinquencyFilter{Limit: 500, Offset: -1}
+	if err := filter.normalize(); err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	if filter.Limit != MaxListLimit || filter.Offset != 0 {
+		t.Errorf("expected limit %d and offset 0, got %d and %d", MaxListLimit, filter.Limit, filter.Offset)
+	}
+
+	filter = DelinquencyFilter{Bucket: BucketCurrent}
+	if err := filter.normalize(); !errors.Is(err, ErrInvalidBucket) {
+		t.Errorf("expected ErrInvalidBucket for the current bucket, got %v", err)
+	}
+}
@@ -1,10 +1,15 @@
 package loans
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"service3/api/internal/apierror"
 )
 
 type Handler struct {
@@ -23,78 +28,300 @@ func (h *Handler) Create(c echo.Context) error {
 
 	loan.Id = uuid.New()
 	if loan.Status == "" {
-		loan.Status = "active"
+		loan.Status = StatusActive
 	}
-	if err := h.service.Create(c.Request().Context(), *loan); err != nil {
+	if err := c.Validate(loan); err != nil {
+		return err
+	}
+	created, err := h.service.Create(c.Request().Context(), *loan)
+	if err != nil {
 		return err
 	}
 
-	return c.JSON(http.StatusCreated, loan)
+	return c.JSON(http.StatusCreated, created)
 }
 
 func (h *Handler) Read(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c, "id", "invalid loan id")
 	if err != nil {
 		return err
 	}
 
 	loan, err := h.service.Read(c.Request().Context(), id)
 	if err != nil {
-		return err
+		return httpError(err)
+	}
+	if notModified(c, setETag(c, loan)) {
+		return c.NoContent(http.StatusNotModified)
 	}
 	return c.JSON(http.StatusOK, loan)
 }
 
 func (h *Handler) Update(c echo.Context) error {
-	id := c.Param("id")
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
 	loan := new(Loan)
 	if err := c.Bind(loan); err != nil {
 		return err
 	}
-	var err error
-	loan.Id, err = uuid.Parse(id)
-	if err != nil {
+	loan.Id = id
+	if err := c.Validate(loan); err != nil {
 		return err
 	}
-	if err := h.service.Update(c.Request().Context(), *loan); err != nil {
-		return err
+	updated, err := h.service.Update(c.Request().Context(), *loan)
+	if err != nil {
+		return httpError(err)
 	}
-	return c.JSON(http.StatusOK, loan)
+	return c.JSON(http.StatusOK, updated)
 }
 
+// Delete cancels the loan instead of removing it. A missing or already cancelled
+// loan is not an error, so saga compensations can safely be retried.
 func (h *Handler) Delete(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c, "id", "invalid loan id")
 	if err != nil {
 		return err
 	}
-	if err := h.service.Delete(c.Request().Context(), id); err != nil {
-		return err
+	_, err = h.service.Cancel(c.Request().Context(), id, Cancellation{})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return httpError(err)
 	}
 	return c.NoContent(http.StatusNoContent)
 }
 
+// Cancel cancels an active loan, recording who cancelled it and why
+func (h *Handler) Cancel(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	cancellation := new(Cancellation)
+	if err := c.Bind(cancellation); err != nil {
+		return err
+	}
+	cancellation.CancelledBy = strings.TrimSpace(cancellation.CancelledBy)
+	cancellation.Reason = strings.TrimSpace(cancellation.Reason)
+
+	loan, err := h.service.Cancel(c.Request().Context(), id, *cancellation)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, loan)
+}
+
 func (h *Handler) GetByCustomerId(c echo.Context) error {
-	customerId, err := uuid.Parse(c.Param("customerId"))
+	customerId, err := parseID(c, "customerId", "invalid customer id")
+	if err != nil {
+		return err
+	}
+
+	var filter LoanFilter
+	err = echo.QueryParamsBinder(c).
+		String("status", &filter.Status).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
 	if err != nil {
 		return err
 	}
 
-	loans, err := h.service.GetByCustomerId(c.Request().Context(), customerId)
+	loans, err := h.service.GetByCustomerId(c.Request().Context(), customerId, filter)
 	if err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, loans)
 }
 
+// Summary returns the totals of the customer's loans and payments
+func (h *Handler) Summary(c echo.Context) error {
+	customerId, err := parseID(c, "customerId", "invalid customer id")
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.service.Summary(c.Request().Context(), customerId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, summary)
+}
+
 func (h *Handler) GetByMortgageId(c echo.Context) error {
-	mortgageId, err := uuid.Parse(c.Param("mortgageId"))
+	mortgageId, err := parseID(c, "mortgageId", "invalid mortgage id")
 	if err != nil {
 		return err
 	}
 
 	loan, err := h.service.GetByMortgageId(c.Request().Context(), mortgageId)
 	if err != nil {
-		return err
+		return httpError(err)
+	}
+	if notModified(c, setETag(c, *loan)) {
+		return c.NoContent(http.StatusNotModified)
 	}
 	return c.JSON(http.StatusOK, loan)
 }
+
+// PayoffQuote quotes the payoff amount as of the as_of date (YYYY-MM-DD), today by default
+func (h *Handler) PayoffQuote(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	asOf := time.Now()
+	if value := c.QueryParam("as_of"); value != "" {
+		asOf, err = time.Parse(time.DateOnly, value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "as_of must be a date (YYYY-MM-DD)").SetInternal(err)
+		}
+	}
+
+	quote, err := h.service.PayoffQuote(c.Request().Context(), id, asOf)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, quote)
+}
+
+// Amortization lists the loan's remaining installments from as_of (default today),
+// paged with limit and offset
+func (h *Handler) Amortization(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	var filter AmortizationFilter
+	var asOfParam string
+	err = echo.QueryParamsBinder(c).
+		String("as_of", &asOfParam).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
+	if err != nil {
+		return err
+	}
+	asOf := time.Now()
+	if asOfParam != "" {
+		asOf, err = time.Parse(time.DateOnly, asOfParam)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "as_of must be a date (YYYY-MM-DD)").SetInternal(err)
+		}
+	}
+
+	schedule, err := h.service.Amortization(c.Request().Context(), id, asOf, filter)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handler) GetAccruals(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	accruals, err := h.service.GetAccruals(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, accruals)
+}
+
+// Modify changes an active loan's rate, term or payment and returns the recorded modification
+func (h *Handler) Modify(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	request := new(ModificationRequest)
+	if err := c.Bind(request); err != nil {
+		return err
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	request.ModifiedBy = strings.TrimSpace(request.ModifiedBy)
+
+	modification, err := h.service.Modify(c.Request().Context(), id, *request)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, modification)
+}
+
+func (h *Handler) GetModifications(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	modifications, err := h.service.GetModifications(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, modifications)
+}
+
+// GetDelinquent lists delinquent loans, optionally in one bucket (30, 60 or 90),
+// paged with limit and offset
+func (h *Handler) GetDelinquent(c echo.Context) error {
+	var filter DelinquencyFilter
+	err := echo.QueryParamsBinder(c).
+		String("bucket", &filter.Bucket).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
+	if err != nil {
+		return err
+	}
+
+	delinquent, err := h.service.GetDelinquent(c.Request().Context(), filter)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, delinquent)
+}
+
+// setETag exposes a weak ETag derived from modified_at, which every change to a loan
+// bumps, so polling clients can send it back in If-None-Match. It returns the ETag.
+func setETag(c echo.Context, loan Loan) string {
+	etag := "W/" + strconv.Quote(strconv.FormatInt(loan.ModifiedAt.UnixMicro(), 10))
+	c.Response().Header().Set("ETag", etag)
+	return etag
+}
+
+// notModified reports whether If-None-Match names etag or "*", meaning the client's
+// copy is current. ETags are compared weakly, so quoted and W/ forms both match.
+func notModified(c echo.Context, etag string) bool {
+	for _, candidate := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest(message, err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidModification) || errors.Is(err, ErrInvalidBucket) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrNotActive) || errors.Is(err, ErrManagedField) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package loans
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"service3/api/internal/apierror"
+)
+
+func TestHandler_Read_IfNoneMatch(t *testing.T) {
+	repo := &countingRepository{loan: Loan{Id: uuid.New(), Status: StatusActive, ModifiedAt: time.Now()}}
+	handler := NewLoanHandler(NewLoanService(repo))
+	e := echo.New()
+
+	read := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/loans/"+repo.loan.Id.String(), nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(repo.loan.Id.String())
+		if err := handler.Read(c); err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		return rec
+	}
+
+	first := read("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || len(etag) < 4 || etag[:2] != "W/" {
+		t.Fatalf("Expected 200 with a weak ETag, got %d and %q", first.Code, etag)
+	}
+
+	for _, header := range []string{etag, etag[2:], `"other", ` + etag, "*"} {
+		if rec := read(header); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+			t.Errorf("Expected 304 with no body for If-None-Match %s, got %d", header, rec.Code)
+		}
+	}
+
+	repo.loan.ModifiedAt = repo.loan.ModifiedAt.Add(time.Second)
+	if rec := read(etag); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("Expected a changed loan to be sent with a new ETag, got %d and %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestHandler_Read_InvalidID(t *testing.T) {
+	handler := NewLoanHandler(NewLoanService(&countingRepository{}))
+	e := echo.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.GET("/loans/:id", handler.Read)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/loans/not-a-uuid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a malformed ID to be 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
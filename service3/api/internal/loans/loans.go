@@ -2,89 +2,150 @@ package loans
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/cache"
+	"service3/api/internal/database"
+	"service3/api/internal/outbox"
+	"service3/api/internal/pagination"
+	"service3/api/internal/tenant"
 )
 
 type Loan struct {
-	Id                 uuid.UUID `json:"id"`
-	CustomerId         uuid.UUID `json:"customer_id"`
-	MortgageId         uuid.UUID `json:"mortgage_id"`
-	LoanAmount         float64   `json:"loan_amount"`
-	InterestRate       float64   `json:"interest_rate"`
-	TermYears          int       `json:"term_years"`
-	MonthlyPayment     float64   `json:"monthly_payment"`
-	OutstandingBalance float64   `json:"outstanding_balance"`
-	Status             string    `json:"status"` // active, paid_off, defaulted
-	StartDate          time.Time `json:"start_date"`
-	MaturityDate       time.Time `json:"maturity_date"`
-	CreatedAt          time.Time `json:"created_at"`
-	ModifiedAt         time.Time `json:"modified_at"`
+	Id                 uuid.UUID       `json:"id"`
+	TenantId           string          `json:"tenant_id"`
+	CustomerId         uuid.UUID       `json:"customer_id" validate:"required"`
+	MortgageId         uuid.UUID       `json:"mortgage_id" validate:"required"`
+	LoanAmount         decimal.Decimal `json:"loan_amount" validate:"gt=0"`
+	InterestRate       float64         `json:"interest_rate" validate:"gte=0"`
+	TermYears          int             `json:"term_years" validate:"gt=0"`
+	MonthlyPayment     decimal.Decimal `json:"monthly_payment" validate:"gte=0"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance" validate:"gte=0"`
+	Status             string          `json:"status" validate:"required,oneof=active paid_off defaulted cancelled"`
+	StartDate          time.Time       `json:"start_date" validate:"required"`
+	MaturityDate       time.Time       `json:"maturity_date" validate:"required"`
+	// AccruedInterest is interest accrued by the daily job and not yet paid, covering
+	// the days before InterestAccruedThrough
+	AccruedInterest        decimal.Decimal `json:"accrued_interest"`
+	InterestAccruedThrough *time.Time      `json:"interest_accrued_through"`
+	LateFeesDue            decimal.Decimal `json:"late_fees_due"`      // assessed late fees not waived
+	DaysPastDue            int             `json:"days_past_due"`      // age of the oldest unpaid installment, set by the delinquency job
+	DelinquencyBucket      string          `json:"delinquency_bucket"` // current, 30, 60 or 90
+	CancelledAt            *time.Time      `json:"cancelled_at"`
+	CancelledBy            *string         `json:"cancelled_by"`
+	CancellationReason     *string         `json:"cancellation_reason"`
+	CreatedAt              time.Time       `json:"created_at"`
+	ModifiedAt             time.Time       `json:"modified_at"`
 }
 
+// Loan statuses. A loan is paid off once payments bring its outstanding balance to zero.
+// Loans are cancelled rather than deleted so their payments keep a loan to point at.
+const (
+	StatusActive    = "active"
+	StatusPaidOff   = "paid_off"
+	StatusDefaulted = "defaulted"
+	StatusCancelled = "cancelled"
+)
+
+// CancelReasonSagaCompensation is the reason recorded when a saga rolls back the
+// onboarding attempt that exported the loan
+const CancelReasonSagaCompensation = "saga_compensation"
+
+// Cancellation records who cancelled a loan and why
+type Cancellation struct {
+	CancelledBy string `json:"cancelled_by"`
+	Reason      string `json:"reason"`
+}
+
+// Domain events written to the outbox alongside loan changes
+const (
+	AggregateType          = "loan"
+	EventLoanCreated       = "LoanCreated"
+	EventLoanStatusChanged = "LoanStatusChanged"
+)
+
+// Events lists the event types above; webhooks can be subscribed to them
+var Events = []string{EventLoanCreated, EventLoanStatusChanged}
+
+// StatusChange is the payload of a LoanStatusChanged event
+type StatusChange struct {
+	LoanId     uuid.UUID `json:"loan_id"`
+	CustomerId uuid.UUID `json:"customer_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+var (
+	// ErrNotFound is returned when no loan exists with the requested ID or mortgage
+	ErrNotFound = errors.New("loan not found")
+	// ErrNotActive is returned when modifying or cancelling a loan that is paid off or defaulted
+	ErrNotActive = errors.New("loan is not active")
+	// ErrManagedField is returned when an update changes the loan's status, balance or
+	// terms, which only cancellations, payments and modifications may change
+	ErrManagedField = errors.New("loan status, balance and terms cannot be updated")
+)
+
+// LoanFilter narrows and pages a customer's loans. An empty Status matches every loan.
+type LoanFilter struct {
+	Status string
+	Limit  int
+	Offset int
+}
+
+const (
+	DefaultListLimit = pagination.DefaultLimit
+	MaxListLimit     = pagination.MaxLimit
+)
+
 type Repository interface {
-	Create(ctx context.Context, loan Loan) error
+	Create(ctx context.Context, loan Loan) (Loan, error)
 	Read(ctx context.Context, id uuid.UUID) (Loan, error)
-	Update(ctx context.Context, loan Loan) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Loan, error)
+	Update(ctx context.Context, loan Loan) (Loan, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error)
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter LoanFilter) ([]Loan, error)
 	GetByMortgageId(ctx context.Context, mortgageId uuid.UUID) (*Loan, error)
+	GetAccruals(ctx context.Context, loanId uuid.UUID) ([]Accrual, error)
+	Summary(ctx context.Context, customerId uuid.UUID) (Summary, error)
+	Modify(ctx context.Context, id uuid.UUID, request ModificationRequest, asOf time.Time) (Modification, error)
+	GetModifications(ctx context.Context, loanId uuid.UUID) ([]Modification, error)
+	GetDelinquent(ctx context.Context, filter DelinquencyFilter) ([]DelinquentLoan, error)
 }
 
 type Service interface {
-	Create(ctx context.Context, loan Loan) error
+	Create(ctx context.Context, loan Loan) (Loan, error)
 	Read(ctx context.Context, id uuid.UUID) (Loan, error)
-	Update(ctx context.Context, loan Loan) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Loan, error)
+	Update(ctx context.Context, loan Loan) (Loan, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error)
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter LoanFilter) ([]Loan, error)
 	GetByMortgageId(ctx context.Context, mortgageId uuid.UUID) (*Loan, error)
+	GetAccruals(ctx context.Context, loanId uuid.UUID) ([]Accrual, error)
+	PayoffQuote(ctx context.Context, id uuid.UUID, asOf time.Time) (PayoffQuote, error)
+	Amortization(ctx context.Context, id uuid.UUID, asOf time.Time, filter AmortizationFilter) ([]Installment, error)
+	Summary(ctx context.Context, customerId uuid.UUID) (Summary, error)
+	Modify(ctx context.Context, id uuid.UUID, request ModificationRequest) (Modification, error)
+	GetModifications(ctx context.Context, loanId uuid.UUID) ([]Modification, error)
+	GetDelinquent(ctx context.Context, filter DelinquencyFilter) ([]DelinquentLoan, error)
 }
 
-type LoanRepository struct {
-	conn *pgx.Conn
-}
-
-func NewLoanRepository(conn *pgx.Conn) *LoanRepository {
-	return &LoanRepository{conn}
-}
+const loanColumns = `id, tenant_id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
+	monthly_payment, outstanding_balance, status, start_date, maturity_date,
+	accrued_interest, interest_accrued_through, late_fees_due, days_past_due, delinquency_bucket,
+	cancelled_at, cancelled_by, cancellation_reason, created_at, modified_at`
 
-func (r *LoanRepository) Create(ctx context.Context, loan Loan) error {
-	sql := `INSERT INTO loans
-		(id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
-		 monthly_payment, outstanding_balance, status, start_date, maturity_date,
-		 created_at, modified_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW(), NOW())`
-
-	_, err := r.conn.Exec(ctx, sql,
-		loan.Id,
-		loan.CustomerId,
-		loan.MortgageId,
-		loan.LoanAmount,
-		loan.InterestRate,
-		loan.TermYears,
-		loan.MonthlyPayment,
-		loan.OutstandingBalance,
-		loan.Status,
-		loan.StartDate,
-		loan.MaturityDate,
-	)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (r *LoanRepository) Read(ctx context.Context, id uuid.UUID) (Loan, error) {
-	sql := `SELECT id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
-		monthly_payment, outstanding_balance, status, start_date, maturity_date,
-		created_at, modified_at
-		FROM loans WHERE id = $1`
-	row := r.conn.QueryRow(ctx, sql, id)
+// scanLoan scans a row selected with loanColumns
+func scanLoan(row pgx.Row) (Loan, error) {
 	var loan Loan
 	err := row.Scan(
 		&loan.Id,
+		&loan.TenantId,
 		&loan.CustomerId,
 		&loan.MortgageId,
 		&loan.LoanAmount,
@@ -95,142 +156,380 @@ func (r *LoanRepository) Read(ctx context.Context, id uuid.UUID) (Loan, error) {
 		&loan.Status,
 		&loan.StartDate,
 		&loan.MaturityDate,
+		&loan.AccruedInterest,
+		&loan.InterestAccruedThrough,
+		&loan.LateFeesDue,
+		&loan.DaysPastDue,
+		&loan.DelinquencyBucket,
+		&loan.CancelledAt,
+		&loan.CancelledBy,
+		&loan.CancellationReason,
 		&loan.CreatedAt,
 		&loan.ModifiedAt,
 	)
+	return loan, err
+}
+
+type LoanRepository struct {
+	db database.DB
+}
+
+func NewLoanRepository(pool *pgxpool.Pool) *LoanRepository {
+	return &LoanRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *LoanRepository) WithTx(tx pgx.Tx) *LoanRepository {
+	return &LoanRepository{tx}
+}
+
+// Create inserts the loan and returns it as stored, with its tenant and timestamps
+func (r *LoanRepository) Create(ctx context.Context, loan Loan) (Loan, error) {
+	var created Loan
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		sql := `INSERT INTO loans
+		(id, tenant_id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
+		 monthly_payment, outstanding_balance, status, start_date, maturity_date,
+		 created_at, modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		RETURNING ` + loanColumns
+
+		var err error
+		created, err = scanLoan(tx.QueryRow(ctx, sql,
+			loan.Id,
+			tenant.FromContext(ctx),
+			loan.CustomerId,
+			loan.MortgageId,
+			loan.LoanAmount,
+			loan.InterestRate,
+			loan.TermYears,
+			loan.MonthlyPayment,
+			loan.OutstandingBalance,
+			loan.Status,
+			loan.StartDate,
+			loan.MaturityDate,
+		))
+		if err != nil {
+			return err
+		}
+		return recordEvent(ctx, tx, created.Id, EventLoanCreated, created)
+	})
 	if err != nil {
 		return Loan{}, err
 	}
-	return loan, nil
+	return created, nil
 }
 
-func (r *LoanRepository) Update(ctx context.Context, loan Loan) error {
-	sql := `UPDATE loans
-		SET customer_id = $1, mortgage_id = $2, loan_amount = $3, interest_rate = $4,
-			term_years = $5, monthly_payment = $6, outstanding_balance = $7, status = $8,
-			start_date = $9, maturity_date = $10, modified_at = NOW()
-		WHERE id = $11`
-	_, err := r.conn.Exec(ctx, sql,
-		loan.CustomerId,
-		loan.MortgageId,
-		loan.LoanAmount,
-		loan.InterestRate,
-		loan.TermYears,
-		loan.MonthlyPayment,
-		loan.OutstandingBalance,
-		loan.Status,
-		loan.StartDate,
-		loan.MaturityDate,
-		loan.Id,
-	)
+func (r *LoanRepository) Read(ctx context.Context, id uuid.UUID) (Loan, error) {
+	sql := "SELECT " + loanColumns + " FROM loans WHERE id = $1 AND tenant_id = $2"
+	loan, err := scanLoan(r.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Loan{}, ErrNotFound
+	}
 	if err != nil {
+		return Loan{}, err
+	}
+	return loan, nil
+}
+
+// QueueRead queues reading the loan into batch, so other packages can fetch it in the
+// same round trip as their own rows. Closing the batch results fails with ErrNotFound
+// if the loan does not exist in the tenant of ctx.
+func QueueRead(ctx context.Context, batch *pgx.Batch, id uuid.UUID, loan *Loan) {
+	sql := "SELECT " + loanColumns + " FROM loans WHERE id = $1 AND tenant_id = $2"
+	batch.Queue(sql, id, tenant.FromContext(ctx)).QueryRow(func(row pgx.Row) error {
+		var err error
+		*loan, err = scanLoan(row)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	})
+}
+
+// Update replaces the loan's customer and mortgage. The rest of the loan must match
+// the stored one, or ErrManagedField is returned: Cancel changes the status, payments
+// the balance and Modify the terms. The loan is returned as stored.
+func (r *LoanRepository) Update(ctx context.Context, loan Loan) (Loan, error) {
+	var updated Loan
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		current, err := scanLoan(tx.QueryRow(ctx, "SELECT "+loanColumns+" FROM loans WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+			loan.Id, tenant.FromContext(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if field := managedChange(current, loan); field != "" {
+			return fmt.Errorf("%w: %s differs from the stored loan", ErrManagedField, field)
+		}
+
+		sql := "UPDATE loans SET customer_id = $1, mortgage_id = $2, modified_at = NOW() WHERE id = $3 RETURNING " + loanColumns
+		updated, err = scanLoan(tx.QueryRow(ctx, sql, loan.CustomerId, loan.MortgageId, loan.Id))
 		return err
+	})
+	if err != nil {
+		return Loan{}, err
 	}
-	return nil
+	return updated, nil
+}
+
+// managedChange returns the first field an update of current to loan would change
+// that only cancellations, payments and modifications may change, or "" if none
+func managedChange(current, loan Loan) string {
+	switch {
+	case loan.Status != current.Status:
+		return "status"
+	case !loan.OutstandingBalance.Equal(current.OutstandingBalance):
+		return "outstanding_balance"
+	case !loan.LoanAmount.Equal(current.LoanAmount):
+		return "loan_amount"
+	case loan.InterestRate != current.InterestRate:
+		return "interest_rate"
+	case loan.TermYears != current.TermYears:
+		return "term_years"
+	case !loan.MonthlyPayment.Equal(current.MonthlyPayment):
+		return "monthly_payment"
+	case !sameDay(loan.StartDate, current.StartDate):
+		return "start_date"
+	case !sameDay(loan.MaturityDate, current.MaturityDate):
+		return "maturity_date"
+	}
+	return ""
 }
 
-func (r *LoanRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	sql := "DELETE FROM loans WHERE id = $1"
-	_, err := r.conn.Exec(ctx, sql, id)
+// sameDay reports whether a and b fall on the same UTC date, as loan dates are stored
+func sameDay(a, b time.Time) bool {
+	return a.UTC().Format(time.DateOnly) == b.UTC().Format(time.DateOnly)
+}
+
+// Cancel marks an active loan cancelled, keeping the loan and its payments on record.
+// Cancelling a cancelled loan returns it unchanged, so saga compensations can
+// safely be retried.
+func (r *LoanRepository) Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error) {
+	var loan Loan
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		loan, err = scanLoan(tx.QueryRow(ctx, "SELECT "+loanColumns+" FROM loans WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+			id, tenant.FromContext(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if loan.Status == StatusCancelled {
+			return nil
+		}
+		if loan.Status != StatusActive {
+			return fmt.Errorf("%w: status is %s", ErrNotActive, loan.Status)
+		}
+
+		sql := `UPDATE loans
+			SET status = $1, cancelled_at = NOW(), cancelled_by = $2, cancellation_reason = $3, modified_at = NOW()
+			WHERE id = $4
+			RETURNING ` + loanColumns
+		loan, err = scanLoan(tx.QueryRow(ctx, sql, StatusCancelled,
+			nullIfEmpty(cancellation.CancelledBy), nullIfEmpty(cancellation.Reason), id))
+		if err != nil {
+			return err
+		}
+		return RecordStatusChange(ctx, tx, StatusChange{
+			LoanId:     loan.Id,
+			CustomerId: loan.CustomerId,
+			FromStatus: StatusActive,
+			ToStatus:   StatusCancelled,
+			Reason:     cancellation.Reason,
+		})
+	})
 	if err != nil {
-		return err
+		return Loan{}, err
 	}
-	return nil
+	return loan, nil
 }
 
-func (r *LoanRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Loan, error) {
-	sql := `SELECT id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
-		monthly_payment, outstanding_balance, status, start_date, maturity_date,
-		created_at, modified_at
-		FROM loans WHERE customer_id = $1 ORDER BY created_at DESC`
-	rows, err := r.conn.Query(ctx, sql, customerId)
+func (r *LoanRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter LoanFilter) ([]Loan, error) {
+	sql := "SELECT " + loanColumns + ` FROM loans
+		WHERE customer_id = $1 AND tenant_id = $2 AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC, id
+		LIMIT $4 OFFSET $5`
+	rows, err := r.db.Query(ctx, sql, customerId, tenant.FromContext(ctx), filter.Status, filter.Limit, filter.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var loans []Loan
+	loans := []Loan{}
 	for rows.Next() {
-		var loan Loan
-		err := rows.Scan(
-			&loan.Id,
-			&loan.CustomerId,
-			&loan.MortgageId,
-			&loan.LoanAmount,
-			&loan.InterestRate,
-			&loan.TermYears,
-			&loan.MonthlyPayment,
-			&loan.OutstandingBalance,
-			&loan.Status,
-			&loan.StartDate,
-			&loan.MaturityDate,
-			&loan.CreatedAt,
-			&loan.ModifiedAt,
-		)
+		loan, err := scanLoan(rows)
 		if err != nil {
 			return nil, err
 		}
 		loans = append(loans, loan)
 	}
-	return loans, nil
+	return loans, rows.Err()
 }
 
 func (r *LoanRepository) GetByMortgageId(ctx context.Context, mortgageId uuid.UUID) (*Loan, error) {
-	sql := `SELECT id, customer_id, mortgage_id, loan_amount, interest_rate, term_years,
-		monthly_payment, outstanding_balance, status, start_date, maturity_date,
-		created_at, modified_at
-		FROM loans WHERE mortgage_id = $1`
-	row := r.conn.QueryRow(ctx, sql, mortgageId)
-	var loan Loan
-	err := row.Scan(
-		&loan.Id,
-		&loan.CustomerId,
-		&loan.MortgageId,
-		&loan.LoanAmount,
-		&loan.InterestRate,
-		&loan.TermYears,
-		&loan.MonthlyPayment,
-		&loan.OutstandingBalance,
-		&loan.Status,
-		&loan.StartDate,
-		&loan.MaturityDate,
-		&loan.CreatedAt,
-		&loan.ModifiedAt,
-	)
+	// A mortgage exported again after its loan was cancelled has a newer loan
+	sql := "SELECT " + loanColumns + ` FROM loans WHERE mortgage_id = $1 AND tenant_id = $2
+		ORDER BY status = $3, created_at DESC
+		LIMIT 1`
+	loan, err := scanLoan(r.db.QueryRow(ctx, sql, mortgageId, tenant.FromContext(ctx), StatusCancelled))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &loan, nil
 }
 
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (r *LoanRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, r.db, fn)
+}
+
+// InTenant is a condition limiting a table with a loan_id column to the loans of the
+// tenant passed as parameter n. The tables hanging off loans scope their queries with it.
+func InTenant(n int) string {
+	return fmt.Sprintf("loan_id IN (SELECT id FROM loans WHERE tenant_id = $%d)", n)
+}
+
+// recordEvent writes a loan event to the outbox in the caller's transaction
+func recordEvent(ctx context.Context, tx pgx.Tx, id uuid.UUID, eventType string, payload any) error {
+	event, err := outbox.NewEvent(AggregateType, id, eventType, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Insert(ctx, tx, event)
+}
+
+// RecordStatusChange writes a LoanStatusChanged event in the caller's transaction.
+// Payments use it when a payment pays the loan off or a reversal reactivates it.
+func RecordStatusChange(ctx context.Context, tx pgx.Tx, change StatusChange) error {
+	return recordEvent(ctx, tx, change.LoanId, EventLoanStatusChanged, change)
+}
+
+// QueueStatusChange adds the LoanStatusChanged event to batch, for bulk payments
+// recording their events in one round trip
+func QueueStatusChange(ctx context.Context, batch *pgx.Batch, change StatusChange) error {
+	event, err := outbox.NewEvent(AggregateType, change.LoanId, EventLoanStatusChanged, change)
+	if err != nil {
+		return err
+	}
+	outbox.Queue(ctx, batch, event)
+	return nil
+}
+
 type LoanService struct {
-	repo Repository
+	repo  Repository
+	cache cache.Cache
 }
 
 func NewLoanService(repo Repository) *LoanService {
-	return &LoanService{repo}
+	return &LoanService{repo: repo, cache: cache.Nop{}}
 }
 
-func (s *LoanService) Create(ctx context.Context, loan Loan) error {
+// WithCache serves loan reads, and the payoff quotes computed from them, through c
+func (s *LoanService) WithCache(c cache.Cache) *LoanService {
+	s.cache = c
+	return s
+}
+
+func (s *LoanService) Create(ctx context.Context, loan Loan) (Loan, error) {
 	return s.repo.Create(ctx, loan)
 }
 
+// Read returns the cached loan, reading it from the repository and caching it on a miss.
+// Loans are cached by ID alone, so a cached loan of another tenant is not found.
 func (s *LoanService) Read(ctx context.Context, id uuid.UUID) (Loan, error) {
-	return s.repo.Read(ctx, id)
+	var loan Loan
+	found, err := s.cache.Get(ctx, CacheKey(id), &loan)
+	if err != nil {
+		slog.WarnContext(ctx, "Unable to read cached loan", "loan_id", id, "error", err)
+	}
+	if found && err == nil {
+		if loan.TenantId != tenant.FromContext(ctx) {
+			return Loan{}, ErrNotFound
+		}
+		return loan, nil
+	}
+
+	loan, err = s.repo.Read(ctx, id)
+	if err != nil {
+		return Loan{}, err
+	}
+	if err := s.cache.Set(ctx, CacheKey(id), loan); err != nil {
+		slog.WarnContext(ctx, "Unable to cache loan", "loan_id", id, "error", err)
+	}
+	return loan, nil
 }
 
-func (s *LoanService) Update(ctx context.Context, loan Loan) error {
+func (s *LoanService) Update(ctx context.Context, loan Loan) (Loan, error) {
+	defer Invalidate(ctx, s.cache, loan.Id)
 	return s.repo.Update(ctx, loan)
 }
 
-func (s *LoanService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+func (s *LoanService) Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error) {
+	defer Invalidate(ctx, s.cache, id)
+	return s.repo.Cancel(ctx, id, cancellation)
 }
 
-func (s *LoanService) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Loan, error) {
-	return s.repo.GetByCustomerId(ctx, customerId)
+func (s *LoanService) GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter LoanFilter) ([]Loan, error) {
+	filter.Limit = pagination.ClampLimit(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.GetByCustomerId(ctx, customerId, filter)
 }
 
 func (s *LoanService) GetByMortgageId(ctx context.Context, mortgageId uuid.UUID) (*Loan, error) {
 	return s.repo.GetByMortgageId(ctx, mortgageId)
-}
\ No newline at end of file
+}
+
+func (s *LoanService) GetAccruals(ctx context.Context, loanId uuid.UUID) ([]Accrual, error) {
+	return s.repo.GetAccruals(ctx, loanId)
+}
+
+// PayoffQuote quotes the amount that pays the loan off on asOf
+func (s *LoanService) PayoffQuote(ctx context.Context, id uuid.UUID, asOf time.Time) (PayoffQuote, error) {
+	loan, err := s.Read(ctx, id)
+	if err != nil {
+		return PayoffQuote{}, err
+	}
+	return NewPayoffQuote(loan, asOf), nil
+}
+
+// Amortization returns a page of the loan's amortization schedule from asOf
+func (s *LoanService) Amortization(ctx context.Context, id uuid.UUID, asOf time.Time, filter AmortizationFilter) ([]Installment, error) {
+	loan, err := s.Read(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	filter.normalize()
+	return filter.page(Amortize(loan, asOf)), nil
+}
+
+func (s *LoanService) Summary(ctx context.Context, customerId uuid.UUID) (Summary, error) {
+	return s.repo.Summary(ctx, customerId)
+}
+
+// Modify changes the loan's terms effective today
+func (s *LoanService) Modify(ctx context.Context, id uuid.UUID, request ModificationRequest) (Modification, error) {
+	defer Invalidate(ctx, s.cache, id)
+	return s.repo.Modify(ctx, id, request, time.Now())
+}
+
+func (s *LoanService) GetModifications(ctx context.Context, loanId uuid.UUID) ([]Modification, error) {
+	return s.repo.GetModifications(ctx, loanId)
+}
+
+// GetDelinquent returns a page of the aging report
+func (s *LoanService) GetDelinquent(ctx context.Context, filter DelinquencyFilter) ([]DelinquentLoan, error) {
+	if err := filter.normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDelinquent(ctx, filter)
+}
@@ -0,0 +1,49 @@
+package loans
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestManagedChange(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := Loan{
+		CustomerId:         uuid.New(),
+		MortgageId:         uuid.New(),
+		LoanAmount:         decimal.NewFromInt(300000),
+		InterestRate:       4.5,
+		TermYears:          25,
+		MonthlyPayment:     decimal.RequireFromString("1667.50"),
+		OutstandingBalance: decimal.NewFromInt(250000),
+		Status:             StatusActive,
+		StartDate:          start,
+		MaturityDate:       start.AddDate(25, 0, 0),
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Loan)
+		want   string
+	}{
+		{"customer and mortgage", func(l *Loan) { l.CustomerId, l.MortgageId = uuid.New(), uuid.New() }, ""},
+		{"same dates in another zone", func(l *Loan) { l.StartDate = start.In(time.FixedZone("UTC+1", 3600)) }, ""},
+		{"cancelled", func(l *Loan) { l.Status = StatusCancelled }, "status"},
+		{"paid off", func(l *Loan) { l.Status = StatusPaidOff }, "status"},
+		{"balance", func(l *Loan) { l.OutstandingBalance = decimal.Zero }, "outstanding_balance"},
+		{"rate", func(l *Loan) { l.InterestRate = 3.9 }, "interest_rate"},
+		{"term", func(l *Loan) { l.TermYears = 30 }, "term_years"},
+		{"maturity", func(l *Loan) { l.MaturityDate = start.AddDate(30, 0, 0) }, "maturity_date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loan := current
+			tt.change(&loan)
+			if got := managedChange(current, loan); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
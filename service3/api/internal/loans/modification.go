@@ -0,0 +1,249 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/tenant"
+)
+
+// Modification is a change to an active loan's rate, term or payment, recorded with
+// the terms it replaced. Unless set explicitly, MonthlyPayment amortizes
+// OutstandingBalance over the months from EffectiveDate to MaturityDate.
+type Modification struct {
+	Id                     uuid.UUID       `json:"id"`
+	LoanId                 uuid.UUID       `json:"loan_id"`
+	EffectiveDate          time.Time       `json:"effective_date"`
+	OutstandingBalance     decimal.Decimal `json:"outstanding_balance"`
+	PreviousInterestRate   float64         `json:"previous_interest_rate"`
+	PreviousTermYears      int             `json:"previous_term_years"`
+	PreviousMonthlyPayment decimal.Decimal `json:"previous_monthly_payment"`
+	PreviousMaturityDate   time.Time       `json:"previous_maturity_date"`
+	InterestRate           float64         `json:"interest_rate"`
+	TermYears              int             `json:"term_years"`
+	MonthlyPayment         decimal.Decimal `json:"monthly_payment"`
+	MaturityDate           time.Time       `json:"maturity_date"`
+	Reason                 *string         `json:"reason"`
+	ModifiedBy             *string         `json:"modified_by"`
+	CreatedAt              time.Time       `json:"created_at"`
+}
+
+// ModificationRequest asks for new loan terms. Unset fields keep the loan's current
+// terms, except MonthlyPayment, which is recalculated when not set.
+type ModificationRequest struct {
+	InterestRate   *float64         `json:"interest_rate"`
+	TermYears      *int             `json:"term_years"` // total term, counted from the start date
+	MonthlyPayment *decimal.Decimal `json:"monthly_payment"`
+	Reason         string           `json:"reason"`
+	ModifiedBy     string           `json:"modified_by"`
+}
+
+// ErrInvalidModification is returned when the requested terms are out of range or do not repay the loan
+var ErrInvalidModification = errors.New("invalid loan modification")
+
+// MonthlyPayment returns the level monthly payment, rounded to cents, that repays
+// balance over months at the annual ratePercent
+func MonthlyPayment(balance decimal.Decimal, ratePercent float64, months int) decimal.Decimal {
+	if months <= 0 {
+		return balance
+	}
+	if ratePercent == 0 {
+		return balance.Div(decimal.NewFromInt(int64(months))).Round(2)
+	}
+	rate := monthlyRate(ratePercent)
+	// balance * rate / (1 - (1+rate)^-months), multiplied through by (1+rate)^months
+	growth := decimal.NewFromInt(1).Add(rate).Pow(decimal.NewFromInt(int64(months)))
+	payment := balance.Mul(rate).Mul(growth).Div(growth.Sub(decimal.NewFromInt(1)))
+	return payment.Round(2)
+}
+
+// monthlyRate is the monthly interest rate as a fraction of the annual ratePercent
+func monthlyRate(ratePercent float64) decimal.Decimal {
+	return decimal.NewFromFloat(ratePercent).Div(decimal.NewFromInt(100 * 12))
+}
+
+// monthsBetween counts the whole months from from to to
+func monthsBetween(from, to time.Time) int {
+	months := (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	if to.Day() < from.Day() {
+		months--
+	}
+	return months
+}
+
+// NewModification applies request to loan's current terms as of the asOf day,
+// returning ErrInvalidModification if the new terms are out of range
+func NewModification(loan Loan, request ModificationRequest, asOf time.Time) (Modification, error) {
+	if request.InterestRate == nil && request.TermYears == nil && request.MonthlyPayment == nil {
+		return Modification{}, fmt.Errorf("%w: set interest_rate, term_years or monthly_payment", ErrInvalidModification)
+	}
+
+	rate := loan.InterestRate
+	if request.InterestRate != nil {
+		rate = *request.InterestRate
+		if rate < 0 || rate > 100 {
+			return Modification{}, fmt.Errorf("%w: interest_rate must be between 0 and 100", ErrInvalidModification)
+		}
+	}
+	term, maturity := loan.TermYears, loan.MaturityDate
+	if request.TermYears != nil {
+		term = *request.TermYears
+		if term < 1 || term > 50 {
+			return Modification{}, fmt.Errorf("%w: term_years must be between 1 and 50", ErrInvalidModification)
+		}
+		maturity = loan.StartDate.AddDate(term, 0, 0)
+	}
+
+	effective := startOfDay(asOf)
+	months := monthsBetween(effective, maturity)
+	if months < 1 {
+		return Modification{}, fmt.Errorf("%w: the term must end at least a month after %s", ErrInvalidModification, effective.Format(time.DateOnly))
+	}
+	payment := MonthlyPayment(loan.OutstandingBalance, rate, months)
+	if request.MonthlyPayment != nil {
+		payment = *request.MonthlyPayment
+		if !payment.IsPositive() {
+			return Modification{}, fmt.Errorf("%w: monthly_payment must be greater than 0", ErrInvalidModification)
+		}
+		if payment.LessThanOrEqual(loan.OutstandingBalance.Mul(monthlyRate(rate))) {
+			return Modification{}, fmt.Errorf("%w: monthly_payment does not cover the monthly interest", ErrInvalidModification)
+		}
+	}
+
+	return Modification{
+		Id:                     uuid.New(),
+		LoanId:                 loan.Id,
+		EffectiveDate:          effective,
+		OutstandingBalance:     loan.OutstandingBalance,
+		PreviousInterestRate:   loan.InterestRate,
+		PreviousTermYears:      loan.TermYears,
+		PreviousMonthlyPayment: loan.MonthlyPayment,
+		PreviousMaturityDate:   loan.MaturityDate,
+		InterestRate:           rate,
+		TermYears:              term,
+		MonthlyPayment:         payment,
+		MaturityDate:           maturity,
+		Reason:                 nullIfEmpty(request.Reason),
+		ModifiedBy:             nullIfEmpty(request.ModifiedBy),
+	}, nil
+}
+
+const modificationColumns = `id, loan_id, effective_date, outstanding_balance,
+	previous_interest_rate, previous_term_years, previous_monthly_payment, previous_maturity_date,
+	interest_rate, term_years, monthly_payment, maturity_date, reason, modified_by, created_at`
+
+// scanModification scans a row selected with modificationColumns
+func scanModification(row pgx.Row) (Modification, error) {
+	var modification Modification
+	err := row.Scan(
+		&modification.Id,
+		&modification.LoanId,
+		&modification.EffectiveDate,
+		&modification.OutstandingBalance,
+		&modification.PreviousInterestRate,
+		&modification.PreviousTermYears,
+		&modification.PreviousMonthlyPayment,
+		&modification.PreviousMaturityDate,
+		&modification.InterestRate,
+		&modification.TermYears,
+		&modification.MonthlyPayment,
+		&modification.MaturityDate,
+		&modification.Reason,
+		&modification.ModifiedBy,
+		&modification.CreatedAt,
+	)
+	return modification, err
+}
+
+// Modify changes the loan's terms as of the asOf day and records the modification in
+// one transaction. Interest up to that day is accrued at the old rate first.
+func (r *LoanRepository) Modify(ctx context.Context, id uuid.UUID, request ModificationRequest, asOf time.Time) (Modification, error) {
+	var modification Modification
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		loan, err := scanLoan(tx.QueryRow(ctx, "SELECT "+loanColumns+" FROM loans WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+			id, tenant.FromContext(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if loan.Status != StatusActive {
+			return fmt.Errorf("%w: status is %s", ErrNotActive, loan.Status)
+		}
+		modification, err = NewModification(loan, request, asOf)
+		if err != nil {
+			return err
+		}
+		if err := accrue(ctx, tx, loan, modification.EffectiveDate); err != nil {
+			return err
+		}
+
+		sql := `UPDATE loans
+			SET interest_rate = $1, term_years = $2, monthly_payment = $3, maturity_date = $4, modified_at = NOW()
+			WHERE id = $5`
+		_, err = tx.Exec(ctx, sql, modification.InterestRate, modification.TermYears, modification.MonthlyPayment,
+			modification.MaturityDate, id)
+		if err != nil {
+			return err
+		}
+		sql = `INSERT INTO loan_modifications (` + modificationColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+			RETURNING ` + modificationColumns
+		modification, err = scanModification(tx.QueryRow(ctx, sql,
+			modification.Id,
+			modification.LoanId,
+			modification.EffectiveDate,
+			modification.OutstandingBalance,
+			modification.PreviousInterestRate,
+			modification.PreviousTermYears,
+			modification.PreviousMonthlyPayment,
+			modification.PreviousMaturityDate,
+			modification.InterestRate,
+			modification.TermYears,
+			modification.MonthlyPayment,
+			modification.MaturityDate,
+			modification.Reason,
+			modification.ModifiedBy,
+		))
+		return err
+	})
+	if err != nil {
+		return Modification{}, err
+	}
+	return modification, nil
+}
+
+// GetModifications lists the loan's modifications, most recent first
+func (r *LoanRepository) GetModifications(ctx context.Context, loanId uuid.UUID) ([]Modification, error) {
+	sql := "SELECT " + modificationColumns + " FROM loan_modifications WHERE loan_id = $1 AND " + InTenant(2) +
+		" ORDER BY created_at DESC"
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	modifications := []Modification{}
+	for rows.Next() {
+		modification, err := scanModification(rows)
+		if err != nil {
+			return nil, err
+		}
+		modifications = append(modifications, modification)
+	}
+	return modifications, rows.Err()
+}
+
+// nullIfEmpty stores an empty string as SQL NULL
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
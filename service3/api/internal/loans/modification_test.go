@@ -0,0 +1,78 @@
+package loans
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestMonthlyPayment(t *testing.T) {
+	cases := []struct {
+		balance string
+		rate    float64
+		months  int
+		want    string
+	}{
+		{"200000", 6, 360, "1199.10"},
+		{"180000", 4.5, 300, "1000.50"},
+		{"12000", 0, 24, "500"},
+		{"1000", 0, 3, "333.33"},
+	}
+	for _, c := range cases {
+		if got := MonthlyPayment(decimal.RequireFromString(c.balance), c.rate, c.months); !got.Equal(decimal.RequireFromString(c.want)) {
+			t.Errorf("MonthlyPayment(%v, %v, %d) = %v, want %v", c.balance, c.rate, c.months, got, c.want)
+		}
+	}
+}
+
+func TestNewModification(t *testing.T) {
+	loan := Loan{
+		InterestRate:       6,
+		TermYears:          30,
+		MonthlyPayment:     decimal.RequireFromString("1199.10"),
+		OutstandingBalance: decimal.NewFromInt(180000),
+		Status:             StatusActive,
+		StartDate:          time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaturityDate:       time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	asOf := time.Date(2025, 1, 1, 15, 30, 0, 0, time.UTC)
+
+	rate := 4.5
+	modification, err := NewModification(loan, ModificationRequest{InterestRate: &rate, Reason: "hardship"}, asOf)
+	if err != nil {
+		t.Fatalf("Expected a valid modification, got %v", err)
+	}
+	if !modification.MonthlyPayment.Equal(decimal.RequireFromString("1000.50")) || !modification.MaturityDate.Equal(loan.MaturityDate) {
+		t.Errorf("Expected the payment recalculated over the remaining term, got %+v", modification)
+	}
+	if modification.PreviousInterestRate != 6 || !modification.PreviousMonthlyPayment.Equal(decimal.RequireFromString("1199.10")) {
+		t.Errorf("Expected the previous terms to be recorded, got %+v", modification)
+	}
+	if !modification.EffectiveDate.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the modification to take effect at the start of the day, got %v", modification.EffectiveDate)
+	}
+
+	term := 25
+	modification, err = NewModification(loan, ModificationRequest{InterestRate: &rate, TermYears: &term}, asOf)
+	if err != nil {
+		t.Fatalf("Expected a valid modification, got %v", err)
+	}
+	if !modification.MaturityDate.Equal(time.Date(2045, 1, 1, 0, 0, 0, 0, time.UTC)) || !modification.MonthlyPayment.Equal(decimal.RequireFromString("1138.77")) {
+		t.Errorf("Expected a shorter term and higher payment, got %+v", modification)
+	}
+
+	shortTerm, negativeRate, lowPayment := 5, -1.0, decimal.NewFromInt(100)
+	invalid := []ModificationRequest{
+		{},
+		{TermYears: &shortTerm},
+		{InterestRate: &negativeRate},
+		{MonthlyPayment: &lowPayment},
+	}
+	for _, request := range invalid {
+		if _, err := NewModification(loan, request, asOf); !errors.Is(err, ErrInvalidModification) {
+			t.Errorf("Expected ErrInvalidModification for %+v, got %v", request, err)
+		}
+	}
+}
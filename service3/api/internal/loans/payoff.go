@@ -0,0 +1,41 @@
+package loans
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoffQuote is the amount that pays the loan off on AsOf: the outstanding balance,
+// unpaid accrued interest (including the days the accrual job has not run for yet) and
+// late fees due.
+// PerDiem is the interest added for each day the payoff is later than AsOf.
+type PayoffQuote struct {
+	LoanId             uuid.UUID       `json:"loan_id"`
+	AsOf               time.Time       `json:"as_of"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+	AccruedInterest    decimal.Decimal `json:"accrued_interest"`
+	LateFeesDue        decimal.Decimal `json:"late_fees_due"`
+	PerDiem            decimal.Decimal `json:"per_diem"`
+	PayoffAmount       decimal.Decimal `json:"payoff_amount"`
+}
+
+// NewPayoffQuote quotes the payoff of loan at the start of the asOf day
+func NewPayoffQuote(loan Loan, asOf time.Time) PayoffQuote {
+	asOf = startOfDay(asOf)
+	accrued := loan.AccruedInterest
+	if loan.Status == StatusActive {
+		days := daysBetween(accruedThrough(loan), asOf)
+		accrued = accrued.Add(InterestFor(loan.OutstandingBalance, loan.InterestRate, days))
+	}
+	return PayoffQuote{
+		LoanId:             loan.Id,
+		AsOf:               asOf,
+		OutstandingBalance: loan.OutstandingBalance,
+		AccruedInterest:    accrued,
+		LateFeesDue:        loan.LateFeesDue,
+		PerDiem:            InterestFor(loan.OutstandingBalance, loan.InterestRate, 1),
+		PayoffAmount:       loan.OutstandingBalance.Add(accrued).Add(loan.LateFeesDue).Round(2),
+	}
+}
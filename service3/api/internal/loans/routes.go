@@ -2,11 +2,19 @@ package loans
 
 import "github.com/labstack/echo/v4"
 
-func Routes(e *echo.Echo, handler Handler) {
-	e.POST("/loans", handler.Create)
-	e.GET("/loans/:id", handler.Read)
-	e.PUT("/loans/:id", handler.Update)
-	e.DELETE("/loans/:id", handler.Delete)
-	e.GET("/customers/:customerId/loans", handler.GetByCustomerId)
-	e.GET("/mortgages/:mortgageId/loan", handler.GetByMortgageId)
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/loans", handler.Create)
+	g.GET("/loans/delinquent", handler.GetDelinquent)
+	g.GET("/loans/:id", handler.Read)
+	g.PUT("/loans/:id", handler.Update)
+	g.DELETE("/loans/:id", handler.Delete)
+	g.POST("/loans/:id/cancel", handler.Cancel)
+	g.GET("/loans/:id/payoff-quote", handler.PayoffQuote)
+	g.GET("/loans/:id/amortization", handler.Amortization)
+	g.GET("/loans/:id/accruals", handler.GetAccruals)
+	g.POST("/loans/:id/modify", handler.Modify)
+	g.GET("/loans/:id/modifications", handler.GetModifications)
+	g.GET("/customers/:customerId/loans", handler.GetByCustomerId)
+	g.GET("/customers/:customerId/loans/summary", handler.Summary)
+	g.GET("/mortgages/:mortgageId/loan", handler.GetByMortgageId)
 }
@@ -0,0 +1,80 @@
+package loans
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/tenant"
+)
+
+// Summary totals a customer's loans for dashboards. Cancelled loans and their
+// payments are left out, and the paid totals are net of reversals.
+type Summary struct {
+	CustomerId         uuid.UUID       `json:"customer_id"`
+	LoanCount          int             `json:"loan_count"`
+	ActiveLoanCount    int             `json:"active_loan_count"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+	PrincipalPaid      decimal.Decimal `json:"principal_paid"`
+	InterestPaid       decimal.Decimal `json:"interest_paid"`
+	NextPaymentDue     *NextPayment    `json:"next_payment_due"` // nil when no payment is scheduled
+}
+
+// NextPayment is the earliest open installment or scheduled payment on an active loan
+type NextPayment struct {
+	LoanId  uuid.UUID       `json:"loan_id"`
+	DueDate time.Time       `json:"due_date"`
+	Amount  decimal.Decimal `json:"amount"`
+}
+
+// Summary aggregates the customer's loans and payments in the database, sending the
+// three queries as one batch
+func (r *LoanRepository) Summary(ctx context.Context, customerId uuid.UUID) (Summary, error) {
+	summary := Summary{CustomerId: customerId}
+	tenantId := tenant.FromContext(ctx)
+	batch := &pgx.Batch{}
+
+	sql := `SELECT COUNT(*), COUNT(*) FILTER (WHERE status = $2), COALESCE(SUM(outstanding_balance), 0)
+		FROM loans WHERE customer_id = $1 AND status <> $3 AND tenant_id = $4`
+	batch.Queue(sql, customerId, StatusActive, StatusCancelled, tenantId).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&summary.LoanCount, &summary.ActiveLoanCount, &summary.OutstandingBalance)
+	})
+
+	sql = `SELECT COALESCE(SUM(p.principal_amount), 0), COALESCE(SUM(p.interest_amount), 0)
+		FROM payments p JOIN loans l ON l.id = p.loan_id
+		WHERE l.customer_id = $1 AND l.status <> $2 AND l.tenant_id = $3`
+	batch.Queue(sql, customerId, StatusCancelled, tenantId).QueryRow(func(row pgx.Row) error {
+		return row.Scan(&summary.PrincipalPaid, &summary.InterestPaid)
+	})
+
+	// Installments already due come before the schedules' upcoming dates
+	sql = `SELECT loan_id, due_date, amount FROM (
+			SELECT d.loan_id, d.due_date, d.amount FROM due_payments d JOIN loans l ON l.id = d.loan_id
+			WHERE l.customer_id = $1 AND l.status = $2 AND l.tenant_id = $3 AND d.status = 'due'
+			UNION ALL
+			SELECT s.loan_id, s.next_due_date, s.amount FROM payment_schedules s JOIN loans l ON l.id = s.loan_id
+			WHERE l.customer_id = $1 AND l.status = $2 AND l.tenant_id = $3
+		) upcoming
+		ORDER BY due_date, loan_id
+		LIMIT 1`
+	batch.Queue(sql, customerId, StatusActive, tenantId).QueryRow(func(row pgx.Row) error {
+		var next NextPayment
+		err := row.Scan(&next.LoanId, &next.DueDate, &next.Amount)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		summary.NextPaymentDue = &next
+		return nil
+	})
+
+	if err := r.db.SendBatch(ctx, batch).Close(); err != nil {
+		return Summary{}, err
+	}
+	return summary, nil
+}
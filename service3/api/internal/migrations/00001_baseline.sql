@@ -0,0 +1,174 @@
+-- Baseline: the schema the service used to create at startup. The statements are
+-- idempotent so databases created that way are adopted unchanged.
+
+-- +goose Up
+-- loans
+CREATE TABLE IF NOT EXISTS loans(
+    id uuid PRIMARY KEY,
+    customer_id uuid NOT NULL,
+    mortgage_id uuid NOT NULL,
+    loan_amount numeric NOT NULL,
+    interest_rate numeric NOT NULL,
+    term_years int NOT NULL,
+    monthly_payment numeric NOT NULL,
+    outstanding_balance numeric NOT NULL,
+    status varchar NOT NULL,
+    start_date timestamp NOT NULL,
+    maturity_date timestamp NOT NULL,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+ALTER TABLE loans
+    ADD COLUMN IF NOT EXISTS accrued_interest numeric NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS interest_accrued_through date,
+    ADD COLUMN IF NOT EXISTS late_fees_due numeric NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS days_past_due int NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS delinquency_bucket varchar NOT NULL DEFAULT 'current',
+    ADD COLUMN IF NOT EXISTS cancelled_at timestamp,
+    ADD COLUMN IF NOT EXISTS cancelled_by varchar,
+    ADD COLUMN IF NOT EXISTS cancellation_reason varchar;
+
+CREATE TABLE IF NOT EXISTS loan_accruals(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL,
+    period_start date NOT NULL,
+    period_end date NOT NULL,
+    days int NOT NULL,
+    balance numeric NOT NULL,
+    interest_rate numeric NOT NULL,
+    amount numeric NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS loan_accruals_loan_idx ON loan_accruals (loan_id, period_start);
+
+CREATE TABLE IF NOT EXISTS loan_modifications(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL,
+    effective_date date NOT NULL,
+    outstanding_balance numeric NOT NULL,
+    previous_interest_rate numeric NOT NULL,
+    previous_term_years int NOT NULL,
+    previous_monthly_payment numeric NOT NULL,
+    previous_maturity_date timestamp NOT NULL,
+    interest_rate numeric NOT NULL,
+    term_years int NOT NULL,
+    monthly_payment numeric NOT NULL,
+    maturity_date timestamp NOT NULL,
+    reason varchar,
+    modified_by varchar,
+    created_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS loan_modifications_loan_idx ON loan_modifications (loan_id, created_at);
+
+-- payments
+CREATE TABLE IF NOT EXISTS payments(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL,
+    customer_id uuid NOT NULL,
+    payment_amount numeric NOT NULL,
+    principal_amount numeric NOT NULL,
+    interest_amount numeric NOT NULL,
+    payment_date timestamp NOT NULL,
+    payment_type varchar NOT NULL,
+    created_at timestamp NOT NULL
+);
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS reversal_of uuid,
+    ADD COLUMN IF NOT EXISTS escrow_amount numeric NOT NULL DEFAULT 0;
+
+-- A payment can be reversed at most once
+CREATE UNIQUE INDEX IF NOT EXISTS payments_reversal_of_idx ON payments (reversal_of);
+
+-- payment schedules
+CREATE TABLE IF NOT EXISTS payment_schedules(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL,
+    amount numeric NOT NULL,
+    day_of_month int NOT NULL,
+    autopay boolean NOT NULL,
+    next_due_date date NOT NULL,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS payment_schedules_next_due_idx ON payment_schedules (next_due_date);
+
+CREATE TABLE IF NOT EXISTS due_payments(
+    id uuid PRIMARY KEY,
+    schedule_id uuid NOT NULL,
+    loan_id uuid NOT NULL,
+    due_date date NOT NULL,
+    amount numeric NOT NULL,
+    status varchar NOT NULL,
+    payment_id uuid,
+    paid_at timestamp,
+    created_at timestamp NOT NULL,
+    UNIQUE (schedule_id, due_date)
+);
+
+CREATE INDEX IF NOT EXISTS due_payments_loan_idx ON due_payments (loan_id, due_date);
+
+-- late fees
+CREATE TABLE IF NOT EXISTS late_fees(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL,
+    due_payment_id uuid NOT NULL UNIQUE,
+    amount numeric NOT NULL,
+    status varchar NOT NULL,
+    assessed_at timestamp NOT NULL,
+    waived_at timestamp,
+    waived_by varchar,
+    waived_reason varchar
+);
+
+CREATE INDEX IF NOT EXISTS late_fees_loan_idx ON late_fees (loan_id);
+
+-- escrow
+CREATE TABLE IF NOT EXISTS escrow_accounts(
+    id uuid PRIMARY KEY,
+    loan_id uuid NOT NULL UNIQUE,
+    balance numeric NOT NULL,
+    created_at timestamp NOT NULL,
+    modified_at timestamp NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS escrow_disbursements(
+    id uuid PRIMARY KEY,
+    account_id uuid NOT NULL REFERENCES escrow_accounts(id) ON DELETE CASCADE,
+    loan_id uuid NOT NULL,
+    type varchar NOT NULL,
+    amount numeric NOT NULL,
+    payee varchar NOT NULL,
+    disbursed_at timestamp NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS escrow_disbursements_loan_idx ON escrow_disbursements (loan_id, disbursed_at);
+
+-- outbox
+CREATE TABLE IF NOT EXISTS outbox(
+    id uuid PRIMARY KEY,
+    aggregate_type varchar NOT NULL,
+    aggregate_id uuid NOT NULL,
+    event_type varchar NOT NULL,
+    payload jsonb NOT NULL,
+    created_at timestamp NOT NULL,
+    published_at timestamp
+);
+
+CREATE INDEX IF NOT EXISTS outbox_unpublished_idx ON outbox (created_at) WHERE published_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS escrow_disbursements;
+DROP TABLE IF EXISTS escrow_accounts;
+DROP TABLE IF EXISTS late_fees;
+DROP TABLE IF EXISTS due_payments;
+DROP TABLE IF EXISTS payment_schedules;
+DROP TABLE IF EXISTS payments;
+DROP TABLE IF EXISTS loan_modifications;
+DROP TABLE IF EXISTS loan_accruals;
+DROP TABLE IF EXISTS loans;
@@ -0,0 +1,16 @@
+-- Tenancy: every loan and payment belongs to a tenant (a lender). Rows created before
+-- tenancy belong to the default tenant. Schedules, escrow, accruals and late fees are
+-- scoped through their loan.
+
+-- +goose Up
+ALTER TABLE loans ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+CREATE INDEX loans_tenant_idx ON loans (tenant_id, customer_id);
+
+ALTER TABLE payments ADD COLUMN tenant_id varchar NOT NULL DEFAULT 'default';
+CREATE INDEX payments_tenant_idx ON payments (tenant_id, customer_id);
+
+-- +goose Down
+DROP INDEX payments_tenant_idx;
+ALTER TABLE payments DROP COLUMN tenant_id;
+DROP INDEX loans_tenant_idx;
+ALTER TABLE loans DROP COLUMN tenant_id;
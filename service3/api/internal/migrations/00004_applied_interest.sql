@@ -0,0 +1,9 @@
+-- Applied interest: a payment takes at most the loan's accrued interest off it, so
+-- payments record how much they actually took for a reversal to restore. Payments
+-- made before this migration have none and reverse their full interest amount.
+
+-- +goose Up
+ALTER TABLE payments ADD COLUMN applied_interest numeric;
+
+-- +goose Down
+ALTER TABLE payments DROP COLUMN applied_interest;
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"service3/api/internal/escrow"
+	"service3/api/internal/health"
+	"service3/api/internal/latefees"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/internal/schedules"
+	"service3/api/internal/versioning"
+	"service3/api/internal/webhooks"
+)
+
+func TestDocument_Valid(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Errorf("Document is not valid OpenAPI: %v", err)
+	}
+	if len(doc.Security) != 2 {
+		t.Errorf("Expected API key and bearer security, got %v", doc.Security)
+	}
+	if readyz := doc.Paths.Value("/readyz").Get; readyz.Security == nil || len(*readyz.Security) != 0 {
+		t.Error("Expected /readyz to be served without credentials")
+	}
+
+	payment := doc.Components.Schemas["Payment"]
+	if payment == nil {
+		t.Fatal("Expected a Payment component schema")
+	}
+	if len(payment.Value.Required) != 2 {
+		t.Errorf("Expected loan_id and customer_id to be required, got %v", payment.Value.Required)
+	}
+	if paymentType := payment.Value.Properties["payment_type"].Value; len(paymentType.Enum) != 3 {
+		t.Errorf("Expected the three payment types as an enum, got %v", paymentType.Enum)
+	}
+	if amount := payment.Value.Properties["payment_amount"].Value; !amount.Type.Is("string") || amount.Format != "decimal" || amount.Min == nil {
+		t.Errorf("Expected payment_amount as a positive decimal string, got %+v", amount)
+	}
+}
+
+// TestDocument_CoversRoutes keeps the document in step with the routes: every
+// registered route must be documented, and every documented operation registered
+func TestDocument_CoversRoutes(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+
+	e := echo.New()
+	v1 := e.Group(versioning.Prefix("v1"))
+	loans.Routes(v1, loans.NewLoanHandler(nil))
+	payments.Routes(v1, payments.NewPaymentHandler(nil))
+	escrow.Routes(v1, escrow.NewEscrowHandler(nil))
+	latefees.Routes(v1, latefees.NewLateFeeHandler(nil))
+	schedules.Routes(v1, schedules.NewScheduleHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+
+	for _, route := range e.Routes() {
+		path, _ := pathParams(route.Path)
+		item := doc.Paths.Value(path)
+		if item == nil || item.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s is not documented", route.Method, route.Path)
+		}
+	}
+	if documented := len(operations); documented != len(e.Routes()) {
+		t.Errorf("Expected %d documented operations, got %d", len(e.Routes()), documented)
+	}
+}
+
+// TestDocument_MatchesClient keeps the client in step with the document: the client
+// package is generated from a copy of it, which must be regenerated when it changes
+func TestDocument_MatchesClient(t *testing.T) {
+	doc, err := Document()
+	if err != nil {
+		t.Fatalf("Document failed: %v", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	generated, err := os.ReadFile("../../pkg/client/internal/oapi/openapi.json")
+	if err != nil {
+		t.Fatalf("Unable to read the client's document: %v", err)
+	}
+
+	var want, got any
+	if err := json.Unmarshal(data, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(generated, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Error("The client was generated from a stale document; run go generate ./api/pkg/client/...")
+	}
+}
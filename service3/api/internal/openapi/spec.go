@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"service3/api/internal/escrow"
+	"service3/api/internal/health"
+	"service3/api/internal/latefees"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/internal/schedules"
+	"service3/api/internal/webhooks"
+)
+
+var pageParams = []Param{
+	{Name: "limit", Type: "integer", Description: "page size, at most 100 (default 20)"},
+	{Name: "offset", Type: "integer", Description: "number of results to skip"},
+}
+
+var paymentParams = append([]Param{
+	{Name: "type", Type: "string", Description: "regular, extra, payoff or reversal"},
+	{Name: "from", Type: "string", Description: "RFC 3339 timestamp or YYYY-MM-DD date"},
+	{Name: "to", Type: "string", Description: "RFC 3339 timestamp or YYYY-MM-DD date"},
+	{Name: "sort", Type: "string", Description: "payment_date (default), payment_amount or created_at"},
+	{Name: "order", Type: "string", Description: "desc (default) or asc"},
+}, pageParams...)
+
+var operations = []Operation{
+	{ID: "createLoan", Method: http.MethodPost, Path: "/v1/loans", Tag: "loans", Summary: "Create a loan",
+		Request: loans.Loan{}, Status: http.StatusCreated, Response: loans.Loan{}},
+	{ID: "listDelinquentLoans", Method: http.MethodGet, Path: "/v1/loans/delinquent", Tag: "loans",
+		Summary: "Aging report of delinquent loans, furthest behind first",
+		Query:   append([]Param{{Name: "bucket", Type: "string", Description: "30, 60 or 90"}}, pageParams...),
+		Status:  http.StatusOK, Response: []loans.DelinquentLoan{}},
+	{ID: "getLoan", Method: http.MethodGet, Path: "/v1/loans/:id", Tag: "loans", Summary: "Get a loan; 304 if If-None-Match names its ETag",
+		Status: http.StatusOK, Response: loans.Loan{}},
+	{ID: "updateLoan", Method: http.MethodPut, Path: "/v1/loans/:id", Tag: "loans", Summary: "Update a loan's customer and mortgage; 409 if it changes the status, balance or terms",
+		Request: loans.Loan{}, Status: http.StatusOK, Response: loans.Loan{}},
+	{ID: "deleteLoan", Method: http.MethodDelete, Path: "/v1/loans/:id", Tag: "loans",
+		Summary: "Cancel a loan; missing or already cancelled loans are not an error",
+		Status:  http.StatusNoContent},
+	{ID: "cancelLoan", Method: http.MethodPost, Path: "/v1/loans/:id/cancel", Tag: "loans",
+		Summary: "Cancel an active loan, recording who cancelled it and why",
+		Request: loans.Cancellation{}, Status: http.StatusOK, Response: loans.Loan{}},
+	{ID: "getPayoffQuote", Method: http.MethodGet, Path: "/v1/loans/:id/payoff-quote", Tag: "loans",
+		Summary: "Quote the amount needed to pay the loan off",
+		Query:   []Param{{Name: "as_of", Type: "string", Description: "YYYY-MM-DD date (default today)"}},
+		Status:  http.StatusOK, Response: loans.PayoffQuote{}},
+	{ID: "getAmortizationSchedule", Method: http.MethodGet, Path: "/v1/loans/:id/amortization", Tag: "loans",
+		Summary: "List the installments that repay an active loan from a date to maturity",
+		Query: append([]Param{{Name: "as_of", Type: "string", Description: "YYYY-MM-DD date (default today)"}},
+			pageParams...),
+		Status: http.StatusOK, Response: []loans.Installment{}},
+	{ID: "listAccruals", Method: http.MethodGet, Path: "/v1/loans/:id/accruals", Tag: "loans",
+		Summary: "List a loan's daily interest accruals",
+		Status:  http.StatusOK, Response: []loans.Accrual{}},
+	{ID: "modifyLoan", Method: http.MethodPost, Path: "/v1/loans/:id/modify", Tag: "loans",
+		Summary: "Modify a loan's rate, term or monthly payment",
+		Request: loans.ModificationRequest{}, Status: http.StatusCreated, Response: loans.Modification{}},
+	{ID: "listModifications", Method: http.MethodGet, Path: "/v1/loans/:id/modifications", Tag: "loans",
+		Summary: "List a loan's modifications",
+		Status:  http.StatusOK, Response: []loans.Modification{}},
+	{ID: "listCustomerLoans", Method: http.MethodGet, Path: "/v1/customers/:customerId/loans", Tag: "loans",
+		Summary: "List a customer's loans",
+		Query:   append([]Param{{Name: "status", Type: "string", Description: "loan status"}}, pageParams...),
+		Status:  http.StatusOK, Response: []loans.Loan{}},
+	{ID: "getCustomerLoanSummary", Method: http.MethodGet, Path: "/v1/customers/:customerId/loans/summary", Tag: "loans",
+		Summary: "Totals of a customer's loans and payments",
+		Status:  http.StatusOK, Response: loans.Summary{}},
+	{ID: "getMortgageLoan", Method: http.MethodGet, Path: "/v1/mortgages/:mortgageId/loan", Tag: "loans",
+		Summary: "Get the loan created for a mortgage application; 304 if If-None-Match names its ETag",
+		Status:  http.StatusOK, Response: loans.Loan{}},
+
+	{ID: "createPayment", Method: http.MethodPost, Path: "/v1/payments", Tag: "payments", Summary: "Record a payment",
+		Request: payments.Payment{}, Status: http.StatusCreated, Response: payments.Payment{}},
+	{ID: "createPaymentsBulk", Method: http.MethodPost, Path: "/v1/payments/bulk", Tag: "payments",
+		Summary: "Record up to 100 payments atomically; 422 with a result per payment if any is rejected",
+		Request: []payments.Payment{}, Status: http.StatusCreated, Response: []payments.BulkResult{}},
+	{ID: "getPayment", Method: http.MethodGet, Path: "/v1/payments/:id", Tag: "payments", Summary: "Get a payment",
+		Status: http.StatusOK, Response: payments.Payment{}},
+	{ID: "reversePayment", Method: http.MethodPost, Path: "/v1/payments/:id/reverse", Tag: "payments",
+		Summary: "Reverse a payment; 200 with the existing reversal if it was already reversed",
+		Status:  http.StatusCreated, Response: payments.Payment{}},
+	{ID: "listLoanPayments", Method: http.MethodGet, Path: "/v1/loans/:loanId/payments", Tag: "payments",
+		Summary: "List a loan's payments",
+		Query:   paymentParams,
+		Status:  http.StatusOK, Response: []payments.Payment{}},
+	{ID: "getLoanStatement", Method: http.MethodGet, Path: "/v1/loans/:loanId/statement", Tag: "payments",
+		Summary: "Get a loan with a page of its payments",
+		Query:   paymentParams,
+		Status:  http.StatusOK, Response: payments.Statement{}},
+	{ID: "listCustomerPayments", Method: http.MethodGet, Path: "/v1/customers/:customerId/payments", Tag: "payments",
+		Summary: "List a customer's payments",
+		Query:   paymentParams,
+		Status:  http.StatusOK, Response: []payments.Payment{}},
+
+	{ID: "openEscrow", Method: http.MethodPost, Path: "/v1/loans/:loanId/escrow", Tag: "escrow",
+		Summary: "Open a loan's escrow account",
+		Status:  http.StatusCreated, Response: escrow.Account{}},
+	{ID: "getEscrow", Method: http.MethodGet, Path: "/v1/loans/:loanId/escrow", Tag: "escrow",
+		Summary: "Get a loan's escrow account",
+		Status:  http.StatusOK, Response: escrow.Account{}},
+	{ID: "disburseEscrow", Method: http.MethodPost, Path: "/v1/loans/:loanId/escrow/disbursements", Tag: "escrow",
+		Summary: "Pay a bill from a loan's escrow account",
+		Request: escrow.Disbursement{}, Status: http.StatusCreated, Response: escrow.Disbursement{}},
+	{ID: "listEscrowDisbursements", Method: http.MethodGet, Path: "/v1/loans/:loanId/escrow/disbursements", Tag: "escrow",
+		Summary: "List a loan's escrow disbursements",
+		Status:  http.StatusOK, Response: []escrow.Disbursement{}},
+
+	{ID: "listLateFees", Method: http.MethodGet, Path: "/v1/loans/:loanId/late-fees", Tag: "late-fees",
+		Summary: "List a loan's late fees",
+		Status:  http.StatusOK, Response: []latefees.LateFee{}},
+	{ID: "getLateFee", Method: http.MethodGet, Path: "/v1/late-fees/:id", Tag: "late-fees", Summary: "Get a late fee",
+		Status: http.StatusOK, Response: latefees.LateFee{}},
+	{ID: "waiveLateFee", Method: http.MethodPost, Path: "/v1/late-fees/:id/waive", Tag: "late-fees",
+		Summary: "Waive a late fee; a reason is required",
+		Request: latefees.Waiver{}, Status: http.StatusOK, Response: latefees.LateFee{}},
+
+	{ID: "createSchedule", Method: http.MethodPost, Path: "/v1/loans/:loanId/schedules", Tag: "schedules",
+		Summary: "Create a payment schedule",
+		Request: schedules.Schedule{}, Status: http.StatusCreated, Response: schedules.Schedule{}},
+	{ID: "listSchedules", Method: http.MethodGet, Path: "/v1/loans/:loanId/schedules", Tag: "schedules",
+		Summary: "List a loan's payment schedules",
+		Status:  http.StatusOK, Response: []schedules.Schedule{}},
+	{ID: "listDuePayments", Method: http.MethodGet, Path: "/v1/loans/:loanId/due-payments", Tag: "schedules",
+		Summary: "List a loan's scheduled installments",
+		Status:  http.StatusOK, Response: []schedules.DuePayment{}},
+	{ID: "getSchedule", Method: http.MethodGet, Path: "/v1/schedules/:id", Tag: "schedules", Summary: "Get a payment schedule",
+		Status: http.StatusOK, Response: schedules.Schedule{}},
+	{ID: "updateSchedule", Method: http.MethodPut, Path: "/v1/schedules/:id", Tag: "schedules",
+		Summary: "Update a payment schedule",
+		Request: schedules.Schedule{}, Status: http.StatusOK, Response: schedules.Schedule{}},
+	{ID: "deleteSchedule", Method: http.MethodDelete, Path: "/v1/schedules/:id", Tag: "schedules",
+		Summary: "Delete a payment schedule",
+		Status:  http.StatusNoContent},
+
+	{ID: "createWebhook", Method: http.MethodPost, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "Subscribe a URL to events; the response is the only one carrying the signing secret",
+		Request: webhooks.Subscription{}, Status: http.StatusCreated, Response: webhooks.Subscription{}},
+	{ID: "listWebhooks", Method: http.MethodGet, Path: "/v1/webhooks", Tag: "webhooks",
+		Summary: "List the tenant's webhook subscriptions",
+		Status:  http.StatusOK, Response: []webhooks.Subscription{}},
+	{ID: "getWebhook", Method: http.MethodGet, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Get a webhook subscription",
+		Status:  http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "updateWebhook", Method: http.MethodPut, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Change a subscription's URL or event types, or pause it with active false",
+		Request: webhooks.Subscription{}, Status: http.StatusOK, Response: webhooks.Subscription{}},
+	{ID: "deleteWebhook", Method: http.MethodDelete, Path: "/v1/webhooks/:id", Tag: "webhooks",
+		Summary: "Delete a webhook subscription and its delivery log",
+		Status:  http.StatusNoContent},
+	{ID: "listWebhookDeliveries", Method: http.MethodGet, Path: "/v1/webhooks/:id/deliveries", Tag: "webhooks",
+		Summary: "List a subscription's deliveries, newest first",
+		Query:   pageParams,
+		Status:  http.StatusOK, Response: []webhooks.Delivery{}},
+
+	{ID: "getLiveness", Method: http.MethodGet, Path: "/healthz", Tag: "health", Public: true,
+		Summary: "Liveness probe; 200 while the process is serving",
+		Status:  http.StatusOK, Response: health.Status{}},
+	{ID: "getReadiness", Method: http.MethodGet, Path: "/readyz", Tag: "health", Public: true,
+		Summary: "Readiness probe; 503 with the failing check until the database answers",
+		Status:  http.StatusOK, Response: health.Status{}},
+}
+
+// Document returns the loan servicing service's OpenAPI document
+func Document() (*openapi3.T, error) {
+	return Build(openapi3.Info{Title: "Loan Servicing Service", Version: "1.0.0"}, operations)
+}
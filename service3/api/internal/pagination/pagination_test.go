@@ -0,0 +1,79 @@
+package pagination
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+func TestClampLimit(t *testing.T) {
+	cases := map[int]int{-5: DefaultLimit, 0: DefaultLimit, 1: 1, 50: 50, MaxLimit: MaxLimit, 500: MaxLimit}
+	for limit, want := range cases {
+		if got := ClampLimit(limit); got != want {
+			t.Errorf("ClampLimit(%d) = %d, want %d", limit, got, want)
+		}
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 1, 12, 30, 0, 123456789, time.FixedZone("EST", -5*3600))
+	cursor := TimeCursor(createdAt, uuid.New())
+
+	decoded, err := Decode(cursor.Encode())
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if *decoded != cursor {
+		t.Errorf("Expected %+v back, got %+v", cursor, *decoded)
+	}
+	at, err := decoded.Time()
+	if err != nil || !at.Equal(createdAt) {
+		t.Errorf("Expected %v, got %v (%v)", createdAt, at, err)
+	}
+
+	if first, err := Decode(""); first != nil || err != nil {
+		t.Errorf("Expected an empty cursor to ask for the first page, got %v, %v", first, err)
+	}
+	for _, token := range []string{"not base64!", "bm90IGpzb24", Cursor{Key: "x"}.Encode()} {
+		if _, err := Decode(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor for %q, got %v", token, err)
+		}
+	}
+}
+
+func TestTrim(t *testing.T) {
+	rows, more := Trim([]int{1, 2, 3}, 2)
+	if len(rows) != 2 || !more {
+		t.Errorf("Expected two rows and a next page, got %v, %v", rows, more)
+	}
+	rows, more = Trim([]int{1, 2}, 2)
+	if len(rows) != 2 || more {
+		t.Errorf("Expected the last page, got %v, %v", rows, more)
+	}
+}
+
+func TestSetNextLink(t *testing.T) {
+	e := echo.New()
+	next := Cursor{Key: "2025-03-01T00:00:00Z", Id: uuid.New()}
+
+	req := httptest.NewRequest(http.MethodGet, "/items?limit=10&cursor=old&status=active", nil)
+	rec := httptest.NewRecorder()
+	SetNextLink(e.NewContext(req, rec), &next)
+
+	want := "</items?" + url.Values{"limit": {"10"}, "cursor": {next.Encode()}, "status": {"active"}}.Encode() + `>; rel="next"`
+	if got := rec.Header().Get("Link"); got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	rec = httptest.NewRecorder()
+	SetNextLink(e.NewContext(req, rec), nil)
+	if got := rec.Header().Get("Link"); got != "" {
+		t.Errorf("Expected no Link header on the last page, got %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"service3/api/internal/loans"
+	"service3/api/internal/outbox"
+	"service3/api/internal/tenant"
+)
+
+// MaxBulkPayments is how many payments one bulk request may post
+const MaxBulkPayments = 100
+
+// ErrInvalidBulk is returned for a bulk request with no payments or more than MaxBulkPayments
+var ErrInvalidBulk = fmt.Errorf("a bulk request must hold between 1 and %d payments", MaxBulkPayments)
+
+// BulkError is returned when payments of a bulk request were rejected. None of the
+// payments was recorded. Errors holds each payment's error, in request order, and
+// is nil for the payments that would have been posted.
+type BulkError struct {
+	Errors []error
+}
+
+func (e *BulkError) Error() string {
+	rejected := 0
+	for _, err := range e.Errors {
+		if err != nil {
+			rejected++
+		}
+	}
+	return fmt.Sprintf("%d of %d payments rejected", rejected, len(e.Errors))
+}
+
+// bulkError returns a *BulkError for errs, or nil when no payment failed
+func bulkError(errs []error) error {
+	if slices.ContainsFunc(errs, func(err error) bool { return err != nil }) {
+		return &BulkError{Errors: errs}
+	}
+	return nil
+}
+
+// CreateBulk posts the payments in one transaction, each as Create would, in request
+// order, so several payments on one loan see each other's effect. Every step is sent
+// for all payments as one batch, so the number of payments does not add round trips.
+// If any payment is rejected none is recorded and a *BulkError says why.
+func (r *PaymentRepository) CreateBulk(ctx context.Context, payments []Payment) ([]Payment, error) {
+	posted := make([]Payment, len(payments))
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		statuses, err := lockLoanStatuses(ctx, tx, payments)
+		if err != nil {
+			return err
+		}
+
+		errs := make([]error, len(payments))
+		var changes []loans.StatusChange
+		batch := &pgx.Batch{}
+		for i, payment := range payments {
+			if _, ok := statuses[payment.LoanId]; !ok {
+				errs[i] = ErrLoanNotFound
+				continue
+			}
+			batch.Queue(insertSQL, insertArgs(ctx, payment)...).QueryRow(func(row pgx.Row) error {
+				var err error
+				posted[i], err = scanPayment(row)
+				return err
+			})
+			// Results are read in order, so statuses follows the loan through the batch
+			batch.Queue(applySQL, payment.PrincipalAmount, payment.InterestAmount, loans.StatusPaidOff, payment.LoanId).
+				QueryRow(func(row pgx.Row) error {
+					status := statuses[payment.LoanId]
+					var newStatus string
+					err := row.Scan(&newStatus)
+					switch {
+					case status != loans.StatusActive:
+						errs[i] = fmt.Errorf("%w: status is %s", ErrLoanNotActive, status)
+						return nil
+					case errors.Is(err, pgx.ErrNoRows):
+						errs[i] = ErrExceedsBalance
+						return nil
+					case err != nil:
+						return err
+					}
+					statuses[payment.LoanId] = newStatus
+					if newStatus != status {
+						changes = append(changes, loans.StatusChange{
+							LoanId:     payment.LoanId,
+							CustomerId: payment.CustomerId,
+							FromStatus: status,
+							ToStatus:   newStatus,
+						})
+					}
+					return nil
+				})
+			if payment.EscrowAmount.IsPositive() {
+				batch.Queue(creditEscrowSQL, payment.EscrowAmount, payment.LoanId).Exec(func(tag pgconn.CommandTag) error {
+					if tag.RowsAffected() == 0 && errs[i] == nil {
+						errs[i] = ErrNoEscrowAccount
+					}
+					return nil
+				})
+			}
+			if payment.PaymentType == TypeRegular {
+				batch.Queue(settleOldestDueSQL, payment.Id, payment.LoanId)
+			}
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return err
+		}
+		if err := bulkError(errs); err != nil {
+			return err
+		}
+
+		events := &pgx.Batch{}
+		for _, payment := range posted {
+			event, err := outbox.NewEvent(AggregateType, payment.Id, EventPaymentPosted, payment)
+			if err != nil {
+				return err
+			}
+			outbox.Queue(ctx, events, event)
+		}
+		for _, change := range changes {
+			if err := loans.QueueStatusChange(ctx, events, change); err != nil {
+				return err
+			}
+		}
+		return tx.SendBatch(ctx, events).Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return posted, nil
+}
+
+// lockLoanStatuses locks the rows of the loans the payments are for, in ID order so
+// concurrent bulk requests cannot deadlock, and returns their statuses. Loans of
+// other tenants are left out.
+func lockLoanStatuses(ctx context.Context, tx pgx.Tx, payments []Payment) (map[uuid.UUID]string, error) {
+	ids := make([]uuid.UUID, 0, len(payments))
+	for _, payment := range payments {
+		ids = append(ids, payment.LoanId)
+	}
+	sql := "SELECT id, status FROM loans WHERE id = ANY($1) AND tenant_id = $2 ORDER BY id FOR UPDATE"
+	rows, err := tx.Query(ctx, sql, ids, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := map[uuid.UUID]string{}
+	for rows.Next() {
+		var id uuid.UUID
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, err
+		}
+		statuses[id] = status
+	}
+	return statuses, rows.Err()
+}
+
+// CreateBulk validates the payments, filling in their defaults, and posts them
+// together. Invalid payments are reported before anything is written.
+func (s *PaymentService) CreateBulk(ctx context.Context, payments []Payment) ([]Payment, error) {
+	if len(payments) == 0 || len(payments) > MaxBulkPayments {
+		return nil, ErrInvalidBulk
+	}
+	now := time.Now()
+	errs := make([]error, len(payments))
+	for i := range payments {
+		errs[i] = payments[i].Validate(now)
+	}
+	if err := bulkError(errs); err != nil {
+		return nil, err
+	}
+
+	posted, err := s.repo.CreateBulk(ctx, payments)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range posted {
+		loans.Invalidate(ctx, s.cache, payment.LoanId)
+	}
+	return posted, nil
+}
@@ -1,10 +1,13 @@
 package payments
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"service3/api/internal/apierror"
 )
 
 type Handler struct {
@@ -20,53 +23,222 @@ func (h *Handler) Create(c echo.Context) error {
 	if err := c.Bind(payment); err != nil {
 		return err
 	}
+	if err := c.Validate(payment); err != nil {
+		return err
+	}
 
 	payment.Id = uuid.New()
-	if payment.PaymentType == "" {
-		payment.PaymentType = "regular"
+	created, err := h.service.Create(c.Request().Context(), *payment)
+	if err != nil {
+		return httpError(err)
 	}
-	if err := h.service.Create(c.Request().Context(), *payment); err != nil {
+
+	return c.JSON(http.StatusCreated, created)
+}
+
+// BulkResult reports what happened to one payment of a bulk request. Status is the
+// status the payment would have been answered with on its own.
+type BulkResult struct {
+	Index   int                `json:"index"`
+	Status  int                `json:"status"`
+	Payment *Payment           `json:"payment,omitempty"`
+	Error   *apierror.Response `json:"error,omitempty"`
+}
+
+// CreateBulk posts an array of payments atomically. It returns 201 with a result for
+// every payment or, when any payment is rejected, posts nothing and returns a 422
+// whose details hold the results.
+func (h *Handler) CreateBulk(c echo.Context) error {
+	var payments []Payment
+	if err := c.Bind(&payments); err != nil {
 		return err
 	}
+	errs := make([]error, len(payments))
+	for i := range payments {
+		errs[i] = c.Validate(&payments[i])
+		payments[i].Id = uuid.New()
+	}
+	err := bulkError(errs)
+	var posted []Payment
+	if err == nil {
+		posted, err = h.service.CreateBulk(c.Request().Context(), payments)
+	}
+	if err != nil {
+		return bulkHTTPError(err)
+	}
 
-	return c.JSON(http.StatusCreated, payment)
+	results := make([]BulkResult, len(posted))
+	for i := range posted {
+		results[i] = BulkResult{Index: i, Status: http.StatusCreated, Payment: &posted[i]}
+	}
+	return c.JSON(http.StatusCreated, results)
+}
+
+// bulkHTTPError reports a rejected bulk request with a result for each payment: the
+// error it would have got on its own, or a 424 when only others failed
+func bulkHTTPError(err error) error {
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) {
+		return httpError(err)
+	}
+	results := make([]BulkResult, len(bulkErr.Errors))
+	for i, itemErr := range bulkErr.Errors {
+		results[i] = BulkResult{Index: i, Status: http.StatusFailedDependency, Error: &apierror.Response{
+			Code:    "not_posted",
+			Message: "not posted because other payments in the request were rejected",
+		}}
+		if itemErr != nil {
+			status, response := apierror.Describe(httpError(itemErr))
+			results[i].Status, results[i].Error = status, &response
+		}
+	}
+	return &apierror.Error{
+		Status:  http.StatusUnprocessableEntity,
+		Code:    "bulk_rejected",
+		Message: bulkErr.Error() + "; nothing was posted",
+		Details: results,
+		Err:     err,
+	}
 }
 
 func (h *Handler) Read(c echo.Context) error {
-	id, err := uuid.Parse(c.Param("id"))
+	id, err := parseID(c, "id", "invalid payment id")
 	if err != nil {
 		return err
 	}
 
 	payment, err := h.service.Read(c.Request().Context(), id)
 	if err != nil {
-		return err
+		return httpError(err)
 	}
 	return c.JSON(http.StatusOK, payment)
 }
 
-func (h *Handler) GetByLoanId(c echo.Context) error {
-	loanId, err := uuid.Parse(c.Param("loanId"))
+// Reverse offsets a payment and restores the loan balance. It returns 201 with the
+// new reversal, or 200 with the existing one when the payment was already reversed.
+func (h *Handler) Reverse(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid payment id")
 	if err != nil {
 		return err
 	}
 
-	payments, err := h.service.GetByLoanId(c.Request().Context(), loanId)
+	reversal, created, err := h.service.Reverse(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	if created {
+		return c.JSON(http.StatusCreated, reversal)
+	}
+	return c.JSON(http.StatusOK, reversal)
+}
+
+func (h *Handler) GetByLoanId(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
 	if err != nil {
 		return err
 	}
+	filter, err := bindFilter(c)
+	if err != nil {
+		return err
+	}
+
+	payments, err := h.service.GetByLoanId(c.Request().Context(), loanId, filter)
+	if err != nil {
+		return httpError(err)
+	}
 	return c.JSON(http.StatusOK, payments)
 }
 
-func (h *Handler) GetByCustomerId(c echo.Context) error {
-	customerId, err := uuid.Parse(c.Param("customerId"))
+// GetStatement returns the loan with a page of its payments, read in one round trip
+func (h *Handler) GetStatement(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	filter, err := bindFilter(c)
 	if err != nil {
 		return err
 	}
 
-	payments, err := h.service.GetByCustomerId(c.Request().Context(), customerId)
+	statement, err := h.service.Statement(c.Request().Context(), loanId, filter)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, statement)
+}
+
+func (h *Handler) GetByCustomerId(c echo.Context) error {
+	customerId, err := parseID(c, "customerId", "invalid customer id")
+	if err != nil {
+		return err
+	}
+	filter, err := bindFilter(c)
 	if err != nil {
 		return err
 	}
+
+	payments, err := h.service.GetByCustomerId(c.Request().Context(), customerId, filter)
+	if err != nil {
+		return httpError(err)
+	}
 	return c.JSON(http.StatusOK, payments)
 }
+
+// bindFilter reads the listing query parameters
+func bindFilter(c echo.Context) (PaymentFilter, error) {
+	var filter PaymentFilter
+	err := echo.QueryParamsBinder(c).
+		String("type", &filter.Type).
+		CustomFunc("from", timeParam(&filter.From)).
+		CustomFunc("to", timeParam(&filter.To)).
+		String("sort", &filter.Sort).
+		String("order", &filter.Order).
+		Int("limit", &filter.Limit).
+		Int("offset", &filter.Offset).
+		BindError()
+	return filter, err
+}
+
+// timeParam binds a query parameter given as an RFC 3339 timestamp or a YYYY-MM-DD date
+func timeParam(dest *time.Time) func(values []string) []error {
+	return func(values []string) []error {
+		value := values[0]
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t, err = time.Parse(time.DateOnly, value)
+		}
+		if err != nil {
+			return []error{echo.NewHTTPError(http.StatusBadRequest, "expected an RFC 3339 timestamp or YYYY-MM-DD date: "+value)}
+		}
+		*dest = t.UTC()
+		return nil
+	}
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, apierror.BadRequest(message, err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return apierror.Validation(validationErr.Fields, err)
+	}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrLoanNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidFilter) || errors.Is(err, ErrInvalidBulk) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrLoanNotActive) || errors.Is(err, ErrExceedsBalance) || errors.Is(err, ErrNotReversible) ||
+		errors.Is(err, ErrNoEscrowAccount) {
+		return echo.NewHTTPError(http.StatusConflict, err.Error()).SetInternal(err)
+	}
+	return err
+}
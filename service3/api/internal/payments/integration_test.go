@@ -0,0 +1,215 @@
+//go:build integration
+
+package payments_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/apierror"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/internal/tenant"
+	"service3/api/internal/testdb"
+	"service3/api/internal/validation"
+	"service3/api/internal/versioning"
+)
+
+func TestMain(m *testing.M) {
+	testdb.Main(m)
+}
+
+// client calls the loans and payments APIs served over httptest on a database of its own,
+// wired the way main.go wires it
+type client struct {
+	t      *testing.T
+	url    string
+	header http.Header
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	pool := testdb.New(t)
+	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.Pre(versioning.Middleware())
+	e.Use(tenant.Middleware())
+	v1 := e.Group(versioning.Prefix("v1"))
+	loans.Routes(v1, loans.NewLoanHandler(loans.NewLoanService(loans.NewLoanRepository(pool))))
+	payments.Routes(v1, payments.NewPaymentHandler(payments.NewPaymentService(payments.NewPaymentRepository(pool))))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return &client{t: t, url: server.URL, header: http.Header{}}
+}
+
+// with returns a client that also sends the header key
+func (c *client) with(key, value string) *client {
+	header := c.header.Clone()
+	header.Set(key, value)
+	return &client{t: c.t, url: c.url, header: header}
+}
+
+// do sends body, if not nil, as JSON and decodes a JSON response into out, if not nil
+func (c *client) do(method, path string, body, out any) *http.Response {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	req.Header = c.header.Clone()
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			c.t.Fatalf("%s %s: decode response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+// createLoan creates an active loan with an outstanding balance of 1000
+func createLoan(t *testing.T, c *client) loans.Loan {
+	t.Helper()
+	start := time.Now().UTC().Truncate(24 * time.Hour)
+	body := map[string]any{
+		"customer_id":         uuid.New(),
+		"mortgage_id":         uuid.New(),
+		"loan_amount":         "1000",
+		"interest_rate":       0,
+		"term_years":          1,
+		"monthly_payment":     "83.33",
+		"outstanding_balance": "1000",
+		"status":              loans.StatusActive,
+		"start_date":          start,
+		"maturity_date":       start.AddDate(1, 0, 0),
+	}
+	var loan loans.Loan
+	if resp := c.do(http.MethodPost, "/v1/loans", body, &loan); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 creating the loan, got %d", resp.StatusCode)
+	}
+	return loan
+}
+
+// pay posts a regular payment of amount, all principal, and returns the response
+func pay(c *client, loan loans.Loan, amount string, out *payments.Payment) *http.Response {
+	body := map[string]any{"loan_id": loan.Id, "customer_id": loan.CustomerId, "payment_amount": amount}
+	return c.do(http.MethodPost, "/v1/payments", body, out)
+}
+
+func TestAPI_PaymentsPayOffLoan(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	loan := createLoan(t, c)
+	path := "/v1/loans/" + loan.Id.String()
+
+	if resp := pay(c, loan, "400", nil); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	var read loans.Loan
+	c.do(http.MethodGet, path, nil, &read)
+	if !read.OutstandingBalance.Equal(decimal.NewFromInt(600)) || read.Status != loans.StatusActive {
+		t.Errorf("Expected an active loan owing 600, got %s %s", read.Status, read.OutstandingBalance)
+	}
+
+	if resp := pay(c, loan, "600.01", nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("Expected paying more than the balance to get 409, got %d", resp.StatusCode)
+	}
+	var last payments.Payment
+	if resp := pay(c, loan, "600", &last); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	c.do(http.MethodGet, path, nil, &read)
+	if read.Status != loans.StatusPaidOff || !read.OutstandingBalance.IsZero() {
+		t.Errorf("Expected the loan to be paid off, got %s %s", read.Status, read.OutstandingBalance)
+	}
+
+	if resp := c.do(http.MethodPost, "/v1/payments/"+last.Id.String()+"/reverse", nil, nil); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 reversing the payment, got %d", resp.StatusCode)
+	}
+	c.do(http.MethodGet, path, nil, &read)
+	if read.Status != loans.StatusActive || !read.OutstandingBalance.Equal(decimal.NewFromInt(600)) {
+		t.Errorf("Expected the reversal to reactivate the loan owing 600, got %s %s", read.Status, read.OutstandingBalance)
+	}
+
+	var posted []payments.Payment
+	c.do(http.MethodGet, path+"/payments", nil, &posted)
+	if len(posted) != 3 {
+		t.Errorf("Expected two payments and a reversal, got %d", len(posted))
+	}
+}
+
+func TestAPI_ReversalRestoresAppliedInterest(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	loan := createLoan(t, c)
+	path := "/v1/loans/" + loan.Id.String()
+
+	// The loan has accrued no interest, so none of the interest portion is applied
+	body := map[string]any{"loan_id": loan.Id, "customer_id": loan.CustomerId,
+		"payment_amount": "150", "principal_amount": "100", "interest_amount": "50"}
+	var payment payments.Payment
+	if resp := c.do(http.MethodPost, "/v1/payments", body, &payment); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+	if payment.TenantId == "" || payment.CreatedAt.IsZero() {
+		t.Errorf("Expected the stored payment, got %+v", payment)
+	}
+	if resp := c.do(http.MethodPost, "/v1/payments/"+payment.Id.String()+"/reverse", nil, nil); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201 reversing the payment, got %d", resp.StatusCode)
+	}
+	var read loans.Loan
+	c.do(http.MethodGet, path, nil, &read)
+	if !read.OutstandingBalance.Equal(decimal.NewFromInt(1000)) || !read.AccruedInterest.IsZero() {
+		t.Errorf("Expected 1000 owing and no accrued interest, got %s and %s", read.OutstandingBalance, read.AccruedInterest)
+	}
+}
+
+func TestAPI_RejectsInvalidPayment(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	loan := createLoan(t, c)
+
+	if resp := pay(c, loan, "10.001", nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Expected fractional cents to get 422, got %d", resp.StatusCode)
+	}
+	loan.Id = uuid.New()
+	if resp := pay(c, loan, "10", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a payment on an unknown loan to get 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestAPI_TenantsAreIsolated(t *testing.T) {
+	t.Parallel()
+	c := newClient(t)
+	lenderA, lenderB := c.with(tenant.Header, "lender-a"), c.with(tenant.Header, "lender-b")
+	loan := createLoan(t, lenderA)
+
+	if resp := lenderB.do(http.MethodGet, "/v1/loans/"+loan.Id.String(), nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected another tenant's loan to be 404, got %d", resp.StatusCode)
+	}
+	if resp := pay(lenderB, loan, "10", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected a payment on another tenant's loan to get 404, got %d", resp.StatusCode)
+	}
+}
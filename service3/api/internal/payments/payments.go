@@ -2,175 +2,596 @@ package payments
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/cache"
+	"service3/api/internal/database"
+	"service3/api/internal/loans"
+	"service3/api/internal/outbox"
+	"service3/api/internal/pagination"
+	"service3/api/internal/tenant"
 )
 
 type Payment struct {
-	Id              uuid.UUID `json:"id"`
-	LoanId          uuid.UUID `json:"loan_id"`
-	CustomerId      uuid.UUID `json:"customer_id"`
-	PaymentAmount   float64   `json:"payment_amount"`
-	PrincipalAmount float64   `json:"principal_amount"`
-	InterestAmount  float64   `json:"interest_amount"`
-	PaymentDate     time.Time `json:"payment_date"`
-	PaymentType     string    `json:"payment_type"` // regular, extra, payoff
-	CreatedAt       time.Time `json:"created_at"`
+	Id              uuid.UUID       `json:"id"`
+	TenantId        string          `json:"tenant_id"`
+	LoanId          uuid.UUID       `json:"loan_id" validate:"required"`
+	CustomerId      uuid.UUID       `json:"customer_id" validate:"required"`
+	PaymentAmount   decimal.Decimal `json:"payment_amount" validate:"gt=0"`
+	PrincipalAmount decimal.Decimal `json:"principal_amount" validate:"gte=0"`
+	InterestAmount  decimal.Decimal `json:"interest_amount" validate:"gte=0"`
+	EscrowAmount    decimal.Decimal `json:"escrow_amount" validate:"gte=0"` // credited to the loan's escrow account
+	PaymentDate     time.Time       `json:"payment_date"`
+	PaymentType     string          `json:"payment_type" validate:"omitempty,oneof=regular extra payoff"` // regular, extra, payoff, reversal (created by reversing a payment)
+	ReversalOf      *uuid.UUID      `json:"reversal_of"`                                                  // for a reversal, the payment it offsets
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// Payment types. A reversal offsets an earlier payment with negated amounts.
+const (
+	TypeRegular  = "regular"
+	TypeExtra    = "extra"
+	TypePayoff   = "payoff"
+	TypeReversal = "reversal"
+)
+
+// Domain events written to the outbox alongside payment changes
+const (
+	AggregateType        = "payment"
+	EventPaymentPosted   = "PaymentPosted"
+	EventPaymentReversed = "PaymentReversed"
+)
+
+// Events lists the event types above; webhooks can be subscribed to them
+var Events = []string{EventPaymentPosted, EventPaymentReversed}
+
+var (
+	// ErrNotFound is returned when no payment exists with the requested ID
+	ErrNotFound = errors.New("payment not found")
+	// ErrInvalidPayment matches the ValidationError returned for a payment that breaks a domain rule
+	ErrInvalidPayment = errors.New("invalid payment")
+	// ErrLoanNotFound is returned when paying a loan that does not exist
+	ErrLoanNotFound = errors.New("loan not found")
+	// ErrLoanNotActive is returned when paying a loan that is paid off or defaulted
+	ErrLoanNotActive = errors.New("loan is not active")
+	// ErrExceedsBalance is returned when a payment's principal is more than the loan's outstanding balance
+	ErrExceedsBalance = errors.New("payment principal exceeds the loan's outstanding balance")
+	// ErrNotReversible is returned when reversing a payment that is itself a reversal
+	ErrNotReversible = errors.New("a reversal cannot be reversed")
+	// ErrNoEscrowAccount is returned when a payment has an escrow portion but its loan has no escrow account
+	ErrNoEscrowAccount = errors.New("loan has no escrow account")
+	// ErrInvalidFilter is returned when a listing asks for an unknown sort or order
+	ErrInvalidFilter = errors.New("invalid payment filter")
+)
+
+// PaymentFilter narrows, sorts and pages a payment listing. Zero values match
+// everything; From is inclusive and To exclusive on the payment date.
+type PaymentFilter struct {
+	Type   string
+	From   time.Time
+	To     time.Time
+	Sort   string // payment_date (default), payment_amount or created_at
+	Order  string // desc (default) or asc
+	Limit  int
+	Offset int
+}
+
+const (
+	DefaultListLimit = pagination.DefaultLimit
+	MaxListLimit     = pagination.MaxLimit
+)
+
+// sortColumns maps each accepted sort option to the column it orders by
+var sortColumns = map[string]string{
+	"payment_date":   "payment_date",
+	"payment_amount": "payment_amount",
+	"created_at":     "created_at",
+}
+
+// normalize fills in the default sort and page size and bounds the paging,
+// returning ErrInvalidFilter for an unknown sort or order
+func (f *PaymentFilter) normalize() error {
+	if f.Sort == "" {
+		f.Sort = "payment_date"
+	}
+	if _, ok := sortColumns[f.Sort]; !ok {
+		return fmt.Errorf("%w: sort must be payment_date, payment_amount or created_at", ErrInvalidFilter)
+	}
+	f.Order = strings.ToLower(f.Order)
+	if f.Order == "" {
+		f.Order = "desc"
+	}
+	if f.Order != "asc" && f.Order != "desc" {
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalidFilter)
+	}
+	f.Limit = pagination.ClampLimit(f.Limit)
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	return nil
+}
+
+// orderBy returns the ORDER BY clause of a normalized filter; id breaks ties so
+// pages do not overlap
+func (f PaymentFilter) orderBy() string {
+	return "ORDER BY " + sortColumns[f.Sort] + " " + strings.ToUpper(f.Order) + ", id"
+}
+
+// Statement is a loan together with a page of its payments
+type Statement struct {
+	Loan     loans.Loan `json:"loan"`
+	Payments []Payment  `json:"payments"`
 }
 
 type Repository interface {
-	Create(ctx context.Context, payment Payment) error
+	Create(ctx context.Context, payment Payment) (Payment, error)
+	CreateBulk(ctx context.Context, payments []Payment) ([]Payment, error)
 	Read(ctx context.Context, id uuid.UUID) (Payment, error)
-	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Payment, error)
-	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Payment, error)
+	Reverse(ctx context.Context, id uuid.UUID) (Payment, bool, error)
+	GetByLoanId(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error)
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter PaymentFilter) ([]Payment, error)
+	Statement(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) (Statement, error)
 }
 
 type Service interface {
-	Create(ctx context.Context, payment Payment) error
+	Create(ctx context.Context, payment Payment) (Payment, error)
+	CreateBulk(ctx context.Context, payments []Payment) ([]Payment, error)
 	Read(ctx context.Context, id uuid.UUID) (Payment, error)
-	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Payment, error)
-	GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Payment, error)
+	Reverse(ctx context.Context, id uuid.UUID) (Payment, bool, error)
+	GetByLoanId(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error)
+	GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter PaymentFilter) ([]Payment, error)
+	Statement(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) (Statement, error)
+}
+
+const paymentColumns = `id, tenant_id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
+	escrow_amount, payment_date, payment_type, reversal_of, created_at`
+
+// scanPayment scans a row selected with paymentColumns
+func scanPayment(row pgx.Row) (Payment, error) {
+	var payment Payment
+	err := row.Scan(
+		&payment.Id,
+		&payment.TenantId,
+		&payment.LoanId,
+		&payment.CustomerId,
+		&payment.PaymentAmount,
+		&payment.PrincipalAmount,
+		&payment.InterestAmount,
+		&payment.EscrowAmount,
+		&payment.PaymentDate,
+		&payment.PaymentType,
+		&payment.ReversalOf,
+		&payment.CreatedAt,
+	)
+	return payment, err
 }
 
 type PaymentRepository struct {
-	conn *pgx.Conn
+	db database.DB
 }
 
-func NewPaymentRepository(conn *pgx.Conn) *PaymentRepository {
-	return &PaymentRepository{conn}
+func NewPaymentRepository(pool *pgxpool.Pool) *PaymentRepository {
+	return &PaymentRepository{pool}
 }
 
-func (r *PaymentRepository) Create(ctx context.Context, payment Payment) error {
-	sql := `INSERT INTO payments
-		(id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
-		 payment_date, payment_type, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())`
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *PaymentRepository) WithTx(tx pgx.Tx) *PaymentRepository {
+	return &PaymentRepository{tx}
+}
+
+// The statements posting a payment, shared by Create and CreateBulk
+const (
+	// applySQL takes the payment's principal and interest off the loan, paying it off
+	// at a zero balance, and returns the loan's new status. No row comes back when the
+	// principal exceeds the outstanding balance, so the principal is applied in full or
+	// not at all; the interest stops at the accrued interest.
+	applySQL = `UPDATE loans
+		SET outstanding_balance = outstanding_balance - $1,
+			accrued_interest = GREATEST(accrued_interest - $2, 0),
+			status = CASE WHEN outstanding_balance - $1 <= 0 THEN $3 ELSE status END,
+			modified_at = NOW()
+		WHERE id = $4 AND outstanding_balance >= $1
+		RETURNING status`
+	creditEscrowSQL = "UPDATE escrow_accounts SET balance = balance + $1, modified_at = NOW() WHERE loan_id = $2"
+	// insertSQL records the payment with the interest applySQL will take off the loan,
+	// so it must run before applySQL
+	insertSQL = `INSERT INTO payments
+		(id, tenant_id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
+		 escrow_amount, payment_date, payment_type, applied_interest, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			(SELECT LEAST($7, accrued_interest) FROM loans WHERE id = $3), NOW())
+		RETURNING ` + paymentColumns
+	settleOldestDueSQL = `UPDATE due_payments SET status = 'paid', payment_id = $1, paid_at = NOW()
+		WHERE id = (
+			SELECT id FROM due_payments WHERE loan_id = $2 AND status = 'due'
+			ORDER BY due_date LIMIT 1
+			FOR UPDATE
+		)`
+)
 
-	_, err := r.conn.Exec(ctx, sql,
+// insertArgs returns the arguments of insertSQL for payment in the tenant of ctx
+func insertArgs(ctx context.Context, payment Payment) []any {
+	return []any{
 		payment.Id,
+		tenant.FromContext(ctx),
 		payment.LoanId,
 		payment.CustomerId,
 		payment.PaymentAmount,
 		payment.PrincipalAmount,
 		payment.InterestAmount,
+		payment.EscrowAmount,
 		payment.PaymentDate,
 		payment.PaymentType,
-	)
+	}
+}
+
+// Create records the payment and applies its principal to the loan's balance, its
+// interest to the loan's accrued interest and its escrow portion to the loan's escrow account in one transaction,
+// marking the loan paid off when its outstanding balance reaches zero.
+// A regular payment also settles the loan's oldest open scheduled installment.
+// PaymentPosted, and LoanStatusChanged on payoff, are recorded in the same transaction.
+// It returns the payment as stored, with its tenant and creation time.
+func (r *PaymentRepository) Create(ctx context.Context, payment Payment) (Payment, error) {
+	var posted Payment
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		status, err := lockLoanStatus(ctx, tx, payment.LoanId)
+		if err != nil {
+			return err
+		}
+		if status != loans.StatusActive {
+			return fmt.Errorf("%w: status is %s", ErrLoanNotActive, status)
+		}
+
+		posted, err = scanPayment(tx.QueryRow(ctx, insertSQL, insertArgs(ctx, payment)...))
+		if err != nil {
+			return err
+		}
+
+		var newStatus string
+		err = tx.QueryRow(ctx, applySQL, payment.PrincipalAmount, payment.InterestAmount, loans.StatusPaidOff, payment.LoanId).Scan(&newStatus)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrExceedsBalance
+		}
+		if err != nil {
+			return err
+		}
+		if err := recordStatusChange(ctx, tx, payment, status, newStatus); err != nil {
+			return err
+		}
+
+		if payment.EscrowAmount.IsPositive() {
+			err := creditEscrow(ctx, tx, payment.LoanId, payment.EscrowAmount)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := recordEvent(ctx, tx, posted.Id, EventPaymentPosted, posted); err != nil {
+			return err
+		}
+		if payment.PaymentType != TypeRegular {
+			return nil
+		}
+		return settleOldestDue(ctx, tx, payment)
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	return posted, nil
+}
+
+// lockLoanStatus locks the loan row for the rest of the transaction and returns its
+// status. Loans of other tenants are not found.
+func lockLoanStatus(ctx context.Context, tx pgx.Tx, loanId uuid.UUID) (string, error) {
+	var status string
+	sql := "SELECT status FROM loans WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+	err := tx.QueryRow(ctx, sql, loanId, tenant.FromContext(ctx)).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrLoanNotFound
+	}
+	return status, err
+}
+
+// recordStatusChange records a LoanStatusChanged event when applying or reversing
+// payment moved the loan from one status to another
+func recordStatusChange(ctx context.Context, tx pgx.Tx, payment Payment, from, to string) error {
+	if from == to {
+		return nil
+	}
+	return loans.RecordStatusChange(ctx, tx, loans.StatusChange{
+		LoanId:     payment.LoanId,
+		CustomerId: payment.CustomerId,
+		FromStatus: from,
+		ToStatus:   to,
+	})
+}
+
+// recordEvent writes a payment event to the outbox in the caller's transaction
+func recordEvent(ctx context.Context, tx pgx.Tx, id uuid.UUID, eventType string, payload any) error {
+	event, err := outbox.NewEvent(AggregateType, id, eventType, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Insert(ctx, tx, event)
+}
+
+// creditEscrow adds amount to the loan's escrow balance; a negative amount takes a
+// reversed escrow portion back out, which may leave the account short
+func creditEscrow(ctx context.Context, tx pgx.Tx, loanId uuid.UUID, amount decimal.Decimal) error {
+	tag, err := tx.Exec(ctx, creditEscrowSQL, amount, loanId)
 	if err != nil {
 		return err
 	}
+	if tag.RowsAffected() == 0 {
+		return ErrNoEscrowAccount
+	}
 	return nil
 }
 
+// settleOldestDue marks the loan's oldest open scheduled installment as paid by payment
+func settleOldestDue(ctx context.Context, tx pgx.Tx, payment Payment) error {
+	_, err := tx.Exec(ctx, settleOldestDueSQL, payment.Id, payment.LoanId)
+	return err
+}
+
 func (r *PaymentRepository) Read(ctx context.Context, id uuid.UUID) (Payment, error) {
-	sql := `SELECT id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
-		payment_date, payment_type, created_at
-		FROM payments WHERE id = $1`
-	row := r.conn.QueryRow(ctx, sql, id)
-	var payment Payment
-	err := row.Scan(
-		&payment.Id,
-		&payment.LoanId,
-		&payment.CustomerId,
-		&payment.PaymentAmount,
-		&payment.PrincipalAmount,
-		&payment.InterestAmount,
-		&payment.PaymentDate,
-		&payment.PaymentType,
-		&payment.CreatedAt,
-	)
+	sql := "SELECT " + paymentColumns + " FROM payments WHERE id = $1 AND tenant_id = $2"
+	payment, err := scanPayment(r.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Payment{}, ErrNotFound
+	}
 	if err != nil {
 		return Payment{}, err
 	}
 	return payment, nil
 }
 
-func (r *PaymentRepository) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Payment, error) {
-	sql := `SELECT id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
-		payment_date, payment_type, created_at
-		FROM payments WHERE loan_id = $1 ORDER BY payment_date DESC`
-	rows, err := r.conn.Query(ctx, sql, loanId)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+// Reverse offsets the payment with a reversal record carrying the negated amounts and
+// restores its principal and the interest it applied to the loan's balance and accrued interest and takes its escrow
+// portion back out of the escrow account, reactivating a paid-off
+// loan and reopening any installment it settled. Reversing a payment again returns the existing reversal with created false,
+// so compensations can be retried. PaymentReversed, and LoanStatusChanged on reactivation, are recorded in the same transaction.
+func (r *PaymentRepository) Reverse(ctx context.Context, id uuid.UUID) (Payment, bool, error) {
+	var reversal Payment
+	created := false
+	err := r.withTx(ctx, func(tx pgx.Tx) error {
+		sql := "SELECT " + paymentColumns + " FROM payments WHERE id = $1 AND tenant_id = $2 FOR UPDATE"
+		original, err := scanPayment(tx.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if original.PaymentType == TypeReversal {
+			return ErrNotReversible
+		}
+		// Restore only the interest the payment took off the loan
+		var appliedInterest decimal.Decimal
+		sql = "SELECT COALESCE(applied_interest, interest_amount) FROM payments WHERE id = $1"
+		if err := tx.QueryRow(ctx, sql, id).Scan(&appliedInterest); err != nil {
+			return err
+		}
 
-	var payments []Payment
-	for rows.Next() {
-		var payment Payment
-		err := rows.Scan(
-			&payment.Id,
-			&payment.LoanId,
-			&payment.CustomerId,
-			&payment.PaymentAmount,
-			&payment.PrincipalAmount,
-			&payment.InterestAmount,
-			&payment.PaymentDate,
-			&payment.PaymentType,
-			&payment.CreatedAt,
-		)
+		reversal, err = scanPayment(tx.QueryRow(ctx, "SELECT "+paymentColumns+" FROM payments WHERE reversal_of = $1", id))
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		status, err := lockLoanStatus(ctx, tx, original.LoanId)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		payments = append(payments, payment)
+
+		sql = `UPDATE loans
+			SET outstanding_balance = outstanding_balance + $1,
+				accrued_interest = accrued_interest + $2,
+				status = CASE WHEN status = $3 THEN $4 ELSE status END,
+				modified_at = NOW()
+			WHERE id = $5
+			RETURNING status`
+		var newStatus string
+		err = tx.QueryRow(ctx, sql, original.PrincipalAmount, appliedInterest, loans.StatusPaidOff, loans.StatusActive, original.LoanId).Scan(&newStatus)
+		if err != nil {
+			return err
+		}
+		if err := recordStatusChange(ctx, tx, original, status, newStatus); err != nil {
+			return err
+		}
+
+		if original.EscrowAmount.IsPositive() {
+			err := creditEscrow(ctx, tx, original.LoanId, original.EscrowAmount.Neg())
+			if err != nil {
+				return err
+			}
+		}
+
+		// The installment the payment settled is open again
+		sql = "UPDATE due_payments SET status = 'due', payment_id = NULL, paid_at = NULL WHERE payment_id = $1"
+		if _, err := tx.Exec(ctx, sql, original.Id); err != nil {
+			return err
+		}
+
+		sql = `INSERT INTO payments
+			(id, tenant_id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
+			 escrow_amount, payment_date, payment_type, reversal_of, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), $9, $10, NOW())
+			RETURNING ` + paymentColumns
+		reversal, err = scanPayment(tx.QueryRow(ctx, sql,
+			uuid.New(),
+			original.TenantId,
+			original.LoanId,
+			original.CustomerId,
+			original.PaymentAmount.Neg(),
+			original.PrincipalAmount.Neg(),
+			original.InterestAmount.Neg(),
+			original.EscrowAmount.Neg(),
+			TypeReversal,
+			original.Id,
+		))
+		if err != nil {
+			return err
+		}
+		created = true
+		return recordEvent(ctx, tx, reversal.Id, EventPaymentReversed, reversal)
+	})
+	if err != nil {
+		return Payment{}, false, err
 	}
-	return payments, nil
+	return reversal, created, nil
+}
+
+func (r *PaymentRepository) GetByLoanId(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	return r.list(ctx, "loan_id", loanId, filter)
+}
+
+func (r *PaymentRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	return r.list(ctx, "customer_id", customerId, filter)
 }
 
-func (r *PaymentRepository) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Payment, error) {
-	sql := `SELECT id, loan_id, customer_id, payment_amount, principal_amount, interest_amount,
-		payment_date, payment_type, created_at
-		FROM payments WHERE customer_id = $1 ORDER BY payment_date DESC`
-	rows, err := r.conn.Query(ctx, sql, customerId)
+// list returns a page of the payments whose owner column (loan_id or customer_id)
+// is id. The filter must be normalized.
+func (r *PaymentRepository) list(ctx context.Context, column string, id uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	sql, args := listQuery(ctx, column, id, filter)
+	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
+	return scanPayments(rows)
+}
+
+// Statement reads the loan and a page of its payments in one round trip. The filter
+// must be normalized.
+func (r *PaymentRepository) Statement(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) (Statement, error) {
+	var statement Statement
+	batch := &pgx.Batch{}
+	loans.QueueRead(ctx, batch, loanId, &statement.Loan)
+	sql, args := listQuery(ctx, "loan_id", loanId, filter)
+	batch.Queue(sql, args...).Query(func(rows pgx.Rows) error {
+		var err error
+		statement.Payments, err = scanPayments(rows)
+		return err
+	})
+
+	err := r.db.SendBatch(ctx, batch).Close()
+	if errors.Is(err, loans.ErrNotFound) {
+		return Statement{}, ErrLoanNotFound
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+	return statement, nil
+}
+
+// listQuery selects a page of the payments whose owner column is id in the tenant of ctx
+func listQuery(ctx context.Context, column string, id uuid.UUID, filter PaymentFilter) (string, []any) {
+	sql := "SELECT " + paymentColumns + " FROM payments WHERE " + column + ` = $1 AND tenant_id = $2
+			AND ($3 = '' OR payment_type = $3)
+			AND ($4::timestamp IS NULL OR payment_date >= $4)
+			AND ($5::timestamp IS NULL OR payment_date < $5)
+		` + filter.orderBy() + `
+		LIMIT $6 OFFSET $7`
+	return sql, []any{id, tenant.FromContext(ctx), filter.Type, nullIfZero(filter.From), nullIfZero(filter.To),
+		filter.Limit, filter.Offset}
+}
+
+// scanPayments scans and closes rows selected with paymentColumns
+func scanPayments(rows pgx.Rows) ([]Payment, error) {
 	defer rows.Close()
 
-	var payments []Payment
+	payments := []Payment{}
 	for rows.Next() {
-		var payment Payment
-		err := rows.Scan(
-			&payment.Id,
-			&payment.LoanId,
-			&payment.CustomerId,
-			&payment.PaymentAmount,
-			&payment.PrincipalAmount,
-			&payment.InterestAmount,
-			&payment.PaymentDate,
-			&payment.PaymentType,
-			&payment.CreatedAt,
-		)
+		payment, err := scanPayment(rows)
 		if err != nil {
 			return nil, err
 		}
 		payments = append(payments, payment)
 	}
-	return payments, nil
+	return payments, rows.Err()
+}
+
+// nullIfZero maps an unset time bound to SQL NULL
+func nullIfZero(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// withTx runs fn in a transaction, committing only if fn succeeds
+func (r *PaymentRepository) withTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return database.InTx(ctx, r.db, fn)
 }
 
 type PaymentService struct {
-	repo Repository
+	repo  Repository
+	cache cache.Cache
 }
 
 func NewPaymentService(repo Repository) *PaymentService {
-	return &PaymentService{repo}
+	return &PaymentService{repo: repo, cache: cache.Nop{}}
 }
 
-func (s *PaymentService) Create(ctx context.Context, payment Payment) error {
-	return s.repo.Create(ctx, payment)
+// WithCache drops the paid loan from c whenever a payment or reversal changes its balance
+func (s *PaymentService) WithCache(c cache.Cache) *PaymentService {
+	s.cache = c
+	return s
+}
+
+// Create validates the payment, filling in its defaults, records it and returns the
+// stored payment
+func (s *PaymentService) Create(ctx context.Context, payment Payment) (Payment, error) {
+	if err := payment.Validate(time.Now()); err != nil {
+		return Payment{}, err
+	}
+	posted, err := s.repo.Create(ctx, payment)
+	if err != nil {
+		return Payment{}, err
+	}
+	loans.Invalidate(ctx, s.cache, posted.LoanId)
+	return posted, nil
 }
 
 func (s *PaymentService) Read(ctx context.Context, id uuid.UUID) (Payment, error) {
 	return s.repo.Read(ctx, id)
 }
 
-func (s *PaymentService) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Payment, error) {
-	return s.repo.GetByLoanId(ctx, loanId)
+func (s *PaymentService) Reverse(ctx context.Context, id uuid.UUID) (Payment, bool, error) {
+	reversal, created, err := s.repo.Reverse(ctx, id)
+	if err != nil {
+		return Payment{}, false, err
+	}
+	loans.Invalidate(ctx, s.cache, reversal.LoanId)
+	return reversal, created, nil
+}
+
+func (s *PaymentService) GetByLoanId(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	if err := filter.normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByLoanId(ctx, loanId, filter)
+}
+
+func (s *PaymentService) Statement(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) (Statement, error) {
+	if err := filter.normalize(); err != nil {
+		return Statement{}, err
+	}
+	return s.repo.Statement(ctx, loanId, filter)
 }
 
-func (s *PaymentService) GetByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Payment, error) {
-	return s.repo.GetByCustomerId(ctx, customerId)
+func (s *PaymentService) GetByCustomerId(ctx context.Context, customerId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	if err := filter.normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByCustomerId(ctx, customerId, filter)
 }
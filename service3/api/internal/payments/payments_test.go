@@ -0,0 +1,203 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/cache"
+	"service3/api/internal/loans"
+)
+
+func TestPaymentFilter_normalize(t *testing.T) {
+	var filter PaymentFilter
+	if err := filter.normalize(); err != nil {
+		t.Fatalf("Expected the zero filter to be valid, got %v", err)
+	}
+	if filter.Limit != DefaultListLimit || filter.Sort != "payment_date" || filter.Order != "desc" {
+		t.Errorf("Expected the default page and sort, got %+v", filter)
+	}
+	if got := filter.orderBy(); got != "ORDER BY payment_date DESC, id" {
+		t.Errorf("Unexpected order by clause %q", got)
+	}
+
+	filter = PaymentFilter{Sort: "payment_amount", Order: "ASC", Limit: 500, Offset: -1}
+	if err := filter.normalize(); err != nil {
+		t.Fatalf("Expected a valid filter, got %v", err)
+	}
+	if filter.Limit != MaxListLimit || filter.Offset != 0 {
+		t.Errorf("Expected the page to be bounded, got limit %d offset %d", filter.Limit, filter.Offset)
+	}
+	if got := filter.orderBy(); got != "ORDER BY payment_amount ASC, id" {
+		t.Errorf("Unexpected order by clause %q", got)
+	}
+
+	for _, filter := range []PaymentFilter{{Sort: "customer_id; DROP TABLE payments"}, {Order: "sideways"}} {
+		if err := filter.normalize(); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("Expected ErrInvalidFilter for %+v, got %v", filter, err)
+		}
+	}
+}
+
+func TestPayment_Validate(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	payment := Payment{LoanId: uuid.New(), CustomerId: uuid.New(), PaymentAmount: decimal.NewFromInt(1500)}
+	if err := payment.Validate(now); err != nil {
+		t.Fatalf("Expected a valid payment, got %v", err)
+	}
+	if payment.PaymentType != TypeRegular || !payment.PaymentDate.Equal(now) || !payment.PrincipalAmount.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("Expected the defaults to be filled in, got %+v", payment)
+	}
+
+	payment = Payment{
+		PaymentAmount:   decimal.NewFromInt(100),
+		PrincipalAmount: decimal.NewFromInt(80),
+		InterestAmount:  decimal.NewFromInt(30),
+		PaymentType:     TypeReversal,
+		PaymentDate:     now.AddDate(0, 0, MaxFutureDays+1),
+	}
+	err := payment.Validate(now)
+	if !errors.Is(err, ErrInvalidPayment) {
+		t.Fatalf("Expected ErrInvalidPayment, got %v", err)
+	}
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	got := map[string]bool{}
+	for _, field := range validationErr.Fields {
+		got[field.Field] = true
+	}
+	for _, field := range []string{"loan_id", "customer_id", "payment_type", "payment_date", "payment_amount"} {
+		if !got[field] {
+			t.Errorf("Expected an error for %s, got %v", field, validationErr.Fields)
+		}
+	}
+}
+
+func TestPayment_Validate_Escrow(t *testing.T) {
+	now := time.Now()
+	payment := Payment{LoanId: uuid.New(), CustomerId: uuid.New(), PaymentAmount: decimal.NewFromInt(1500), EscrowAmount: decimal.NewFromInt(300)}
+	if err := payment.Validate(now); err != nil {
+		t.Fatalf("Expected a valid payment, got %v", err)
+	}
+	if !payment.PrincipalAmount.Equal(decimal.NewFromInt(1200)) {
+		t.Errorf("Expected the amount after escrow to go to principal, got %v", payment.PrincipalAmount)
+	}
+
+	invalid := []Payment{
+		{PaymentAmount: decimal.NewFromInt(100), EscrowAmount: decimal.NewFromInt(150)},
+		{PaymentAmount: decimal.NewFromInt(100), EscrowAmount: decimal.NewFromInt(-10)},
+		{PaymentAmount: decimal.NewFromInt(1500), PrincipalAmount: decimal.NewFromInt(1000), InterestAmount: decimal.NewFromInt(400), EscrowAmount: decimal.NewFromInt(300)},
+	}
+	for _, payment := range invalid {
+		payment.LoanId, payment.CustomerId = uuid.New(), uuid.New()
+		if err := payment.Validate(now); !errors.Is(err, ErrInvalidPayment) {
+			t.Errorf("Expected ErrInvalidPayment for %+v, got %v", payment, err)
+		}
+	}
+}
+
+func TestPayment_Validate_WholeCents(t *testing.T) {
+	now := time.Now()
+	payment := Payment{
+		LoanId:          uuid.New(),
+		CustomerId:      uuid.New(),
+		PaymentAmount:   decimal.RequireFromString("0.30"),
+		PrincipalAmount: decimal.RequireFromString("0.10"),
+		InterestAmount:  decimal.RequireFromString("0.20"),
+	}
+	if err := payment.Validate(now); err != nil {
+		t.Errorf("Expected 0.10 + 0.20 to add up to 0.30 exactly, got %v", err)
+	}
+
+	payment.PaymentAmount = decimal.RequireFromString("0.305")
+	if err := payment.Validate(now); !errors.Is(err, ErrInvalidPayment) {
+		t.Errorf("Expected ErrInvalidPayment for a fraction of a cent, got %v", err)
+	}
+}
+
+var reversedLoanId = uuid.New()
+
+// recordingCache records the keys deleted from it
+type recordingCache struct {
+	cache.Nop
+	deleted []string
+}
+
+func (c *recordingCache) Delete(ctx context.Context, keys ...string) error {
+	c.deleted = append(c.deleted, keys...)
+	return nil
+}
+
+// postingRepository accepts every payment and reverses payments of reversedLoanId
+type postingRepository struct {
+	Repository
+}
+
+func (postingRepository) Create(ctx context.Context, payment Payment) (Payment, error) {
+	payment.TenantId = "lender-a"
+	payment.CreatedAt = time.Now()
+	return payment, nil
+}
+
+func (postingRepository) Reverse(ctx context.Context, id uuid.UUID) (Payment, bool, error) {
+	return Payment{Id: uuid.New(), LoanId: reversedLoanId, ReversalOf: &id}, true, nil
+}
+
+func TestPaymentService_InvalidatesCachedLoan(t *testing.T) {
+	recorder := &recordingCache{}
+	service := NewPaymentService(postingRepository{}).WithCache(recorder)
+	ctx := context.Background()
+
+	payment, err := service.Create(ctx, Payment{LoanId: uuid.New(), CustomerId: uuid.New(), PaymentAmount: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if payment.TenantId != "lender-a" || payment.CreatedAt.IsZero() {
+		t.Errorf("Expected the stored payment, got %+v", payment)
+	}
+	if _, _, err := service.Reverse(ctx, payment.Id); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+	want := []string{loans.CacheKey(payment.LoanId), loans.CacheKey(reversedLoanId)}
+	if len(recorder.deleted) != 2 || recorder.deleted[0] != want[0] || recorder.deleted[1] != want[1] {
+		t.Errorf("Expected %v to be invalidated, got %v", want, recorder.deleted)
+	}
+}
+
+func (postingRepository) CreateBulk(ctx context.Context, payments []Payment) ([]Payment, error) {
+	return payments, nil
+}
+
+func TestPaymentService_CreateBulk(t *testing.T) {
+	service := NewPaymentService(postingRepository{})
+	ctx := context.Background()
+
+	for _, payments := range [][]Payment{nil, make([]Payment, MaxBulkPayments+1)} {
+		if _, err := service.CreateBulk(ctx, payments); !errors.Is(err, ErrInvalidBulk) {
+			t.Errorf("Expected ErrInvalidBulk for %d payments, got %v", len(payments), err)
+		}
+	}
+
+	valid := Payment{LoanId: uuid.New(), CustomerId: uuid.New(), PaymentAmount: decimal.NewFromInt(100)}
+	_, err := service.CreateBulk(ctx, []Payment{valid, {LoanId: valid.LoanId}})
+	var bulkErr *BulkError
+	if !errors.As(err, &bulkErr) || len(bulkErr.Errors) != 2 {
+		t.Fatalf("Expected a BulkError for both payments, got %v", err)
+	}
+	if bulkErr.Errors[0] != nil || !errors.Is(bulkErr.Errors[1], ErrInvalidPayment) {
+		t.Errorf("Expected only the second payment to be rejected, got %v", bulkErr.Errors)
+	}
+
+	posted, err := service.CreateBulk(ctx, []Payment{valid, valid})
+	if err != nil {
+		t.Fatalf("CreateBulk failed: %v", err)
+	}
+	if len(posted) != 2 || posted[1].PaymentType != TypeRegular {
+		t.Errorf("Expected both payments posted with their defaults, got %+v", posted)
+	}
+}
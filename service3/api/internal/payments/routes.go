@@ -2,9 +2,12 @@ package payments
 
 import "github.com/labstack/echo/v4"
 
-func Routes(e *echo.Echo, handler Handler) {
-	e.POST("/payments", handler.Create)
-	e.GET("/payments/:id", handler.Read)
-	e.GET("/loans/:loanId/payments", handler.GetByLoanId)
-	e.GET("/customers/:customerId/payments", handler.GetByCustomerId)
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/payments", handler.Create)
+	g.POST("/payments/bulk", handler.CreateBulk)
+	g.GET("/payments/:id", handler.Read)
+	g.POST("/payments/:id/reverse", handler.Reverse)
+	g.GET("/loans/:loanId/payments", handler.GetByLoanId)
+	g.GET("/loans/:loanId/statement", handler.GetStatement)
+	g.GET("/customers/:customerId/payments", handler.GetByCustomerId)
 }
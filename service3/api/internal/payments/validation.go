@@ -0,0 +1,110 @@
+package payments
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxFutureDays is how far ahead of today a payment may be dated
+const MaxFutureDays = 30
+
+// FieldError describes why a single payment field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned when a payment breaks a domain rule. It matches
+// ErrInvalidPayment with errors.Is.
+type ValidationError struct {
+	Fields []FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "invalid payment: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidPayment
+}
+
+// Validate checks the payment against the rules for recording it on now, reporting
+// every rule it breaks. It fills in the defaults first: a regular payment type, a
+// payment date of now, and, for a payment sent without a principal/interest split,
+// principal of the amount after its escrow portion.
+func (p *Payment) Validate(now time.Time) error {
+	var fields []FieldError
+	fail := func(field, format string, args ...any) {
+		fields = append(fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if p.PaymentType == "" {
+		p.PaymentType = TypeRegular
+	}
+	if p.PaymentDate.IsZero() {
+		p.PaymentDate = now.UTC()
+	}
+
+	if p.LoanId == uuid.Nil {
+		fail("loan_id", "is required")
+	}
+	if p.CustomerId == uuid.Nil {
+		fail("customer_id", "is required")
+	}
+	switch p.PaymentType {
+	case TypeRegular, TypeExtra, TypePayoff:
+	case TypeReversal:
+		fail("payment_type", "reversals are created by reversing a payment")
+	default:
+		fail("payment_type", "must be %s, %s or %s", TypeRegular, TypeExtra, TypePayoff)
+	}
+	if latest := now.AddDate(0, 0, MaxFutureDays); p.PaymentDate.After(latest) {
+		fail("payment_date", "must not be more than %d days in the future", MaxFutureDays)
+	}
+
+	amountsValid := true
+	check := func(field string, amount decimal.Decimal) {
+		switch {
+		case amount.IsNegative():
+			fail(field, "must not be negative")
+			amountsValid = false
+		case !amount.Equal(amount.Round(2)):
+			fail(field, "must be in whole cents")
+			amountsValid = false
+		}
+	}
+	if !p.PaymentAmount.IsPositive() {
+		fail("payment_amount", "must be greater than 0")
+		amountsValid = false
+	} else {
+		check("payment_amount", p.PaymentAmount)
+	}
+	check("principal_amount", p.PrincipalAmount)
+	check("interest_amount", p.InterestAmount)
+	check("escrow_amount", p.EscrowAmount)
+	if amountsValid && p.EscrowAmount.GreaterThan(p.PaymentAmount) {
+		fail("escrow_amount", "must not exceed payment_amount")
+		amountsValid = false
+	}
+	if amountsValid {
+		if p.PrincipalAmount.IsZero() && p.InterestAmount.IsZero() {
+			p.PrincipalAmount = p.PaymentAmount.Sub(p.EscrowAmount)
+		}
+		if !p.PrincipalAmount.Add(p.InterestAmount).Add(p.EscrowAmount).Equal(p.PaymentAmount) {
+			fail("payment_amount", "must equal principal_amount + interest_amount + escrow_amount")
+		}
+	}
+
+	if len(fields) > 0 {
+		return &ValidationError{Fields: fields}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package schedules
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewScheduleHandler(service Service) Handler {
+	return Handler{service}
+}
+
+func (h *Handler) Create(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+	schedule := new(Schedule)
+	if err := c.Bind(schedule); err != nil {
+		return err
+	}
+	if err := schedule.Validate(); err != nil {
+		return httpError(err)
+	}
+
+	schedule.Id = uuid.New()
+	schedule.LoanId = loanId
+	created, err := h.service.Create(c.Request().Context(), *schedule)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusCreated, created)
+}
+
+func (h *Handler) Read(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid schedule id")
+	if err != nil {
+		return err
+	}
+
+	schedule, err := h.service.Read(c.Request().Context(), id)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handler) Update(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid schedule id")
+	if err != nil {
+		return err
+	}
+	schedule := new(Schedule)
+	if err := c.Bind(schedule); err != nil {
+		return err
+	}
+	if err := schedule.Validate(); err != nil {
+		return httpError(err)
+	}
+
+	schedule.Id = id
+	updated, err := h.service.Update(c.Request().Context(), *schedule)
+	if err != nil {
+		return httpError(err)
+	}
+	return c.JSON(http.StatusOK, updated)
+}
+
+func (h *Handler) Delete(c echo.Context) error {
+	id, err := parseID(c, "id", "invalid schedule id")
+	if err != nil {
+		return err
+	}
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) GetByLoanId(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	schedules, err := h.service.GetByLoanId(c.Request().Context(), loanId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, schedules)
+}
+
+func (h *Handler) GetDuePayments(c echo.Context) error {
+	loanId, err := parseID(c, "loanId", "invalid loan id")
+	if err != nil {
+		return err
+	}
+
+	dues, err := h.service.GetDuePayments(c.Request().Context(), loanId)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, dues)
+}
+
+// parseID reads a UUID path parameter, rejecting malformed IDs with a 400
+func parseID(c echo.Context, param, message string) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		return uuid.Nil, echo.NewHTTPError(http.StatusBadRequest, message).SetInternal(err)
+	}
+	return id, nil
+}
+
+// httpError translates domain errors into HTTP errors; other errors are returned unchanged
+func httpError(err error) error {
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrLoanNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, ErrInvalidSchedule) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+package schedules
+
+import "github.com/labstack/echo/v4"
+
+func Routes(g *echo.Group, handler Handler) {
+	g.POST("/loans/:loanId/schedules", handler.Create)
+	g.GET("/loans/:loanId/schedules", handler.GetByLoanId)
+	g.GET("/loans/:loanId/due-payments", handler.GetDuePayments)
+	g.GET("/schedules/:id", handler.Read)
+	g.PUT("/schedules/:id", handler.Update)
+	g.DELETE("/schedules/:id", handler.Delete)
+}
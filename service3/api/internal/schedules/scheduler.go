@@ -0,0 +1,163 @@
+package schedules
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/internal/tenant"
+)
+
+// Scheduler records the installments of payment schedules as they fall due and
+// collects autopay installments as regular payments. Recording a payment settles the
+// loan's oldest open due payment, so manual payments settle them the same way.
+type Scheduler struct {
+	pool      *pgxpool.Pool
+	payments  payments.Repository
+	interval  time.Duration
+	batchSize int
+	logger    *log.Logger
+}
+
+// NewScheduler creates a scheduler.
+func NewScheduler(pool *pgxpool.Pool, logger *log.Logger) *Scheduler {
+	return &Scheduler{
+		pool:      pool,
+		payments:  payments.NewPaymentRepository(pool),
+		interval:  time.Hour,
+		batchSize: 100,
+		logger:    logger,
+	}
+}
+
+// Run generates due payments every interval until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if generated, err := s.GenerateDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			s.logger.Printf("payment scheduler: %v", err)
+		} else if generated > 0 {
+			s.logger.Printf("payment scheduler: recorded %d due payments", generated)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pendingInstallment is a schedule whose next due date has arrived, with the loan
+// details needed to collect it
+type pendingInstallment struct {
+	schedule   Schedule
+	customerId uuid.UUID
+	balance    decimal.Decimal
+	tenantId   string
+}
+
+// GenerateDue records a due payment for every schedule on an active loan whose next
+// due date is on or before asOf, advancing the schedule a month each time so missed
+// months are caught up, and returns how many it recorded. An autopay installment that
+// cannot be collected is logged and left due.
+func (s *Scheduler) GenerateDue(ctx context.Context, asOf time.Time) (int, error) {
+	generated := 0
+	for {
+		batch, err := s.pendingInstallments(ctx, asOf)
+		if err != nil {
+			return generated, err
+		}
+		for _, installment := range batch {
+			if err := s.recordDue(ctx, installment.schedule); err != nil {
+				return generated, err
+			}
+			generated++
+			if installment.schedule.Autopay {
+				s.collect(ctx, installment)
+			}
+		}
+		if len(batch) < s.batchSize {
+			return generated, nil
+		}
+	}
+}
+
+func (s *Scheduler) pendingInstallments(ctx context.Context, asOf time.Time) ([]pendingInstallment, error) {
+	sql := `SELECT s.id, s.loan_id, s.amount, s.day_of_month, s.autopay, s.next_due_date, s.created_at, s.modified_at,
+			l.customer_id, l.outstanding_balance, l.tenant_id
+		FROM payment_schedules s JOIN loans l ON l.id = s.loan_id
+		WHERE s.next_due_date <= $1 AND l.status = $2
+		ORDER BY s.next_due_date, s.id
+		LIMIT $3`
+	rows, err := s.pool.Query(ctx, sql, asOf, loans.StatusActive, s.batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batch := []pendingInstallment{}
+	for rows.Next() {
+		var installment pendingInstallment
+		schedule := &installment.schedule
+		err := rows.Scan(&schedule.Id, &schedule.LoanId, &schedule.Amount, &schedule.DayOfMonth, &schedule.Autopay,
+			&schedule.NextDueDate, &schedule.CreatedAt, &schedule.ModifiedAt, &installment.customerId, &installment.balance, &installment.tenantId)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, installment)
+	}
+	return batch, rows.Err()
+}
+
+// recordDue records the schedule's next installment and moves it to the following month
+func (s *Scheduler) recordDue(ctx context.Context, schedule Schedule) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO due_payments (id, schedule_id, loan_id, due_date, amount, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (schedule_id, due_date) DO NOTHING`
+	_, err = tx.Exec(ctx, sql, uuid.New(), schedule.Id, schedule.LoanId, schedule.NextDueDate, schedule.Amount, DueStatusDue)
+	if err != nil {
+		return err
+	}
+
+	next := NextDueDate(schedule.NextDueDate.AddDate(0, 0, 1), schedule.DayOfMonth)
+	_, err = tx.Exec(ctx, "UPDATE payment_schedules SET next_due_date = $1, modified_at = NOW() WHERE id = $2", next, schedule.Id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// collect records the autopay installment as a regular payment, capped at the loan's
+// outstanding balance so the final installment pays the loan off. The payment is made
+// as the loan's tenant.
+func (s *Scheduler) collect(ctx context.Context, installment pendingInstallment) {
+	ctx = tenant.WithTenant(ctx, installment.tenantId)
+	payment := payments.Payment{
+		Id:            uuid.New(),
+		LoanId:        installment.schedule.LoanId,
+		CustomerId:    installment.customerId,
+		PaymentAmount: decimal.Min(installment.schedule.Amount, installment.balance),
+		PaymentDate:   installment.schedule.NextDueDate,
+		PaymentType:   payments.TypeRegular,
+	}
+	err := payment.Validate(time.Now())
+	if err == nil {
+		_, err = s.payments.Create(ctx, payment)
+	}
+	if err != nil {
+		s.logger.Printf("payment scheduler: autopay for schedule %s due %s failed: %v",
+			installment.schedule.Id, installment.schedule.NextDueDate.Format(time.DateOnly), err)
+	}
+}
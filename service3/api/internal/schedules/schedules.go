@@ -0,0 +1,276 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/database"
+	"service3/api/internal/loans"
+	"service3/api/internal/tenant"
+)
+
+// Schedule is a recurring monthly payment on a loan. Each month the Scheduler records
+// a DuePayment on DayOfMonth and, with Autopay, collects it as a payment.
+type Schedule struct {
+	Id          uuid.UUID       `json:"id"`
+	LoanId      uuid.UUID       `json:"loan_id"`
+	Amount      decimal.Decimal `json:"amount"`
+	DayOfMonth  int             `json:"day_of_month"` // 1-28, so every month has the day
+	Autopay     bool            `json:"autopay"`
+	NextDueDate time.Time       `json:"next_due_date"`
+	CreatedAt   time.Time       `json:"created_at"`
+	ModifiedAt  time.Time       `json:"modified_at"`
+}
+
+// Due payment statuses. A due payment is settled by the next regular payment on the loan.
+const (
+	DueStatusDue  = "due"
+	DueStatusPaid = "paid"
+)
+
+// DuePayment is one installment a schedule expects on DueDate
+type DuePayment struct {
+	Id         uuid.UUID       `json:"id"`
+	ScheduleId uuid.UUID       `json:"schedule_id"`
+	LoanId     uuid.UUID       `json:"loan_id"`
+	DueDate    time.Time       `json:"due_date"`
+	Amount     decimal.Decimal `json:"amount"`
+	Status     string          `json:"status"`
+	PaymentId  *uuid.UUID      `json:"payment_id"` // the payment that settled it
+	PaidAt     *time.Time      `json:"paid_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+var (
+	// ErrNotFound is returned when no schedule exists with the requested ID
+	ErrNotFound = errors.New("payment schedule not found")
+	// ErrLoanNotFound is returned when scheduling payments for a loan that does not exist
+	ErrLoanNotFound = errors.New("loan not found")
+	// ErrInvalidSchedule is returned when the amount or day of month is out of range
+	ErrInvalidSchedule = errors.New("invalid payment schedule")
+)
+
+// Validate checks the schedule's amount and day of month
+func (s Schedule) Validate() error {
+	if !s.Amount.IsPositive() {
+		return fmt.Errorf("%w: amount must be greater than 0", ErrInvalidSchedule)
+	}
+	if s.DayOfMonth < 1 || s.DayOfMonth > 28 {
+		return fmt.Errorf("%w: day_of_month must be between 1 and 28", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// NextDueDate returns the first date on or after from that falls on dayOfMonth
+func NextDueDate(from time.Time, dayOfMonth int) time.Time {
+	year, month, day := from.UTC().Date()
+	due := time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+	if day > dayOfMonth {
+		due = due.AddDate(0, 1, 0)
+	}
+	return due
+}
+
+type Repository interface {
+	Create(ctx context.Context, schedule Schedule) (Schedule, error)
+	Read(ctx context.Context, id uuid.UUID) (Schedule, error)
+	Update(ctx context.Context, schedule Schedule) (Schedule, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Schedule, error)
+	GetDuePayments(ctx context.Context, loanId uuid.UUID) ([]DuePayment, error)
+}
+
+type Service interface {
+	Create(ctx context.Context, schedule Schedule) (Schedule, error)
+	Read(ctx context.Context, id uuid.UUID) (Schedule, error)
+	Update(ctx context.Context, schedule Schedule) (Schedule, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Schedule, error)
+	GetDuePayments(ctx context.Context, loanId uuid.UUID) ([]DuePayment, error)
+}
+
+const scheduleColumns = "id, loan_id, amount, day_of_month, autopay, next_due_date, created_at, modified_at"
+
+// scanSchedule scans a row selected with scheduleColumns
+func scanSchedule(row pgx.Row) (Schedule, error) {
+	var schedule Schedule
+	err := row.Scan(&schedule.Id, &schedule.LoanId, &schedule.Amount, &schedule.DayOfMonth, &schedule.Autopay,
+		&schedule.NextDueDate, &schedule.CreatedAt, &schedule.ModifiedAt)
+	return schedule, err
+}
+
+const duePaymentColumns = "id, schedule_id, loan_id, due_date, amount, status, payment_id, paid_at, created_at"
+
+// scanDuePayment scans a row selected with duePaymentColumns
+func scanDuePayment(row pgx.Row) (DuePayment, error) {
+	var due DuePayment
+	err := row.Scan(&due.Id, &due.ScheduleId, &due.LoanId, &due.DueDate, &due.Amount, &due.Status,
+		&due.PaymentId, &due.PaidAt, &due.CreatedAt)
+	return due, err
+}
+
+type ScheduleRepository struct {
+	db database.DB
+}
+
+func NewScheduleRepository(pool *pgxpool.Pool) *ScheduleRepository {
+	return &ScheduleRepository{pool}
+}
+
+// WithTx returns the repository bound to tx, so its calls join that unit of work
+func (r *ScheduleRepository) WithTx(tx pgx.Tx) *ScheduleRepository {
+	return &ScheduleRepository{tx}
+}
+
+// Create schedules payments on the loan, starting on the next occurrence of the day of month
+func (r *ScheduleRepository) Create(ctx context.Context, schedule Schedule) (Schedule, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM loans WHERE id = $1 AND tenant_id = $2)",
+		schedule.LoanId, tenant.FromContext(ctx)).Scan(&exists)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if !exists {
+		return Schedule{}, ErrLoanNotFound
+	}
+
+	sql := `INSERT INTO payment_schedules
+		(id, loan_id, amount, day_of_month, autopay, next_due_date, created_at, modified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING ` + scheduleColumns
+	return scanSchedule(r.db.QueryRow(ctx, sql,
+		schedule.Id,
+		schedule.LoanId,
+		schedule.Amount,
+		schedule.DayOfMonth,
+		schedule.Autopay,
+		NextDueDate(time.Now(), schedule.DayOfMonth),
+	))
+}
+
+func (r *ScheduleRepository) Read(ctx context.Context, id uuid.UUID) (Schedule, error) {
+	sql := "SELECT " + scheduleColumns + " FROM payment_schedules WHERE id = $1 AND " + loans.InTenant(2)
+	schedule, err := scanSchedule(r.db.QueryRow(ctx, sql, id, tenant.FromContext(ctx)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Schedule{}, ErrNotFound
+	}
+	if err != nil {
+		return Schedule{}, err
+	}
+	return schedule, nil
+}
+
+// Update changes the amount, day of month and autopay flag. Moving the day of month
+// moves the next due date to its next occurrence; due payments already recorded stay.
+func (r *ScheduleRepository) Update(ctx context.Context, schedule Schedule) (Schedule, error) {
+	sql := `UPDATE payment_schedules
+		SET amount = $1, day_of_month = $2, autopay = $3,
+			next_due_date = CASE WHEN day_of_month <> $2 THEN $4 ELSE next_due_date END,
+			modified_at = NOW()
+		WHERE id = $5 AND ` + loans.InTenant(6) + `
+		RETURNING ` + scheduleColumns
+	updated, err := scanSchedule(r.db.QueryRow(ctx, sql,
+		schedule.Amount,
+		schedule.DayOfMonth,
+		schedule.Autopay,
+		NextDueDate(time.Now(), schedule.DayOfMonth),
+		schedule.Id,
+		tenant.FromContext(ctx),
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Schedule{}, ErrNotFound
+	}
+	if err != nil {
+		return Schedule{}, err
+	}
+	return updated, nil
+}
+
+// Delete stops the schedule. Its due payments are kept; deleting a missing schedule is not an error.
+func (r *ScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql := "DELETE FROM payment_schedules WHERE id = $1 AND " + loans.InTenant(2)
+	_, err := r.db.Exec(ctx, sql, id, tenant.FromContext(ctx))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *ScheduleRepository) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Schedule, error) {
+	sql := "SELECT " + scheduleColumns + " FROM payment_schedules WHERE loan_id = $1 AND " + loans.InTenant(2) +
+		" ORDER BY created_at"
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	schedules := []Schedule{}
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// GetDuePayments lists the loan's due payments, most recent first
+func (r *ScheduleRepository) GetDuePayments(ctx context.Context, loanId uuid.UUID) ([]DuePayment, error) {
+	sql := "SELECT " + duePaymentColumns + " FROM due_payments WHERE loan_id = $1 AND " + loans.InTenant(2) +
+		" ORDER BY due_date DESC"
+	rows, err := r.db.Query(ctx, sql, loanId, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dues := []DuePayment{}
+	for rows.Next() {
+		due, err := scanDuePayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		dues = append(dues, due)
+	}
+	return dues, rows.Err()
+}
+
+type ScheduleService struct {
+	repo Repository
+}
+
+func NewScheduleService(repo Repository) *ScheduleService {
+	return &ScheduleService{repo}
+}
+
+func (s *ScheduleService) Create(ctx context.Context, schedule Schedule) (Schedule, error) {
+	return s.repo.Create(ctx, schedule)
+}
+
+func (s *ScheduleService) Read(ctx context.Context, id uuid.UUID) (Schedule, error) {
+	return s.repo.Read(ctx, id)
+}
+
+func (s *ScheduleService) Update(ctx context.Context, schedule Schedule) (Schedule, error) {
+	return s.repo.Update(ctx, schedule)
+}
+
+func (s *ScheduleService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *ScheduleService) GetByLoanId(ctx context.Context, loanId uuid.UUID) ([]Schedule, error) {
+	return s.repo.GetByLoanId(ctx, loanId)
+}
+
+func (s *ScheduleService) GetDuePayments(ctx context.Context, loanId uuid.UUID) ([]DuePayment, error) {
+	return s.repo.GetDuePayments(ctx, loanId)
+}
@@ -0,0 +1,53 @@
+package schedules
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNextDueDate(t *testing.T) {
+	tests := []struct {
+		from       time.Time
+		dayOfMonth int
+		want       time.Time
+	}{
+		{time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC), 15, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 15, 15, 0, 0, 0, time.UTC), 15, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC), 15, time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC), 1, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), 28, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := NextDueDate(tt.from, tt.dayOfMonth); !got.Equal(tt.want) {
+			t.Errorf("NextDueDate(%s, %d) = %s, want %s", tt.from, tt.dayOfMonth, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		valid    bool
+	}{
+		{"valid", Schedule{Amount: decimal.NewFromInt(2176), DayOfMonth: 1}, true},
+		{"last allowed day", Schedule{Amount: decimal.NewFromInt(2176), DayOfMonth: 28}, true},
+		{"zero amount", Schedule{Amount: decimal.NewFromInt(0), DayOfMonth: 1}, false},
+		{"day zero", Schedule{Amount: decimal.NewFromInt(2176), DayOfMonth: 0}, false},
+		{"day past 28", Schedule{Amount: decimal.NewFromInt(2176), DayOfMonth: 31}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected schedule to be valid, got: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidSchedule) {
+				t.Errorf("Expected ErrInvalidSchedule, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+//go:build integration
+
+// Package testdb gives integration tests a PostgreSQL of their own. Main starts a
+// container for the test binary and migrates a template database in it; New clones
+// the template into a fresh database per test, so tests neither share rows nor need
+// a database provisioned beforehand, and can run in parallel. Docker must be
+// available; run the tests with -tags integration.
+package testdb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"service3/api/internal/migrations"
+)
+
+// image matches the database docker-compose runs
+const image = "postgres:15-alpine"
+
+// template is the migrated database every test database is cloned from
+const template = "template_migrated"
+
+var (
+	// adminURL connects to the container's postgres database, from which the test
+	// databases are created and dropped
+	adminURL string
+	// databaseURL is the container's URL with the database name left to fill in
+	databaseURL string
+
+	// cloneMu serializes clones; PostgreSQL refuses to copy a template another
+	// CREATE DATABASE is reading
+	cloneMu   sync.Mutex
+	databases atomic.Int64
+)
+
+// Main starts the container, runs the package's tests and removes the container.
+// Call it from TestMain. DATABASE_URL is pointed at a migrated database of its own,
+// so tests still using it run against the container too.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	ctx := context.Background()
+	container, err := postgres.Run(ctx, image,
+		postgres.WithDatabase(template),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			// The server restarts once after initdb, so wait for the second start
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute),
+		),
+	)
+	if err != nil {
+		log.Printf("testdb: start postgres: %v", err)
+		return 1
+	}
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("testdb: remove postgres: %v", err)
+		}
+	}()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		log.Printf("testdb: %v", err)
+		return 1
+	}
+	port, err := container.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		log.Printf("testdb: %v", err)
+		return 1
+	}
+	databaseURL = fmt.Sprintf("postgres://postgres:postgres@%s:%s/%%s?sslmode=disable", host, port.Port())
+	adminURL = fmt.Sprintf(databaseURL, "postgres")
+
+	if err := migrate(ctx); err != nil {
+		log.Printf("testdb: %v", err)
+		return 1
+	}
+	name, err := clone(ctx)
+	if err != nil {
+		log.Printf("testdb: %v", err)
+		return 1
+	}
+	os.Setenv("DATABASE_URL", fmt.Sprintf(databaseURL, name))
+	return m.Run()
+}
+
+// migrate applies the migrations to the template, closing its connections again so
+// it can be copied
+func migrate(ctx context.Context) error {
+	pool, err := pgxpool.New(ctx, fmt.Sprintf(databaseURL, template))
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+	if err := migrations.Up(ctx, pool); err != nil {
+		return fmt.Errorf("migrate template: %w", err)
+	}
+	return nil
+}
+
+// clone creates a database from the template and returns its name
+func clone(ctx context.Context) (string, error) {
+	cloneMu.Lock()
+	defer cloneMu.Unlock()
+
+	conn, err := pgx.Connect(ctx, adminURL)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close(ctx)
+	name := fmt.Sprintf("test_%d", databases.Add(1))
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+name+" TEMPLATE "+template); err != nil {
+		return "", fmt.Errorf("create %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// New returns a pool on a freshly migrated database that only t uses. The pool is
+// closed and the database dropped when t ends.
+func New(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	if adminURL == "" {
+		t.Fatal("testdb: no container; call testdb.Main from TestMain")
+	}
+	ctx := context.Background()
+	name, err := clone(ctx)
+	if err != nil {
+		t.Fatalf("testdb: %v", err)
+	}
+	pool, err := pgxpool.New(ctx, fmt.Sprintf(databaseURL, name))
+	if err != nil {
+		t.Fatalf("testdb: %v", err)
+	}
+	t.Cleanup(func() {
+		pool.Close()
+		conn, err := pgx.Connect(ctx, adminURL)
+		if err != nil {
+			t.Logf("testdb: drop %s: %v", name, err)
+			return
+		}
+		defer conn.Close(ctx)
+		if _, err := conn.Exec(ctx, "DROP DATABASE "+name); err != nil {
+			t.Logf("testdb: drop %s: %v", name, err)
+		}
+	})
+	return pool
+}
@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is returned when a payload fails validation
+type Error struct {
+	Fields []FieldError `json:"errors"`
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validator implements echo.Validator using go-playground/validator struct tags
+type Validator struct {
+	validate *validator.Validate
+}
+
+func New() *Validator {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields by their JSON name so errors match the request payload
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	// Compare money amounts as numbers so gt, gte and lte work on them
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		return field.Interface().(decimal.Decimal).InexactFloat64()
+	}, decimal.Decimal{})
+
+	return &Validator{validate}
+}
+
+// Validate checks i against its validate tags. Failures are returned as a 422
+// echo.HTTPError wrapping an *Error that lists the offending fields.
+func (v *Validator) Validate(i any) error {
+	err := v.validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, FieldError{
+			Field:   fieldErr.Field(),
+			Message: message(fieldErr),
+		})
+	}
+	return echo.NewHTTPError(http.StatusUnprocessableEntity, "validation failed").SetInternal(&Error{Fields: fields})
+}
+
+func message(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "max":
+		return "must be at most " + fieldErr.Param() + " characters"
+	case "min":
+		if fieldErr.Param() == "1" {
+			return "must not be empty"
+		}
+		return "must be at least " + fieldErr.Param() + " characters"
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "gte":
+		return "must not be less than " + fieldErr.Param()
+	case "lte":
+		return "must not be greater than " + fieldErr.Param()
+	case "oneof":
+		return "must be one of: " + fieldErr.Param()
+	default:
+		return "failed " + fieldErr.Tag() + " validation"
+	}
+}
@@ -2,97 +2,307 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"log"
+	"log/slog"
+	"math"
+	"net"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
-	"github.com/jackc/pgx/v5"
-	"github.com/joho/godotenv"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"service3/api/internal/apierror"
+	"service3/api/internal/auth"
+	"service3/api/internal/cache"
+	"service3/api/internal/config"
+	"service3/api/internal/database"
+	"service3/api/internal/escrow"
+	"service3/api/internal/grpcserver"
+	"service3/api/internal/health"
+	"service3/api/internal/latefees"
 	"service3/api/internal/loans"
+	"service3/api/internal/logging"
+	"service3/api/internal/metrics"
+	"service3/api/internal/migrations"
+	"service3/api/internal/openapi"
+	"service3/api/internal/outbox"
 	"service3/api/internal/payments"
+	"service3/api/internal/ratelimit"
+	"service3/api/internal/schedules"
+	"service3/api/internal/security"
+	"service3/api/internal/tenant"
+	"service3/api/internal/timeout"
+	"service3/api/internal/tracing"
+	"service3/api/internal/validation"
+	"service3/api/internal/versioning"
+	"service3/api/internal/webhooks"
+	"service3/api/pkg/pb/servicingv1"
 )
 
 func main() {
-	// Load .env file if it exists (optional - environment variables can also be set via docker-compose)
-	err := godotenv.Load(".env")
+	// Everything, the standard log package included, goes out as JSON lines
+	logger := logging.New(os.Stdout)
+	slog.SetDefault(logger)
+
+	cfg, err := config.Load()
 	if err != nil {
-		log.Println("Warning: .env file not found, using environment variables")
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-	ctx := context.Background()
-	conn, err := pgx.Connect(ctx, os.Getenv("DATABASE_URL"))
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to connect to database: %v\n", err)
+	authConfig := cfg.Auth()
+	if !authConfig.Enabled() {
+		slog.Warn("Authentication disabled: set API_KEYS or JWT_SECRET to require credentials")
 	}
-	defer conn.Close(context.Background())
 
-	err = createLoansTable(ctx, conn)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdownTracing, err := tracing.Setup(ctx, "service3")
+	if err != nil {
+		log.Fatalf("Unable to set up tracing: %v", err)
+	}
+	defer func() {
+		// Flush the spans still batched; the collector may be gone, so don't wait long
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Warn("Unable to flush traces", "error", err)
+		}
+	}()
+	pool, err := newPool(ctx, cfg)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to create loans table: %v\n", err)
+		log.Fatalf("Unable to connect to database: %v", err)
 	}
+	defer pool.Close()
+	prometheus.MustRegister(metrics.NewPoolCollector(pool))
 
-	err = createPaymentsTable(ctx, conn)
+	err = migrations.Up(ctx, pool)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Unable to create payments table: %v\n", err)
+		slog.Error("Unable to migrate database", "error", err)
 	}
 
+	scheduler := schedules.NewScheduler(pool, log.Default())
+	go scheduler.Run(ctx)
+
+	accruer := loans.NewAccruer(pool, log.Default())
+	go accruer.Run(ctx)
+
+	assessor := latefees.NewAssessor(pool, cfg.LateFeePolicy(), log.Default())
+	go assessor.Run(ctx)
+
+	detector := loans.NewDelinquencyDetector(pool, log.Default())
+	go detector.Run(ctx)
+
+	// The relay queues webhook deliveries for each event before publishing it
+	relay := outbox.NewRelay(pool, webhooks.NewPublisher(pool, newPublisher(cfg)), log.Default())
+	go relay.Run(ctx)
+
+	dispatcher := webhooks.NewDispatcher(pool, log.Default())
+	go dispatcher.Run(ctx)
+
+	loanCache := newCache(cfg)
+
 	e := echo.New()
+	e.Validator = validation.New()
+	e.HTTPErrorHandler = apierror.Handler
+	e.HideBanner = true
+	e.HidePort = true
+	// Routes unversioned paths, the deprecated aliases, to an API version
+	e.Pre(versioning.Middleware())
+	e.Use(middleware.RequestID())
+	e.Use(tracing.Middleware("service3"))
+	e.Use(logging.Middleware(logger))
+	e.Use(echoprometheus.NewMiddleware("http"))
+	e.Use(middleware.Recover())
+	e.Use(security.Middleware(cfg.Security()))
+	e.Use(timeout.Middleware(cfg.Timeouts()))
+	e.Use(auth.Middleware(authConfig))
+	e.Use(tenant.Middleware())
+	limiter := newRateLimiter(cfg)
+	if limiter != nil {
+		e.Use(ratelimit.Middleware(limiter))
+	}
+
+	health.Routes(e, health.NewHealthHandler(pool))
+
+	// The API is served under /v1; a breaking change ships in a /v2 group next to it
+	v1 := e.Group(versioning.Prefix("v1"))
 
 	// Loans setup
-	loanRepository := loans.NewLoanRepository(conn)
-	loanService := loans.NewLoanService(loanRepository)
+	loanRepository := loans.NewLoanRepository(pool)
+	loanService := loans.NewLoanService(loanRepository).WithCache(loanCache)
 	loanHandler := loans.NewLoanHandler(loanService)
-	loans.Routes(e, loanHandler)
+	loans.Routes(v1, loanHandler)
 
 	// Payments setup
-	paymentRepository := payments.NewPaymentRepository(conn)
-	paymentService := payments.NewPaymentService(paymentRepository)
+	paymentRepository := payments.NewPaymentRepository(pool)
+	paymentService := payments.NewPaymentService(paymentRepository).WithCache(loanCache)
 	paymentHandler := payments.NewPaymentHandler(paymentService)
-	payments.Routes(e, paymentHandler)
+	payments.Routes(v1, paymentHandler)
 
-	e.Logger.Fatal(e.Start(":8083"))
-}
+	// Payment schedules setup
+	scheduleRepository := schedules.NewScheduleRepository(pool)
+	scheduleService := schedules.NewScheduleService(scheduleRepository)
+	scheduleHandler := schedules.NewScheduleHandler(scheduleService)
+	schedules.Routes(v1, scheduleHandler)
+
+	// Late fees setup
+	lateFeeRepository := latefees.NewLateFeeRepository(pool)
+	lateFeeService := latefees.NewLateFeeService(lateFeeRepository).WithCache(loanCache)
+	lateFeeHandler := latefees.NewLateFeeHandler(lateFeeService)
+	latefees.Routes(v1, lateFeeHandler)
+
+	// Escrow setup
+	escrowRepository := escrow.NewEscrowRepository(pool)
+	escrowService := escrow.NewEscrowService(escrowRepository)
+	escrowHandler := escrow.NewEscrowHandler(escrowService)
+	escrow.Routes(v1, escrowHandler)
+
+	// Webhooks setup
+	webhookRepository := webhooks.NewWebhookRepository(pool)
+	webhookService := webhooks.NewWebhookService(webhookRepository, slices.Concat(loans.Events, payments.Events))
+	webhookHandler := webhooks.NewWebhookHandler(webhookService)
+	webhooks.Routes(v1, webhookHandler)
 
-func createLoansTable(ctx context.Context, conn *pgx.Conn) error {
-	loansTable := `CREATE TABLE IF NOT EXISTS loans(
-		id uuid PRIMARY KEY,
-		customer_id uuid NOT NULL,
-		mortgage_id uuid NOT NULL,
-		loan_amount numeric NOT NULL,
-		interest_rate numeric NOT NULL,
-		term_years int NOT NULL,
-		monthly_payment numeric NOT NULL,
-		outstanding_balance numeric NOT NULL,
-		status varchar NOT NULL,
-		start_date timestamp NOT NULL,
-		maturity_date timestamp NOT NULL,
-		created_at timestamp NOT NULL,
-		modified_at timestamp NOT NULL
-	)`
-	_, err := conn.Exec(ctx, loansTable)
+	doc, err := openapi.Document()
 	if err != nil {
+		log.Fatalf("Unable to build OpenAPI document: %v", err)
+	}
+	e.GET("/openapi.json", openapi.Handler(doc))
+	e.GET("/metrics", echoprometheus.NewHandler())
+
+	// The gRPC API shares the REST API's services, credentials and rate limit buckets
+	grpcServer := grpcserver.New(authConfig, limiter, logger)
+	servicingv1.RegisterLoanServiceServer(grpcServer, grpcserver.NewLoanServer(loanService))
+	servicingv1.RegisterPaymentServiceServer(grpcServer, grpcserver.NewPaymentServer(paymentService))
+	grpcDone := make(chan struct{})
+	go func() {
+		defer close(grpcDone)
+		if err := serveGRPC(ctx, grpcServer, cfg.GRPCAddr, cfg.ShutdownTimeout); err != nil {
+			slog.Error("gRPC server failed", "error", err)
+			stop() // take the REST API down with it rather than run half a service
+		}
+	}()
+
+	// Background jobs stop with ctx; the deferred pool.Close then waits for their connections
+	if err := serve(ctx, e, cfg.HTTPAddr, cfg.ShutdownTimeout); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+	<-grpcDone
+}
+
+// serve runs e on addr until ctx is cancelled by SIGINT or SIGTERM, then stops accepting
+// connections and gives in-flight requests up to timeout to finish
+func serve(ctx context.Context, e *echo.Echo, addr string, timeout time.Duration) error {
+	errs := make(chan error, 1)
+	slog.Info("Listening", "addr", addr)
+	go func() {
+		errs <- e.Start(addr)
+	}()
+
+	select {
+	case err := <-errs:
 		return err
+	case <-ctx.Done():
 	}
 
-	return nil
+	slog.Info("Shutting down, draining in-flight requests", "timeout", timeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return e.Shutdown(shutdownCtx)
 }
 
-func createPaymentsTable(ctx context.Context, conn *pgx.Conn) error {
-	paymentsTable := `CREATE TABLE IF NOT EXISTS payments(
-		id uuid PRIMARY KEY,
-		loan_id uuid NOT NULL,
-		customer_id uuid NOT NULL,
-		payment_amount numeric NOT NULL,
-		principal_amount numeric NOT NULL,
-		interest_amount numeric NOT NULL,
-		payment_date timestamp NOT NULL,
-		payment_type varchar NOT NULL,
-		created_at timestamp NOT NULL
-	)`
-	_, err := conn.Exec(ctx, paymentsTable)
+// serveGRPC runs server on addr until ctx is cancelled, then lets in-flight calls finish,
+// closing their connections once timeout has passed
+func serveGRPC(ctx context.Context, server *grpc.Server, addr string, timeout time.Duration) error {
+	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return err
 	}
+	errs := make(chan error, 1)
+	slog.Info("Listening for gRPC", "addr", addr)
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
 
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		server.Stop()
+	}
 	return nil
 }
+
+// newRateLimiter allows each caller RATE_LIMIT_RPS requests per second with bursts of
+// RATE_LIMIT_BURST, or twice the rate when unset. It returns nil when RATE_LIMIT_RPS
+// is 0, turning rate limiting off.
+func newRateLimiter(cfg config.Config) *ratelimit.Limiter {
+	if cfg.RateLimitRPS == 0 {
+		return nil
+	}
+	burst := cfg.RateLimitBurst
+	if burst == 0 {
+		burst = int(math.Ceil(cfg.RateLimitRPS * 2))
+	}
+	return ratelimit.NewLimiter(cfg.RateLimitRPS, burst)
+}
+
+// newPublisher publishes outbox events to OUTBOX_PUBLISH_URL, or to the log when unset
+func newPublisher(cfg config.Config) outbox.Publisher {
+	if cfg.OutboxPublishURL != "" {
+		return outbox.NewHTTPPublisher(cfg.OutboxPublishURL)
+	}
+	return outbox.NewLogPublisher(log.Default())
+}
+
+// newCache caches loan reads in the Redis at REDIS_URL for CACHE_TTL, or not at all
+// when REDIS_URL is unset
+func newCache(cfg config.Config) cache.Cache {
+	if cfg.RedisURL == "" {
+		return cache.Nop{}
+	}
+	options, _ := redis.ParseURL(cfg.RedisURL) // validated by config.Load
+	return cache.NewRedis(redis.NewClient(options), cfg.CacheTTL)
+}
+
+// newPool connects the pool for DATABASE_URL, sized by DB_MAX_CONNS and DB_MIN_CONNS
+// when set, waiting for the database as DB_CONNECT_ATTEMPTS allows. Request handlers
+// and background jobs share it, and its queries are timed for /metrics and traced as
+// children of the request's span.
+func newPool(ctx context.Context, cfg config.Config) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.DBMaxConns > 0 {
+		poolConfig.MaxConns = cfg.DBMaxConns
+	}
+	if cfg.DBMinConns > 0 {
+		poolConfig.MinConns = cfg.DBMinConns
+	}
+	poolConfig.HealthCheckPeriod = cfg.DBHealthCheckPeriod
+	poolConfig.ConnConfig.Tracer = multitracer.New(
+		metrics.NewQueryTracer(prometheus.DefaultRegisterer),
+		tracing.NewQueryTracer(otel.GetTracerProvider()),
+	)
+	return database.Connect(ctx, poolConfig, cfg.ConnectRetry())
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"service3/api/internal/payments"
+)
+
+// PaymentBulkResult is what happened to one payment of Payments.CreateBulk: its
+// Status is the one the payment would have been answered with on its own, with
+// Payment set when it was recorded and Error when it was not
+type PaymentBulkResult = payments.BulkResult
+
+// MaxBulkPayments is how many payments one Payments.CreateBulk call may record
+const MaxBulkPayments = payments.MaxBulkPayments
+
+// CreateBulk records up to MaxBulkPayments payments in one request, in
+// order and atomically, returning a result for each. When any payment is rejected
+// none is recorded: err is the 422's APIError and the results say which payments
+// failed and why, the others having a 424.
+func (p *Payments) CreateBulk(ctx context.Context, payments []Payment) ([]PaymentBulkResult, error) {
+	body, err := jsonBody(payments)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.api.CreatePaymentsBulkWithBody(ctx, contentTypeJSON, body)
+	return decodeBulk[PaymentBulkResult](resp, err)
+}
+
+// decodeBulk reads the results of a bulk request, from the 201's body or, when the
+// request was rejected, the details of its 422
+func decodeBulk[T any](resp *http.Response, err error) ([]T, error) {
+	results, err := decode[[]T](resp, err, http.StatusCreated)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		var envelope struct {
+			Details []T `json:"details"`
+		}
+		if json.Unmarshal(apiErr.RawBody, &envelope) == nil {
+			results = envelope.Details
+		}
+	}
+	return results, err
+}
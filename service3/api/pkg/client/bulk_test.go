@@ -0,0 +1,59 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreatePaymentsBulk_ReturnsResultPerPayment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payments []Payment
+		if r.URL.Path != "/v1/payments/bulk" || json.NewDecoder(r.Body).Decode(&payments) != nil {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		results := make([]PaymentBulkResult, len(payments))
+		for i := range payments {
+			payments[i].Id = uuid.New()
+			results[i] = PaymentBulkResult{Index: i, Status: http.StatusCreated, Payment: &payments[i]}
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL).Payments().CreateBulk(context.Background(), []Payment{{}, {}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 2 || results[1].Index != 1 || results[1].Payment == nil || results[1].Payment.Id == uuid.Nil {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestCreatePaymentsBulk_ReturnsResultsOfRejectedRequest(t *testing.T) {
+	body := `{"code":"bulk_rejected","message":"1 of 2 payments rejected; nothing was recorded","details":[` +
+		`{"index":0,"status":424,"error":{"code":"not_created","message":"not created"}},` +
+		`{"index":1,"status":400,"error":{"code":"bad_request","message":"payment_amount is required"}}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(server.URL).Payments().CreateBulk(context.Background(), []Payment{{}, {}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "bulk_rejected" {
+		t.Fatalf("Expected the 422 as an APIError, got %v", err)
+	}
+	if len(results) != 2 || results[0].Status != http.StatusFailedDependency ||
+		results[1].Status != http.StatusBadRequest || results[1].Error == nil || results[1].Error.Message != "payment_amount is required" {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
@@ -1,379 +1,144 @@
 package client
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
-	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/escrow"
 	"service3/api/internal/loans"
 	"service3/api/internal/payments"
+	"service3/api/internal/schedules"
+	"service3/api/pkg/client/internal/oapi"
 )
 
 type Loan = loans.Loan
+type LoanFilter = loans.LoanFilter
+type Cancellation = loans.Cancellation
+type LoanSummary = loans.Summary
+type LoanModification = loans.Modification
+type ModificationRequest = loans.ModificationRequest
+type DelinquentLoan = loans.DelinquentLoan
+type DelinquencyFilter = loans.DelinquencyFilter
+type PayoffQuote = loans.PayoffQuote
+type Installment = loans.Installment
+type AmortizationFilter = loans.AmortizationFilter
 type Payment = payments.Payment
+type PaymentFilter = payments.PaymentFilter
+type Schedule = schedules.Schedule
+type DuePayment = schedules.DuePayment
+type EscrowAccount = escrow.Account
+type EscrowDisbursement = escrow.Disbursement
+
+const CancelReasonSagaCompensation = loans.CancelReasonSagaCompensation
 
 type Client struct {
-	baseURL    string
+	// api builds and sends the requests; it is generated from the service's OpenAPI
+	// document, so the paths and parameters follow the handlers
+	api        *oapi.Client
 	httpClient *http.Client
+	// transport connects to the service; the With options wrap it
+	transport *http.Transport
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+	// versions sends the client's version headers, innermost of the transports
+	versions *versionHeaders
 }
 
 func NewClient(baseURL string) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: &http.Client{},
-	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	versions := &versionHeaders{userAgent: userAgent, next: transport}
+	c := &Client{
+		httpClient: &http.Client{Transport: versions},
+		transport:  transport,
+		timeout:    DefaultTimeout,
+		versions:   versions,
+	}
+	c.api = &oapi.Client{Server: strings.TrimSuffix(baseURL, "/") + "/", Client: doerFunc(c.do)}
+	return c
 }
 
-// Loan operations
-
-func (c *Client) CreateLoan(ctx context.Context, customerId, mortgageId uuid.UUID, loanAmount, interestRate float64, termYears int, monthlyPayment, outstandingBalance float64, startDate, maturityDate time.Time) (Loan, error) {
-	payload := struct {
-		CustomerId         uuid.UUID `json:"customer_id"`
-		MortgageId         uuid.UUID `json:"mortgage_id"`
-		LoanAmount         float64   `json:"loan_amount"`
-		InterestRate       float64   `json:"interest_rate"`
-		TermYears          int       `json:"term_years"`
-		MonthlyPayment     float64   `json:"monthly_payment"`
-		OutstandingBalance float64   `json:"outstanding_balance"`
-		StartDate          time.Time `json:"start_date"`
-		MaturityDate       time.Time `json:"maturity_date"`
-	}{
-		CustomerId:         customerId,
-		MortgageId:         mortgageId,
-		LoanAmount:         loanAmount,
-		InterestRate:       interestRate,
-		TermYears:          termYears,
-		MonthlyPayment:     monthlyPayment,
-		OutstandingBalance: outstandingBalance,
-		StartDate:          startDate,
-		MaturityDate:       maturityDate,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return Loan{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/loans", bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return Loan{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Loan{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return Loan{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var loan Loan
-	err = json.NewDecoder(resp.Body).Decode(&loan)
-	if err != nil {
-		return Loan{}, err
-	}
-
-	return loan, nil
+// Loans calls the loan endpoints, with the client's transport and options
+func (c *Client) Loans() *Loans {
+	return &Loans{client: c}
 }
 
-func (c *Client) GetLoan(ctx context.Context, id uuid.UUID) (Loan, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/loans", id.String())
-	if err != nil {
-		return Loan{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return Loan{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Loan{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Loan{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var loan Loan
-	err = json.NewDecoder(resp.Body).Decode(&loan)
-	if err != nil {
-		return Loan{}, err
-	}
-	return loan, nil
+// Payments calls the payment endpoints, with the client's transport and options
+func (c *Client) Payments() *Payments {
+	return &Payments{client: c}
 }
 
-func (c *Client) UpdateLoan(ctx context.Context, id, customerId, mortgageId uuid.UUID, loanAmount, interestRate float64, termYears int, monthlyPayment, outstandingBalance float64, status string, startDate, maturityDate time.Time) (Loan, error) {
-	payload := struct {
-		CustomerId         uuid.UUID `json:"customer_id"`
-		MortgageId         uuid.UUID `json:"mortgage_id"`
-		LoanAmount         float64   `json:"loan_amount"`
-		InterestRate       float64   `json:"interest_rate"`
-		TermYears          int       `json:"term_years"`
-		MonthlyPayment     float64   `json:"monthly_payment"`
-		OutstandingBalance float64   `json:"outstanding_balance"`
-		Status             string    `json:"status"`
-		StartDate          time.Time `json:"start_date"`
-		MaturityDate       time.Time `json:"maturity_date"`
-	}{
-		CustomerId:         customerId,
-		MortgageId:         mortgageId,
-		LoanAmount:         loanAmount,
-		InterestRate:       interestRate,
-		TermYears:          termYears,
-		MonthlyPayment:     monthlyPayment,
-		OutstandingBalance: outstandingBalance,
-		Status:             status,
-		StartDate:          startDate,
-		MaturityDate:       maturityDate,
-	}
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return Loan{}, err
-	}
-
-	fullURL, err := url.JoinPath(c.baseURL, "/loans", id.String())
-	if err != nil {
-		return Loan{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, fullURL, bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return Loan{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
+// Payment schedule operations
 
+// CreateSchedule schedules a monthly payment of amount on the loan, collected
+// automatically when autopay is set
+func (c *Client) CreateSchedule(ctx context.Context, loanId uuid.UUID, amount decimal.Decimal, dayOfMonth int, autopay bool) (Schedule, error) {
+	body, err := jsonBody(struct {
+		Amount     decimal.Decimal `json:"amount"`
+		DayOfMonth int             `json:"day_of_month"`
+		Autopay    bool            `json:"autopay"`
+	}{
+		Amount:     amount,
+		DayOfMonth: dayOfMonth,
+		Autopay:    autopay,
+	})
 	if err != nil {
-		return Loan{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Loan{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var loan Loan
-	err = json.NewDecoder(resp.Body).Decode(&loan)
-	if err != nil {
-		return Loan{}, err
+		return Schedule{}, err
 	}
-	return loan, nil
+	resp, err := c.api.CreateScheduleWithBody(ctx, loanId, contentTypeJSON, body)
+	return decode[Schedule](resp, err, http.StatusCreated)
 }
 
-func (c *Client) DeleteLoan(ctx context.Context, id uuid.UUID) error {
-	fullURL, err := url.JoinPath(c.baseURL, "/loans", id.String())
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodDelete, fullURL, nil)
-	if err != nil {
-		return err
-	}
-	req = req.WithContext(ctx)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	return nil
+// DeleteSchedule stops a payment schedule. Deleting a missing schedule is not an error.
+func (c *Client) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	resp, err := c.api.DeleteSchedule(ctx, id)
+	return noContent(resp, err)
 }
 
-func (c *Client) GetLoansByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Loan, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/customers", customerId.String(), "loans")
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var loanList []Loan
-	err = json.NewDecoder(resp.Body).Decode(&loanList)
-	if err != nil {
-		return nil, err
-	}
-	return loanList, nil
-}
-
-func (c *Client) GetLoanByMortgageId(ctx context.Context, mortgageId uuid.UUID) (Loan, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/mortgages", mortgageId.String(), "loan")
-	if err != nil {
-		return Loan{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return Loan{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Loan{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Loan{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var loan Loan
-	err = json.NewDecoder(resp.Body).Decode(&loan)
-	if err != nil {
-		return Loan{}, err
-	}
-	return loan, nil
+// GetDuePayments lists the loan's scheduled installments, most recent first
+func (c *Client) GetDuePayments(ctx context.Context, loanId uuid.UUID) ([]DuePayment, error) {
+	resp, err := c.api.ListDuePayments(ctx, loanId)
+	return decode[[]DuePayment](resp, err, http.StatusOK)
 }
 
-// Payment operations
-
-func (c *Client) CreatePayment(ctx context.Context, loanId, customerId uuid.UUID, paymentAmount, principalAmount, interestAmount float64, paymentDate time.Time, paymentType string) (Payment, error) {
-	payload := struct {
-		LoanId          uuid.UUID `json:"loan_id"`
-		CustomerId      uuid.UUID `json:"customer_id"`
-		PaymentAmount   float64   `json:"payment_amount"`
-		PrincipalAmount float64   `json:"principal_amount"`
-		InterestAmount  float64   `json:"interest_amount"`
-		PaymentDate     time.Time `json:"payment_date"`
-		PaymentType     string    `json:"payment_type"`
-	}{
-		LoanId:          loanId,
-		CustomerId:      customerId,
-		PaymentAmount:   paymentAmount,
-		PrincipalAmount: principalAmount,
-		InterestAmount:  interestAmount,
-		PaymentDate:     paymentDate,
-		PaymentType:     paymentType,
-	}
-
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return Payment{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/payments", bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return Payment{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Payment{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusCreated {
-		return Payment{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var payment Payment
-	err = json.NewDecoder(resp.Body).Decode(&payment)
-	if err != nil {
-		return Payment{}, err
-	}
+// Escrow operations
 
-	return payment, nil
+// OpenEscrowAccount opens an empty escrow account for the loan
+func (c *Client) OpenEscrowAccount(ctx context.Context, loanId uuid.UUID) (EscrowAccount, error) {
+	resp, err := c.api.OpenEscrow(ctx, loanId)
+	return decode[EscrowAccount](resp, err, http.StatusCreated)
 }
 
-func (c *Client) GetPayment(ctx context.Context, id uuid.UUID) (Payment, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/payments", id.String())
-	if err != nil {
-		return Payment{}, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return Payment{}, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return Payment{}, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return Payment{}, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var payment Payment
-	err = json.NewDecoder(resp.Body).Decode(&payment)
-	if err != nil {
-		return Payment{}, err
-	}
-	return payment, nil
+func (c *Client) GetEscrowAccount(ctx context.Context, loanId uuid.UUID) (EscrowAccount, error) {
+	resp, err := c.api.GetEscrow(ctx, loanId)
+	return decode[EscrowAccount](resp, err, http.StatusOK)
 }
 
-func (c *Client) GetPaymentsByLoanId(ctx context.Context, loanId uuid.UUID) ([]Payment, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/loans", loanId.String(), "payments")
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var paymentList []Payment
-	err = json.NewDecoder(resp.Body).Decode(&paymentList)
+// DisburseEscrow pays a tax or insurance bill from the loan's escrow account
+func (c *Client) DisburseEscrow(ctx context.Context, loanId uuid.UUID, disbursementType string, amount decimal.Decimal, payee string) (EscrowDisbursement, error) {
+	body, err := jsonBody(struct {
+		Type   string          `json:"type"`
+		Amount decimal.Decimal `json:"amount"`
+		Payee  string          `json:"payee"`
+	}{
+		Type:   disbursementType,
+		Amount: amount,
+		Payee:  payee,
+	})
 	if err != nil {
-		return nil, err
+		return EscrowDisbursement{}, err
 	}
-	return paymentList, nil
+	resp, err := c.api.DisburseEscrowWithBody(ctx, loanId, contentTypeJSON, body)
+	return decode[EscrowDisbursement](resp, err, http.StatusCreated)
 }
 
-func (c *Client) GetPaymentsByCustomerId(ctx context.Context, customerId uuid.UUID) ([]Payment, error) {
-	fullURL, err := url.JoinPath(c.baseURL, "/customers", customerId.String(), "payments")
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodGet, fullURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req = req.WithContext(ctx)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-	var paymentList []Payment
-	err = json.NewDecoder(resp.Body).Decode(&paymentList)
-	if err != nil {
-		return nil, err
+// positive returns n for a query parameter when it is set, nil otherwise
+func positive(n int) *int {
+	if n <= 0 {
+		return nil
 	}
-	return paymentList, nil
+	return &n
 }
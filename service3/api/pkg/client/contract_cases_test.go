@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/escrow"
+	"service3/api/internal/health"
+	"service3/api/internal/latefees"
+	"service3/api/internal/loans"
+	"service3/api/internal/payments"
+	"service3/api/internal/schedules"
+	"service3/api/internal/versioning"
+	"service3/api/internal/webhooks"
+)
+
+// contractRoutes registers the service's routes as main does; the handlers are
+// replaced by the replaying server, so they need no services
+func contractRoutes(e *echo.Echo) {
+	v1 := e.Group(versioning.Prefix("v1"))
+	loans.Routes(v1, loans.NewLoanHandler(nil))
+	payments.Routes(v1, payments.NewPaymentHandler(nil))
+	escrow.Routes(v1, escrow.NewEscrowHandler(nil))
+	latefees.Routes(v1, latefees.NewLateFeeHandler(nil))
+	schedules.Routes(v1, schedules.NewScheduleHandler(nil))
+	health.Routes(e, health.NewHealthHandler(nil))
+	webhooks.Routes(v1, webhooks.NewWebhookHandler(nil))
+}
+
+var (
+	contractStart    = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	contractMaturity = time.Date(2055, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+var contractCases = []contractCase{
+	{"create_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Create(ctx, uuid.New(), uuid.New(), decimal.NewFromInt(300000), 5.5, 30,
+			decimal.RequireFromString("1703.37"), decimal.NewFromInt(300000), contractStart, contractMaturity)
+	}},
+	{"get_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Get(ctx, uuid.New())
+	}},
+	{"get_missing_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Get(ctx, uuid.New())
+	}},
+	{"cancel_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Cancel(ctx, uuid.New(), Cancellation{CancelledBy: "saga", Reason: "saga_compensation"})
+	}},
+	{"cancel_paid_off_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Cancel(ctx, uuid.New(), Cancellation{CancelledBy: "saga", Reason: "saga_compensation"})
+	}},
+	{"list_customer_loans", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().ListByCustomer(ctx, uuid.New(), LoanFilter{Status: "active", Limit: 10})
+	}},
+	{"customer_loan_summary", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().Summary(ctx, uuid.New())
+	}},
+	{"get_mortgage_loan", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().GetByMortgageId(ctx, uuid.New())
+	}},
+	{"payoff_quote", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().PayoffQuote(ctx, uuid.New(), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC))
+	}},
+	{"amortization_schedule", func(ctx context.Context, c *Client) (any, error) {
+		return c.Loans().AmortizationSchedule(ctx, uuid.New(), contractStart, AmortizationFilter{Limit: 1})
+	}},
+	{"create_payment", func(ctx context.Context, c *Client) (any, error) {
+		return c.Payments().Create(ctx, uuid.New(), uuid.New(), decimal.RequireFromString("1703.37"),
+			decimal.RequireFromString("328.37"), decimal.RequireFromString("1375.00"), decimal.Zero,
+			time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), "regular")
+	}},
+	{"reverse_payment", func(ctx context.Context, c *Client) (any, error) {
+		return c.Payments().Reverse(ctx, uuid.New())
+	}},
+	{"list_loan_payments", func(ctx context.Context, c *Client) (any, error) {
+		return c.Payments().ListByLoan(ctx, uuid.New(), PaymentFilter{Type: "regular", Limit: 10})
+	}},
+	{"list_customer_payments", func(ctx context.Context, c *Client) (any, error) {
+		return c.Payments().ListByCustomer(ctx, uuid.New(), PaymentFilter{Limit: 10})
+	}},
+	{"create_schedule", func(ctx context.Context, c *Client) (any, error) {
+		return c.CreateSchedule(ctx, uuid.New(), decimal.RequireFromString("1703.37"), 1, true)
+	}},
+	{"get_escrow_account", func(ctx context.Context, c *Client) (any, error) {
+		return c.GetEscrowAccount(ctx, uuid.New())
+	}},
+}
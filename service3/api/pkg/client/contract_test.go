@@ -0,0 +1,220 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// The contract tests replay the interactions in testdata/contract against the
+// client, behind a server that routes with the service's own route table. Each
+// fixture holds the request the client sends, as its method, the route it matches
+// and an example body, and the response the service answers it with. A call passes
+// when its request matches a registered route and the fixture's method, route and
+// body shape, and the client keeps every field of the response or, for an error
+// status, returns the envelope as an APIError.
+//
+// After changing a call on purpose, record the requests the client now sends with
+//
+//	go test ./api/pkg/client/ -run TestContract -update
+//
+// which keeps the responses, and review the fixtures' diff.
+var update = flag.Bool("update", false, "record the requests the client sends into testdata/contract")
+
+// interaction is a fixture in testdata/contract
+type interaction struct {
+	Request struct {
+		Method string          `json:"method"`
+		Route  string          `json:"route"`
+		Body   json.RawMessage `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body,omitempty"`
+	} `json:"response"`
+}
+
+// contractCase replays testdata/contract/<name>.json with call, which returns what
+// the client read from the response
+type contractCase struct {
+	name string
+	call func(ctx context.Context, c *Client) (any, error)
+}
+
+// sentRequest is what the client sent to the replaying server
+type sentRequest struct {
+	method, path, route string
+	body                []byte
+}
+
+func TestContract(t *testing.T) {
+	for _, tc := range contractCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replay(t, tc)
+		})
+	}
+}
+
+func replay(t *testing.T, tc contractCase) {
+	file := filepath.Join("testdata", "contract", tc.name+".json")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Unable to read fixture: %v", err)
+	}
+	var fixture interaction
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		t.Fatalf("Invalid fixture %s: %v", file, err)
+	}
+
+	var sent sentRequest
+	server := httptest.NewServer(replayServer(&fixture, &sent))
+	defer server.Close()
+	result, err := tc.call(context.Background(), NewClient(server.URL))
+
+	if sent.route == "" {
+		t.Fatalf("%s %s matched no route of the service", sent.method, sent.path)
+	}
+	if *update {
+		record(t, file, fixture, sent)
+		return
+	}
+	if sent.method != fixture.Request.Method || sent.route != fixture.Request.Route {
+		t.Errorf("Expected %s %s, sent %s %s", fixture.Request.Method, fixture.Request.Route, sent.method, sent.route)
+	}
+	if got, want := shape(t, sent.body), shape(t, fixture.Request.Body); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected a request body shaped %v, sent %v", want, got)
+	}
+
+	if fixture.Response.Status >= http.StatusBadRequest {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an APIError for a %d, got %v", fixture.Response.Status, err)
+		}
+		var envelope struct {
+			Code string `json:"code"`
+		}
+		_ = json.Unmarshal(fixture.Response.Body, &envelope)
+		if apiErr.StatusCode != fixture.Response.Status || apiErr.Code != envelope.Code {
+			t.Errorf("Expected a %d %q, got %+v", fixture.Response.Status, envelope.Code, apiErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fixture.Response.Body) > 0 {
+		read, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Unable to encode %T: %v", result, err)
+		}
+		for _, field := range missingFields(shape(t, fixture.Response.Body), shape(t, read), "") {
+			t.Errorf("The client dropped the response's %s", field)
+		}
+	}
+}
+
+// replayServer answers requests to the service's routes with fixture's response,
+// recording what was sent
+func replayServer(fixture *interaction, sent *sentRequest) http.Handler {
+	routes := echo.New()
+	contractRoutes(routes)
+	e := echo.New()
+	for _, route := range routes.Routes() {
+		e.Add(route.Method, route.Path, func(c echo.Context) error {
+			sent.route = c.Path()
+			sent.body, _ = io.ReadAll(c.Request().Body)
+			if len(fixture.Response.Body) == 0 {
+				return c.NoContent(fixture.Response.Status)
+			}
+			return c.JSONBlob(fixture.Response.Status, fixture.Response.Body)
+		})
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.method, sent.path = r.Method, r.URL.Path
+		e.ServeHTTP(w, r)
+	})
+}
+
+// record rewrites the fixture in file with the request the client sent
+func record(t *testing.T, file string, fixture interaction, sent sentRequest) {
+	fixture.Request.Method, fixture.Request.Route, fixture.Request.Body = sent.method, sent.route, nil
+	if len(sent.body) > 0 {
+		if !json.Valid(sent.body) {
+			t.Fatalf("The request body is not JSON: %s", sent.body)
+		}
+		fixture.Request.Body = sent.body
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// shape reduces a JSON document to its structure: the keys of its objects, the shape
+// of each array's first element and the type of every other value. Empty is nil.
+func shape(t *testing.T, data []byte) any {
+	t.Helper()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", data, err)
+	}
+	return shapeOf(v)
+}
+
+func shapeOf(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		fields := make(map[string]any, len(v))
+		for key, value := range v {
+			fields[key] = shapeOf(value)
+		}
+		return fields
+	case []any:
+		if len(v) == 0 {
+			return []any{}
+		}
+		return []any{shapeOf(v[0])}
+	case nil:
+		return "null"
+	}
+	return reflect.TypeOf(v).Kind().String()
+}
+
+// missingFields lists the object keys in want that got lacks, as paths from prefix
+func missingFields(want, got any, prefix string) []string {
+	var missing []string
+	switch want := want.(type) {
+	case map[string]any:
+		got, _ := got.(map[string]any)
+		for key, value := range want {
+			field, ok := got[key]
+			if !ok {
+				missing = append(missing, prefix+key)
+				continue
+			}
+			missing = append(missing, missingFields(value, field, prefix+key+".")...)
+		}
+	case []any:
+		if got, ok := got.([]any); ok && len(want) > 0 && len(got) > 0 {
+			missing = missingFields(want[0], got[0], prefix+"[0].")
+		}
+	}
+	return missing
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAPIError_ParsesEnvelope(t *testing.T) {
+	body := `{"code":"not_found","message":"resource not found","request_id":"req-1"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Loans().Delete(context.Background(), uuid.New())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || apiErr.Code != "not_found" ||
+		apiErr.Message != "resource not found" || apiErr.RequestID != "req-1" || string(apiErr.RawBody) != body {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+	if !IsStatus(fmt.Errorf("compensate: %w", err), http.StatusNotFound) || IsStatus(err, http.StatusConflict) {
+		t.Error("Expected IsStatus to match the wrapped 404 only")
+	}
+}
+
+func TestAPIError_KeepsBodyWithoutEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream unavailable"))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Loans().Delete(context.Background(), uuid.New())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || apiErr.Code != "" ||
+		string(apiErr.RawBody) != "upstream unavailable" {
+		t.Fatalf("Unexpected error %#v", err)
+	}
+	if got, want := err.Error(), "unexpected status code: 502"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAPIError_WrapsSentinels(t *testing.T) {
+	cases := map[int]error{http.StatusNotFound: ErrNotFound, http.StatusConflict: ErrConflict}
+	for status, want := range cases {
+		err := fmt.Errorf("call: %w", &APIError{StatusCode: status})
+		if !errors.Is(err, want) {
+			t.Errorf("Expected a %d to wrap %v", status, want)
+		}
+	}
+	err := &APIError{StatusCode: http.StatusInternalServerError}
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrConflict) {
+		t.Error("Expected a 500 to wrap neither sentinel")
+	}
+}
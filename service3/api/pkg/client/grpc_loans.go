@@ -0,0 +1,229 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"service3/api/pkg/pb/servicingv1"
+)
+
+// GRPCClient calls the loan servicing service's gRPC API. Its Loans and Payments
+// have the methods of Client.Loans and Client.Payments that the gRPC API covers,
+// taking and returning the same types, and its errors are the APIErrors Client
+// returns, so errors.Is(err, ErrNotFound) works with either.
+type GRPCClient struct {
+	loans    servicingv1.LoanServiceClient
+	payments servicingv1.PaymentServiceClient
+	// metadata is added to every call; the With options append to it
+	metadata []outgoingMetadata
+	// timeout bounds each call; see WithTimeout
+	timeout time.Duration
+}
+
+// NewGRPCClient calls the service over conn, which is dialled with the transport
+// credentials, and any interceptors, the caller needs
+func NewGRPCClient(conn grpc.ClientConnInterface) *GRPCClient {
+	return &GRPCClient{
+		loans:    servicingv1.NewLoanServiceClient(conn),
+		payments: servicingv1.NewPaymentServiceClient(conn),
+		timeout:  DefaultTimeout,
+	}
+}
+
+// Loans calls the loan RPCs, with the client's metadata and timeout
+func (c *GRPCClient) Loans() *GRPCLoans {
+	return &GRPCLoans{client: c}
+}
+
+// Payments calls the payment RPCs, with the client's metadata and timeout
+func (c *GRPCClient) Payments() *GRPCPayments {
+	return &GRPCPayments{client: c}
+}
+
+// GRPCLoans calls the loan RPCs; get one from GRPCClient.Loans
+type GRPCLoans struct {
+	client *GRPCClient
+}
+
+func (l *GRPCLoans) Create(ctx context.Context, customerId, mortgageId uuid.UUID, loanAmount decimal.Decimal, interestRate float64, termYears int, monthlyPayment, outstandingBalance decimal.Decimal, startDate, maturityDate time.Time) (Loan, error) {
+	loan, err := invoke(ctx, l.client, l.client.loans.CreateLoan, &servicingv1.CreateLoanRequest{
+		CustomerId:         customerId.String(),
+		MortgageId:         mortgageId.String(),
+		LoanAmount:         loanAmount.String(),
+		InterestRate:       interestRate,
+		TermYears:          int32(termYears),
+		MonthlyPayment:     monthlyPayment.String(),
+		OutstandingBalance: outstandingBalance.String(),
+		StartDate:          toTimestamp(startDate),
+		MaturityDate:       toTimestamp(maturityDate),
+	})
+	if err != nil {
+		return Loan{}, err
+	}
+	return fromLoanMessage(loan)
+}
+
+func (l *GRPCLoans) Get(ctx context.Context, id uuid.UUID) (Loan, error) {
+	loan, err := invoke(ctx, l.client, l.client.loans.GetLoan, &servicingv1.GetLoanRequest{Id: id.String()})
+	if err != nil {
+		return Loan{}, err
+	}
+	return fromLoanMessage(loan)
+}
+
+// Cancel cancels an active loan. Cancelling an already cancelled loan returns it
+// unchanged, so it is safe to retry from a compensation.
+func (l *GRPCLoans) Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error) {
+	loan, err := invoke(ctx, l.client, l.client.loans.CancelLoan, &servicingv1.CancelLoanRequest{
+		Id:          id.String(),
+		CancelledBy: cancellation.CancelledBy,
+		Reason:      cancellation.Reason,
+	})
+	if err != nil {
+		return Loan{}, err
+	}
+	return fromLoanMessage(loan)
+}
+
+// GRPCPayments calls the payment RPCs; get one from GRPCClient.Payments
+type GRPCPayments struct {
+	client *GRPCClient
+}
+
+func (p *GRPCPayments) Create(ctx context.Context, loanId, customerId uuid.UUID, paymentAmount, principalAmount, interestAmount, escrowAmount decimal.Decimal, paymentDate time.Time, paymentType string) (Payment, error) {
+	payment, err := invoke(ctx, p.client, p.client.payments.CreatePayment, &servicingv1.CreatePaymentRequest{
+		LoanId:          loanId.String(),
+		CustomerId:      customerId.String(),
+		PaymentAmount:   paymentAmount.String(),
+		PrincipalAmount: principalAmount.String(),
+		InterestAmount:  interestAmount.String(),
+		EscrowAmount:    escrowAmount.String(),
+		PaymentDate:     toTimestamp(paymentDate),
+		PaymentType:     paymentType,
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	return fromPaymentMessage(payment)
+}
+
+func (p *GRPCPayments) Get(ctx context.Context, id uuid.UUID) (Payment, error) {
+	payment, err := invoke(ctx, p.client, p.client.payments.GetPayment, &servicingv1.GetPaymentRequest{Id: id.String()})
+	if err != nil {
+		return Payment{}, err
+	}
+	return fromPaymentMessage(payment)
+}
+
+// ListByLoan returns a page of the loan's payments, newest first. The gRPC API
+// pages but does not filter, so only filter.Limit and filter.Offset may be set.
+func (p *GRPCPayments) ListByLoan(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	if filter != (PaymentFilter{Limit: filter.Limit, Offset: filter.Offset}) {
+		return nil, fmt.Errorf("the gRPC API only pages payments, it cannot filter them")
+	}
+	resp, err := invoke(ctx, p.client, p.client.payments.ListLoanPayments, &servicingv1.ListLoanPaymentsRequest{
+		LoanId: loanId.String(),
+		Limit:  int32(filter.Limit),
+		Offset: int32(filter.Offset),
+	})
+	if err != nil {
+		return nil, err
+	}
+	paymentList := make([]Payment, 0, len(resp.GetPayments()))
+	for _, message := range resp.GetPayments() {
+		payment, err := fromPaymentMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		paymentList = append(paymentList, payment)
+	}
+	return paymentList, nil
+}
+
+// toTimestamp converts t, leaving the zero time unset so the service applies its default
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// messageFields parses a message's UUID and decimal fields, keeping the first error
+type messageFields struct {
+	err error
+}
+
+func (f *messageFields) id(field, value string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("invalid %s %q: %w", field, value, err)
+	}
+	return id
+}
+
+func (f *messageFields) amount(field, value string) decimal.Decimal {
+	amount, err := decimal.NewFromString(value)
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("invalid %s %q: %w", field, value, err)
+	}
+	return amount
+}
+
+func fromLoanMessage(message *servicingv1.Loan) (Loan, error) {
+	var fields messageFields
+	loan := Loan{
+		Id:                 fields.id("id", message.GetId()),
+		CustomerId:         fields.id("customer_id", message.GetCustomerId()),
+		MortgageId:         fields.id("mortgage_id", message.GetMortgageId()),
+		LoanAmount:         fields.amount("loan_amount", message.GetLoanAmount()),
+		InterestRate:       message.GetInterestRate(),
+		TermYears:          int(message.GetTermYears()),
+		MonthlyPayment:     fields.amount("monthly_payment", message.GetMonthlyPayment()),
+		OutstandingBalance: fields.amount("outstanding_balance", message.GetOutstandingBalance()),
+		Status:             message.GetStatus(),
+		StartDate:          fromTimestamp(message.GetStartDate()),
+		MaturityDate:       fromTimestamp(message.GetMaturityDate()),
+		CancelledAt:        fromOptionalTimestamp(message.GetCancelledAt()),
+		CreatedAt:          fromTimestamp(message.GetCreatedAt()),
+		ModifiedAt:         fromTimestamp(message.GetModifiedAt()),
+	}
+	if fields.err != nil {
+		return Loan{}, fields.err
+	}
+	if cancelledBy := message.GetCancelledBy(); cancelledBy != "" {
+		loan.CancelledBy = &cancelledBy
+	}
+	if reason := message.GetCancellationReason(); reason != "" {
+		loan.CancellationReason = &reason
+	}
+	return loan, nil
+}
+
+func fromPaymentMessage(message *servicingv1.Payment) (Payment, error) {
+	var fields messageFields
+	payment := Payment{
+		Id:              fields.id("id", message.GetId()),
+		LoanId:          fields.id("loan_id", message.GetLoanId()),
+		CustomerId:      fields.id("customer_id", message.GetCustomerId()),
+		PaymentAmount:   fields.amount("payment_amount", message.GetPaymentAmount()),
+		PrincipalAmount: fields.amount("principal_amount", message.GetPrincipalAmount()),
+		InterestAmount:  fields.amount("interest_amount", message.GetInterestAmount()),
+		EscrowAmount:    fields.amount("escrow_amount", message.GetEscrowAmount()),
+		PaymentDate:     fromTimestamp(message.GetPaymentDate()),
+		PaymentType:     message.GetPaymentType(),
+		CreatedAt:       fromTimestamp(message.GetCreatedAt()),
+	}
+	if reversalOf := message.GetReversalOf(); reversalOf != "" {
+		id := fields.id("reversal_of", reversalOf)
+		payment.ReversalOf = &id
+	}
+	if fields.err != nil {
+		return Payment{}, fields.err
+	}
+	return payment, nil
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"service3/api/pkg/pb/servicingv1"
+)
+
+// loanServer creates the loan it is asked for and answers cancels for any other
+// loan with NotFound, keeping the metadata of the last call
+type loanServer struct {
+	servicingv1.UnimplementedLoanServiceServer
+	servicingv1.UnimplementedPaymentServiceServer
+	loan *servicingv1.Loan
+	md   metadata.MD
+}
+
+func (s *loanServer) CreateLoan(ctx context.Context, req *servicingv1.CreateLoanRequest) (*servicingv1.Loan, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	s.loan = &servicingv1.Loan{
+		Id: uuid.NewString(), CustomerId: req.GetCustomerId(), MortgageId: req.GetMortgageId(), LoanAmount: req.GetLoanAmount(),
+		InterestRate: req.GetInterestRate(), TermYears: req.GetTermYears(), MonthlyPayment: req.GetMonthlyPayment(),
+		OutstandingBalance: req.GetOutstandingBalance(), Status: "active", StartDate: req.GetStartDate(), MaturityDate: req.GetMaturityDate(),
+	}
+	return s.loan, nil
+}
+
+func (s *loanServer) CancelLoan(ctx context.Context, req *servicingv1.CancelLoanRequest) (*servicingv1.Loan, error) {
+	if s.loan == nil || req.GetId() != s.loan.GetId() {
+		return nil, status.Error(codes.NotFound, "loan not found")
+	}
+	s.loan.Status, s.loan.CancelledBy, s.loan.CancellationReason = "cancelled", req.GetCancelledBy(), req.GetReason()
+	return s.loan, nil
+}
+
+func dialServicing(t *testing.T, server *loanServer) *GRPCClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	servicingv1.RegisterLoanServiceServer(grpcServer, server)
+	servicingv1.RegisterPaymentServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("Unable to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewGRPCClient(conn)
+}
+
+func TestGRPCClient_CreateAndCancelLoan(t *testing.T) {
+	server := &loanServer{}
+	client := dialServicing(t, server).WithTenantFrom(func(ctx context.Context) string { return "lender-a" })
+	ctx := context.Background()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	loan, err := client.Loans().Create(ctx, uuid.New(), uuid.New(), decimal.NewFromInt(300000), 5.5, 30,
+		decimal.RequireFromString("1703.37"), decimal.NewFromInt(300000), start, start.AddDate(30, 0, 0))
+	if err != nil {
+		t.Fatalf("CreateLoan failed: %v", err)
+	}
+	if !loan.MonthlyPayment.Equal(decimal.RequireFromString("1703.37")) || !loan.StartDate.Equal(start) || loan.CancelledAt != nil {
+		t.Errorf("Unexpected loan %+v", loan)
+	}
+	if got := server.md.Get("x-tenant-id"); len(got) != 1 || got[0] != "lender-a" {
+		t.Errorf("Expected the tenant in the metadata, got %v", server.md)
+	}
+
+	cancelled, err := client.Loans().Cancel(ctx, loan.Id, Cancellation{CancelledBy: "saga", Reason: CancelReasonSagaCompensation})
+	if err != nil {
+		t.Fatalf("CancelLoan failed: %v", err)
+	}
+	if cancelled.Status != "cancelled" || cancelled.CancelledBy == nil || *cancelled.CancelledBy != "saga" {
+		t.Errorf("Unexpected cancelled loan %+v", cancelled)
+	}
+
+	_, err = client.Loans().Cancel(ctx, uuid.New(), Cancellation{CancelledBy: "saga"})
+	if !errors.Is(err, ErrNotFound) || !IsStatus(err, http.StatusNotFound) {
+		t.Errorf("Expected a missing loan to be ErrNotFound, got %v", err)
+	}
+}
+
+func TestGRPCClient_ListPaymentsByLoanOnlyPages(t *testing.T) {
+	client := dialServicing(t, &loanServer{})
+	_, err := client.Payments().ListByLoan(context.Background(), uuid.New(), PaymentFilter{Type: "extra"})
+	if err == nil {
+		t.Error("Expected a filter the gRPC API lacks to be refused")
+	}
+}
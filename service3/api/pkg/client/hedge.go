@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// hedgeWindow is how many recent GET latencies a hedging transport keeps to take
+// the percentile of, and hedgeMinSamples how many it needs before it does
+const (
+	hedgeWindow     = 128
+	hedgeMinSamples = 20
+)
+
+// Hedge configures WithHedging
+type Hedge struct {
+	// Percentile of the recent GET latencies to wait before sending the second
+	// request, e.g. 0.95 hedges the slowest one in twenty
+	Percentile float64
+	// Delay is waited instead until enough GETs have been timed
+	Delay time.Duration
+}
+
+// DefaultHedge hedges the slowest 5% of reads, waiting 100ms until it has seen enough
+var DefaultHedge = Hedge{Percentile: 0.95, Delay: 100 * time.Millisecond}
+
+// WithHedging sends a second copy of a GET that has not been answered within the
+// hedge's percentile of recent GET latencies, and uses whichever response comes
+// first, cancelling the other. A GetLoan stuck behind a slow replica or a lost
+// packet then costs about the percentile rather than the whole timeout, for a few
+// percent more reads. Only GETs are hedged, since they are safe to send twice.
+func (c *Client) WithHedging(hedge Hedge) *Client {
+	c.httpClient.Transport = &hedging{hedge: hedge, next: c.httpClient.Transport}
+	return c
+}
+
+// hedging sends GETs with next, and a second copy once they are slower than most
+type hedging struct {
+	hedge Hedge
+	next  http.RoundTripper
+
+	mu        sync.Mutex
+	latencies []time.Duration // the last hedgeWindow, oldest first
+}
+
+// hedgeResult is one copy's outcome
+type hedgeResult struct {
+	index int
+	resp  *http.Response
+	err   error
+}
+
+func (t *hedging) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet || req.Body != nil && req.Body != http.NoBody {
+		return next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := next.RoundTrip(req.Clone(ctx))
+			results <- hedgeResult{index: index, resp: resp, err: err}
+		}()
+	}
+	send()
+	timer := time.NewTimer(t.delay())
+	defer timer.Stop()
+
+	var err error
+	for received := 0; received < len(cancels); {
+		select {
+		case <-timer.C:
+			send()
+		case result := <-results:
+			received++
+			if result.err != nil {
+				// The other copy, if sent, may still succeed
+				cancels[result.index]()
+				err = result.err
+				if received == 1 && len(cancels) == 1 {
+					return nil, err
+				}
+				continue
+			}
+			t.record(time.Since(start))
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+			if received < len(cancels) {
+				go discard(results)
+			}
+			result.resp.Body = cancelOnClose{ReadCloser: result.resp.Body, cancel: cancels[result.index]}
+			return result.resp, nil
+		}
+	}
+	return nil, err
+}
+
+// discard closes the losing copy's response, if it got one, once it arrives
+func discard(results <-chan hedgeResult) {
+	if result := <-results; result.resp != nil {
+		result.resp.Body.Close()
+	}
+}
+
+// delay is how long to wait for a GET before hedging it
+func (t *hedging) delay() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.latencies) < hedgeMinSamples {
+		return t.hedge.Delay
+	}
+	sorted := slices.Clone(t.latencies)
+	slices.Sort(sorted)
+	i := int(t.hedge.Percentile * float64(len(sorted)-1))
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// record adds a GET's latency to the window
+func (t *hedging) record(latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.latencies) == hedgeWindow {
+		t.latencies = t.latencies[1:]
+	}
+	t.latencies = append(t.latencies, latency)
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// slowFirstServer stalls the first GET until the client gives up on it and
+// answers the rest straight away; it returns the number of requests it received
+func slowFirstServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 && r.Method == http.MethodGet {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"` + uuid.NewString() + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestWithHedging_UsesFasterCopy(t *testing.T) {
+	server, requests := slowFirstServer(t)
+	c := NewClient(server.URL).WithHedging(Hedge{Percentile: 0.95, Delay: 10 * time.Millisecond})
+
+	start := time.Now()
+	loan, err := c.Loans().Get(context.Background(), uuid.New())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loan.Id == uuid.Nil || requests.Load() != 2 {
+		t.Errorf("Expected the hedged copy's loan after 2 requests, got %+v after %d", loan, requests.Load())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the hedge to answer quickly, took %v", elapsed)
+	}
+}
+
+// TestWithHedging_SendsFastReadsOnce stays within the reads timed before the
+// percentile is used: past them, a read slower than the p95 is hedged by design
+func TestWithHedging_SendsFastReadsOnce(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	c := NewClient(server.URL).WithHedging(Hedge{Percentile: 0.95, Delay: time.Second})
+
+	for range hedgeMinSamples {
+		resp, err := get(t, c, context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if body, _ := io.ReadAll(resp.Body); string(body) != "ok" {
+			t.Errorf("Unexpected body %q", body)
+		}
+		resp.Body.Close()
+	}
+	if n := requests.Load(); n != hedgeMinSamples {
+		t.Errorf("Expected no read to be hedged, got %d requests", n)
+	}
+}
+
+func TestWithHedging_SendsWritesOnce(t *testing.T) {
+	server, requests := slowFirstServer(t)
+	c := NewClient(server.URL).WithHedging(Hedge{Delay: time.Millisecond})
+	if _, err := c.Loans().Cancel(context.Background(), uuid.New(), Cancellation{CancelledBy: "saga"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected the write to be sent once, got %d requests", n)
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// headerIdempotencyKey matches the Idempotency-Key header services deduplicate POSTs by
+const headerIdempotencyKey = "Idempotency-Key"
+
+// WithIdempotencyKeyFrom sends the key keyOf returns for a request's context in the
+// Idempotency-Key header of POSTs that do not already carry one, so a caller such as
+// a saga step can make every create it sends safe to repeat. Requests for which it
+// returns "" are sent without a key. Call it after WithRetry so retries see the key.
+func (c *Client) WithIdempotencyKeyFrom(keyOf func(ctx context.Context) string) *Client {
+	c.httpClient.Transport = idempotencyKey{keyOf: keyOf, next: c.httpClient.Transport}
+	return c
+}
+
+// idempotencyKey adds the Idempotency-Key header to POSTs before sending them with next
+type idempotencyKey struct {
+	keyOf func(ctx context.Context) string
+	next  http.RoundTripper
+}
+
+func (t idempotencyKey) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPost && req.Header.Get(headerIdempotencyKey) == "" {
+		if key := t.keyOf(req.Context()); key != "" {
+			req = req.Clone(req.Context())
+			req.Header.Set(headerIdempotencyKey, key)
+		}
+	}
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(req)
+}
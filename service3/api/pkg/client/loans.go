@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/loans"
+	"service3/api/pkg/client/internal/oapi"
+)
+
+// Loans calls the loan endpoints; get one from Client.Loans
+type Loans struct {
+	client *Client
+}
+
+func (l *Loans) Create(ctx context.Context, customerId, mortgageId uuid.UUID, loanAmount decimal.Decimal, interestRate float64, termYears int, monthlyPayment, outstandingBalance decimal.Decimal, startDate, maturityDate time.Time) (Loan, error) {
+	body, err := jsonBody(struct {
+		CustomerId         uuid.UUID       `json:"customer_id"`
+		MortgageId         uuid.UUID       `json:"mortgage_id"`
+		LoanAmount         decimal.Decimal `json:"loan_amount"`
+		InterestRate       float64         `json:"interest_rate"`
+		TermYears          int             `json:"term_years"`
+		MonthlyPayment     decimal.Decimal `json:"monthly_payment"`
+		OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+		StartDate          time.Time       `json:"start_date"`
+		MaturityDate       time.Time       `json:"maturity_date"`
+	}{
+		CustomerId:         customerId,
+		MortgageId:         mortgageId,
+		LoanAmount:         loanAmount,
+		InterestRate:       interestRate,
+		TermYears:          termYears,
+		MonthlyPayment:     monthlyPayment,
+		OutstandingBalance: outstandingBalance,
+		StartDate:          startDate,
+		MaturityDate:       maturityDate,
+	})
+	if err != nil {
+		return Loan{}, err
+	}
+	resp, err := l.client.api.CreateLoanWithBody(ctx, contentTypeJSON, body)
+	return decode[Loan](resp, err, http.StatusCreated)
+}
+
+func (l *Loans) Get(ctx context.Context, id uuid.UUID) (Loan, error) {
+	resp, err := l.client.api.GetLoan(ctx, id)
+	return decode[Loan](resp, err, http.StatusOK)
+}
+
+// Update changes the loan's customer and mortgage; the other arguments must match the
+// stored loan. Use Cancel, payments and Modify to change its status, balance and terms.
+func (l *Loans) Update(ctx context.Context, id, customerId, mortgageId uuid.UUID, loanAmount decimal.Decimal, interestRate float64, termYears int, monthlyPayment, outstandingBalance decimal.Decimal, status string, startDate, maturityDate time.Time) (Loan, error) {
+	body, err := jsonBody(struct {
+		CustomerId         uuid.UUID       `json:"customer_id"`
+		MortgageId         uuid.UUID       `json:"mortgage_id"`
+		LoanAmount         decimal.Decimal `json:"loan_amount"`
+		InterestRate       float64         `json:"interest_rate"`
+		TermYears          int             `json:"term_years"`
+		MonthlyPayment     decimal.Decimal `json:"monthly_payment"`
+		OutstandingBalance decimal.Decimal `json:"outstanding_balance"`
+		Status             string          `json:"status"`
+		StartDate          time.Time       `json:"start_date"`
+		MaturityDate       time.Time       `json:"maturity_date"`
+	}{
+		CustomerId:         customerId,
+		MortgageId:         mortgageId,
+		LoanAmount:         loanAmount,
+		InterestRate:       interestRate,
+		TermYears:          termYears,
+		MonthlyPayment:     monthlyPayment,
+		OutstandingBalance: outstandingBalance,
+		Status:             status,
+		StartDate:          startDate,
+		MaturityDate:       maturityDate,
+	})
+	if err != nil {
+		return Loan{}, err
+	}
+	resp, err := l.client.api.UpdateLoanWithBody(ctx, id, contentTypeJSON, body)
+	return decode[Loan](resp, err, http.StatusOK)
+}
+
+// Delete cancels the loan; loans are kept so their payments stay on record.
+// Use Cancel to record who cancelled it and why.
+func (l *Loans) Delete(ctx context.Context, id uuid.UUID) error {
+	resp, err := l.client.api.DeleteLoan(ctx, id)
+	return noContent(resp, err)
+}
+
+// Cancel cancels an active loan. Cancelling an already cancelled loan returns it
+// unchanged, so it is safe to retry from a compensation.
+func (l *Loans) Cancel(ctx context.Context, id uuid.UUID, cancellation Cancellation) (Loan, error) {
+	body, err := jsonBody(cancellation)
+	if err != nil {
+		return Loan{}, err
+	}
+	resp, err := l.client.api.CancelLoanWithBody(ctx, id, contentTypeJSON, body)
+	return decode[Loan](resp, err, http.StatusOK)
+}
+
+// Modify changes an active loan's rate, term or payment; unset request fields
+// keep the current terms and the monthly payment is recalculated unless set
+func (l *Loans) Modify(ctx context.Context, id uuid.UUID, request ModificationRequest) (LoanModification, error) {
+	body, err := jsonBody(request)
+	if err != nil {
+		return LoanModification{}, err
+	}
+	resp, err := l.client.api.ModifyLoanWithBody(ctx, id, contentTypeJSON, body)
+	return decode[LoanModification](resp, err, http.StatusCreated)
+}
+
+// ListByCustomer returns a page of the customer's loans, newest first
+func (l *Loans) ListByCustomer(ctx context.Context, customerId uuid.UUID, filter LoanFilter) ([]Loan, error) {
+	params := &oapi.ListCustomerLoansParams{Limit: positive(filter.Limit), Offset: positive(filter.Offset)}
+	if filter.Status != "" {
+		params.Status = &filter.Status
+	}
+	resp, err := l.client.api.ListCustomerLoans(ctx, customerId, params)
+	return decode[[]Loan](resp, err, http.StatusOK)
+}
+
+// Summary returns the totals of the customer's loans and payments and their
+// next payment due
+func (l *Loans) Summary(ctx context.Context, customerId uuid.UUID) (LoanSummary, error) {
+	resp, err := l.client.api.GetCustomerLoanSummary(ctx, customerId)
+	return decode[LoanSummary](resp, err, http.StatusOK)
+}
+
+// ListDelinquent returns a page of the delinquency aging report, optionally
+// limited to one bucket (30, 60 or 90 days past due)
+func (l *Loans) ListDelinquent(ctx context.Context, filter DelinquencyFilter) ([]DelinquentLoan, error) {
+	params := &oapi.ListDelinquentLoansParams{Limit: positive(filter.Limit), Offset: positive(filter.Offset)}
+	if filter.Bucket != "" {
+		params.Bucket = &filter.Bucket
+	}
+	resp, err := l.client.api.ListDelinquentLoans(ctx, params)
+	return decode[[]DelinquentLoan](resp, err, http.StatusOK)
+}
+
+func (l *Loans) GetByMortgageId(ctx context.Context, mortgageId uuid.UUID) (Loan, error) {
+	resp, err := l.client.api.GetMortgageLoan(ctx, mortgageId)
+	return decode[Loan](resp, err, http.StatusOK)
+}
+
+// PayoffQuote quotes the amount that pays the loan off on asOf; a zero asOf quotes
+// today's payoff
+func (l *Loans) PayoffQuote(ctx context.Context, id uuid.UUID, asOf time.Time) (PayoffQuote, error) {
+	resp, err := l.client.api.GetPayoffQuote(ctx, id, &oapi.GetPayoffQuoteParams{AsOf: dateParam(asOf)})
+	return decode[PayoffQuote](resp, err, http.StatusOK)
+}
+
+// AmortizationSchedule returns a page of the installments that repay the loan from
+// asOf, or from today when asOf is zero, to maturity
+func (l *Loans) AmortizationSchedule(ctx context.Context, id uuid.UUID, asOf time.Time, filter AmortizationFilter) ([]Installment, error) {
+	resp, err := l.client.api.GetAmortizationSchedule(ctx, id, amortizationParams(asOf, filter))
+	return decode[[]Installment](resp, err, http.StatusOK)
+}
+
+// Installments iterates over the loan's amortization schedule from asOf, starting at
+// filter.Offset and fetching filter.Limit installments, or as many as the service
+// allows, per request
+func (l *Loans) Installments(ctx context.Context, id uuid.UUID, asOf time.Time, filter AmortizationFilter) *Iterator[Installment] {
+	limit := pageSize(filter.Limit, loans.MaxListLimit)
+	return newIterator[Installment](ctx, l.client, limit, filter.Offset, func(limit, offset int) (*http.Request, error) {
+		filter.Limit, filter.Offset = limit, offset
+		return oapi.NewGetAmortizationScheduleRequest(l.client.api.Server, id, amortizationParams(asOf, filter))
+	})
+}
+
+// amortizationParams sets the query parameters for an amortization schedule page
+func amortizationParams(asOf time.Time, filter AmortizationFilter) *oapi.GetAmortizationScheduleParams {
+	return &oapi.GetAmortizationScheduleParams{AsOf: dateParam(asOf), Limit: positive(filter.Limit), Offset: positive(filter.Offset)}
+}
+
+// dateParam formats t as a date query parameter when it is set, nil otherwise
+func dateParam(t time.Time) *string {
+	if t.IsZero() {
+		return nil
+	}
+	return ptr(t.Format(time.DateOnly))
+}
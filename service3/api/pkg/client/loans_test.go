@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestInstallments_PagesScheduleFromAsOf(t *testing.T) {
+	loanId := uuid.New()
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/loans/"+loanId.String()+"/amortization" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var page []Installment
+		for number := offset + 1; number <= min(offset+2, 3); number++ {
+			page = append(page, Installment{Number: number})
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	asOf := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	it := NewClient(server.URL).Loans().Installments(context.Background(), loanId, asOf, AmortizationFilter{Limit: 2})
+	var numbers []int
+	for it.Next() {
+		numbers = append(numbers, it.Value().Number)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(numbers) != 3 || numbers[2] != 3 {
+		t.Errorf("Expected installments 1 to 3, got %v", numbers)
+	}
+	want := []string{"as_of=2025-03-01&limit=2", "as_of=2025-03-01&limit=2&offset=2"}
+	if len(queries) != len(want) || queries[0] != want[0] || queries[1] != want[1] {
+		t.Errorf("Expected queries %v, got %v", want, queries)
+	}
+}
+
+func TestPayoffQuote_SendsAsOfOnlyWhenSet(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode(PayoffQuote{PayoffAmount: decimal.NewFromInt(1000)})
+	}))
+	defer server.Close()
+	loans := NewClient(server.URL).Loans()
+
+	quote, err := loans.PayoffQuote(context.Background(), uuid.New(), time.Time{})
+	if err != nil || query != "" || !quote.PayoffAmount.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Expected today's quote without as_of, got %+v, %v (query %q)", quote, err, query)
+	}
+	if _, err := loans.PayoffQuote(context.Background(), uuid.New(), time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)); err != nil || query != "as_of=2025-06-30" {
+		t.Errorf("Expected as_of=2025-06-30, got %q, %v", query, err)
+	}
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"service3/api/internal/payments"
+	"service3/api/pkg/client/internal/oapi"
+)
+
+// Payments calls the payment endpoints; get one from Client.Payments
+type Payments struct {
+	client *Client
+}
+
+func (p *Payments) Create(ctx context.Context, loanId, customerId uuid.UUID, paymentAmount, principalAmount, interestAmount, escrowAmount decimal.Decimal, paymentDate time.Time, paymentType string) (Payment, error) {
+	body, err := jsonBody(struct {
+		LoanId          uuid.UUID       `json:"loan_id"`
+		CustomerId      uuid.UUID       `json:"customer_id"`
+		PaymentAmount   decimal.Decimal `json:"payment_amount"`
+		PrincipalAmount decimal.Decimal `json:"principal_amount"`
+		InterestAmount  decimal.Decimal `json:"interest_amount"`
+		EscrowAmount    decimal.Decimal `json:"escrow_amount"`
+		PaymentDate     time.Time       `json:"payment_date"`
+		PaymentType     string          `json:"payment_type"`
+	}{
+		LoanId:          loanId,
+		CustomerId:      customerId,
+		PaymentAmount:   paymentAmount,
+		PrincipalAmount: principalAmount,
+		InterestAmount:  interestAmount,
+		EscrowAmount:    escrowAmount,
+		PaymentDate:     paymentDate,
+		PaymentType:     paymentType,
+	})
+	if err != nil {
+		return Payment{}, err
+	}
+	resp, err := p.client.api.CreatePaymentWithBody(ctx, contentTypeJSON, body)
+	return decode[Payment](resp, err, http.StatusCreated)
+}
+
+func (p *Payments) Get(ctx context.Context, id uuid.UUID) (Payment, error) {
+	resp, err := p.client.api.GetPayment(ctx, id)
+	return decode[Payment](resp, err, http.StatusOK)
+}
+
+// Reverse offsets the payment and restores the loan balance. Reversing an already
+// reversed payment returns the existing reversal, so it is safe to retry as a saga
+// compensation.
+func (p *Payments) Reverse(ctx context.Context, id uuid.UUID) (Payment, error) {
+	resp, err := p.client.api.ReversePayment(ctx, id)
+	return decode[Payment](resp, err, http.StatusCreated, http.StatusOK)
+}
+
+// ListByLoan returns a page of the loan's payments matching filter
+func (p *Payments) ListByLoan(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	resp, err := p.client.api.ListLoanPayments(ctx, loanId, paymentParams(filter))
+	return decode[[]Payment](resp, err, http.StatusOK)
+}
+
+// ListByCustomer returns a page of the customer's payments matching filter
+func (p *Payments) ListByCustomer(ctx context.Context, customerId uuid.UUID, filter PaymentFilter) ([]Payment, error) {
+	params := oapi.ListCustomerPaymentsParams(*paymentParams(filter))
+	resp, err := p.client.api.ListCustomerPayments(ctx, customerId, &params)
+	return decode[[]Payment](resp, err, http.StatusOK)
+}
+
+// List iterates over every payment on the loan matching filter, starting at
+// filter.Offset and fetching filter.Limit payments, or as many as the service
+// allows, per request
+func (p *Payments) List(ctx context.Context, loanId uuid.UUID, filter PaymentFilter) *Iterator[Payment] {
+	limit := pageSize(filter.Limit, payments.MaxListLimit)
+	return newIterator[Payment](ctx, p.client, limit, filter.Offset, func(limit, offset int) (*http.Request, error) {
+		filter.Limit, filter.Offset = limit, offset
+		return oapi.NewListLoanPaymentsRequest(p.client.api.Server, loanId, paymentParams(filter))
+	})
+}
+
+// paymentParams sets the query parameters for the set fields of a payment filter
+func paymentParams(filter PaymentFilter) *oapi.ListLoanPaymentsParams {
+	params := &oapi.ListLoanPaymentsParams{Limit: positive(filter.Limit), Offset: positive(filter.Offset)}
+	if filter.Type != "" {
+		params.Type = &filter.Type
+	}
+	if !filter.From.IsZero() {
+		params.From = ptr(filter.From.Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		params.To = ptr(filter.To.Format(time.RFC3339))
+	}
+	if filter.Sort != "" {
+		params.Sort = &filter.Sort
+	}
+	if filter.Order != "" {
+		params.Order = &filter.Order
+	}
+	return params
+}
@@ -0,0 +1,11 @@
+package client
+
+import "crypto/tls"
+
+// WithTLSConfig connects to an https service with config, e.g. to trust a private CA
+// in RootCAs or to present a client certificate for mutual TLS in Certificates. Call
+// it before the client makes its first request.
+func (c *Client) WithTLSConfig(config *tls.Config) *Client {
+	c.transport.TLSClientConfig = config
+	return c
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWithTLSConfig_MutualTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// Without trusting the server's CA the handshake fails
+	if err := NewClient(server.URL).Loans().Delete(context.Background(), uuid.New()); err == nil {
+		t.Fatal("Expected an untrusted server certificate to be rejected")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	// The test server's certificate doubles as the client certificate
+	certificate := server.TLS.Certificates[0]
+	c := NewClient(server.URL).WithTLSConfig(&tls.Config{RootCAs: roots, Certificates: []tls.Certificate{certificate}})
+	if err := c.Loans().Delete(context.Background(), uuid.New()); err != nil {
+		t.Errorf("Expected the mutual TLS request to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestWithTraceContextFrom(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	trace := TraceContext{TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", CorrelationID: "saga-1"}
+	c := NewClient(server.URL).WithTraceContextFrom(func(ctx context.Context) TraceContext { return trace })
+	if err := c.Loans().Delete(context.Background(), uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("traceparent") != trace.TraceParent || header.Get("X-Correlation-ID") != "saga-1" {
+		t.Errorf("Expected the trace context to be sent, got %v", header)
+	}
+
+	trace = TraceContext{}
+	if err := c.Loans().Delete(context.Background(), uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := header["Traceparent"]; ok {
+		t.Errorf("Expected no traceparent without a trace, got %v", header)
+	}
+	if _, ok := header["X-Correlation-Id"]; ok {
+		t.Errorf("Expected no X-Correlation-ID without one, got %v", header)
+	}
+}
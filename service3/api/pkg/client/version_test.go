@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestClient_SendsVersionHeaders(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set(headerAPIVersion, APIVersion)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL).WithUserAgent("saga-client/1.0")
+	if err := c.Loans().Delete(context.Background(), uuid.New()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := header.Get(headerAPIVersion); got != APIVersion {
+		t.Errorf("Expected %s %s, got %q", headerAPIVersion, APIVersion, got)
+	}
+	if got := header.Get("User-Agent"); !strings.HasPrefix(got, "saga-client/1.0 ") || !strings.Contains(got, "-client/") ||
+		!strings.Contains(got, "go1.") {
+		t.Errorf("Expected the product, client and Go version in the User-Agent, got %q", got)
+	}
+	if got := c.ServerVersion(); got != APIVersion {
+		t.Errorf("Expected the server version %s, got %q", APIVersion, got)
+	}
+}
+
+func TestWithVersionMismatch_ReportsOtherVersions(t *testing.T) {
+	served, deprecated := "v1", ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerAPIVersion, served)
+		if deprecated != "" {
+			w.Header().Set("Deprecation", deprecated)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	var reports []string
+	c := NewClient(server.URL).WithVersionMismatch(func(ctx context.Context, served string, deprecated bool) {
+		if deprecated {
+			served += " deprecated"
+		}
+		reports = append(reports, served)
+	})
+	for _, version := range []struct{ served, deprecated string }{{"v1", ""}, {"v2", ""}, {"v1", "true"}} {
+		served, deprecated = version.served, version.deprecated
+		if err := c.Loans().Delete(context.Background(), uuid.New()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(reports) != 2 || reports[0] != "v2" || reports[1] != "v1 deprecated" {
+		t.Errorf("Expected the v2 and deprecated responses to be reported, got %v", reports)
+	}
+	if got := c.ServerVersion(); got != "v1" {
+		t.Errorf("Expected the last served version, got %q", got)
+	}
+}
@@ -0,0 +1,522 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: servicing/v1/loans.proto
+
+package servicingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Loan struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CustomerId         string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	MortgageId         string                 `protobuf:"bytes,3,opt,name=mortgage_id,json=mortgageId,proto3" json:"mortgage_id,omitempty"`
+	LoanAmount         string                 `protobuf:"bytes,4,opt,name=loan_amount,json=loanAmount,proto3" json:"loan_amount,omitempty"`
+	InterestRate       float64                `protobuf:"fixed64,5,opt,name=interest_rate,json=interestRate,proto3" json:"interest_rate,omitempty"`
+	TermYears          int32                  `protobuf:"varint,6,opt,name=term_years,json=termYears,proto3" json:"term_years,omitempty"`
+	MonthlyPayment     string                 `protobuf:"bytes,7,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"`
+	OutstandingBalance string                 `protobuf:"bytes,8,opt,name=outstanding_balance,json=outstandingBalance,proto3" json:"outstanding_balance,omitempty"`
+	Status             string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	StartDate          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	MaturityDate       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=maturity_date,json=maturityDate,proto3" json:"maturity_date,omitempty"`
+	CancelledAt        *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	CancelledBy        string                 `protobuf:"bytes,13,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	CancellationReason string                 `protobuf:"bytes,14,opt,name=cancellation_reason,json=cancellationReason,proto3" json:"cancellation_reason,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ModifiedAt         *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Loan) Reset() {
+	*x = Loan{}
+	mi := &file_servicing_v1_loans_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Loan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Loan) ProtoMessage() {}
+
+func (x *Loan) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_loans_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Loan.ProtoReflect.Descriptor instead.
+func (*Loan) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_loans_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Loan) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Loan) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Loan) GetMortgageId() string {
+	if x != nil {
+		return x.MortgageId
+	}
+	return ""
+}
+
+func (x *Loan) GetLoanAmount() string {
+	if x != nil {
+		return x.LoanAmount
+	}
+	return ""
+}
+
+func (x *Loan) GetInterestRate() float64 {
+	if x != nil {
+		return x.InterestRate
+	}
+	return 0
+}
+
+func (x *Loan) GetTermYears() int32 {
+	if x != nil {
+		return x.TermYears
+	}
+	return 0
+}
+
+func (x *Loan) GetMonthlyPayment() string {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return ""
+}
+
+func (x *Loan) GetOutstandingBalance() string {
+	if x != nil {
+		return x.OutstandingBalance
+	}
+	return ""
+}
+
+func (x *Loan) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Loan) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *Loan) GetMaturityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaturityDate
+	}
+	return nil
+}
+
+func (x *Loan) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Loan) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *Loan) GetCancellationReason() string {
+	if x != nil {
+		return x.CancellationReason
+	}
+	return ""
+}
+
+func (x *Loan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Loan) GetModifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModifiedAt
+	}
+	return nil
+}
+
+type CreateLoanRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId         string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	MortgageId         string                 `protobuf:"bytes,2,opt,name=mortgage_id,json=mortgageId,proto3" json:"mortgage_id,omitempty"`
+	LoanAmount         string                 `protobuf:"bytes,3,opt,name=loan_amount,json=loanAmount,proto3" json:"loan_amount,omitempty"`
+	InterestRate       float64                `protobuf:"fixed64,4,opt,name=interest_rate,json=interestRate,proto3" json:"interest_rate,omitempty"`
+	TermYears          int32                  `protobuf:"varint,5,opt,name=term_years,json=termYears,proto3" json:"term_years,omitempty"`
+	MonthlyPayment     string                 `protobuf:"bytes,6,opt,name=monthly_payment,json=monthlyPayment,proto3" json:"monthly_payment,omitempty"`
+	OutstandingBalance string                 `protobuf:"bytes,7,opt,name=outstanding_balance,json=outstandingBalance,proto3" json:"outstanding_balance,omitempty"`
+	StartDate          *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	MaturityDate       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=maturity_date,json=maturityDate,proto3" json:"maturity_date,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateLoanRequest) Reset() {
+	*x = CreateLoanRequest{}
+	mi := &file_servicing_v1_loans_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateLoanRequest) ProtoMessage() {}
+
+func (x *CreateLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_loans_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateLoanRequest.ProtoReflect.Descriptor instead.
+func (*CreateLoanRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_loans_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateLoanRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetMortgageId() string {
+	if x != nil {
+		return x.MortgageId
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetLoanAmount() string {
+	if x != nil {
+		return x.LoanAmount
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetInterestRate() float64 {
+	if x != nil {
+		return x.InterestRate
+	}
+	return 0
+}
+
+func (x *CreateLoanRequest) GetTermYears() int32 {
+	if x != nil {
+		return x.TermYears
+	}
+	return 0
+}
+
+func (x *CreateLoanRequest) GetMonthlyPayment() string {
+	if x != nil {
+		return x.MonthlyPayment
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetOutstandingBalance() string {
+	if x != nil {
+		return x.OutstandingBalance
+	}
+	return ""
+}
+
+func (x *CreateLoanRequest) GetStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *CreateLoanRequest) GetMaturityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MaturityDate
+	}
+	return nil
+}
+
+type GetLoanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoanRequest) Reset() {
+	*x = GetLoanRequest{}
+	mi := &file_servicing_v1_loans_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoanRequest) ProtoMessage() {}
+
+func (x *GetLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_loans_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoanRequest.ProtoReflect.Descriptor instead.
+func (*GetLoanRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_loans_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLoanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelLoanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CancelledBy   string                 `protobuf:"bytes,2,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelLoanRequest) Reset() {
+	*x = CancelLoanRequest{}
+	mi := &file_servicing_v1_loans_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelLoanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelLoanRequest) ProtoMessage() {}
+
+func (x *CancelLoanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_loans_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelLoanRequest.ProtoReflect.Descriptor instead.
+func (*CancelLoanRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_loans_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelLoanRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelLoanRequest) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *CancelLoanRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_servicing_v1_loans_proto protoreflect.FileDescriptor
+
+const file_servicing_v1_loans_proto_rawDesc = "" +
+	"\n" +
+	"\x18servicing/v1/loans.proto\x12\fservicing.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x05\n" +
+	"\x04Loan\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12\x1f\n" +
+	"\vmortgage_id\x18\x03 \x01(\tR\n" +
+	"mortgageId\x12\x1f\n" +
+	"\vloan_amount\x18\x04 \x01(\tR\n" +
+	"loanAmount\x12#\n" +
+	"\rinterest_rate\x18\x05 \x01(\x01R\finterestRate\x12\x1d\n" +
+	"\n" +
+	"term_years\x18\x06 \x01(\x05R\ttermYears\x12'\n" +
+	"\x0fmonthly_payment\x18\a \x01(\tR\x0emonthlyPayment\x12/\n" +
+	"\x13outstanding_balance\x18\b \x01(\tR\x12outstandingBalance\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"start_date\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12?\n" +
+	"\rmaturity_date\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\fmaturityDate\x12=\n" +
+	"\fcancelled_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x12!\n" +
+	"\fcancelled_by\x18\r \x01(\tR\vcancelledBy\x12/\n" +
+	"\x13cancellation_reason\x18\x0e \x01(\tR\x12cancellationReason\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vmodified_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"modifiedAt\"\x90\x03\n" +
+	"\x11CreateLoanRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12\x1f\n" +
+	"\vmortgage_id\x18\x02 \x01(\tR\n" +
+	"mortgageId\x12\x1f\n" +
+	"\vloan_amount\x18\x03 \x01(\tR\n" +
+	"loanAmount\x12#\n" +
+	"\rinterest_rate\x18\x04 \x01(\x01R\finterestRate\x12\x1d\n" +
+	"\n" +
+	"term_years\x18\x05 \x01(\x05R\ttermYears\x12'\n" +
+	"\x0fmonthly_payment\x18\x06 \x01(\tR\x0emonthlyPayment\x12/\n" +
+	"\x13outstanding_balance\x18\a \x01(\tR\x12outstandingBalance\x129\n" +
+	"\n" +
+	"start_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x12?\n" +
+	"\rmaturity_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\fmaturityDate\" \n" +
+	"\x0eGetLoanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"^\n" +
+	"\x11CancelLoanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fcancelled_by\x18\x02 \x01(\tR\vcancelledBy\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason2\xd0\x01\n" +
+	"\vLoanService\x12A\n" +
+	"\n" +
+	"CreateLoan\x12\x1f.servicing.v1.CreateLoanRequest\x1a\x12.servicing.v1.Loan\x12;\n" +
+	"\aGetLoan\x12\x1c.servicing.v1.GetLoanRequest\x1a\x12.servicing.v1.Loan\x12A\n" +
+	"\n" +
+	"CancelLoan\x12\x1f.servicing.v1.CancelLoanRequest\x1a\x12.servicing.v1.LoanB!Z\x1fservice3/api/pkg/pb/servicingv1b\x06proto3"
+
+var (
+	file_servicing_v1_loans_proto_rawDescOnce sync.Once
+	file_servicing_v1_loans_proto_rawDescData []byte
+)
+
+func file_servicing_v1_loans_proto_rawDescGZIP() []byte {
+	file_servicing_v1_loans_proto_rawDescOnce.Do(func() {
+		file_servicing_v1_loans_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_servicing_v1_loans_proto_rawDesc), len(file_servicing_v1_loans_proto_rawDesc)))
+	})
+	return file_servicing_v1_loans_proto_rawDescData
+}
+
+var file_servicing_v1_loans_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_servicing_v1_loans_proto_goTypes = []any{
+	(*Loan)(nil),                  // 0: servicing.v1.Loan
+	(*CreateLoanRequest)(nil),     // 1: servicing.v1.CreateLoanRequest
+	(*GetLoanRequest)(nil),        // 2: servicing.v1.GetLoanRequest
+	(*CancelLoanRequest)(nil),     // 3: servicing.v1.CancelLoanRequest
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_servicing_v1_loans_proto_depIdxs = []int32{
+	4,  // 0: servicing.v1.Loan.start_date:type_name -> google.protobuf.Timestamp
+	4,  // 1: servicing.v1.Loan.maturity_date:type_name -> google.protobuf.Timestamp
+	4,  // 2: servicing.v1.Loan.cancelled_at:type_name -> google.protobuf.Timestamp
+	4,  // 3: servicing.v1.Loan.created_at:type_name -> google.protobuf.Timestamp
+	4,  // 4: servicing.v1.Loan.modified_at:type_name -> google.protobuf.Timestamp
+	4,  // 5: servicing.v1.CreateLoanRequest.start_date:type_name -> google.protobuf.Timestamp
+	4,  // 6: servicing.v1.CreateLoanRequest.maturity_date:type_name -> google.protobuf.Timestamp
+	1,  // 7: servicing.v1.LoanService.CreateLoan:input_type -> servicing.v1.CreateLoanRequest
+	2,  // 8: servicing.v1.LoanService.GetLoan:input_type -> servicing.v1.GetLoanRequest
+	3,  // 9: servicing.v1.LoanService.CancelLoan:input_type -> servicing.v1.CancelLoanRequest
+	0,  // 10: servicing.v1.LoanService.CreateLoan:output_type -> servicing.v1.Loan
+	0,  // 11: servicing.v1.LoanService.GetLoan:output_type -> servicing.v1.Loan
+	0,  // 12: servicing.v1.LoanService.CancelLoan:output_type -> servicing.v1.Loan
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_servicing_v1_loans_proto_init() }
+func file_servicing_v1_loans_proto_init() {
+	if File_servicing_v1_loans_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_servicing_v1_loans_proto_rawDesc), len(file_servicing_v1_loans_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_servicing_v1_loans_proto_goTypes,
+		DependencyIndexes: file_servicing_v1_loans_proto_depIdxs,
+		MessageInfos:      file_servicing_v1_loans_proto_msgTypes,
+	}.Build()
+	File_servicing_v1_loans_proto = out.File
+	file_servicing_v1_loans_proto_goTypes = nil
+	file_servicing_v1_loans_proto_depIdxs = nil
+}
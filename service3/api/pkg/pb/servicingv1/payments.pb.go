@@ -0,0 +1,503 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: servicing/v1/payments.proto
+
+package servicingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Payment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LoanId          string                 `protobuf:"bytes,2,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	CustomerId      string                 `protobuf:"bytes,3,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	PaymentAmount   string                 `protobuf:"bytes,4,opt,name=payment_amount,json=paymentAmount,proto3" json:"payment_amount,omitempty"`
+	PrincipalAmount string                 `protobuf:"bytes,5,opt,name=principal_amount,json=principalAmount,proto3" json:"principal_amount,omitempty"`
+	InterestAmount  string                 `protobuf:"bytes,6,opt,name=interest_amount,json=interestAmount,proto3" json:"interest_amount,omitempty"`
+	EscrowAmount    string                 `protobuf:"bytes,7,opt,name=escrow_amount,json=escrowAmount,proto3" json:"escrow_amount,omitempty"`
+	PaymentDate     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=payment_date,json=paymentDate,proto3" json:"payment_date,omitempty"`
+	PaymentType     string                 `protobuf:"bytes,9,opt,name=payment_type,json=paymentType,proto3" json:"payment_type,omitempty"`
+	ReversalOf      string                 `protobuf:"bytes,10,opt,name=reversal_of,json=reversalOf,proto3" json:"reversal_of,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_servicing_v1_payments_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_payments_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_payments_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Payment) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *Payment) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *Payment) GetPaymentAmount() string {
+	if x != nil {
+		return x.PaymentAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetPrincipalAmount() string {
+	if x != nil {
+		return x.PrincipalAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetInterestAmount() string {
+	if x != nil {
+		return x.InterestAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetEscrowAmount() string {
+	if x != nil {
+		return x.EscrowAmount
+	}
+	return ""
+}
+
+func (x *Payment) GetPaymentDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaymentDate
+	}
+	return nil
+}
+
+func (x *Payment) GetPaymentType() string {
+	if x != nil {
+		return x.PaymentType
+	}
+	return ""
+}
+
+func (x *Payment) GetReversalOf() string {
+	if x != nil {
+		return x.ReversalOf
+	}
+	return ""
+}
+
+func (x *Payment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreatePaymentRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	LoanId          string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	CustomerId      string                 `protobuf:"bytes,2,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	PaymentAmount   string                 `protobuf:"bytes,3,opt,name=payment_amount,json=paymentAmount,proto3" json:"payment_amount,omitempty"`
+	PrincipalAmount string                 `protobuf:"bytes,4,opt,name=principal_amount,json=principalAmount,proto3" json:"principal_amount,omitempty"`
+	InterestAmount  string                 `protobuf:"bytes,5,opt,name=interest_amount,json=interestAmount,proto3" json:"interest_amount,omitempty"`
+	EscrowAmount    string                 `protobuf:"bytes,6,opt,name=escrow_amount,json=escrowAmount,proto3" json:"escrow_amount,omitempty"`
+	PaymentDate     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=payment_date,json=paymentDate,proto3" json:"payment_date,omitempty"`
+	PaymentType     string                 `protobuf:"bytes,8,opt,name=payment_type,json=paymentType,proto3" json:"payment_type,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreatePaymentRequest) Reset() {
+	*x = CreatePaymentRequest{}
+	mi := &file_servicing_v1_payments_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePaymentRequest) ProtoMessage() {}
+
+func (x *CreatePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_payments_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CreatePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_payments_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePaymentRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetPaymentAmount() string {
+	if x != nil {
+		return x.PaymentAmount
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetPrincipalAmount() string {
+	if x != nil {
+		return x.PrincipalAmount
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetInterestAmount() string {
+	if x != nil {
+		return x.InterestAmount
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetEscrowAmount() string {
+	if x != nil {
+		return x.EscrowAmount
+	}
+	return ""
+}
+
+func (x *CreatePaymentRequest) GetPaymentDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaymentDate
+	}
+	return nil
+}
+
+func (x *CreatePaymentRequest) GetPaymentType() string {
+	if x != nil {
+		return x.PaymentType
+	}
+	return ""
+}
+
+type GetPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentRequest) Reset() {
+	*x = GetPaymentRequest{}
+	mi := &file_servicing_v1_payments_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentRequest) ProtoMessage() {}
+
+func (x *GetPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_payments_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_payments_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetPaymentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListLoanPaymentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LoanId        string                 `protobuf:"bytes,1,opt,name=loan_id,json=loanId,proto3" json:"loan_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoanPaymentsRequest) Reset() {
+	*x = ListLoanPaymentsRequest{}
+	mi := &file_servicing_v1_payments_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoanPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoanPaymentsRequest) ProtoMessage() {}
+
+func (x *ListLoanPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_payments_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoanPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*ListLoanPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_payments_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListLoanPaymentsRequest) GetLoanId() string {
+	if x != nil {
+		return x.LoanId
+	}
+	return ""
+}
+
+func (x *ListLoanPaymentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListLoanPaymentsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListLoanPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLoanPaymentsResponse) Reset() {
+	*x = ListLoanPaymentsResponse{}
+	mi := &file_servicing_v1_payments_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLoanPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLoanPaymentsResponse) ProtoMessage() {}
+
+func (x *ListLoanPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_servicing_v1_payments_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLoanPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*ListLoanPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_servicing_v1_payments_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLoanPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+var File_servicing_v1_payments_proto protoreflect.FileDescriptor
+
+const file_servicing_v1_payments_proto_rawDesc = "" +
+	"\n" +
+	"\x1bservicing/v1/payments.proto\x12\fservicing.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb1\x03\n" +
+	"\aPayment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aloan_id\x18\x02 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vcustomer_id\x18\x03 \x01(\tR\n" +
+	"customerId\x12%\n" +
+	"\x0epayment_amount\x18\x04 \x01(\tR\rpaymentAmount\x12)\n" +
+	"\x10principal_amount\x18\x05 \x01(\tR\x0fprincipalAmount\x12'\n" +
+	"\x0finterest_amount\x18\x06 \x01(\tR\x0einterestAmount\x12#\n" +
+	"\rescrow_amount\x18\a \x01(\tR\fescrowAmount\x12=\n" +
+	"\fpayment_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vpaymentDate\x12!\n" +
+	"\fpayment_type\x18\t \x01(\tR\vpaymentType\x12\x1f\n" +
+	"\vreversal_of\x18\n" +
+	" \x01(\tR\n" +
+	"reversalOf\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd2\x02\n" +
+	"\x14CreatePaymentRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x1f\n" +
+	"\vcustomer_id\x18\x02 \x01(\tR\n" +
+	"customerId\x12%\n" +
+	"\x0epayment_amount\x18\x03 \x01(\tR\rpaymentAmount\x12)\n" +
+	"\x10principal_amount\x18\x04 \x01(\tR\x0fprincipalAmount\x12'\n" +
+	"\x0finterest_amount\x18\x05 \x01(\tR\x0einterestAmount\x12#\n" +
+	"\rescrow_amount\x18\x06 \x01(\tR\fescrowAmount\x12=\n" +
+	"\fpayment_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vpaymentDate\x12!\n" +
+	"\fpayment_type\x18\b \x01(\tR\vpaymentType\"#\n" +
+	"\x11GetPaymentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"`\n" +
+	"\x17ListLoanPaymentsRequest\x12\x17\n" +
+	"\aloan_id\x18\x01 \x01(\tR\x06loanId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"M\n" +
+	"\x18ListLoanPaymentsResponse\x121\n" +
+	"\bpayments\x18\x01 \x03(\v2\x15.servicing.v1.PaymentR\bpayments2\x85\x02\n" +
+	"\x0ePaymentService\x12J\n" +
+	"\rCreatePayment\x12\".servicing.v1.CreatePaymentRequest\x1a\x15.servicing.v1.Payment\x12D\n" +
+	"\n" +
+	"GetPayment\x12\x1f.servicing.v1.GetPaymentRequest\x1a\x15.servicing.v1.Payment\x12a\n" +
+	"\x10ListLoanPayments\x12%.servicing.v1.ListLoanPaymentsRequest\x1a&.servicing.v1.ListLoanPaymentsResponseB!Z\x1fservice3/api/pkg/pb/servicingv1b\x06proto3"
+
+var (
+	file_servicing_v1_payments_proto_rawDescOnce sync.Once
+	file_servicing_v1_payments_proto_rawDescData []byte
+)
+
+func file_servicing_v1_payments_proto_rawDescGZIP() []byte {
+	file_servicing_v1_payments_proto_rawDescOnce.Do(func() {
+		file_servicing_v1_payments_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_servicing_v1_payments_proto_rawDesc), len(file_servicing_v1_payments_proto_rawDesc)))
+	})
+	return file_servicing_v1_payments_proto_rawDescData
+}
+
+var file_servicing_v1_payments_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_servicing_v1_payments_proto_goTypes = []any{
+	(*Payment)(nil),                  // 0: servicing.v1.Payment
+	(*CreatePaymentRequest)(nil),     // 1: servicing.v1.CreatePaymentRequest
+	(*GetPaymentRequest)(nil),        // 2: servicing.v1.GetPaymentRequest
+	(*ListLoanPaymentsRequest)(nil),  // 3: servicing.v1.ListLoanPaymentsRequest
+	(*ListLoanPaymentsResponse)(nil), // 4: servicing.v1.ListLoanPaymentsResponse
+	(*timestamppb.Timestamp)(nil),    // 5: google.protobuf.Timestamp
+}
+var file_servicing_v1_payments_proto_depIdxs = []int32{
+	5, // 0: servicing.v1.Payment.payment_date:type_name -> google.protobuf.Timestamp
+	5, // 1: servicing.v1.Payment.created_at:type_name -> google.protobuf.Timestamp
+	5, // 2: servicing.v1.CreatePaymentRequest.payment_date:type_name -> google.protobuf.Timestamp
+	0, // 3: servicing.v1.ListLoanPaymentsResponse.payments:type_name -> servicing.v1.Payment
+	1, // 4: servicing.v1.PaymentService.CreatePayment:input_type -> servicing.v1.CreatePaymentRequest
+	2, // 5: servicing.v1.PaymentService.GetPayment:input_type -> servicing.v1.GetPaymentRequest
+	3, // 6: servicing.v1.PaymentService.ListLoanPayments:input_type -> servicing.v1.ListLoanPaymentsRequest
+	0, // 7: servicing.v1.PaymentService.CreatePayment:output_type -> servicing.v1.Payment
+	0, // 8: servicing.v1.PaymentService.GetPayment:output_type -> servicing.v1.Payment
+	4, // 9: servicing.v1.PaymentService.ListLoanPayments:output_type -> servicing.v1.ListLoanPaymentsResponse
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_servicing_v1_payments_proto_init() }
+func file_servicing_v1_payments_proto_init() {
+	if File_servicing_v1_payments_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_servicing_v1_payments_proto_rawDesc), len(file_servicing_v1_payments_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_servicing_v1_payments_proto_goTypes,
+		DependencyIndexes: file_servicing_v1_payments_proto_depIdxs,
+		MessageInfos:      file_servicing_v1_payments_proto_msgTypes,
+	}.Build()
+	File_servicing_v1_payments_proto = out.File
+	file_servicing_v1_payments_proto_goTypes = nil
+	file_servicing_v1_payments_proto_depIdxs = nil
+}